@@ -25,7 +25,7 @@ func NewAppointmentHandler(s appointment.AppointmentService) *appointmentHandler
 
 // GetByID godoc
 // @Summary      Get a appointment by Id
-// @Description  Get a appointment by Id from repository
+// @Description  Get a appointment by Id from repository with the medical alerts of the patient
 // @Tags         appointments
 // @Produce      json
 // @Param        token header string true "token"
@@ -268,9 +268,44 @@ func (h *appointmentHandler) Patch() gin.HandlerFunc {
 	}
 }
 
+// PatchStatus godoc
+// @Summary      Update the status of a appointment
+// @Description  Update the status of a appointment by id (scheduled, confirmed, cancelled, in_progress, completed, no_show), set by_patient when cancelling on behalf of the patient so late cancellations are recorded and charged
+// @Tags         appointments
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.AppointmentStatus true "Status"
+// @Param        id   path      int  true  "Appointment Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /appointments/:id/status [patch]
+func (h *appointmentHandler) PatchStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var status domain.AppointmentStatus
+		err = c.ShouldBindJSON(&status)
+		if err != nil || status.Status == "" {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		p, err := h.s.UpdateStatus(id, status)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, p)
+	}
+}
+
 // Delete godoc
 // @Summary      Delete a appointment
-// @Description  Delete a appointment by id in repository
+// @Description  Delete a appointment by id in repository, appointments with clinical notes must be cancelled instead
 // @Tags         appointments
 // @Produce      json
 // @Param        token header string true "token"
@@ -287,11 +322,16 @@ func (h *appointmentHandler) Delete() gin.HandlerFunc {
 			web.Failure(c, 400, errors.New("invalid id"))
 			return
 		}
-		err = h.s.Delete(id)
+		_, err = h.s.GetByID(id)
 		if err != nil {
 			web.Failure(c, 404, err)
 			return
 		}
+		err = h.s.Delete(id)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
 		web.Success(c, 204, fmt.Sprintf("user %d deleted", id))
 	}
 }
@@ -301,13 +341,13 @@ func (h *appointmentHandler) Delete() gin.HandlerFunc {
 // validateEmptys valida que los campos no esten vacios
 func (h *appointmentHandler) validateEmptys(appointment domain.Appointment) (bool, error) {
 	switch {
-	case appointment.Description == "":
-		return false, errors.New("Description can't be empty")
-	case appointment.Patient == domain.Patient{}:
+	case appointment.Description == "" && appointment.ProcedureCode == "":
+		return false, errors.New("Description or procedure_code can't be empty")
+	case appointment.Patient.Empty():
 		return false, errors.New("Patient can't be empty")
 	case appointment.Patient.Id == 0:
 		return false, errors.New("Patient.id can't be empty")
-	case appointment.Dentist == domain.Dentist{}:
+	case appointment.Dentist.Empty():
 		return false, errors.New("Dentist can't be empty")
 	case appointment.Dentist.Id == 0:
 		return false, errors.New("Dentist.id can't be empty")
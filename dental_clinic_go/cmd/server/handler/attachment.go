@@ -0,0 +1,279 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+
+	"dental_clinic_go/internal/attachment"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type attachmentHandler struct {
+	s attachment.Service
+}
+
+// NewAttachmentHandler crea un nuevo controller de archivos adjuntos
+func NewAttachmentHandler(s attachment.Service) *attachmentHandler {
+	return &attachmentHandler{s}
+}
+
+// GetByPatient godoc
+// @Summary      List the files of a patient
+// @Description  List the files of a patient, including the ones uploaded to their appointments
+// @Tags         files
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/files [get]
+func (h *attachmentHandler) GetByPatient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		attachments, err := h.s.GetByPatient(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, attachments)
+	}
+}
+
+// GetByAppointment godoc
+// @Summary      List the files of an appointment
+// @Description  List the files uploaded to an appointment
+// @Tags         files
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Appointment Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /appointments/:id/files [get]
+func (h *attachmentHandler) GetByAppointment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		attachments, err := h.s.GetByAppointment(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, attachments)
+	}
+}
+
+// PostByPatient godoc
+// @Summary      Upload a file to a patient
+// @Description  Upload an X-ray, photo or scanned document (jpeg, png, gif, webp, bmp, pdf, dicom) as a multipart field named file
+// @Tags         files
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        file formData file true "File"
+// @Param        description formData string false "Description"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/files [post]
+func (h *attachmentHandler) PostByPatient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		h.upload(c, domain.Attachment{PatientId: id})
+	}
+}
+
+// PostByAppointment godoc
+// @Summary      Upload a file to an appointment
+// @Description  Upload an X-ray, photo or scanned document (jpeg, png, gif, webp, bmp, pdf, dicom) to an appointment as a multipart field named file
+// @Tags         files
+// @Accept       multipart/form-data
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Appointment Id"
+// @Param        file formData file true "File"
+// @Param        description formData string false "Description"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /appointments/:id/files [post]
+func (h *attachmentHandler) PostByAppointment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		h.upload(c, domain.Attachment{AppointmentId: id})
+	}
+}
+
+// GetByID godoc
+// @Summary      Get a file metadata
+// @Description  Get the name, type, size and sha256 checksum of a file
+// @Tags         files
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "File Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /files/:id [get]
+func (h *attachmentHandler) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		a, err := h.s.GetByID(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, a)
+	}
+}
+
+// GetContent godoc
+// @Summary      Download a file
+// @Description  Download the content of a file
+// @Tags         files
+// @Produce      octet-stream
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "File Id"
+// @Success      200 {file}  file
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /files/:id/content [get]
+func (h *attachmentHandler) GetContent() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		a, content, err := h.s.Open(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		defer content.Close()
+		c.DataFromReader(200, a.Size, a.ContentType, content, map[string]string{
+			"Content-Disposition": mime.FormatMediaType("inline", map[string]string{"filename": a.FileName}),
+			"ETag":                fmt.Sprintf("%q", a.Checksum),
+		})
+	}
+}
+
+// GetThumbnail godoc
+// @Summary      Get the thumbnail of an image
+// @Description  Get a JPEG thumbnail of an uploaded image
+// @Tags         files
+// @Produce      jpeg
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "File Id"
+// @Success      200 {file}  file
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /files/:id/thumbnail [get]
+func (h *attachmentHandler) GetThumbnail() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		content, err := h.s.OpenThumbnail(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		defer content.Close()
+		c.DataFromReader(200, -1, "image/jpeg", content, nil)
+	}
+}
+
+// Delete godoc
+// @Summary      Delete a file
+// @Description  Delete a file and its thumbnail
+// @Tags         files
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "File Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /files/:id [delete]
+func (h *attachmentHandler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		err = h.s.Delete(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("file %d deleted", id))
+	}
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// upload lee el archivo del formulario multipart y lo guarda
+func (h *attachmentHandler) upload(c *gin.Context, a domain.Attachment) {
+	// se deja un margen para los demas campos del formulario
+	c.Request.Body = http.MaxBytesReader(c.Writer, c.Request.Body, h.s.MaxSize()+1<<20)
+	file, err := c.FormFile("file")
+	if err != nil {
+		var maxBytesError *http.MaxBytesError
+		if errors.As(err, &maxBytesError) {
+			web.Failure(c, 400, errors.New(fmt.Sprintf("file exceeds the maximum size of %d bytes", h.s.MaxSize())))
+			return
+		}
+		web.Failure(c, 400, errors.New("file not found"))
+		return
+	}
+	content, err := file.Open()
+	if err != nil {
+		web.Failure(c, 400, err)
+		return
+	}
+	defer content.Close()
+	a.FileName = file.Filename
+	a.Description = c.PostForm("description")
+	a, err = h.s.Upload(a, content)
+	if err != nil {
+		web.Failure(c, 400, err)
+		return
+	}
+	web.Success(c, 201, a)
+}
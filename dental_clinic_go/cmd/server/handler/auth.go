@@ -0,0 +1,143 @@
+package handler
+
+import (
+	"errors"
+
+	"dental_clinic_go/internal/auth"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type authHandler struct {
+	s auth.Service
+}
+
+// NewAuthHandler crea un nuevo controller de login y sesiones de usuarios
+func NewAuthHandler(s auth.Service) *authHandler {
+	return &authHandler{s}
+}
+
+// PostLogin godoc
+// @Summary      Login
+// @Description  Validate the username and password and open a session. The access token is a short-lived JWT sent in the Authorization header as Bearer, the refresh token gets new credentials once
+// @Tags         auth
+// @Produce      json
+// @Param        body body domain.LoginRequest true "Credentials"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      401 {object}  web.errorResponse
+// @Router       /auth/login [post]
+func (h *authHandler) PostLogin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var login domain.LoginRequest
+		err := c.ShouldBindJSON(&login)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		tokens, err := h.s.Login(login.Username, login.Password)
+		if err != nil {
+			web.Failure(c, 401, err)
+			return
+		}
+		web.Success(c, 200, tokens)
+	}
+}
+
+// PostRefresh godoc
+// @Summary      Refresh the session
+// @Description  Exchange a refresh token for a new access token and a new refresh token. Reusing a refresh token revokes the session
+// @Tags         auth
+// @Produce      json
+// @Param        body body domain.RefreshRequest true "Refresh token"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      401 {object}  web.errorResponse
+// @Router       /auth/refresh [post]
+func (h *authHandler) PostRefresh() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var refresh domain.RefreshRequest
+		err := c.ShouldBindJSON(&refresh)
+		if err != nil || refresh.RefreshToken == "" {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		tokens, err := h.s.Refresh(refresh.RefreshToken)
+		if err != nil {
+			web.Failure(c, 401, err)
+			return
+		}
+		web.Success(c, 200, tokens)
+	}
+}
+
+// PostLogout godoc
+// @Summary      Logout
+// @Description  Revoke the current session, its access and refresh tokens stop working
+// @Tags         auth
+// @Produce      json
+// @Param        token header string true "token"
+// @Success      204 {object}  web.response
+// @Failure      401 {object}  web.errorResponse
+// @Failure      500 {object}  web.errorResponse
+// @Router       /auth/logout [post]
+func (h *authHandler) PostLogout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := h.s.Logout(c.GetInt("session_id"))
+		if err != nil {
+			web.Failure(c, 500, err)
+			return
+		}
+		web.Success(c, 204, "session closed")
+	}
+}
+
+// GetMe godoc
+// @Summary      Get the current user
+// @Description  Get the user of the access token with the permissions of its role
+// @Tags         auth
+// @Produce      json
+// @Param        token header string true "token"
+// @Success      200 {object}  web.response
+// @Failure      401 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /auth/me [get]
+func (h *authHandler) GetMe() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		user, err := h.s.GetCurrent(c.GetInt("user_id"))
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, user)
+	}
+}
+
+// PutPassword godoc
+// @Summary      Change my password
+// @Description  Change the password of the current user, the other sessions of the user are revoked
+// @Tags         auth
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.PasswordChange true "Current and new password"
+// @Success      204 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /auth/password [put]
+func (h *authHandler) PutPassword() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var change domain.PasswordChange
+		err := c.ShouldBindJSON(&change)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		err = h.s.ChangePassword(c.GetInt("user_id"), c.GetInt("session_id"), change)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 204, "password changed")
+	}
+}
@@ -0,0 +1,126 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/chart"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type chartHandler struct {
+	s chart.Service
+}
+
+// NewChartHandler crea un nuevo controller del odontograma
+func NewChartHandler(s chart.Service) *chartHandler {
+	return &chartHandler{s}
+}
+
+// GetChart godoc
+// @Summary      Get the dental chart of a patient
+// @Description  Get the current conditions of each tooth (FDI numbering) of a patient
+// @Tags         chart
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/chart [get]
+func (h *chartHandler) GetChart() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		chart, err := h.s.GetChart(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, chart)
+	}
+}
+
+// GetHistory godoc
+// @Summary      Get the dental chart history of a patient
+// @Description  Get every entry recorded in the dental chart of a patient, optionally filtered by tooth
+// @Tags         chart
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        tooth   query      int  false  "FDI tooth number"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/chart/history [get]
+func (h *chartHandler) GetHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		tooth := 0
+		if toothParam := c.Query("tooth"); toothParam != "" {
+			tooth, err = strconv.Atoi(toothParam)
+			if err != nil {
+				web.Failure(c, 400, errors.New("invalid tooth"))
+				return
+			}
+		}
+		entries, err := h.s.GetHistory(id, tooth)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, entries)
+	}
+}
+
+// Post godoc
+// @Summary      Record a condition in the dental chart
+// @Description  Record a condition (healthy, caries, filling, crown, missing, implant) on a tooth and its surfaces (M, O, D, B, L)
+// @Tags         chart
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        body body domain.ChartEntry true "Chart entry"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/chart [post]
+func (h *chartHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var entry domain.ChartEntry
+		err = c.ShouldBindJSON(&entry)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		if entry.Dentist.Id == 0 {
+			web.Failure(c, 400, errors.New("Dentist.id can't be empty"))
+			return
+		}
+		entry.PatientId = id
+		e, err := h.s.Create(entry)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, e)
+	}
+}
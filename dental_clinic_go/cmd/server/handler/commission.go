@@ -0,0 +1,153 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"strconv"
+
+	"dental_clinic_go/internal/commission"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type commissionHandler struct {
+	s commission.Service
+}
+
+// NewCommissionHandler crea un nuevo controller de comisiones y produccion de los dentistas
+func NewCommissionHandler(s commission.Service) *commissionHandler {
+	return &commissionHandler{s}
+}
+
+// GetPlan godoc
+// @Summary      Get the commission of a dentist
+// @Description  Get the default commission percent of a dentist and the percent of each procedure with its own rule
+// @Tags         commissions
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Dentist Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /dentists/:id/commission [get]
+func (h *commissionHandler) GetPlan() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		plan, err := h.s.GetPlan(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, plan)
+	}
+}
+
+// PutPlan godoc
+// @Summary      Set the commission of a dentist
+// @Description  Replace the default commission percent of a dentist and its rules per procedure
+// @Tags         commissions
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Dentist Id"
+// @Param        body body domain.CommissionPlan true "Commission"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /dentists/:id/commission [put]
+func (h *commissionHandler) PutPlan() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var plan domain.CommissionPlan
+		err = c.ShouldBindJSON(&plan)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		plan, err = h.s.SavePlan(id, plan)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, plan)
+	}
+}
+
+// GetReport godoc
+// @Summary      Dentist production report
+// @Description  Get per dentist the completed appointments, the procedures billed for them and the commission between two dates. The period defaults to the current month
+// @Tags         commissions
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        from   query      string  false  "From yyyy-mm-dd"
+// @Param        to   query      string  false  "To yyyy-mm-dd"
+// @Param        dentist_id   query      int  false  "Dentist Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /reports/production [get]
+func (h *commissionHandler) GetReport() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dentistId, err := dentistIdQuery(c)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		report, err := h.s.Report(c.Query("from"), c.Query("to"), dentistId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, report)
+	}
+}
+
+// GetExport godoc
+// @Summary      Export the dentist production report
+// @Description  Download the production report as csv with a row per dentist and procedure
+// @Tags         commissions
+// @Produce      text/csv
+// @Param        token header string true "token"
+// @Param        from   query      string  false  "From yyyy-mm-dd"
+// @Param        to   query      string  false  "To yyyy-mm-dd"
+// @Param        dentist_id   query      int  false  "Dentist Id"
+// @Success      200 {file}  file
+// @Failure      400 {object}  web.errorResponse
+// @Router       /reports/production/export [get]
+func (h *commissionHandler) GetExport() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dentistId, err := dentistIdQuery(c)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		file, err := h.s.Export(c.Query("from"), c.Query("to"), dentistId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+		c.Data(200, file.ContentType, file.Content)
+	}
+}
+
+// dentistIdQuery lee el filtro opcional por dentista, 0 si no viene
+func dentistIdQuery(c *gin.Context) (int, error) {
+	if c.Query("dentist_id") == "" {
+		return 0, nil
+	}
+	id, err := strconv.Atoi(c.Query("dentist_id"))
+	if err != nil {
+		return 0, errors.New("invalid dentist_id")
+	}
+	return id, nil
+}
@@ -0,0 +1,287 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/consent"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type consentHandler struct {
+	s consent.Service
+}
+
+// NewConsentHandler crea un nuevo controller de consentimientos informados
+func NewConsentHandler(s consent.Service) *consentHandler {
+	return &consentHandler{s}
+}
+
+// GetTemplates godoc
+// @Summary      List the consent templates
+// @Description  List the informed consent templates and the procedures that require them
+// @Tags         consents
+// @Produce      json
+// @Param        token header string true "token"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /consent-templates [get]
+func (h *consentHandler) GetTemplates() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		templates, err := h.s.GetTemplates()
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, templates)
+	}
+}
+
+// GetTemplateByID godoc
+// @Summary      Get a consent template by Id
+// @Description  Get an informed consent template and the procedures that require it
+// @Tags         consents
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Consent template Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /consent-templates/:id [get]
+func (h *consentHandler) GetTemplateByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		t, err := h.s.GetTemplateByID(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, t)
+	}
+}
+
+// PostTemplate godoc
+// @Summary      Create a consent template
+// @Description  Create an informed consent template, procedures are the codes that can't start without it signed
+// @Tags         consents
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.ConsentTemplate true "Consent template"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /consent-templates [post]
+func (h *consentHandler) PostTemplate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var t domain.ConsentTemplate
+		err := c.ShouldBindJSON(&t)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		t, err = h.s.CreateTemplate(t)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, t)
+	}
+}
+
+// PutTemplate godoc
+// @Summary      Update a consent template
+// @Description  Replace an informed consent template, consents already created keep the previous text
+// @Tags         consents
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Consent template Id"
+// @Param        body body domain.ConsentTemplate true "Consent template"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /consent-templates/:id [put]
+func (h *consentHandler) PutTemplate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var t domain.ConsentTemplate
+		err = c.ShouldBindJSON(&t)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		t, err = h.s.UpdateTemplate(id, t)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, t)
+	}
+}
+
+// GetByPatient godoc
+// @Summary      Get the consents of a patient
+// @Description  Get the informed consents of a patient, newest first
+// @Tags         consents
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/consents [get]
+func (h *consentHandler) GetByPatient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		consents, err := h.s.GetByPatient(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, consents)
+	}
+}
+
+// Post godoc
+// @Summary      Prepare a consent for a patient
+// @Description  Create a pending consent from a template for an appointment or a treatment plan of the patient
+// @Tags         consents
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        body body domain.Consent true "Consent"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/consents [post]
+func (h *consentHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var consent domain.Consent
+		err = c.ShouldBindJSON(&consent)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		if consent.TemplateId == 0 {
+			web.Failure(c, 400, errors.New("template_id can't be empty"))
+			return
+		}
+		consent.PatientId = id
+		consent, err = h.s.Create(consent)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, consent)
+	}
+}
+
+// GetByID godoc
+// @Summary      Get a consent by Id
+// @Description  Get an informed consent with the text the patient signed
+// @Tags         consents
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Consent Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /consents/:id [get]
+func (h *consentHandler) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		consent, err := h.s.GetByID(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, consent)
+	}
+}
+
+// PostSign godoc
+// @Summary      Sign a consent
+// @Description  Save the drawn signature of the patient (base64 png or jpeg) with the time and IP address it was signed from
+// @Tags         consents
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Consent Id"
+// @Param        body body domain.ConsentSignature true "Signature"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /consents/:id/sign [post]
+func (h *consentHandler) PostSign() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var signature domain.ConsentSignature
+		err = c.ShouldBindJSON(&signature)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		consent, err := h.s.Sign(id, signature, c.ClientIP())
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, consent)
+	}
+}
+
+// GetSignature godoc
+// @Summary      Get the signature of a consent
+// @Description  Get the signature image of a signed consent
+// @Tags         consents
+// @Produce      png
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Consent Id"
+// @Success      200 {file}  file
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /consents/:id/signature [get]
+func (h *consentHandler) GetSignature() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		contentType, image, err := h.s.GetSignature(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		c.Data(200, contentType, image)
+	}
+}
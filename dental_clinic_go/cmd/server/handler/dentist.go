@@ -21,6 +21,26 @@ func NewDentistHandler(s dentist.Service) *dentistHandler {
 	return &dentistHandler{s}
 }
 
+// GetAll godoc
+// @Summary      List dentists
+// @Description  List dentists, optionally filtered by specialty
+// @Tags         dentists
+// @Produce      json
+// @Param        specialty   query      string  false  "Specialty"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /dentists [get]
+func (h *dentistHandler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dentists, err := h.s.GetAll(c.Query("specialty"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, dentists)
+	}
+}
+
 // GetByID godoc
 // @Summary      Get a dentist by Id
 // @Description  Get a dentist by Id from repository
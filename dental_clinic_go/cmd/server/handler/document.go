@@ -0,0 +1,113 @@
+package handler
+
+import (
+	"errors"
+	"mime"
+	"strconv"
+
+	"dental_clinic_go/internal/document"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type documentHandler struct {
+	s document.Service
+}
+
+// NewDocumentHandler crea un nuevo controller de documentos en PDF
+func NewDocumentHandler(s document.Service) *documentHandler {
+	return &documentHandler{s}
+}
+
+// GetInvoice godoc
+// @Summary      Download an invoice as PDF
+// @Description  Get an invoice as a PDF document with the clinic header, the patient, the dentist of the appointment and the items
+// @Tags         documents
+// @Produce      application/pdf
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Invoice Id"
+// @Success      200 {file}  file
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /invoices/:id/pdf [get]
+func (h *documentHandler) GetInvoice() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		d, err := h.s.Invoice(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		sendDocument(c, d)
+	}
+}
+
+// GetReceipt godoc
+// @Summary      Download a payment receipt as PDF
+// @Description  Get the receipt of a payment of the ledger as a PDF document with the invoices it was allocated to
+// @Tags         documents
+// @Produce      application/pdf
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Ledger entry Id"
+// @Success      200 {file}  file
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /ledger/:id/receipt [get]
+func (h *documentHandler) GetReceipt() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		d, err := h.s.Receipt(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		sendDocument(c, d)
+	}
+}
+
+// GetStatement godoc
+// @Summary      Download a patient statement as PDF
+// @Description  Get the account statement of a patient between two dates as a PDF document with the opening balance, the entries and the invoices due. Without from includes the whole account, to defaults to today
+// @Tags         documents
+// @Produce      application/pdf
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        from   query      string  false  "From yyyy-mm-dd"
+// @Param        to   query      string  false  "To yyyy-mm-dd"
+// @Success      200 {file}  file
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/statement [get]
+func (h *documentHandler) GetStatement() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		d, err := h.s.Statement(id, c.Query("from"), c.Query("to"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		sendDocument(c, d)
+	}
+}
+
+// sendDocument envia un documento para que el navegador lo muestre con su nombre de archivo
+func sendDocument(c *gin.Context, d domain.Document) {
+	c.Header("Content-Disposition", mime.FormatMediaType("inline", map[string]string{"filename": d.Name}))
+	c.Data(200, d.ContentType, d.Content)
+}
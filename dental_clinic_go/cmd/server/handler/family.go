@@ -0,0 +1,235 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/family"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type familyHandler struct {
+	s family.Service
+}
+
+// NewFamilyHandler crea un nuevo controller de familias y responsables
+func NewFamilyHandler(s family.Service) *familyHandler {
+	return &familyHandler{s}
+}
+
+// GetRelations godoc
+// @Summary      Get the relatives of a patient
+// @Description  Get the guardians, parents and spouse registered for a patient
+// @Tags         families
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/relations [get]
+func (h *familyHandler) GetRelations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		relations, err := h.s.GetRelations(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, relations)
+	}
+}
+
+// PostRelation godoc
+// @Summary      Add a relative to a patient
+// @Description  Register another patient as guardian, parent or spouse of the patient, responsible makes the relative the contact for reminders and invoices
+// @Tags         families
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        body body domain.PatientRelation true "Relation"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/relations [post]
+func (h *familyHandler) PostRelation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var relation domain.PatientRelation
+		err = c.ShouldBindJSON(&relation)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		if relation.RelatedId == 0 {
+			web.Failure(c, 400, errors.New("related_id can't be empty"))
+			return
+		}
+		relation.PatientId = id
+		r, err := h.s.CreateRelation(relation)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, r)
+	}
+}
+
+// DeleteRelation godoc
+// @Summary      Remove a relative of a patient
+// @Description  Delete a relation of a patient, if it was the responsible the patient becomes its own contact
+// @Tags         families
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        itemId   path      int  true  "Relation Id"
+// @Success      204
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/relations/:itemId [delete]
+func (h *familyHandler) DeleteRelation() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, itemId, err := patientAndItemIds(c)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		err = h.s.DeleteRelation(id, itemId)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("relation %d deleted", itemId))
+	}
+}
+
+// GetContact godoc
+// @Summary      Get the contact of a patient
+// @Description  Get who receives the reminders and invoices of a patient, the responsible relative or the patient itself
+// @Tags         families
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/contact [get]
+func (h *familyHandler) GetContact() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		contact, err := h.s.GetContact(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, contact)
+	}
+}
+
+// PutContact godoc
+// @Summary      Designate the contact of a patient
+// @Description  Make one of the patient's relations responsible for contact and billing, relation_id 0 makes the patient its own contact
+// @Tags         families
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        body body domain.ContactDesignation true "Relation"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/contact [put]
+func (h *familyHandler) PutContact() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var designation domain.ContactDesignation
+		err = c.ShouldBindJSON(&designation)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		contact, err := h.s.SetContact(id, designation.RelationId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, contact)
+	}
+}
+
+// GetFamily godoc
+// @Summary      Get the family of a patient
+// @Description  Get every patient related directly or indirectly to the patient with the contact of each one
+// @Tags         families
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/family [get]
+func (h *familyHandler) GetFamily() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		family, err := h.s.GetFamily(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, family)
+	}
+}
+
+// GetFamilyAppointments godoc
+// @Summary      Get the appointments of a family
+// @Description  Get the appointments of every member of the patient's family in chronological order
+// @Tags         families
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/family/appointments [get]
+func (h *familyHandler) GetFamilyAppointments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		appointments, err := h.s.GetFamilyAppointments(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, appointments)
+	}
+}
@@ -0,0 +1,565 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"mime"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/insurance"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type insuranceHandler struct {
+	s insurance.Service
+}
+
+// NewInsuranceHandler crea un nuevo controller de obras sociales y prepagas
+func NewInsuranceHandler(s insurance.Service) *insuranceHandler {
+	return &insuranceHandler{s}
+}
+
+// GetInsurers godoc
+// @Summary      List insurers
+// @Description  List the obras sociales and prepagas ordered by name
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Success      200 {object}  web.response
+// @Failure      500 {object}  web.errorResponse
+// @Router       /insurers [get]
+func (h *insuranceHandler) GetInsurers() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		insurers, err := h.s.GetInsurers()
+		if err != nil {
+			web.Failure(c, 500, err)
+			return
+		}
+		web.Success(c, 200, insurers)
+	}
+}
+
+// GetInsurer godoc
+// @Summary      Get an insurer by Id
+// @Description  Get an obra social or prepaga with its plans
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Insurer Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /insurers/:id [get]
+func (h *insuranceHandler) GetInsurer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		i, err := h.s.GetInsurer(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, i)
+	}
+}
+
+// PostInsurer godoc
+// @Summary      Create an insurer
+// @Description  Create an obra social or prepaga, claim_format is the file format of its claim batches: csv (default) or fixed_width
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.Insurer true "Insurer"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /insurers [post]
+func (h *insuranceHandler) PostInsurer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var i domain.Insurer
+		err := c.ShouldBindJSON(&i)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		i, err = h.s.CreateInsurer(i)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, i)
+	}
+}
+
+// PutInsurer godoc
+// @Summary      Update an insurer
+// @Description  Update the code, name, type and claim format of an insurer
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Insurer Id"
+// @Param        body body domain.Insurer true "Insurer"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /insurers/:id [put]
+func (h *insuranceHandler) PutInsurer() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var i domain.Insurer
+		err = c.ShouldBindJSON(&i)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		i, err = h.s.UpdateInsurer(id, i)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, i)
+	}
+}
+
+// PostPlan godoc
+// @Summary      Create a plan of an insurer
+// @Description  Create a plan of an obra social or prepaga, its coverage rules are added per procedure
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Insurer Id"
+// @Param        body body domain.InsurancePlan true "Plan"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /insurers/:id/plans [post]
+func (h *insuranceHandler) PostPlan() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var p domain.InsurancePlan
+		err = c.ShouldBindJSON(&p)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		p.InsurerId = id
+		p, err = h.s.CreatePlan(p)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, p)
+	}
+}
+
+// GetPlan godoc
+// @Summary      Get a plan by Id
+// @Description  Get an insurance plan with its coverage rules
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Plan Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /insurance-plans/:id [get]
+func (h *insuranceHandler) GetPlan() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		p, err := h.s.GetPlan(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, p)
+	}
+}
+
+// PutRule godoc
+// @Summary      Set the coverage of a procedure
+// @Description  Set the percent of the fee the plan pays for a procedure, the copay the patient pays and how many times a year it is covered (0 unlimited)
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Plan Id"
+// @Param        code   path      string  true  "Procedure code"
+// @Param        body body domain.CoverageRule true "Rule"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /insurance-plans/:id/rules/:code [put]
+func (h *insuranceHandler) PutRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var rule domain.CoverageRule
+		err = c.ShouldBindJSON(&rule)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		rule.PlanId, rule.ProcedureCode = id, c.Param("code")
+		p, err := h.s.SaveRule(rule)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, p)
+	}
+}
+
+// DeleteRule godoc
+// @Summary      Remove the coverage of a procedure
+// @Description  Remove the rule of a procedure, the plan no longer covers it
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Plan Id"
+// @Param        code   path      string  true  "Procedure code"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /insurance-plans/:id/rules/:code [delete]
+func (h *insuranceHandler) DeleteRule() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		code := c.Param("code")
+		err = h.s.DeleteRule(id, code)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("rule of procedure %s deleted", code))
+	}
+}
+
+// GetCoverages godoc
+// @Summary      Get the coverages of a patient
+// @Description  Get the obras sociales and prepagas of a patient with their validity, the most recent first
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/coverages [get]
+func (h *insuranceHandler) GetCoverages() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		coverages, err := h.s.GetCoverages(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, coverages)
+	}
+}
+
+// PostCoverage godoc
+// @Summary      Add a coverage to a patient
+// @Description  Add the plan and member number of a patient, valid_from defaults to today and an empty valid_to never expires
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        body body domain.PatientCoverage true "Coverage"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/coverages [post]
+func (h *insuranceHandler) PostCoverage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var coverage domain.PatientCoverage
+		err = c.ShouldBindJSON(&coverage)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		coverage.PatientId = id
+		coverage, err = h.s.CreateCoverage(coverage)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, coverage)
+	}
+}
+
+// PutCoverage godoc
+// @Summary      Update a coverage of a patient
+// @Description  Update the plan, member number or validity of a coverage, set valid_to to end it
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        itemId   path      int  true  "Coverage Id"
+// @Param        body body domain.PatientCoverage true "Coverage"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/coverages/:itemId [put]
+func (h *insuranceHandler) PutCoverage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, itemId, err := patientAndItemIds(c)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		var coverage domain.PatientCoverage
+		err = c.ShouldBindJSON(&coverage)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		coverage.Id, coverage.PatientId = itemId, id
+		coverage, err = h.s.UpdateCoverage(coverage)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, coverage)
+	}
+}
+
+// DeleteCoverage godoc
+// @Summary      Delete a coverage of a patient
+// @Description  Delete a coverage loaded by mistake, to end a coverage set its valid_to
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        itemId   path      int  true  "Coverage Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/coverages/:itemId [delete]
+func (h *insuranceHandler) DeleteCoverage() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, itemId, err := patientAndItemIds(c)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		err = h.s.DeleteCoverage(id, itemId)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("coverage %d deleted", itemId))
+	}
+}
+
+// GetEstimate godoc
+// @Summary      Estimate the coverage of a procedure
+// @Description  Estimate how much the best coverage of a patient pays for a procedure and how much the patient pays, the date defaults to today
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        procedure_code   query      string  true  "Procedure code"
+// @Param        date   query      string  false  "Date yyyy-mm-dd"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/coverage-estimate [get]
+func (h *insuranceHandler) GetEstimate() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		estimate, err := h.s.Estimate(id, c.Query("procedure_code"), c.Query("date"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, estimate)
+	}
+}
+
+// GetBatches godoc
+// @Summary      List claim batches
+// @Description  List the claim batches without their claims, the newest first
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        insurer_id   query      int  false  "Insurer Id"
+// @Param        status   query      string  false  "open, submitted or paid"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /claim-batches [get]
+func (h *insuranceHandler) GetBatches() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		insurerId := 0
+		if c.Query("insurer_id") != "" {
+			id, err := strconv.Atoi(c.Query("insurer_id"))
+			if err != nil {
+				web.Failure(c, 400, errors.New("invalid insurer_id"))
+				return
+			}
+			insurerId = id
+		}
+		batches, err := h.s.GetBatches(insurerId, c.Query("status"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, batches)
+	}
+}
+
+// GetBatch godoc
+// @Summary      Get a claim batch by Id
+// @Description  Get a claim batch with its claims
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Batch Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /claim-batches/:id [get]
+func (h *insuranceHandler) GetBatch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		b, err := h.s.GetBatch(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, b)
+	}
+}
+
+// PostBatch godoc
+// @Summary      Create a claim batch
+// @Description  Claim the completed appointments between from and to of the patients covered by an insurer that were not claimed yet
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.ClaimBatch true "Insurer and period"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /claim-batches [post]
+func (h *insuranceHandler) PostBatch() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var b domain.ClaimBatch
+		err := c.ShouldBindJSON(&b)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		b, err = h.s.CreateBatch(b)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, b)
+	}
+}
+
+// PatchBatchStatus godoc
+// @Summary      Mark a claim batch as submitted or paid
+// @Description  An open batch can be submitted and a submitted batch paid
+// @Tags         insurance
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Batch Id"
+// @Param        body body domain.ClaimBatchStatus true "Status"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /claim-batches/:id/status [patch]
+func (h *insuranceHandler) PatchBatchStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var status domain.ClaimBatchStatus
+		err = c.ShouldBindJSON(&status)
+		if err != nil || status.Status == "" {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		b, err := h.s.UpdateBatchStatus(id, status.Status)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, b)
+	}
+}
+
+// GetExport godoc
+// @Summary      Export a claim batch
+// @Description  Download a claim batch in the file format of its insurer
+// @Tags         insurance
+// @Produce      plain
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Batch Id"
+// @Success      200 {file}  file
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /claim-batches/:id/export [get]
+func (h *insuranceHandler) GetExport() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		file, err := h.s.Export(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		c.Header("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": file.Name}))
+		c.Data(200, file.ContentType, file.Content)
+	}
+}
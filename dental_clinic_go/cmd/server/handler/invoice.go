@@ -0,0 +1,249 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/invoice"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type invoiceHandler struct {
+	s invoice.Service
+}
+
+// NewInvoiceHandler crea un nuevo controller de facturas
+func NewInvoiceHandler(s invoice.Service) *invoiceHandler {
+	return &invoiceHandler{s}
+}
+
+// GetAll godoc
+// @Summary      List invoices
+// @Description  List the invoices in a status, without status lists every invoice, the newest first
+// @Tags         invoices
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        status   query      string  false  "draft, issued or void"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /invoices [get]
+func (h *invoiceHandler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		invoices, err := h.s.GetByStatus(c.Query("status"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, invoices)
+	}
+}
+
+// GetByID godoc
+// @Summary      Get an invoice by Id
+// @Description  Get an invoice with its items and totals
+// @Tags         invoices
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Invoice Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /invoices/:id [get]
+func (h *invoiceHandler) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		i, err := h.s.GetByID(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, i)
+	}
+}
+
+// GetByPatient godoc
+// @Summary      Get the invoices of a patient
+// @Description  Get every invoice of a patient, the newest first
+// @Tags         invoices
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/invoices [get]
+func (h *invoiceHandler) GetByPatient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		invoices, err := h.s.GetByPatient(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, invoices)
+	}
+}
+
+// Post godoc
+// @Summary      Create a draft invoice
+// @Description  Create a draft invoice billed to the responsible contact of the patient, items without price take the fee of their procedure and an invoice of a completed appointment without items takes its procedures
+// @Tags         invoices
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.Invoice true "Invoice"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /invoices [post]
+func (h *invoiceHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var i domain.Invoice
+		err := c.ShouldBindJSON(&i)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		i, err = h.s.Create(i)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, i)
+	}
+}
+
+// Put godoc
+// @Summary      Update a draft invoice
+// @Description  Replace the items and notes of a draft invoice
+// @Tags         invoices
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Invoice Id"
+// @Param        body body domain.Invoice true "Invoice"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /invoices/:id [put]
+func (h *invoiceHandler) Put() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var i domain.Invoice
+		err = c.ShouldBindJSON(&i)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		i, err = h.s.Update(id, i)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, i)
+	}
+}
+
+// PostIssue godoc
+// @Summary      Issue a draft invoice
+// @Description  Issue a draft invoice with the next number of the series and today as issue date
+// @Tags         invoices
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Invoice Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /invoices/:id/issue [post]
+func (h *invoiceHandler) PostIssue() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		i, err := h.s.Issue(id)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, i)
+	}
+}
+
+// PostVoid godoc
+// @Summary      Void an invoice
+// @Description  Void a draft or issued invoice, issued invoices keep their number and need a reason
+// @Tags         invoices
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Invoice Id"
+// @Param        body body domain.InvoiceVoidRequest false "Reason"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /invoices/:id/void [post]
+func (h *invoiceHandler) PostVoid() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var request domain.InvoiceVoidRequest
+		if c.Request.ContentLength > 0 {
+			err = c.ShouldBindJSON(&request)
+			if err != nil {
+				web.Failure(c, 400, errors.New("invalid json"))
+				return
+			}
+		}
+		i, err := h.s.Void(id, request.Reason)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, i)
+	}
+}
+
+// PostByAppointment godoc
+// @Summary      Invoice a completed appointment
+// @Description  Create and issue the invoice of a completed appointment with its procedures, if the appointment has a draft invoice that one is issued
+// @Tags         invoices
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Appointment Id"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /appointments/:id/invoice [post]
+func (h *invoiceHandler) PostByAppointment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		i, err := h.s.IssueFromAppointment(id)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, i)
+	}
+}
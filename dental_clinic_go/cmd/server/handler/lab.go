@@ -0,0 +1,197 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/lab"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type labHandler struct {
+	s lab.Service
+}
+
+// NewLabHandler crea un nuevo controller de ordenes de laboratorio
+func NewLabHandler(s lab.Service) *labHandler {
+	return &labHandler{s}
+}
+
+// GetAll godoc
+// @Summary      List lab orders
+// @Description  List the lab orders in a status ordered by expected return date, without status lists the orders still at the lab
+// @Tags         lab-orders
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        status   query      string  false  "sent, in_fabrication, received or fitted"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /lab-orders [get]
+func (h *labHandler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		orders, err := h.s.GetByStatus(c.Query("status"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, orders)
+	}
+}
+
+// GetByID godoc
+// @Summary      Get a lab order by Id
+// @Description  Get a lab order with its fitting appointment and a warning if the fitting is booked before the expected return date
+// @Tags         lab-orders
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Lab order Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /lab-orders/:id [get]
+func (h *labHandler) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		order, err := h.s.GetByID(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, order)
+	}
+}
+
+// GetByPatient godoc
+// @Summary      Get the lab orders of a patient
+// @Description  Get every lab order of a patient ordered by expected return date
+// @Tags         lab-orders
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/lab-orders [get]
+func (h *labHandler) GetByPatient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		orders, err := h.s.GetByPatient(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, orders)
+	}
+}
+
+// Post godoc
+// @Summary      Send work to the lab
+// @Description  Create a lab order for a treatment item of the patient, sent_date defaults to today
+// @Tags         lab-orders
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.LabOrder true "Lab order"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /lab-orders [post]
+func (h *labHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var order domain.LabOrder
+		err := c.ShouldBindJSON(&order)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		if order.PatientId == 0 || order.Dentist.Id == 0 || order.TreatmentItemId == 0 {
+			web.Failure(c, 400, errors.New("patient_id, Dentist.id and treatment_item_id can't be empty"))
+			return
+		}
+		o, err := h.s.Create(order)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, o)
+	}
+}
+
+// Put godoc
+// @Summary      Update a lab order
+// @Description  Update the lab, description, expected return date and notes of a lab order
+// @Tags         lab-orders
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Lab order Id"
+// @Param        body body domain.LabOrder true "Lab order"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /lab-orders/:id [put]
+func (h *labHandler) Put() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var order domain.LabOrder
+		err = c.ShouldBindJSON(&order)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		o, err := h.s.Update(id, order)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, o)
+	}
+}
+
+// PatchStatus godoc
+// @Summary      Update the status of a lab order
+// @Description  Move a lab order from sent to in_fabrication, received and fitted, the received and fitted dates are set to today
+// @Tags         lab-orders
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Lab order Id"
+// @Param        body body domain.LabOrderStatus true "Status"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /lab-orders/:id/status [patch]
+func (h *labHandler) PatchStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var status domain.LabOrderStatus
+		err = c.ShouldBindJSON(&status)
+		if err != nil || status.Status == "" {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		o, err := h.s.UpdateStatus(id, status.Status)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, o)
+	}
+}
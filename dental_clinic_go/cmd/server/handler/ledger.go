@@ -0,0 +1,195 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/ledger"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type ledgerHandler struct {
+	s ledger.Service
+}
+
+// NewLedgerHandler crea un nuevo controller de cuentas de pacientes
+func NewLedgerHandler(s ledger.Service) *ledgerHandler {
+	return &ledgerHandler{s}
+}
+
+// GetByPatient godoc
+// @Summary      Get the ledger of a patient
+// @Description  Get the charges, payments, refunds and adjustments of a patient in chronological order with the running balance
+// @Tags         ledger
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/ledger [get]
+func (h *ledgerHandler) GetByPatient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		entries, err := h.s.GetByPatient(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, entries)
+	}
+}
+
+// Post godoc
+// @Summary      Record a ledger entry
+// @Description  Record a charge, payment, refund or adjustment of a patient, payments can be allocated to issued invoices and negative adjustments credit the patient
+// @Tags         ledger
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        body body domain.LedgerEntry true "Ledger entry"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/ledger [post]
+func (h *ledgerHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var entry domain.LedgerEntry
+		err = c.ShouldBindJSON(&entry)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		entry.PatientId = id
+		e, err := h.s.Create(entry)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, e)
+	}
+}
+
+// GetBalance godoc
+// @Summary      Get the balance of a patient
+// @Description  Get the balance of a patient with the aging of the debt and the unpaid invoices
+// @Tags         ledger
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/balance [get]
+func (h *ledgerHandler) GetBalance() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		b, err := h.s.GetBalance(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, b)
+	}
+}
+
+// GetByID godoc
+// @Summary      Get a ledger entry by Id
+// @Description  Get a ledger entry with its invoice allocations
+// @Tags         ledger
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Ledger entry Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /ledger/:id [get]
+func (h *ledgerHandler) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		e, err := h.s.GetByID(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, e)
+	}
+}
+
+// PostAllocations godoc
+// @Summary      Allocate a payment to invoices
+// @Description  Allocate the unallocated part of a payment to issued invoices of the patient
+// @Tags         ledger
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Ledger entry Id"
+// @Param        body body []domain.PaymentAllocation true "Allocations"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /ledger/:id/allocations [post]
+func (h *ledgerHandler) PostAllocations() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var allocations []domain.PaymentAllocation
+		err = c.ShouldBindJSON(&allocations)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		e, err := h.s.Allocate(id, allocations)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, e)
+	}
+}
+
+// GetAging godoc
+// @Summary      Aging report
+// @Description  Get the debt of every patient at a date split in 0-30, 31-60, 61-90 and over 90 days, the date defaults to today
+// @Tags         ledger
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        date   query      string  false  "Date yyyy-mm-dd"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /ledger/aging [get]
+func (h *ledgerHandler) GetAging() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		report, err := h.s.GetAging(c.Query("date"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, report)
+	}
+}
@@ -0,0 +1,115 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/link"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type linkHandler struct {
+	s link.Service
+}
+
+// NewLinkHandler crea un nuevo controller de links firmados
+func NewLinkHandler(s link.Service) *linkHandler {
+	return &linkHandler{s}
+}
+
+// GetAppointmentLinks godoc
+// @Summary      Get the confirm and cancel links of an appointment
+// @Description  Generate signed, expiring links that let the patient confirm or cancel an appointment without logging in
+// @Tags         appointments
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Appointment Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /appointments/:id/links [get]
+func (h *linkHandler) GetAppointmentLinks() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		links, err := h.s.Generate(id)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, links)
+	}
+}
+
+// PostReminder godoc
+// @Summary      Send a reminder of an appointment
+// @Description  Email the confirm and cancel links of an appointment to the patient's contact, the responsible guardian for minors
+// @Tags         appointments
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Appointment Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /appointments/:id/reminder [post]
+func (h *linkHandler) PostReminder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		reminder, err := h.s.Remind(id)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, reminder)
+	}
+}
+
+// GetByToken godoc
+// @Summary      Preview a signed link
+// @Description  Get the action of a signed link and the date, hour, dentist and status of its appointment without applying it, patient data is never included
+// @Tags         links
+// @Produce      json
+// @Param        token   path      string  true  "Signed token"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /links/:token [get]
+func (h *linkHandler) GetByToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		preview, err := h.s.Preview(c.Param("token"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, preview)
+	}
+}
+
+// PostByToken godoc
+// @Summary      Apply a signed link
+// @Description  Confirm or cancel the appointment of a signed link and return its date, hour, dentist and status, applying it again has no effect
+// @Tags         links
+// @Produce      json
+// @Param        token   path      string  true  "Signed token"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /links/:token [post]
+func (h *linkHandler) PostByToken() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		a, err := h.s.Apply(c.Param("token"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, a)
+	}
+}
@@ -0,0 +1,374 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/medical"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type medicalHandler struct {
+	s medical.Service
+}
+
+// NewMedicalHandler crea un nuevo controller de historias medicas
+func NewMedicalHandler(s medical.Service) *medicalHandler {
+	return &medicalHandler{s}
+}
+
+// GetHistory godoc
+// @Summary      Get the medical history of a patient
+// @Description  Get the allergies, current medications, conditions and last reviewed date of a patient
+// @Tags         medical-history
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/medical-history [get]
+func (h *medicalHandler) GetHistory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		history, err := h.s.GetHistory(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, history)
+	}
+}
+
+// PostReview godoc
+// @Summary      Mark the medical history as reviewed
+// @Description  Save the date the medical history was reviewed with the patient, today by default
+// @Tags         medical-history
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        body body domain.MedicalReview false "Review"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/medical-history/review [post]
+func (h *medicalHandler) PostReview() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var review domain.MedicalReview
+		if c.Request.ContentLength > 0 {
+			err = c.ShouldBindJSON(&review)
+			if err != nil {
+				web.Failure(c, 400, errors.New("invalid json"))
+				return
+			}
+		}
+		history, err := h.s.Review(id, review.Date)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, history)
+	}
+}
+
+// GetAllergies godoc
+// @Summary      Get the allergies of a patient
+// @Description  Get the allergies of a patient
+// @Tags         medical-history
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/allergies [get]
+func (h *medicalHandler) GetAllergies() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		history, err := h.s.GetHistory(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, history.Allergies)
+	}
+}
+
+// PostAllergy godoc
+// @Summary      Add an allergy to a patient
+// @Description  Add an allergy with its substance and severity (mild, moderate, severe)
+// @Tags         medical-history
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        body body domain.Allergy true "Allergy"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/allergies [post]
+func (h *medicalHandler) PostAllergy() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var allergy domain.Allergy
+		err = c.ShouldBindJSON(&allergy)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		allergy.PatientId = id
+		allergy, err = h.s.CreateAllergy(allergy)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, allergy)
+	}
+}
+
+// DeleteAllergy godoc
+// @Summary      Delete an allergy of a patient
+// @Description  Delete an allergy that no longer applies
+// @Tags         medical-history
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        itemId   path      int  true  "Allergy Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/allergies/:itemId [delete]
+func (h *medicalHandler) DeleteAllergy() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, itemId, err := patientAndItemIds(c)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		err = h.s.DeleteAllergy(id, itemId)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("allergy %d deleted", itemId))
+	}
+}
+
+// GetMedications godoc
+// @Summary      Get the current medications of a patient
+// @Description  Get the current medications of a patient
+// @Tags         medical-history
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/medications [get]
+func (h *medicalHandler) GetMedications() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		history, err := h.s.GetHistory(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, history.Medications)
+	}
+}
+
+// PostMedication godoc
+// @Summary      Add a medication to a patient
+// @Description  Add a medication with its name, dose and frequency
+// @Tags         medical-history
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        body body domain.Medication true "Medication"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/medications [post]
+func (h *medicalHandler) PostMedication() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var medication domain.Medication
+		err = c.ShouldBindJSON(&medication)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		medication.PatientId = id
+		medication, err = h.s.CreateMedication(medication)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, medication)
+	}
+}
+
+// DeleteMedication godoc
+// @Summary      Delete a medication of a patient
+// @Description  Delete a medication that no longer applies
+// @Tags         medical-history
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        itemId   path      int  true  "Medication Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/medications/:itemId [delete]
+func (h *medicalHandler) DeleteMedication() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, itemId, err := patientAndItemIds(c)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		err = h.s.DeleteMedication(id, itemId)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("medication %d deleted", itemId))
+	}
+}
+
+// GetConditions godoc
+// @Summary      Get the medical conditions of a patient
+// @Description  Get the medical conditions of a patient
+// @Tags         medical-history
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/conditions [get]
+func (h *medicalHandler) GetConditions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		history, err := h.s.GetHistory(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, history.Conditions)
+	}
+}
+
+// PostCondition godoc
+// @Summary      Add a condition to a patient
+// @Description  Add a condition with its name, for example diabetes, anticoagulants or pregnancy
+// @Tags         medical-history
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        body body domain.MedicalCondition true "Condition"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/conditions [post]
+func (h *medicalHandler) PostCondition() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var condition domain.MedicalCondition
+		err = c.ShouldBindJSON(&condition)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		condition.PatientId = id
+		condition, err = h.s.CreateCondition(condition)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, condition)
+	}
+}
+
+// DeleteCondition godoc
+// @Summary      Delete a condition of a patient
+// @Description  Delete a condition that no longer applies
+// @Tags         medical-history
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        itemId   path      int  true  "Condition Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/conditions/:itemId [delete]
+func (h *medicalHandler) DeleteCondition() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, itemId, err := patientAndItemIds(c)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		err = h.s.DeleteCondition(id, itemId)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("condition %d deleted", itemId))
+	}
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// patientAndItemIds lee los ids del paciente y del dato de la historia medica de la ruta
+func patientAndItemIds(c *gin.Context) (int, int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, 0, errors.New("invalid id")
+	}
+	itemId, err := strconv.Atoi(c.Param("itemId"))
+	if err != nil {
+		return 0, 0, errors.New("invalid itemId")
+	}
+	return id, itemId, nil
+}
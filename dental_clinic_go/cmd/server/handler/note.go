@@ -0,0 +1,265 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/note"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type noteHandler struct {
+	s note.Service
+}
+
+// NewNoteHandler crea un nuevo controller de notas clinicas
+func NewNoteHandler(s note.Service) *noteHandler {
+	return &noteHandler{s}
+}
+
+// GetByAppointment godoc
+// @Summary      Get the clinical notes of an appointment
+// @Description  Get the clinical notes of an appointment with their addenda
+// @Tags         clinical-notes
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Appointment Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /appointments/:id/notes [get]
+func (h *noteHandler) GetByAppointment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		notes, err := h.s.GetByAppointment(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, notes)
+	}
+}
+
+// Post godoc
+// @Summary      Write a clinical note
+// @Description  Create a draft clinical note (subjective, objective, assessment, plan) for an appointment. The author is the dentist linked to the current user
+// @Tags         clinical-notes
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Appointment Id"
+// @Param        body body domain.ClinicalNote true "Clinical note"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /appointments/:id/notes [post]
+func (h *noteHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		dentistId, ok := currentDentist(c)
+		if !ok {
+			return
+		}
+		var n domain.ClinicalNote
+		err = c.ShouldBindJSON(&n)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		n.AppointmentId = id
+		n, err = h.s.Create(n, dentistId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, n)
+	}
+}
+
+// GetByID godoc
+// @Summary      Get a clinical note by Id
+// @Description  Get a clinical note with its addenda
+// @Tags         clinical-notes
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Clinical note Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /clinical-notes/:id [get]
+func (h *noteHandler) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		n, err := h.s.GetByID(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, n)
+	}
+}
+
+// Put godoc
+// @Summary      Update a draft clinical note
+// @Description  Replace the sections of a clinical note that isn't signed yet, only its author can change it
+// @Tags         clinical-notes
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Clinical note Id"
+// @Param        body body domain.ClinicalNote true "Clinical note"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /clinical-notes/:id [put]
+func (h *noteHandler) Put() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		dentistId, ok := currentDentist(c)
+		if !ok {
+			return
+		}
+		var n domain.ClinicalNote
+		err = c.ShouldBindJSON(&n)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		n, err = h.s.Update(id, n, dentistId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, n)
+	}
+}
+
+// PostSign godoc
+// @Summary      Sign a clinical note
+// @Description  Sign a draft clinical note as the dentist linked to the current user, who must be its author. Signed notes can't be modified or deleted
+// @Tags         clinical-notes
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Clinical note Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /clinical-notes/:id/sign [post]
+func (h *noteHandler) PostSign() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		dentistId, ok := currentDentist(c)
+		if !ok {
+			return
+		}
+		n, err := h.s.Sign(id, dentistId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, n)
+	}
+}
+
+// PostAddendum godoc
+// @Summary      Add an addendum to a clinical note
+// @Description  Add a correction to a signed clinical note written by the dentist linked to the current user
+// @Tags         clinical-notes
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Clinical note Id"
+// @Param        body body domain.ClinicalAddendum true "Addendum"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /clinical-notes/:id/addenda [post]
+func (h *noteHandler) PostAddendum() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		dentistId, ok := currentDentist(c)
+		if !ok {
+			return
+		}
+		var addendum domain.ClinicalAddendum
+		err = c.ShouldBindJSON(&addendum)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		n, err := h.s.AddAddendum(id, addendum, dentistId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, n)
+	}
+}
+
+// Delete godoc
+// @Summary      Delete a draft clinical note
+// @Description  Delete a clinical note that isn't signed yet, only its author can delete it
+// @Tags         clinical-notes
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Clinical note Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /clinical-notes/:id [delete]
+func (h *noteHandler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		dentistId, ok := currentDentist(c)
+		if !ok {
+			return
+		}
+		err = h.s.Delete(id, dentistId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("clinical note %d deleted", id))
+	}
+}
+
+// currentDentist devuelve el dentista vinculado al usuario actual, si no tiene responde 403
+func currentDentist(c *gin.Context) (int, bool) {
+	dentistId := c.GetInt("dentist_id")
+	if dentistId == 0 {
+		web.Failure(c, 403, errors.New("the current user isn't linked to a dentist"))
+		return 0, false
+	}
+	return dentistId, true
+}
@@ -24,7 +24,7 @@ func NewPatientHandler(s patient.PatientService) *patientHandler {
 
 // GetByID godoc
 // @Summary      Get a patient by Id
-// @Description  Get a patient by Id from repository
+// @Description  Get a patient by Id from repository. The domicilio field is deprecated and read-only, it joins address street and number
 // @Tags         patients
 // @Produce      json
 // @Param        token header string true "token"
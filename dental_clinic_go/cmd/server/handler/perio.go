@@ -0,0 +1,193 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/perio"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type perioHandler struct {
+	s perio.Service
+}
+
+// NewPerioHandler crea un nuevo controller del periodontograma
+func NewPerioHandler(s perio.Service) *perioHandler {
+	return &perioHandler{s}
+}
+
+// GetByPatient godoc
+// @Summary      Get the periodontal exams of a patient
+// @Description  Get every periodontal exam of a patient in chronological order with its measurements
+// @Tags         perio
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/perio-exams [get]
+func (h *perioHandler) GetByPatient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		exams, err := h.s.GetByPatient(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, exams)
+	}
+}
+
+// GetByID godoc
+// @Summary      Get a periodontal exam
+// @Description  Get a periodontal exam of a patient with its measurements
+// @Tags         perio
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        examId   path      int  true  "Periodontal exam Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/perio-exams/:examId [get]
+func (h *perioHandler) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, err := strconv.Atoi(c.Param("id"))
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		examId, err := strconv.Atoi(c.Param("examId"))
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid examId"))
+			return
+		}
+		exam, err := h.s.GetByID(id, examId)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, exam)
+	}
+}
+
+// Post godoc
+// @Summary      Record a periodontal exam
+// @Description  Record pocket depth, recession and bleeding on probing of the six sites (MB, B, DB, ML, L, DL) and the mobility (0 to 3) of each tooth
+// @Tags         perio
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        body body domain.PerioExam true "Periodontal exam"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/perio-exams [post]
+func (h *perioHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var exam domain.PerioExam
+		err = c.ShouldBindJSON(&exam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		if exam.Dentist.Id == 0 {
+			web.Failure(c, 400, errors.New("Dentist.id can't be empty"))
+			return
+		}
+		exam.PatientId = id
+		e, err := h.s.Create(exam)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, e)
+	}
+}
+
+// GetComparison godoc
+// @Summary      Compare two periodontal exams
+// @Description  Compare two periodontal exams of a patient site by site, without from and to compares the last two exams
+// @Tags         perio
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Param        from   query      int  false  "Earlier periodontal exam Id"
+// @Param        to   query      int  false  "Later periodontal exam Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /patients/:id/perio-exams/compare [get]
+func (h *perioHandler) GetComparison() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		from, to := 0, 0
+		if fromParam := c.Query("from"); fromParam != "" {
+			from, err = strconv.Atoi(fromParam)
+			if err != nil {
+				web.Failure(c, 400, errors.New("invalid from"))
+				return
+			}
+		}
+		if toParam := c.Query("to"); toParam != "" {
+			to, err = strconv.Atoi(toParam)
+			if err != nil {
+				web.Failure(c, 400, errors.New("invalid to"))
+				return
+			}
+		}
+		comparison, err := h.s.Compare(id, from, to)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, comparison)
+	}
+}
+
+// GetSummary godoc
+// @Summary      Get the periodontal summary of a patient
+// @Description  Get the mean depth, attachment loss and bleeding of every exam, and the sites and teeth that worsened in the last exam
+// @Tags         perio
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/perio-exams/summary [get]
+func (h *perioHandler) GetSummary() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		summary, err := h.s.GetSummary(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, summary)
+	}
+}
@@ -0,0 +1,77 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"dental_clinic_go/internal/policy"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type policyHandler struct {
+	s policy.Service
+}
+
+// NewPolicyHandler crea un nuevo controller de la politica de ausencias
+func NewPolicyHandler(s policy.Service) *policyHandler {
+	return &policyHandler{s}
+}
+
+// GetByPatient godoc
+// @Summary      Get the no-shows and late cancellations of a patient
+// @Description  Get the no-shows and late cancellations recorded for a patient, with the applied fees
+// @Tags         patients
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/policy-events [get]
+func (h *policyHandler) GetByPatient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		events, err := h.s.GetByPatient(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, events)
+	}
+}
+
+// Delete godoc
+// @Summary      Delete a policy event
+// @Description  Delete a no-show or late cancellation, for example when it was justified
+// @Tags         patients
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Policy event Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /policy-events/:id [delete]
+func (h *policyHandler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		err = h.s.Delete(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("policy event %d deleted", id))
+	}
+}
@@ -0,0 +1,233 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/portal"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type portalHandler struct {
+	s portal.Service
+}
+
+// NewPortalHandler crea un nuevo controller del portal de pacientes
+func NewPortalHandler(s portal.Service) *portalHandler {
+	return &portalHandler{s}
+}
+
+// PostCode godoc
+// @Summary      Request a one-time access code
+// @Description  Send a one-time access code to the email of the patient with the given dni
+// @Tags         portal
+// @Produce      json
+// @Param        body body domain.PortalCode true "Patient dni"
+// @Success      202 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /portal/code [post]
+func (h *portalHandler) PostCode() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request domain.PortalCode
+		err := c.ShouldBindJSON(&request)
+		if err != nil || request.Dni == 0 {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		err = h.s.RequestCode(request.Dni)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 202, "if the dni is registered a code was sent to the patient's email")
+	}
+}
+
+// PostSession godoc
+// @Summary      Open a portal session
+// @Description  Exchange a dni and a one-time access code for a portal session token
+// @Tags         portal
+// @Produce      json
+// @Param        body body domain.PortalLogin true "Dni and code"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      401 {object}  web.errorResponse
+// @Router       /portal/session [post]
+func (h *portalHandler) PostSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request domain.PortalLogin
+		err := c.ShouldBindJSON(&request)
+		if err != nil || request.Dni == 0 || request.Code == "" {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		session, err := h.s.Login(request.Dni, request.Code)
+		if err != nil {
+			web.Failure(c, 401, err)
+			return
+		}
+		web.Success(c, 201, session)
+	}
+}
+
+// DeleteSession godoc
+// @Summary      Close the portal session
+// @Description  Close the portal session of the current patient
+// @Tags         portal
+// @Produce      json
+// @Param        session header string true "session"
+// @Success      200 {object}  web.response
+// @Failure      401 {object}  web.errorResponse
+// @Router       /portal/session [delete]
+func (h *portalHandler) DeleteSession() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		err := h.s.Logout(c.GetHeader("SESSION"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, "session closed")
+	}
+}
+
+// GetAvailability godoc
+// @Summary      Get the free slots of a dentist
+// @Description  Get the free slots of a dentist on a date
+// @Tags         portal
+// @Produce      json
+// @Param        session header string true "session"
+// @Param        license   query      string  true  "License"
+// @Param        date   query      string  true  "Date (yyyy-mm-dd)"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      401 {object}  web.errorResponse
+// @Router       /portal/availability [get]
+func (h *portalHandler) GetAvailability() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		license := c.Query("license")
+		if license == "" {
+			web.Failure(c, 400, errors.New("license can't be empty"))
+			return
+		}
+		availability, err := h.s.GetAvailability(license, c.Query("date"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, availability)
+	}
+}
+
+// GetAppointments godoc
+// @Summary      Get the appointments of the current patient
+// @Description  Get the appointments of the patient that owns the session
+// @Tags         portal
+// @Produce      json
+// @Param        session header string true "session"
+// @Success      200 {object}  web.response
+// @Failure      401 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /portal/appointments [get]
+func (h *portalHandler) GetAppointments() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		appointments, err := h.s.GetAppointments(c.GetInt("patient_id"))
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, appointments)
+	}
+}
+
+// PostAppointment godoc
+// @Summary      Book an appointment
+// @Description  Book a free slot for the patient that owns the session
+// @Tags         portal
+// @Produce      json
+// @Param        session header string true "session"
+// @Param        body body domain.PortalBooking true "Booking"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      401 {object}  web.errorResponse
+// @Router       /portal/appointments [post]
+func (h *portalHandler) PostAppointment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var booking domain.PortalBooking
+		err := c.ShouldBindJSON(&booking)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		if booking.License == "" || booking.Date == "" || booking.Hour == "" {
+			web.Failure(c, 400, errors.New("license, date and hour can't be empty"))
+			return
+		}
+		a, err := h.s.Book(c.GetInt("patient_id"), booking)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, a)
+	}
+}
+
+// PostConfirm godoc
+// @Summary      Confirm an appointment
+// @Description  Confirm an appointment of the patient that owns the session
+// @Tags         portal
+// @Produce      json
+// @Param        session header string true "session"
+// @Param        id   path      int  true  "Appointment Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      401 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /portal/appointments/:id/confirm [post]
+func (h *portalHandler) PostConfirm() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		a, err := h.s.Confirm(c.GetInt("patient_id"), id)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, a)
+	}
+}
+
+// PostCancel godoc
+// @Summary      Cancel an appointment
+// @Description  Cancel an appointment of the patient that owns the session
+// @Tags         portal
+// @Produce      json
+// @Param        session header string true "session"
+// @Param        id   path      int  true  "Appointment Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      401 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /portal/appointments/:id/cancel [post]
+func (h *portalHandler) PostCancel() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		a, err := h.s.Cancel(c.GetInt("patient_id"), id)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, a)
+	}
+}
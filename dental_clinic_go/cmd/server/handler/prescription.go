@@ -0,0 +1,140 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/prescription"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type prescriptionHandler struct {
+	s prescription.Service
+}
+
+// NewPrescriptionHandler crea un nuevo controller de recetas
+func NewPrescriptionHandler(s prescription.Service) *prescriptionHandler {
+	return &prescriptionHandler{s}
+}
+
+// GetByID godoc
+// @Summary      Get a prescription by Id
+// @Description  Get a prescription with its drugs and dentist
+// @Tags         prescriptions
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Prescription Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /prescriptions/:id [get]
+func (h *prescriptionHandler) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		p, err := h.s.GetByID(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, p)
+	}
+}
+
+// GetByPatient godoc
+// @Summary      Get the prescriptions of a patient
+// @Description  Get the prescriptions of a patient, newest first
+// @Tags         prescriptions
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/prescriptions [get]
+func (h *prescriptionHandler) GetByPatient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		prescriptions, err := h.s.GetByPatient(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, prescriptions)
+	}
+}
+
+// Post godoc
+// @Summary      Issue a prescription
+// @Description  Issue a prescription for a patient, optionally linked to an appointment, signed by the dentist linked to the current user. Drugs that match a recorded allergy are rejected unless allergy_override is sent.
+// @Tags         prescriptions
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.Prescription true "Prescription"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /prescriptions [post]
+func (h *prescriptionHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dentistId, ok := currentDentist(c)
+		if !ok {
+			return
+		}
+		var p domain.Prescription
+		err := c.ShouldBindJSON(&p)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		if p.PatientId == 0 {
+			web.Failure(c, 400, errors.New("patient_id can't be empty"))
+			return
+		}
+		p, err = h.s.Create(p, dentistId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, p)
+	}
+}
+
+// GetPrint godoc
+// @Summary      Print a prescription
+// @Description  Get a prescription as a printable HTML document with the dentist name and license
+// @Tags         prescriptions
+// @Produce      html
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Prescription Id"
+// @Success      200 {string}  string
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /prescriptions/:id/print [get]
+func (h *prescriptionHandler) GetPrint() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		html, err := h.s.Print(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		c.Data(200, "text/html; charset=utf-8", html)
+	}
+}
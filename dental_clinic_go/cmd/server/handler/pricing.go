@@ -0,0 +1,286 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/pricing"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type pricingHandler struct {
+	s pricing.Service
+}
+
+// NewPricingHandler crea un nuevo controller de listas de precios y descuentos
+func NewPricingHandler(s pricing.Service) *pricingHandler {
+	return &pricingHandler{s}
+}
+
+// GetLists godoc
+// @Summary      List price lists
+// @Description  List the price lists without their prices, the newest first. Without insurer_id lists all, insurer_id 0 lists the private ones
+// @Tags         pricing
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        insurer_id   query      int  false  "Insurer Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      500 {object}  web.errorResponse
+// @Router       /price-lists [get]
+func (h *pricingHandler) GetLists() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		insurerId := -1
+		if c.Query("insurer_id") != "" {
+			id, err := strconv.Atoi(c.Query("insurer_id"))
+			if err != nil || id < 0 {
+				web.Failure(c, 400, errors.New("invalid insurer_id"))
+				return
+			}
+			insurerId = id
+		}
+		lists, err := h.s.GetLists(insurerId)
+		if err != nil {
+			web.Failure(c, 500, err)
+			return
+		}
+		web.Success(c, 200, lists)
+	}
+}
+
+// GetList godoc
+// @Summary      Get a price list by Id
+// @Description  Get a price list with its prices
+// @Tags         pricing
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Price list Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /price-lists/:id [get]
+func (h *pricingHandler) GetList() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		list, err := h.s.GetList(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, list)
+	}
+}
+
+// PostList godoc
+// @Summary      Create a price list
+// @Description  Create a new version of prices effective from a date, for an insurer or private if insurer_id is empty. With base_list_id copies the prices of that list increased by increase percent
+// @Tags         pricing
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.PriceList true "Price list"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /price-lists [post]
+func (h *pricingHandler) PostList() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var list domain.PriceList
+		err := c.ShouldBindJSON(&list)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		list, err = h.s.CreateList(list)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, list)
+	}
+}
+
+// PutList godoc
+// @Summary      Update a pending price list
+// @Description  Replace the name, effective date and prices of a list that is not effective yet
+// @Tags         pricing
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Price list Id"
+// @Param        body body domain.PriceList true "Price list"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /price-lists/:id [put]
+func (h *pricingHandler) PutList() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var list domain.PriceList
+		err = c.ShouldBindJSON(&list)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		list, err = h.s.UpdateList(id, list)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, list)
+	}
+}
+
+// GetDiscounts godoc
+// @Summary      List discounts
+// @Description  List the discount rules, active or not
+// @Tags         pricing
+// @Produce      json
+// @Param        token header string true "token"
+// @Success      200 {object}  web.response
+// @Failure      500 {object}  web.errorResponse
+// @Router       /discounts [get]
+func (h *pricingHandler) GetDiscounts() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		discounts, err := h.s.GetDiscounts()
+		if err != nil {
+			web.Failure(c, 500, err)
+			return
+		}
+		web.Success(c, 200, discounts)
+	}
+}
+
+// GetDiscount godoc
+// @Summary      Get a discount by Id
+// @Description  Get a discount rule by Id
+// @Tags         pricing
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Discount Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /discounts/:id [get]
+func (h *pricingHandler) GetDiscount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		discount, err := h.s.GetDiscount(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, discount)
+	}
+}
+
+// PostDiscount godoc
+// @Summary      Create a discount
+// @Description  Create a discount rule of type percentage, fixed or family, optionally limited to a procedure and a validity period
+// @Tags         pricing
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.DiscountRule true "Discount"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /discounts [post]
+func (h *pricingHandler) PostDiscount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var d domain.DiscountRule
+		err := c.ShouldBindJSON(&d)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		d, err = h.s.CreateDiscount(d)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, d)
+	}
+}
+
+// PutDiscount godoc
+// @Summary      Update a discount
+// @Description  Update a discount rule, to stop applying it set active to false or a valid_to date
+// @Tags         pricing
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Discount Id"
+// @Param        body body domain.DiscountRule true "Discount"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /discounts/:id [put]
+func (h *pricingHandler) PutDiscount() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var d domain.DiscountRule
+		err = c.ShouldBindJSON(&d)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		d, err = h.s.UpdateDiscount(id, d)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, d)
+	}
+}
+
+// GetQuote godoc
+// @Summary      Quote the price of a procedure
+// @Description  Get the list price of a procedure on a date for an insurer or private, with the best discount for the patient applied. The date defaults to today
+// @Tags         pricing
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        code   path      string  true  "Procedure code"
+// @Param        patient_id   query      int  false  "Patient Id"
+// @Param        insurer_id   query      int  false  "Insurer Id"
+// @Param        date   query      string  false  "Date yyyy-mm-dd"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /procedures/:code/price [get]
+func (h *pricingHandler) GetQuote() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		ids := map[string]int{"patient_id": 0, "insurer_id": 0}
+		for name := range ids {
+			if c.Query(name) == "" {
+				continue
+			}
+			id, err := strconv.Atoi(c.Query(name))
+			if err != nil {
+				web.Failure(c, 400, errors.New("invalid "+name))
+				return
+			}
+			ids[name] = id
+		}
+		quote, err := h.s.Quote(ids["patient_id"], c.Param("code"), c.Query("date"), ids["insurer_id"])
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, quote)
+	}
+}
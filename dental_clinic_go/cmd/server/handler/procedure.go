@@ -0,0 +1,176 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"strings"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/procedure"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type procedureHandler struct {
+	s procedure.Service
+}
+
+// NewProcedureHandler crea un nuevo controller del catalogo de procedimientos
+func NewProcedureHandler(s procedure.Service) *procedureHandler {
+	return &procedureHandler{s}
+}
+
+// GetAll godoc
+// @Summary      List the procedure catalog
+// @Description  List the procedure catalog, optionally filtered by category
+// @Tags         procedures
+// @Produce      json
+// @Param        category   query      string  false  "Category"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /procedures [get]
+func (h *procedureHandler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		procedures, err := h.s.GetAll(c.Query("category"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, procedures)
+	}
+}
+
+// GetByCode godoc
+// @Summary      Get a procedure by code
+// @Description  Get a procedure of the catalog by code
+// @Tags         procedures
+// @Produce      json
+// @Param        code   path      string  true  "Procedure code"
+// @Success      200 {object}  web.response
+// @Failure      404 {object}  web.errorResponse
+// @Router       /procedures/:code [get]
+func (h *procedureHandler) GetByCode() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		p, err := h.s.GetByCode(c.Param("code"))
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, p)
+	}
+}
+
+// Post godoc
+// @Summary      Create a new procedure
+// @Description  Create a new procedure in the catalog
+// @Tags         procedures
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.Procedure true "Procedure"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /procedures [post]
+func (h *procedureHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var p domain.Procedure
+		err := c.ShouldBindJSON(&p)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		p, err = h.s.Create(p)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, p)
+	}
+}
+
+// Put godoc
+// @Summary      Update a procedure
+// @Description  Replace a procedure of the catalog by code
+// @Tags         procedures
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        code   path      string  true  "Procedure code"
+// @Param        body body domain.Procedure true "Procedure"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /procedures/:code [put]
+func (h *procedureHandler) Put() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var p domain.Procedure
+		err := c.ShouldBindJSON(&p)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		p, err = h.s.Update(c.Param("code"), p)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, p)
+	}
+}
+
+// Delete godoc
+// @Summary      Delete a procedure
+// @Description  Delete a procedure of the catalog that isn't referenced
+// @Tags         procedures
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        code   path      string  true  "Procedure code"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /procedures/:code [delete]
+func (h *procedureHandler) Delete() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		code := c.Param("code")
+		err := h.s.Delete(code)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("procedure %s deleted", code))
+	}
+}
+
+// PostImport godoc
+// @Summary      Import the procedure catalog
+// @Description  Create or update procedures from a CSV with columns code, name, category, default_fee, default_duration, teeth, surfaces and the optional specialty, without it the specialty of existing codes is kept. Send it as the request body or as a multipart file named file.
+// @Tags         procedures
+// @Accept       text/csv
+// @Produce      json
+// @Param        token header string true "token"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /procedures/import [post]
+func (h *procedureHandler) PostImport() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var body io.Reader = c.Request.Body
+		if strings.HasPrefix(c.ContentType(), "multipart/") {
+			file, err := c.FormFile("file")
+			if err != nil {
+				web.Failure(c, 400, errors.New("file not found"))
+				return
+			}
+			f, err := file.Open()
+			if err != nil {
+				web.Failure(c, 400, err)
+				return
+			}
+			defer f.Close()
+			body = f
+		}
+		result, err := h.s.Import(body)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, result)
+	}
+}
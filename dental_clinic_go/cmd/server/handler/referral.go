@@ -0,0 +1,210 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/referral"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type referralHandler struct {
+	s referral.Service
+}
+
+// NewReferralHandler crea un nuevo controller de derivaciones
+func NewReferralHandler(s referral.Service) *referralHandler {
+	return &referralHandler{s}
+}
+
+// GetByID godoc
+// @Summary      Get a referral by Id
+// @Description  Get a referral with the referring dentist and the dentist that received it
+// @Tags         referrals
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Referral Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /referrals/:id [get]
+func (h *referralHandler) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		r, err := h.s.GetByID(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, r)
+	}
+}
+
+// GetByPatient godoc
+// @Summary      Get the referrals of a patient
+// @Description  Get every referral of a patient, the most urgent first
+// @Tags         referrals
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/referrals [get]
+func (h *referralHandler) GetByPatient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		referrals, err := h.s.GetByPatient(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, referrals)
+	}
+}
+
+// GetInbox godoc
+// @Summary      Get the referral inbox of a dentist
+// @Description  Get the referrals addressed to a dentist or to one of their specialties, without status lists the pending and accepted ones, the most urgent first
+// @Tags         referrals
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Dentist Id"
+// @Param        status   query      string  false  "pending, accepted, rejected, scheduled or cancelled"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /dentists/:id/referrals [get]
+func (h *referralHandler) GetInbox() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		referrals, err := h.s.GetInbox(id, c.Query("status"))
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, referrals)
+	}
+}
+
+// Post godoc
+// @Summary      Refer a patient
+// @Description  Refer a patient to a dentist or to a specialty on behalf of the dentist linked to the current user, urgency defaults to routine
+// @Tags         referrals
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.Referral true "Referral"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /referrals [post]
+func (h *referralHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		dentistId, ok := currentDentist(c)
+		if !ok {
+			return
+		}
+		var r domain.Referral
+		err := c.ShouldBindJSON(&r)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		r, err = h.s.Create(r, dentistId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, r)
+	}
+}
+
+// PatchStatus godoc
+// @Summary      Accept, reject or cancel a referral
+// @Description  Accept, reject or cancel a pending referral. Only the dentist linked to the current user can accept or reject it, if it is addressed to them or to one of their specialties
+// @Tags         referrals
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Referral Id"
+// @Param        body body domain.ReferralStatus true "Status"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /referrals/:id/status [patch]
+func (h *referralHandler) PatchStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var status domain.ReferralStatus
+		err = c.ShouldBindJSON(&status)
+		if err != nil || status.Status == "" {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		dentistId := c.GetInt("dentist_id")
+		if status.Status == domain.ReferralAccepted || status.Status == domain.ReferralRejected {
+			if _, ok := currentDentist(c); !ok {
+				return
+			}
+		}
+		r, err := h.s.UpdateStatus(id, status, dentistId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, r)
+	}
+}
+
+// PostAppointment godoc
+// @Summary      Schedule an accepted referral
+// @Description  Create an appointment with the patient and the dentist that accepted the referral, the description defaults to the referral reason
+// @Tags         referrals
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Referral Id"
+// @Param        body body domain.ReferralAppointment true "Date, hour and procedure"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /referrals/:id/appointment [post]
+func (h *referralHandler) PostAppointment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var request domain.ReferralAppointment
+		err = c.ShouldBindJSON(&request)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		a, err := h.s.Schedule(id, request)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, a)
+	}
+}
@@ -0,0 +1,259 @@
+package handler
+
+import (
+	"errors"
+	"strconv"
+
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/treatment"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type treatmentHandler struct {
+	s treatment.Service
+}
+
+// NewTreatmentHandler crea un nuevo controller de planes de tratamiento
+func NewTreatmentHandler(s treatment.Service) *treatmentHandler {
+	return &treatmentHandler{s}
+}
+
+// GetByID godoc
+// @Summary      Get a treatment plan by Id
+// @Description  Get a treatment plan with its procedures and progress
+// @Tags         treatment-plans
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Treatment plan Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /treatment-plans/:id [get]
+func (h *treatmentHandler) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		plan, err := h.s.GetByID(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, plan)
+	}
+}
+
+// GetByPatient godoc
+// @Summary      Get the treatment plans of a patient
+// @Description  Get the treatment plans of a patient with their procedures and progress
+// @Tags         treatment-plans
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Patient Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /patients/:id/treatment-plans [get]
+func (h *treatmentHandler) GetByPatient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		plans, err := h.s.GetByPatient(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, plans)
+	}
+}
+
+// Post godoc
+// @Summary      Create a new treatment plan
+// @Description  Create a proposed treatment plan for a patient with its ordered procedures
+// @Tags         treatment-plans
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.TreatmentPlan true "Treatment plan"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /treatment-plans [post]
+func (h *treatmentHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var plan domain.TreatmentPlan
+		err := c.ShouldBindJSON(&plan)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		switch {
+		case plan.PatientId == 0:
+			web.Failure(c, 400, errors.New("patient_id can't be empty"))
+			return
+		case plan.Dentist.Id == 0:
+			web.Failure(c, 400, errors.New("Dentist.id can't be empty"))
+			return
+		case len(plan.Items) == 0:
+			web.Failure(c, 400, errors.New("items can't be empty"))
+			return
+		}
+		p, err := h.s.Create(plan)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, p)
+	}
+}
+
+// PatchStatus godoc
+// @Summary      Accept or cancel a treatment plan
+// @Description  Update the status of a treatment plan to accepted or cancelled, in_progress and completed are set automatically
+// @Tags         treatment-plans
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Treatment plan Id"
+// @Param        body body domain.TreatmentPlanStatus true "Status"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /treatment-plans/:id/status [patch]
+func (h *treatmentHandler) PatchStatus() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var status domain.TreatmentPlanStatus
+		err = c.ShouldBindJSON(&status)
+		if err != nil || status.Status == "" {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		p, err := h.s.UpdateStatus(id, status.Status)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, p)
+	}
+}
+
+// PostItem godoc
+// @Summary      Add a procedure to a treatment plan
+// @Description  Add a procedure at the end of an open treatment plan
+// @Tags         treatment-plans
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Treatment plan Id"
+// @Param        body body domain.TreatmentItem true "Procedure"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /treatment-plans/:id/items [post]
+func (h *treatmentHandler) PostItem() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var item domain.TreatmentItem
+		err = c.ShouldBindJSON(&item)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		p, err := h.s.AddItem(id, item)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, p)
+	}
+}
+
+// PostItemCancel godoc
+// @Summary      Cancel a procedure of a treatment plan
+// @Description  Cancel a procedure that wasn't completed yet, a scheduled procedure also cancels its appointment
+// @Tags         treatment-plans
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Treatment plan Id"
+// @Param        itemId   path      int  true  "Procedure Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /treatment-plans/:id/items/:itemId/cancel [post]
+func (h *treatmentHandler) PostItemCancel() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, itemId, err := planAndItemIds(c)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		p, err := h.s.CancelItem(id, itemId)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, p)
+	}
+}
+
+// PostItemAppointment godoc
+// @Summary      Schedule a procedure of a treatment plan
+// @Description  Create an appointment with the patient and dentist of the plan to perform a procedure, the procedure is completed when the appointment is
+// @Tags         treatment-plans
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "Treatment plan Id"
+// @Param        itemId   path      int  true  "Procedure Id"
+// @Param        body body domain.TreatmentItemAppointment true "Date and hour"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /treatment-plans/:id/items/:itemId/appointment [post]
+func (h *treatmentHandler) PostItemAppointment() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		id, itemId, err := planAndItemIds(c)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		var request domain.TreatmentItemAppointment
+		err = c.ShouldBindJSON(&request)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		a, err := h.s.ScheduleItem(id, itemId, request)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, a)
+	}
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// planAndItemIds lee los ids del plan y del procedimiento de la ruta
+func planAndItemIds(c *gin.Context) (int, int, error) {
+	id, err := strconv.Atoi(c.Param("id"))
+	if err != nil {
+		return 0, 0, errors.New("invalid id")
+	}
+	itemId, err := strconv.Atoi(c.Param("itemId"))
+	if err != nil {
+		return 0, 0, errors.New("invalid itemId")
+	}
+	return id, itemId, nil
+}
@@ -0,0 +1,207 @@
+package handler
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+
+	"dental_clinic_go/internal/auth"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/web"
+
+	"github.com/gin-gonic/gin"
+)
+
+type userHandler struct {
+	s auth.Service
+}
+
+// NewUserHandler crea un nuevo controller de usuarios
+func NewUserHandler(s auth.Service) *userHandler {
+	return &userHandler{s}
+}
+
+// GetAll godoc
+// @Summary      List users
+// @Description  List the user accounts of the clinic staff, active or not
+// @Tags         users
+// @Produce      json
+// @Param        token header string true "token"
+// @Success      200 {object}  web.response
+// @Failure      500 {object}  web.errorResponse
+// @Router       /users [get]
+func (h *userHandler) GetAll() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		users, err := h.s.GetAll()
+		if err != nil {
+			web.Failure(c, 500, err)
+			return
+		}
+		web.Success(c, 200, users)
+	}
+}
+
+// GetRoles godoc
+// @Summary      List roles
+// @Description  List the staff roles with the permissions each one grants
+// @Tags         users
+// @Produce      json
+// @Param        token header string true "token"
+// @Success      200 {object}  web.response
+// @Router       /roles [get]
+func (h *userHandler) GetRoles() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		web.Success(c, 200, h.s.GetRoles())
+	}
+}
+
+// GetByID godoc
+// @Summary      Get a user by Id
+// @Description  Get a user account by Id
+// @Tags         users
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "User Id"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /users/:id [get]
+func (h *userHandler) GetByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		user, err := h.s.GetByID(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 200, user)
+	}
+}
+
+// Post godoc
+// @Summary      Create a user
+// @Description  Create an active user account with a role (admin, dentist, hygienist, receptionist or billing), users with role dentist must be linked to a dentist with dentist_id. The password is stored as a bcrypt hash
+// @Tags         users
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        body body domain.User true "User"
+// @Success      201 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /users [post]
+func (h *userHandler) Post() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var user domain.User
+		err := c.ShouldBindJSON(&user)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		user, err = h.s.Create(user)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 201, user)
+	}
+}
+
+// Put godoc
+// @Summary      Update a user
+// @Description  Update the name, email, role, dentist and active flag of a user, disabling a user revokes its sessions. The username and password can't be changed here and the last active admin can't be disabled or lose its role
+// @Tags         users
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "User Id"
+// @Param        body body domain.User true "User"
+// @Success      200 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /users/:id [put]
+func (h *userHandler) Put() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var user domain.User
+		err = c.ShouldBindJSON(&user)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		user, err = h.s.Update(id, user)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 200, user)
+	}
+}
+
+// PutPassword godoc
+// @Summary      Reset the password of a user
+// @Description  Replace the password of a user without the current one, the sessions of the user are revoked
+// @Tags         users
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "User Id"
+// @Param        body body domain.PasswordChange true "New password"
+// @Success      204 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Router       /users/:id/password [put]
+func (h *userHandler) PutPassword() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		var change domain.PasswordChange
+		err = c.ShouldBindJSON(&change)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid json"))
+			return
+		}
+		err = h.s.SetPassword(id, change.NewPassword)
+		if err != nil {
+			web.Failure(c, 400, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("password of user %d changed", id))
+	}
+}
+
+// DeleteSessions godoc
+// @Summary      Revoke the sessions of a user
+// @Description  Revoke every open session of a user, its access and refresh tokens stop working
+// @Tags         users
+// @Produce      json
+// @Param        token header string true "token"
+// @Param        id   path      int  true  "User Id"
+// @Success      204 {object}  web.response
+// @Failure      400 {object}  web.errorResponse
+// @Failure      404 {object}  web.errorResponse
+// @Router       /users/:id/sessions [delete]
+func (h *userHandler) DeleteSessions() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idParam := c.Param("id")
+		id, err := strconv.Atoi(idParam)
+		if err != nil {
+			web.Failure(c, 400, errors.New("invalid id"))
+			return
+		}
+		err = h.s.RevokeSessions(id)
+		if err != nil {
+			web.Failure(c, 404, err)
+			return
+		}
+		web.Success(c, 204, fmt.Sprintf("sessions of user %d revoked", id))
+	}
+}
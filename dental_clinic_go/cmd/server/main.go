@@ -5,12 +5,39 @@ import (
 	"dental_clinic_go/cmd/server/handler"
 	"dental_clinic_go/docs"
 	"dental_clinic_go/internal/appointment"
+	"dental_clinic_go/internal/attachment"
+	"dental_clinic_go/internal/auth"
+	"dental_clinic_go/internal/chart"
+	"dental_clinic_go/internal/commission"
+	"dental_clinic_go/internal/consent"
 	"dental_clinic_go/internal/dentist"
+	"dental_clinic_go/internal/document"
+	"dental_clinic_go/internal/family"
+	"dental_clinic_go/internal/insurance"
+	"dental_clinic_go/internal/invoice"
+	"dental_clinic_go/internal/lab"
+	"dental_clinic_go/internal/ledger"
+	"dental_clinic_go/internal/link"
+	"dental_clinic_go/internal/medical"
+	"dental_clinic_go/internal/note"
 	"dental_clinic_go/internal/patient"
+	"dental_clinic_go/internal/perio"
+	"dental_clinic_go/internal/policy"
+	"dental_clinic_go/internal/portal"
+	"dental_clinic_go/internal/prescription"
+	"dental_clinic_go/internal/pricing"
+	"dental_clinic_go/internal/procedure"
+	"dental_clinic_go/internal/referral"
+	"dental_clinic_go/internal/treatment"
+	"dental_clinic_go/pkg/blob"
 	"dental_clinic_go/pkg/middleware"
+	"dental_clinic_go/pkg/notify"
 	"dental_clinic_go/pkg/store"
+	"dental_clinic_go/pkg/token"
 	"fmt"
 	"os"
+	"strconv"
+	"time"
 
 	"github.com/gin-gonic/gin"
 	_ "github.com/go-sql-driver/mysql"
@@ -37,6 +64,36 @@ func main() {
 	DB_URL := os.Getenv("DB_URL")
 	HOST := os.Getenv("HOST")
 	PORT := os.Getenv("PORT")
+	SMTP_HOST := os.Getenv("SMTP_HOST")
+	LINK_SECRET := os.Getenv("LINK_SECRET")
+	JWT_SECRET := os.Getenv("JWT_SECRET")
+	ACCESS_TOKEN_TTL_MINUTES := getEnvInt("ACCESS_TOKEN_TTL_MINUTES", 15)
+	REFRESH_TOKEN_TTL_HOURS := getEnvInt("REFRESH_TOKEN_TTL_HOURS", 168)
+	LINK_BASE_URL := getEnv("LINK_BASE_URL", "http://"+HOST)
+	LINK_TTL_HOURS := getEnvInt("LINK_TTL_HOURS", 72)
+	CANCELLATION_CUTOFF_HOURS := getEnvInt("CANCELLATION_CUTOFF_HOURS", 24)
+	LATE_CANCELLATION_HOURS := getEnvInt("LATE_CANCELLATION_HOURS", 48)
+	NO_SHOW_FEE := getEnvFloat("NO_SHOW_FEE", 0)
+	LATE_CANCELLATION_FEE := getEnvFloat("LATE_CANCELLATION_FEE", 0)
+	BOOKING_BLOCK_THRESHOLD := getEnvInt("BOOKING_BLOCK_THRESHOLD", 3)
+	PROCEDURES_CSV := os.Getenv("PROCEDURES_CSV")
+	CLINIC_NAME := getEnv("CLINIC_NAME", "Dental Clinic")
+	CLINIC_ADDRESS := os.Getenv("CLINIC_ADDRESS")
+	CLINIC_PHONE := os.Getenv("CLINIC_PHONE")
+	CLINIC_TAX_ID := os.Getenv("CLINIC_TAX_ID")
+	FILES_DIR := getEnv("FILES_DIR", "files")
+	FILES_MAX_MB := getEnvInt("FILES_MAX_MB", 20)
+	INVOICE_SERIES := getEnv("INVOICE_SERIES", "A")
+	INVOICE_TAX_RATE := getEnvFloat("INVOICE_TAX_RATE", 0)
+	if LINK_SECRET == "" {
+		panic("LINK_SECRET can't be empty")
+	}
+	if JWT_SECRET == "" {
+		panic("JWT_SECRET can't be empty")
+	}
+	if LATE_CANCELLATION_HOURS <= CANCELLATION_CUTOFF_HOURS {
+		panic("LATE_CANCELLATION_HOURS must be greater than CANCELLATION_CUTOFF_HOURS")
+	}
 
 	/* ----------------------- Levantamos la base de datos ---------------------- */
 	db, err := sql.Open("mysql", DB_URL)
@@ -52,58 +109,492 @@ func main() {
 		panic(errPing.Error())
 	}
 
+	/* ---------------------------- Notificaciones ------------------------------ */
+	notifier := notify.NewLogNotifier()
+	if SMTP_HOST != "" {
+		notifier = notify.NewSmtpNotifier(SMTP_HOST, os.Getenv("SMTP_PORT"), os.Getenv("SMTP_USER"),
+			os.Getenv("SMTP_PASSWORD"), os.Getenv("SMTP_FROM"))
+	}
+
 	/* -------------------- Instanciamos gin-gonic y swagger -------------------- */
 	r := gin.Default()
 	docs.SwaggerInfo.Host = HOST
 	r.GET("/docs/*any",
 		ginSwagger.WrapHandler(swaggerFiles.Handler))
 
-	/* --------------------------------- Dentists ------------------------------- */
+	/* ---------------------------------- Users --------------------------------- */
+	userStorage := store.NewUserSqlStore(db)
 	dentistStorage := store.NewDentistSqlStore(db)
+	authRepo := auth.NewAuthRepository(userStorage, dentistStorage)
+	authService := auth.NewAuthService(authRepo, token.NewJWT(JWT_SECRET), auth.Config{
+		AccessTTL:  time.Duration(ACCESS_TOKEN_TTL_MINUTES) * time.Minute,
+		RefreshTTL: time.Duration(REFRESH_TOKEN_TTL_HOURS) * time.Hour,
+	})
+	if err := authService.Bootstrap(os.Getenv("ADMIN_USERNAME"), os.Getenv("ADMIN_PASSWORD")); err != nil {
+		panic(err.Error())
+	}
+	authHandler := handler.NewAuthHandler(authService)
+	userHandler := handler.NewUserHandler(authService)
+
+	authGroup := r.Group("/auth")
+	{
+		authGroup.POST("/login", authHandler.PostLogin())
+		authGroup.POST("/refresh", authHandler.PostRefresh())
+		authGroup.POST("/logout", middleware.Authentication(authService), authHandler.PostLogout())
+		authGroup.GET("/me", middleware.Authentication(authService), authHandler.GetMe())
+		authGroup.PUT("/password", middleware.Authentication(authService), authHandler.PutPassword())
+	}
+	users := r.Group("/users")
+	{
+		users.GET("", middleware.Authentication(authService), middleware.Authorization(authService, auth.UsersManage), userHandler.GetAll())
+		users.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.UsersManage), userHandler.Post())
+		users.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.UsersManage), userHandler.GetByID())
+		users.PUT(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.UsersManage), userHandler.Put())
+		users.PUT(":id/password", middleware.Authentication(authService), middleware.Authorization(authService, auth.UsersManage), userHandler.PutPassword())
+		users.DELETE(":id/sessions", middleware.Authentication(authService), middleware.Authorization(authService, auth.UsersManage), userHandler.DeleteSessions())
+	}
+	r.GET("/roles", middleware.Authentication(authService), userHandler.GetRoles())
+
+	/* --------------------------------- Dentists ------------------------------- */
 	dentistRepo := dentist.NewDentistRepository(dentistStorage)
 	dentistService := dentist.NewDentistService(dentistRepo)
 	dentistHandler := handler.NewDentistHandler(dentistService)
 
 	dentists := r.Group("/dentists")
 	{
-		dentists.POST("", middleware.Authentication(), dentistHandler.Post())
+		dentists.GET("", dentistHandler.GetAll())
+		dentists.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.DentistsWrite), dentistHandler.Post())
 		dentists.GET(":id", dentistHandler.GetByID())
-		dentists.PUT(":id", middleware.Authentication(), dentistHandler.Put())
-		dentists.PATCH(":id", middleware.Authentication(), dentistHandler.Patch())
-		dentists.DELETE(":id", middleware.Authentication(), dentistHandler.Delete())
+		dentists.PUT(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.DentistsWrite), dentistHandler.Put())
+		dentists.PATCH(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.DentistsWrite), dentistHandler.Patch())
+		dentists.DELETE(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.DentistsDelete), dentistHandler.Delete())
+	}
+
+	/* ---------------------------- Procedure catalog --------------------------- */
+	procedureStorage := store.NewProcedureSqlStore(db)
+	procedureRepo := procedure.NewProcedureRepository(procedureStorage)
+	procedureService := procedure.NewProcedureService(procedureRepo)
+	procedureHandler := handler.NewProcedureHandler(procedureService)
+	if PROCEDURES_CSV != "" {
+		file, err := os.Open(PROCEDURES_CSV)
+		if err != nil {
+			panic(err.Error())
+		}
+		result, err := procedureService.Import(file)
+		file.Close()
+		if err != nil {
+			panic(err.Error())
+		}
+		fmt.Printf("procedures imported from %s: %d created, %d updated\n", PROCEDURES_CSV, result.Created, result.Updated)
+	}
+
+	procedures := r.Group("/procedures")
+	{
+		procedures.GET("", procedureHandler.GetAll())
+		procedures.GET(":code", procedureHandler.GetByCode())
+		procedures.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.ProceduresWrite), procedureHandler.Post())
+		procedures.POST("/import", middleware.Authentication(authService), middleware.Authorization(authService, auth.ProceduresWrite), procedureHandler.PostImport())
+		procedures.PUT(":code", middleware.Authentication(authService), middleware.Authorization(authService, auth.ProceduresWrite), procedureHandler.Put())
+		procedures.DELETE(":code", middleware.Authentication(authService), middleware.Authorization(authService, auth.ProceduresWrite), procedureHandler.Delete())
 	}
 
+	/* ------------------------ No-show and late cancel ------------------------- */
+	policyStorage := store.NewPolicySqlStore(db)
+	policyRepo := policy.NewPolicyRepository(policyStorage)
+	policyService := policy.NewPolicyService(policyRepo, policy.Config{
+		NoShowFee:              NO_SHOW_FEE,
+		LateCancellationFee:    LATE_CANCELLATION_FEE,
+		LateCancellationWindow: time.Duration(LATE_CANCELLATION_HOURS) * time.Hour,
+		BlockThreshold:         BOOKING_BLOCK_THRESHOLD,
+	})
+	policyHandler := handler.NewPolicyHandler(policyService)
+
+	r.DELETE("/policy-events/:id", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingWrite), policyHandler.Delete())
+
 	/* --------------------------------- Patients ------------------------------- */
 	patientStorage := store.NewPatientSqlStore(db)
 	patientRepo := patient.NewPatientRepository(patientStorage)
-	patientService := patient.NewPatientService(patientRepo)
+	patientService := patient.NewPatientService(patientRepo, policyService)
 	patientHandler := handler.NewPatientHandler(patientService)
 
+	chartStorage := store.NewChartSqlStore(db)
+	chartRepo := chart.NewChartRepository(chartStorage, patientStorage, dentistStorage)
+	chartService := chart.NewChartService(chartRepo, procedureService)
+	chartHandler := handler.NewChartHandler(chartService)
+
+	perioStorage := store.NewPerioSqlStore(db)
+	perioRepo := perio.NewPerioRepository(perioStorage, patientStorage, dentistStorage)
+	perioService := perio.NewPerioService(perioRepo)
+	perioHandler := handler.NewPerioHandler(perioService)
+
+	medicalStorage := store.NewMedicalSqlStore(db)
+	medicalRepo := medical.NewMedicalRepository(medicalStorage, patientStorage)
+	medicalService := medical.NewMedicalService(medicalRepo)
+	medicalHandler := handler.NewMedicalHandler(medicalService)
+
 	patients := r.Group("/patients")
 	{
-		patients.POST("", middleware.Authentication(), patientHandler.Post())
-		patients.GET(":id", patientHandler.GetByID())
-		patients.PUT(":id", middleware.Authentication(), patientHandler.Put())
-		patients.PATCH(":id", middleware.Authentication(), patientHandler.Patch())
-		patients.DELETE(":id", middleware.Authentication(), patientHandler.Delete())
+		patients.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsWrite), patientHandler.Post())
+		patients.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsRead), patientHandler.GetByID())
+		patients.GET(":id/policy-events", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsRead), policyHandler.GetByPatient())
+		patients.GET(":id/chart", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), chartHandler.GetChart())
+		patients.GET(":id/chart/history", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), chartHandler.GetHistory())
+		patients.POST(":id/chart", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), chartHandler.Post())
+		patients.GET(":id/perio-exams", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), perioHandler.GetByPatient())
+		patients.POST(":id/perio-exams", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), perioHandler.Post())
+		patients.GET(":id/perio-exams/compare", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), perioHandler.GetComparison())
+		patients.GET(":id/perio-exams/summary", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), perioHandler.GetSummary())
+		patients.GET(":id/perio-exams/:examId", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), perioHandler.GetByID())
+		patients.GET(":id/medical-history", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), medicalHandler.GetHistory())
+		patients.POST(":id/medical-history/review", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), medicalHandler.PostReview())
+		patients.GET(":id/allergies", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), medicalHandler.GetAllergies())
+		patients.POST(":id/allergies", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), medicalHandler.PostAllergy())
+		patients.DELETE(":id/allergies/:itemId", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), medicalHandler.DeleteAllergy())
+		patients.GET(":id/medications", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), medicalHandler.GetMedications())
+		patients.POST(":id/medications", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), medicalHandler.PostMedication())
+		patients.DELETE(":id/medications/:itemId", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), medicalHandler.DeleteMedication())
+		patients.GET(":id/conditions", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), medicalHandler.GetConditions())
+		patients.POST(":id/conditions", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), medicalHandler.PostCondition())
+		patients.DELETE(":id/conditions/:itemId", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), medicalHandler.DeleteCondition())
+		patients.PUT(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsWrite), patientHandler.Put())
+		patients.PATCH(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsWrite), patientHandler.Patch())
+		patients.DELETE(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsDelete), patientHandler.Delete())
 	}
 
 	/* ------------------------------- Appointment ------------------------------ */
 	appointmentStorage := store.NewAppointmentSqlStore(db)
-	appointmentRepo := appointment.NewAppointmentRepository(appointmentStorage, patientStorage, dentistStorage)
-	appointmentService := appointment.NewAppointmentService(appointmentRepo)
+	noteStorage := store.NewNoteSqlStore(db)
+	appointmentRepo := appointment.NewAppointmentRepository(appointmentStorage, patientStorage, dentistStorage, procedureStorage, noteStorage)
+	appointmentService := appointment.NewAppointmentService(appointmentRepo, time.Duration(CANCELLATION_CUTOFF_HOURS)*time.Hour)
+	appointmentService.OnStatusChange(policyService)
 	appointmentHandler := handler.NewAppointmentHandler(appointmentService)
+	familyStorage := store.NewFamilySqlStore(db)
+	familyRepo := family.NewFamilyRepository(familyStorage, patientStorage, appointmentStorage)
+	familyService := family.NewFamilyService(familyRepo)
+	familyHandler := handler.NewFamilyHandler(familyService)
+	linkService := link.NewLinkService(appointmentService, token.NewSigner(LINK_SECRET), LINK_BASE_URL, time.Duration(LINK_TTL_HOURS)*time.Hour,
+		familyService, notifier)
+	linkHandler := handler.NewLinkHandler(linkService)
+	noteRepo := note.NewNoteRepository(noteStorage, appointmentStorage, dentistStorage)
+	noteService := note.NewNoteService(noteRepo)
+	noteHandler := handler.NewNoteHandler(noteService)
+
+	patients.GET(":id/relations", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsRead), familyHandler.GetRelations())
+	patients.POST(":id/relations", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsWrite), familyHandler.PostRelation())
+	patients.DELETE(":id/relations/:itemId", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsWrite), familyHandler.DeleteRelation())
+	patients.GET(":id/contact", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsRead), familyHandler.GetContact())
+	patients.PUT(":id/contact", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsWrite), familyHandler.PutContact())
+	patients.GET(":id/family", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsRead), familyHandler.GetFamily())
+	patients.GET(":id/family/appointments", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsRead), familyHandler.GetFamilyAppointments())
 
 	appointments := r.Group("/appointments")
 	{
-		appointments.POST("", middleware.Authentication(), appointmentHandler.Post())
-		appointments.POST("/dni/license", middleware.Authentication(), appointmentHandler.PostByDniAndLicense())
-		appointments.GET(":id", appointmentHandler.GetByID())
-		appointments.GET("/dni/:dni", appointmentHandler.GetByDni())
-		appointments.PUT(":id", middleware.Authentication(), appointmentHandler.Put())
-		appointments.PATCH(":id", middleware.Authentication(), appointmentHandler.Patch())
-		appointments.DELETE(":id", middleware.Authentication(), appointmentHandler.Delete())
+		appointments.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsWrite), appointmentHandler.Post())
+		appointments.POST("/dni/license", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsWrite), appointmentHandler.PostByDniAndLicense())
+		appointments.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsRead), appointmentHandler.GetByID())
+		appointments.GET("/dni/:dni", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsRead), appointmentHandler.GetByDni())
+		appointments.GET(":id/links", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsRead), linkHandler.GetAppointmentLinks())
+		appointments.POST(":id/reminder", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsWrite), linkHandler.PostReminder())
+		appointments.GET(":id/notes", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), noteHandler.GetByAppointment())
+		appointments.POST(":id/notes", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), noteHandler.Post())
+		appointments.PUT(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsWrite), appointmentHandler.Put())
+		appointments.PATCH(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsWrite), appointmentHandler.Patch())
+		appointments.PATCH(":id/status", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsWrite), appointmentHandler.PatchStatus())
+		appointments.DELETE(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsDelete), appointmentHandler.Delete())
+	}
+
+	clinicalNotes := r.Group("/clinical-notes")
+	{
+		clinicalNotes.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), noteHandler.GetByID())
+		clinicalNotes.PUT(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), noteHandler.Put())
+		clinicalNotes.POST(":id/sign", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalSign), noteHandler.PostSign())
+		clinicalNotes.POST(":id/addenda", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), noteHandler.PostAddendum())
+		clinicalNotes.DELETE(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), noteHandler.Delete())
+	}
+
+	/* ---------------------------------- Files --------------------------------- */
+	blobStorage, err := blob.NewLocalStorage(FILES_DIR)
+	if err != nil {
+		panic(err.Error())
+	}
+	attachmentStorage := store.NewAttachmentSqlStore(db)
+	attachmentRepo := attachment.NewAttachmentRepository(attachmentStorage, patientStorage, appointmentStorage)
+	attachmentService := attachment.NewAttachmentService(attachmentRepo, blobStorage, int64(FILES_MAX_MB)<<20)
+	attachmentHandler := handler.NewAttachmentHandler(attachmentService)
+
+	patients.GET(":id/files", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), attachmentHandler.GetByPatient())
+	patients.POST(":id/files", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), attachmentHandler.PostByPatient())
+	appointments.GET(":id/files", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), attachmentHandler.GetByAppointment())
+	appointments.POST(":id/files", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), attachmentHandler.PostByAppointment())
+	files := r.Group("/files")
+	{
+		files.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), attachmentHandler.GetByID())
+		files.GET(":id/content", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), attachmentHandler.GetContent())
+		files.GET(":id/thumbnail", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), attachmentHandler.GetThumbnail())
+		files.DELETE(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalWrite), attachmentHandler.Delete())
+	}
+
+	/* ------------------------------ Prescriptions ----------------------------- */
+	prescriptionStorage := store.NewPrescriptionSqlStore(db)
+	prescriptionRepo := prescription.NewPrescriptionRepository(prescriptionStorage, patientStorage, dentistStorage, appointmentStorage, medicalStorage)
+	prescriptionService := prescription.NewPrescriptionService(prescriptionRepo, CLINIC_NAME)
+	prescriptionHandler := handler.NewPrescriptionHandler(prescriptionService)
+
+	patients.GET(":id/prescriptions", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), prescriptionHandler.GetByPatient())
+	prescriptions := r.Group("/prescriptions")
+	{
+		prescriptions.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.PrescriptionsWrite), prescriptionHandler.Post())
+		prescriptions.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), prescriptionHandler.GetByID())
+		prescriptions.GET(":id/print", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), prescriptionHandler.GetPrint())
+	}
+
+	/* --------------------------------- Pricing -------------------------------- */
+	pricingStorage := store.NewPricingSqlStore(db)
+	insuranceStorage := store.NewInsuranceSqlStore(db)
+	pricingRepo := pricing.NewPricingRepository(pricingStorage, procedureStorage, insuranceStorage)
+	pricingService := pricing.NewPricingService(pricingRepo, familyService)
+	pricingHandler := handler.NewPricingHandler(pricingService)
+
+	procedures.GET(":code/price", middleware.Authentication(authService), middleware.Authorization(authService, auth.PricingRead), pricingHandler.GetQuote())
+	priceLists := r.Group("/price-lists")
+	{
+		priceLists.GET("", middleware.Authentication(authService), middleware.Authorization(authService, auth.PricingRead), pricingHandler.GetLists())
+		priceLists.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.PricingWrite), pricingHandler.PostList())
+		priceLists.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.PricingRead), pricingHandler.GetList())
+		priceLists.PUT(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.PricingWrite), pricingHandler.PutList())
+	}
+	discounts := r.Group("/discounts")
+	{
+		discounts.GET("", middleware.Authentication(authService), middleware.Authorization(authService, auth.PricingRead), pricingHandler.GetDiscounts())
+		discounts.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.PricingWrite), pricingHandler.PostDiscount())
+		discounts.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.PricingRead), pricingHandler.GetDiscount())
+		discounts.PUT(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.PricingWrite), pricingHandler.PutDiscount())
+	}
+
+	/* ---------------------------- Treatment plans ----------------------------- */
+	treatmentStorage := store.NewTreatmentSqlStore(db)
+	treatmentRepo := treatment.NewTreatmentRepository(treatmentStorage, patientStorage, dentistStorage)
+	treatmentService := treatment.NewTreatmentService(treatmentRepo, appointmentService, procedureService, pricingService)
+	appointmentService.OnStatusChange(treatmentService)
+	treatmentHandler := handler.NewTreatmentHandler(treatmentService)
+
+	patients.GET(":id/treatment-plans", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), treatmentHandler.GetByPatient())
+	treatmentPlans := r.Group("/treatment-plans")
+	{
+		treatmentPlans.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.TreatmentWrite), treatmentHandler.Post())
+		treatmentPlans.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), treatmentHandler.GetByID())
+		treatmentPlans.PATCH(":id/status", middleware.Authentication(authService), middleware.Authorization(authService, auth.TreatmentWrite), treatmentHandler.PatchStatus())
+		treatmentPlans.POST(":id/items", middleware.Authentication(authService), middleware.Authorization(authService, auth.TreatmentWrite), treatmentHandler.PostItem())
+		treatmentPlans.POST(":id/items/:itemId/cancel", middleware.Authentication(authService), middleware.Authorization(authService, auth.TreatmentWrite), treatmentHandler.PostItemCancel())
+		treatmentPlans.POST(":id/items/:itemId/appointment", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsWrite), treatmentHandler.PostItemAppointment())
+	}
+
+	/* ------------------------------- Lab orders ------------------------------- */
+	labStorage := store.NewLabSqlStore(db)
+	labRepo := lab.NewLabRepository(labStorage, patientStorage, dentistStorage, treatmentStorage)
+	labService := lab.NewLabService(labRepo)
+	appointmentService.AddAdvisor(labService)
+	labHandler := handler.NewLabHandler(labService)
+
+	patients.GET(":id/lab-orders", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), labHandler.GetByPatient())
+	labOrders := r.Group("/lab-orders")
+	{
+		labOrders.GET("", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), labHandler.GetAll())
+		labOrders.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.TreatmentWrite), labHandler.Post())
+		labOrders.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), labHandler.GetByID())
+		labOrders.PUT(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.TreatmentWrite), labHandler.Put())
+		labOrders.PATCH(":id/status", middleware.Authentication(authService), middleware.Authorization(authService, auth.TreatmentWrite), labHandler.PatchStatus())
+	}
+
+	/* -------------------------------- Referrals ------------------------------- */
+	referralStorage := store.NewReferralSqlStore(db)
+	referralRepo := referral.NewReferralRepository(referralStorage, patientStorage, dentistStorage)
+	referralService := referral.NewReferralService(referralRepo, appointmentService)
+	appointmentService.OnStatusChange(referralService)
+	referralHandler := handler.NewReferralHandler(referralService)
+
+	patients.GET(":id/referrals", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), referralHandler.GetByPatient())
+	dentists.GET(":id/referrals", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), referralHandler.GetInbox())
+	referrals := r.Group("/referrals")
+	{
+		referrals.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.TreatmentWrite), referralHandler.Post())
+		referrals.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ClinicalRead), referralHandler.GetByID())
+		referrals.PATCH(":id/status", middleware.Authentication(authService), middleware.Authorization(authService, auth.TreatmentWrite), referralHandler.PatchStatus())
+		referrals.POST(":id/appointment", middleware.Authentication(authService), middleware.Authorization(authService, auth.AppointmentsWrite), referralHandler.PostAppointment())
+	}
+
+	/* --------------------------------- Consents ------------------------------- */
+	consentStorage := store.NewConsentSqlStore(db)
+	consentRepo := consent.NewConsentRepository(consentStorage, patientStorage, appointmentStorage, treatmentStorage, procedureStorage)
+	consentService := consent.NewConsentService(consentRepo, blobStorage)
+	appointmentService.BeforeStatusChange(consentService)
+	consentHandler := handler.NewConsentHandler(consentService)
+
+	patients.GET(":id/consents", middleware.Authentication(authService), middleware.Authorization(authService, auth.ConsentsRead), consentHandler.GetByPatient())
+	patients.POST(":id/consents", middleware.Authentication(authService), middleware.Authorization(authService, auth.ConsentsWrite), consentHandler.Post())
+	consentTemplates := r.Group("/consent-templates")
+	{
+		consentTemplates.GET("", middleware.Authentication(authService), middleware.Authorization(authService, auth.ConsentsRead), consentHandler.GetTemplates())
+		consentTemplates.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ConsentsRead), consentHandler.GetTemplateByID())
+		consentTemplates.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.ConsentTemplatesWrite), consentHandler.PostTemplate())
+		consentTemplates.PUT(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ConsentTemplatesWrite), consentHandler.PutTemplate())
+	}
+	consents := r.Group("/consents")
+	{
+		consents.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.ConsentsRead), consentHandler.GetByID())
+		consents.POST(":id/sign", middleware.Authentication(authService), middleware.Authorization(authService, auth.ConsentsWrite), consentHandler.PostSign())
+		consents.GET(":id/signature", middleware.Authentication(authService), middleware.Authorization(authService, auth.ConsentsRead), consentHandler.GetSignature())
+	}
+
+	/* -------------------------------- Invoices -------------------------------- */
+	invoiceStorage := store.NewInvoiceSqlStore(db)
+	invoiceRepo := invoice.NewInvoiceRepository(invoiceStorage, patientStorage, procedureStorage, treatmentStorage, insuranceStorage)
+	invoiceService := invoice.NewInvoiceService(invoiceRepo, appointmentService, familyService, pricingService, invoice.Config{
+		Series:  INVOICE_SERIES,
+		TaxRate: INVOICE_TAX_RATE,
+	})
+	invoiceHandler := handler.NewInvoiceHandler(invoiceService)
+
+	patients.GET(":id/invoices", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingRead), invoiceHandler.GetByPatient())
+	appointments.POST(":id/invoice", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingWrite), invoiceHandler.PostByAppointment())
+	invoices := r.Group("/invoices")
+	{
+		invoices.GET("", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingRead), invoiceHandler.GetAll())
+		invoices.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingWrite), invoiceHandler.Post())
+		invoices.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingRead), invoiceHandler.GetByID())
+		invoices.PUT(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingWrite), invoiceHandler.Put())
+		invoices.POST(":id/issue", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingWrite), invoiceHandler.PostIssue())
+		invoices.POST(":id/void", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingWrite), invoiceHandler.PostVoid())
+	}
+
+	/* --------------------------------- Ledger --------------------------------- */
+	ledgerStorage := store.NewLedgerSqlStore(db)
+	ledgerRepo := ledger.NewLedgerRepository(ledgerStorage, patientStorage, invoiceStorage)
+	ledgerService := ledger.NewLedgerService(ledgerRepo)
+	ledgerHandler := handler.NewLedgerHandler(ledgerService)
+
+	patients.GET(":id/ledger", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingRead), ledgerHandler.GetByPatient())
+	patients.POST(":id/ledger", middleware.Authentication(authService), middleware.Authorization(authService, auth.PaymentsWrite), ledgerHandler.Post())
+	patients.GET(":id/balance", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingRead), ledgerHandler.GetBalance())
+	ledgerGroup := r.Group("/ledger")
+	{
+		ledgerGroup.GET("/aging", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingRead), ledgerHandler.GetAging())
+		ledgerGroup.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingRead), ledgerHandler.GetByID())
+		ledgerGroup.POST(":id/allocations", middleware.Authentication(authService), middleware.Authorization(authService, auth.PaymentsWrite), ledgerHandler.PostAllocations())
+	}
+
+	/* -------------------------------- Insurance ------------------------------- */
+	insuranceRepo := insurance.NewInsuranceRepository(insuranceStorage, patientStorage, procedureStorage)
+	insuranceService := insurance.NewInsuranceService(insuranceRepo, pricingService)
+	appointmentService.SetEstimator(insuranceService)
+	insuranceHandler := handler.NewInsuranceHandler(insuranceService)
+
+	patients.GET(":id/coverages", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceRead), insuranceHandler.GetCoverages())
+	patients.POST(":id/coverages", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsWrite), insuranceHandler.PostCoverage())
+	patients.PUT(":id/coverages/:itemId", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsWrite), insuranceHandler.PutCoverage())
+	patients.DELETE(":id/coverages/:itemId", middleware.Authentication(authService), middleware.Authorization(authService, auth.PatientsWrite), insuranceHandler.DeleteCoverage())
+	patients.GET(":id/coverage-estimate", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceRead), insuranceHandler.GetEstimate())
+	insurers := r.Group("/insurers")
+	{
+		insurers.GET("", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceRead), insuranceHandler.GetInsurers())
+		insurers.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceWrite), insuranceHandler.PostInsurer())
+		insurers.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceRead), insuranceHandler.GetInsurer())
+		insurers.PUT(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceWrite), insuranceHandler.PutInsurer())
+		insurers.POST(":id/plans", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceWrite), insuranceHandler.PostPlan())
+	}
+	insurancePlans := r.Group("/insurance-plans")
+	{
+		insurancePlans.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceRead), insuranceHandler.GetPlan())
+		insurancePlans.PUT(":id/rules/:code", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceWrite), insuranceHandler.PutRule())
+		insurancePlans.DELETE(":id/rules/:code", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceWrite), insuranceHandler.DeleteRule())
+	}
+	claimBatches := r.Group("/claim-batches")
+	{
+		claimBatches.GET("", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceRead), insuranceHandler.GetBatches())
+		claimBatches.POST("", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceWrite), insuranceHandler.PostBatch())
+		claimBatches.GET(":id", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceRead), insuranceHandler.GetBatch())
+		claimBatches.PATCH(":id/status", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceWrite), insuranceHandler.PatchBatchStatus())
+		claimBatches.GET(":id/export", middleware.Authentication(authService), middleware.Authorization(authService, auth.InsuranceRead), insuranceHandler.GetExport())
+	}
+
+	/* -------------------------------- Documents ------------------------------- */
+	documentRepo := document.NewDocumentRepository(patientStorage, appointmentStorage)
+	documentService := document.NewDocumentService(documentRepo, invoiceService, ledgerService, document.Clinic{
+		Name:    CLINIC_NAME,
+		Address: CLINIC_ADDRESS,
+		Phone:   CLINIC_PHONE,
+		TaxId:   CLINIC_TAX_ID,
+	})
+	documentHandler := handler.NewDocumentHandler(documentService)
+
+	invoices.GET(":id/pdf", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingRead), documentHandler.GetInvoice())
+	ledgerGroup.GET(":id/receipt", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingRead), documentHandler.GetReceipt())
+	patients.GET(":id/statement", middleware.Authentication(authService), middleware.Authorization(authService, auth.BillingRead), documentHandler.GetStatement())
+
+	/* ------------------------------- Commissions ------------------------------ */
+	commissionStorage := store.NewCommissionSqlStore(db)
+	commissionRepo := commission.NewCommissionRepository(commissionStorage, dentistStorage, procedureStorage)
+	commissionService := commission.NewCommissionService(commissionRepo)
+	commissionHandler := handler.NewCommissionHandler(commissionService)
+
+	dentists.GET(":id/commission", middleware.Authentication(authService), middleware.Authorization(authService, auth.CommissionsRead), commissionHandler.GetPlan())
+	dentists.PUT(":id/commission", middleware.Authentication(authService), middleware.Authorization(authService, auth.CommissionsWrite), commissionHandler.PutPlan())
+	reports := r.Group("/reports")
+	{
+		reports.GET("/production", middleware.Authentication(authService), middleware.Authorization(authService, auth.CommissionsRead), commissionHandler.GetReport())
+		reports.GET("/production/export", middleware.Authentication(authService), middleware.Authorization(authService, auth.CommissionsRead), commissionHandler.GetExport())
+	}
+
+	links := r.Group("/links")
+	{
+		links.GET(":token", linkHandler.GetByToken())
+		links.POST(":token", linkHandler.PostByToken())
+	}
+
+	/* ----------------------------- Patient portal ----------------------------- */
+	portalStorage := store.NewPortalSqlStore(db)
+	portalRepo := portal.NewPortalRepository(portalStorage, patientStorage, dentistStorage)
+	portalService := portal.NewPortalService(portalRepo, appointmentService, policyService, notifier)
+	portalHandler := handler.NewPortalHandler(portalService)
+
+	portalGroup := r.Group("/portal")
+	{
+		portalGroup.POST("/code", portalHandler.PostCode())
+		portalGroup.POST("/session", portalHandler.PostSession())
+		portalGroup.DELETE("/session", middleware.PatientSession(portalService), portalHandler.DeleteSession())
+		portalGroup.GET("/availability", middleware.PatientSession(portalService), portalHandler.GetAvailability())
+		portalGroup.GET("/appointments", middleware.PatientSession(portalService), portalHandler.GetAppointments())
+		portalGroup.POST("/appointments", middleware.PatientSession(portalService), portalHandler.PostAppointment())
+		portalGroup.POST("/appointments/:id/confirm", middleware.PatientSession(portalService), portalHandler.PostConfirm())
+		portalGroup.POST("/appointments/:id/cancel", middleware.PatientSession(portalService), portalHandler.PostCancel())
 	}
 
 	r.Run(fmt.Sprintf(":%s", PORT))
 }
+
+// getEnv devuelve una variable de entorno o un valor por defecto si esta vacia
+func getEnv(key string, fallback string) string {
+	value := os.Getenv(key)
+	if value == "" {
+		return fallback
+	}
+	return value
+}
+
+// getEnvInt devuelve una variable de entorno numerica o un valor por defecto si esta vacia
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+// getEnvFloat devuelve una variable de entorno decimal o un valor por defecto si esta vacia
+func getEnvFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return fallback
+	}
+	return value
+}
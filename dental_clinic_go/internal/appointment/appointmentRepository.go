@@ -10,22 +10,26 @@ import (
 type AppointmentRepository interface {
 	GetByID(id int) (domain.Appointment, error)
 	GetByDni(dni int) ([]domain.Appointment, error)
+	GetByDentistAndDate(dentistId int, date string) ([]domain.Appointment, error)
 	Create(a domain.Appointment) (domain.Appointment, error)
 	CreateByDniAndLicense(dni int, license string, appointment domain.Appointment) (domain.Appointment, error)
 	Update(id int, updatedAppointment domain.Appointment) (domain.Appointment, error)
+	UpdateStatus(id int, status string) (domain.Appointment, error)
 	Delete(id int) error
 }
 
 type appointmentRepository struct {
-	storage      store.AppointmentStore
-	patientStore store.PatientStore
-	dentistStore store.DentistStore
+	storage        store.AppointmentStore
+	patientStore   store.PatientStore
+	dentistStore   store.DentistStore
+	procedureStore store.ProcedureStore
+	noteStore      store.NoteStore
 }
 
 // NewAppointmentRepository crea un nuevo repositorio
 func NewAppointmentRepository(storage store.AppointmentStore, patientStore store.PatientStore,
-	dentistStore store.DentistStore) AppointmentRepository {
-	return &appointmentRepository{storage, patientStore, dentistStore}
+	dentistStore store.DentistStore, procedureStore store.ProcedureStore, noteStore store.NoteStore) AppointmentRepository {
+	return &appointmentRepository{storage, patientStore, dentistStore, procedureStore, noteStore}
 }
 
 // GetByID busca un paciente por su id
@@ -46,9 +50,29 @@ func (r *appointmentRepository) GetByDni(dni int) ([]domain.Appointment, error)
 	return appointment, nil
 }
 
+// GetByDentistAndDate busca los turnos de un dentista en una fecha
+func (r *appointmentRepository) GetByDentistAndDate(dentistId int, date string) ([]domain.Appointment, error) {
+	appointments, err := r.storage.GetByDentistAndDate(dentistId, date)
+	if err != nil {
+		return []domain.Appointment{}, errors.New(fmt.Sprintf("appointments with dentist.id: %d not found", dentistId))
+	}
+	return appointments, nil
+}
+
 // Create agrega un nuevo paciente
 func (r *appointmentRepository) Create(a domain.Appointment) (domain.Appointment, error) {
+	a, err := r.completeProcedure(a)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	err = r.checkSpecialty(a.ProcedureCode, a.Dentist.Id)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
 	appointment, err := r.storage.Create(a)
+	if errors.Is(err, store.ErrSlotTaken) {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("slot %s %s is not available", a.Date, a.Hour))
+	}
 	if err != nil {
 		return domain.Appointment{}, errors.New("error creating appointment")
 	}
@@ -67,17 +91,52 @@ func (r *appointmentRepository) CreateByDniAndLicense(dni int, license string, a
 		return domain.Appointment{}, err
 	}
 	appointment.Dentist = dentist
+	appointment, err = r.completeProcedure(appointment)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	err = r.checkSpecialty(appointment.ProcedureCode, dentist.Id)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
 	appointment, err = r.storage.Create(appointment)
+	if errors.Is(err, store.ErrSlotTaken) {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("slot %s %s is not available", appointment.Date, appointment.Hour))
+	}
 	if err != nil {
 		return domain.Appointment{}, errors.New("error creating appointment")
 	}
 	return appointment, nil
 }
 
-// Update actualiza un paciente
+// Update actualiza un paciente, si cambia el procedimiento o el dentista vuelve a verificar la especialidad
 func (r *appointmentRepository) Update(id int, updatedAppointment domain.Appointment) (domain.Appointment, error) {
 	updatedAppointment.Id = id
+	updatedAppointment, err := r.completeProcedure(updatedAppointment)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	if updatedAppointment.ProcedureCode != "" || updatedAppointment.Dentist.Id != 0 {
+		current, err := r.GetByID(id)
+		if err != nil {
+			return domain.Appointment{}, err
+		}
+		code, dentistId := current.ProcedureCode, current.Dentist.Id
+		if updatedAppointment.ProcedureCode != "" {
+			code = updatedAppointment.ProcedureCode
+		}
+		if updatedAppointment.Dentist.Id != 0 {
+			dentistId = updatedAppointment.Dentist.Id
+		}
+		err = r.checkSpecialty(code, dentistId)
+		if err != nil {
+			return domain.Appointment{}, err
+		}
+	}
 	patientFlag, dentistFlag, p, err := r.storage.Update(updatedAppointment)
+	if errors.Is(err, store.ErrSlotTaken) {
+		return domain.Appointment{}, errors.New("the dentist already has an appointment at that date and hour")
+	}
 	if err != nil {
 		return domain.Appointment{}, errors.New("error updating appointment")
 	}
@@ -98,11 +157,67 @@ func (r *appointmentRepository) Update(id int, updatedAppointment domain.Appoint
 	return p, nil
 }
 
-// Delete busca un paciente por su id y lo elimina
+// UpdateStatus actualiza el estado de un turno
+func (r *appointmentRepository) UpdateStatus(id int, status string) (domain.Appointment, error) {
+	err := r.storage.UpdateStatus(id, status)
+	if err != nil {
+		return domain.Appointment{}, errors.New("error updating appointment status")
+	}
+	return r.GetByID(id)
+}
+
+// Delete busca un turno por su id y lo elimina, los turnos con notas clinicas no se
+// eliminan para no perder la historia clinica
 func (r *appointmentRepository) Delete(id int) error {
-	err := r.storage.Delete(id)
+	notes, err := r.noteStore.CountByAppointment(id)
 	if err != nil {
 		return err
 	}
+	if notes > 0 {
+		return errors.New(fmt.Sprintf("appointment %d has clinical notes and can't be deleted, cancel it instead", id))
+	}
+	err = r.storage.Delete(id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// completeProcedure verifica que exista el procedimiento del turno y si la descripcion
+// esta vacia la completa con el nombre del procedimiento
+func (r *appointmentRepository) completeProcedure(a domain.Appointment) (domain.Appointment, error) {
+	if a.ProcedureCode == "" {
+		return a, nil
+	}
+	procedure, err := r.procedureStore.GetByCode(a.ProcedureCode)
+	if err != nil {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("procedure %s not found", a.ProcedureCode))
+	}
+	if a.Description == "" {
+		a.Description = procedure.Name
+	}
+	return a, nil
+}
+
+// checkSpecialty verifica que el dentista tenga la especialidad que requiere el procedimiento,
+// los procedimientos sin especialidad los puede hacer cualquier dentista
+func (r *appointmentRepository) checkSpecialty(code string, dentistId int) error {
+	if code == "" {
+		return nil
+	}
+	procedure, err := r.procedureStore.GetByCode(code)
+	if err != nil {
+		return errors.New(fmt.Sprintf("procedure %s not found", code))
+	}
+	if procedure.Specialty == "" {
+		return nil
+	}
+	dentist, err := r.dentistStore.GetByID(dentistId)
+	if err != nil {
+		return errors.New(fmt.Sprintf("dentist %d not found", dentistId))
+	}
+	if !dentist.HasSpecialty(procedure.Specialty) {
+		return errors.New(fmt.Sprintf("procedure %s (%s) requires a dentist with specialty %s", code, procedure.Name, procedure.Specialty))
+	}
 	return nil
 }
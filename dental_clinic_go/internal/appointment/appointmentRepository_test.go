@@ -0,0 +1,162 @@
+package appointment
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeAppointmentStore guarda el turno 1 y cuenta los turnos creados y actualizados
+type fakeAppointmentStore struct {
+	store.AppointmentStore
+	created int
+	updated int
+}
+
+func (s *fakeAppointmentStore) GetByID(id int) (domain.Appointment, error) {
+	return domain.Appointment{Id: 1, ProcedureCode: "ORT01", Dentist: domain.Dentist{Id: 2}}, nil
+}
+
+func (s *fakeAppointmentStore) Create(a domain.Appointment) (domain.Appointment, error) {
+	s.created++
+	a.Id = s.created
+	return a, nil
+}
+
+func (s *fakeAppointmentStore) Update(a domain.Appointment) (bool, bool, domain.Appointment, error) {
+	s.updated++
+	return false, false, a, nil
+}
+
+// fakeDentistStore devuelve los dentistas por id y por matricula
+type fakeDentistStore struct {
+	store.DentistStore
+	dentists map[int]domain.Dentist
+}
+
+func (s *fakeDentistStore) GetByID(id int) (domain.Dentist, error) {
+	dentist, ok := s.dentists[id]
+	if !ok {
+		return domain.Dentist{}, errors.New("sql: no rows in result set")
+	}
+	return dentist, nil
+}
+
+func (s *fakeDentistStore) GetByLicense(license string) (domain.Dentist, error) {
+	for _, dentist := range s.dentists {
+		if dentist.License == license {
+			return dentist, nil
+		}
+	}
+	return domain.Dentist{}, errors.New(fmt.Sprintf("dentist with license %s not found", license))
+}
+
+// fakePatientStore devuelve cualquier dni como el paciente 1
+type fakePatientStore struct {
+	store.PatientStore
+}
+
+func (s *fakePatientStore) GetByDni(dni int) (domain.Patient, error) {
+	return domain.Patient{Id: 1, Dni: dni}, nil
+}
+
+// fakeProcedureStore tiene un procedimiento general y uno de ortodoncia
+type fakeProcedureStore struct {
+	store.ProcedureStore
+}
+
+func (s *fakeProcedureStore) GetByCode(code string) (domain.Procedure, error) {
+	switch code {
+	case "CON01":
+		return domain.Procedure{Code: code, Name: "Consulta"}, nil
+	case "ORT01":
+		return domain.Procedure{Code: code, Name: "Brackets", Specialty: domain.SpecialtyOrthodontics}, nil
+	}
+	return domain.Procedure{}, errors.New("sql: no rows in result set")
+}
+
+func newSpecialtyRepository(s *fakeAppointmentStore) AppointmentRepository {
+	dentists := &fakeDentistStore{dentists: map[int]domain.Dentist{
+		1: {Id: 1, License: "MP-1"},
+		2: {Id: 2, License: "MP-2", Specialties: []string{domain.SpecialtyOrthodontics}},
+	}}
+	return NewAppointmentRepository(s, &fakePatientStore{}, dentists, &fakeProcedureStore{}, nil)
+}
+
+func TestCreateChecksSpecialty(t *testing.T) {
+	tests := []struct {
+		name    string
+		code    string
+		dentist int
+		license string
+		err     string
+	}{
+		{name: "general procedure with any dentist", code: "CON01", dentist: 1},
+		{name: "without procedure", dentist: 1},
+		{name: "specialist", code: "ORT01", dentist: 2},
+		{name: "dentist without the specialty", code: "ORT01", dentist: 1, err: "procedure ORT01 (Brackets) requires a dentist with specialty orthodontics"},
+		{name: "unknown dentist", code: "ORT01", dentist: 9, err: "dentist 9 not found"},
+		{name: "unknown procedure", code: "XXX", dentist: 2, err: "procedure XXX not found"},
+		{name: "specialist by license", code: "ORT01", license: "MP-2"},
+		{name: "dentist without the specialty by license", code: "ORT01", license: "MP-1", err: "procedure ORT01 (Brackets) requires a dentist with specialty orthodontics"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeAppointmentStore{}
+			r := newSpecialtyRepository(s)
+			a := domain.Appointment{ProcedureCode: tt.code, Dentist: domain.Dentist{Id: tt.dentist}, Date: "2026-10-20", Hour: "10:00:00"}
+			var err error
+			if tt.license != "" {
+				_, err = r.CreateByDniAndLicense(30111222, tt.license, a)
+			} else {
+				_, err = r.Create(a)
+			}
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				if s.created != 0 {
+					t.Fatalf("expected no appointment to be created")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestUpdateChecksSpecialty(t *testing.T) {
+	// el turno 1 es de ortodoncia con el dentista 2
+	tests := []struct {
+		name   string
+		update domain.Appointment
+		err    string
+	}{
+		{name: "only the date", update: domain.Appointment{Date: "2026-10-21"}},
+		{name: "to a general procedure", update: domain.Appointment{ProcedureCode: "CON01"}},
+		{name: "to a dentist without the specialty", update: domain.Appointment{Dentist: domain.Dentist{Id: 1}}, err: "procedure ORT01 (Brackets) requires a dentist with specialty orthodontics"},
+		{name: "procedure and dentist together", update: domain.Appointment{ProcedureCode: "CON01", Dentist: domain.Dentist{Id: 1}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &fakeAppointmentStore{}
+			_, err := newSpecialtyRepository(s).Update(1, tt.update)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				if s.updated != 0 {
+					t.Fatalf("expected the appointment not to be updated")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
@@ -2,33 +2,79 @@ package appointment
 
 import (
 	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"log"
+	"time"
 )
 
+// transitions indica a que estados puede pasar un turno desde cada estado
+var transitions = map[string][]string{
+	domain.AppointmentScheduled:  {domain.AppointmentConfirmed, domain.AppointmentCancelled, domain.AppointmentInProgress, domain.AppointmentCompleted, domain.AppointmentNoShow},
+	domain.AppointmentConfirmed:  {domain.AppointmentCancelled, domain.AppointmentInProgress, domain.AppointmentCompleted, domain.AppointmentNoShow},
+	domain.AppointmentInProgress: {domain.AppointmentCompleted},
+}
+
+// StatusListener recibe los cambios de estado de los turnos
+type StatusListener interface {
+	AppointmentStatusChanged(before domain.Appointment, after domain.Appointment) error
+}
+
+// StatusGuard puede impedir un cambio de estado devolviendo el motivo como error
+type StatusGuard interface {
+	CanChangeStatus(a domain.Appointment, status string) error
+}
+
+// Advisor agrega avisos a un turno sin impedir que se agende
+type Advisor interface {
+	AppointmentWarnings(a domain.Appointment) ([]string, error)
+}
+
+// Estimator calcula lo que cubre el seguro del paciente en un turno, nil si no tiene cobertura
+type Estimator interface {
+	CoverageEstimate(a domain.Appointment) (*domain.CoverageEstimate, error)
+}
+
 type AppointmentService interface {
 	GetByID(id int) (domain.Appointment, error)
 	GetByDni(id int) ([]domain.Appointment, error)
+	GetByDentistAndDate(dentistId int, date string) ([]domain.Appointment, error)
 	Create(a domain.Appointment) (domain.Appointment, error)
 	CreateByDniAndLicense(dni int, license string, appointment domain.Appointment) (domain.Appointment, error)
 	Update(id int, updatedAppointment domain.Appointment) (domain.Appointment, error)
+	UpdateStatus(id int, status domain.AppointmentStatus) (domain.Appointment, error)
+	Confirm(id int) (domain.Appointment, error)
+	Cancel(id int) (domain.Appointment, error)
+	CancelByPatient(id int) (domain.Appointment, error)
+	OnStatusChange(l StatusListener)
+	BeforeStatusChange(g StatusGuard)
+	AddAdvisor(a Advisor)
+	SetEstimator(e Estimator)
 	Delete(id int) error
 }
 
 type appointmentService struct {
-	r AppointmentRepository
+	r                  AppointmentRepository
+	cancellationCutoff time.Duration
+	listeners          []StatusListener
+	guards             []StatusGuard
+	advisors           []Advisor
+	estimator          Estimator
 }
 
-// NewService crea un nuevo servicio
-func NewAppointmentService(r AppointmentRepository) AppointmentService {
-	return &appointmentService{r}
+// NewService crea un nuevo servicio, cancellationCutoff es la anticipacion minima
+// con la que un paciente puede cancelar su turno
+func NewAppointmentService(r AppointmentRepository, cancellationCutoff time.Duration) AppointmentService {
+	return &appointmentService{r: r, cancellationCutoff: cancellationCutoff}
 }
 
-// GetByID busca un turno por su id
+// GetByID busca un turno por su id y completa los avisos de los advisors y la cobertura
 func (s *appointmentService) GetByID(id int) (domain.Appointment, error) {
 	p, err := s.r.GetByID(id)
 	if err != nil {
 		return domain.Appointment{}, err
 	}
-	return p, nil
+	return s.withEstimate(s.withWarnings(p)), nil
 }
 
 // GetByID busca un turno por su id
@@ -40,31 +86,136 @@ func (s *appointmentService) GetByDni(dni int) ([]domain.Appointment, error) {
 	return p, nil
 }
 
-// Create agrega un nuevo turno
+// GetByDentistAndDate busca los turnos de un dentista en una fecha
+func (s *appointmentService) GetByDentistAndDate(dentistId int, date string) ([]domain.Appointment, error) {
+	p, err := s.r.GetByDentistAndDate(dentistId, date)
+	if err != nil {
+		return []domain.Appointment{}, err
+	}
+	return p, nil
+}
+
+// Create agrega un nuevo turno, los turnos nuevos siempre empiezan agendados. Devuelve los avisos de
+// los advisors y lo que cubriria el seguro del paciente.
 func (s *appointmentService) Create(a domain.Appointment) (domain.Appointment, error) {
+	a.Status = domain.AppointmentScheduled
 	p, err := s.r.Create(a)
 	if err != nil {
 		return domain.Appointment{}, err
 	}
-	return p, nil
+	return s.withEstimate(s.withWarnings(p)), nil
 }
 
 // CreateByDniAndLicense agrega un nuevo turno por medio de el dni del paciente y la matricula del dentista
 func (s *appointmentService) CreateByDniAndLicense(dni int, license string, appointment domain.Appointment) (domain.Appointment, error) {
+	appointment.Status = domain.AppointmentScheduled
 	p, err := s.r.CreateByDniAndLicense(dni, license, appointment)
 	if err != nil {
 		return domain.Appointment{}, err
 	}
-	return p, nil
+	return s.withEstimate(s.withWarnings(p)), nil
 }
 
-// UpdateAppointment actualiza un turno
+// UpdateAppointment actualiza un turno, el estado solo se cambia con UpdateStatus
 func (s *appointmentService) Update(id int, updatedAppointment domain.Appointment) (domain.Appointment, error) {
+	updatedAppointment.Status = ""
 	p, err := s.r.Update(id, updatedAppointment)
 	if err != nil {
 		return domain.Appointment{}, err
 	}
-	return p, nil
+	return s.withEstimate(s.withWarnings(p)), nil
+}
+
+// UpdateStatus cambia el estado de un turno validando la transicion y avisa a los listeners.
+// Si el turno ya tiene ese estado lo devuelve sin cambios. ByPatient registra que la cancelacion la pidio
+// el paciente (por ejemplo por telefono) para la politica de cancelaciones tardias, sin aplicar la
+// anticipacion minima que tiene el paciente cuando cancela el mismo.
+func (s *appointmentService) UpdateStatus(id int, status domain.AppointmentStatus) (domain.Appointment, error) {
+	if status.ByPatient && status.Status != domain.AppointmentCancelled {
+		return domain.Appointment{}, errors.New("by_patient only applies to cancellations")
+	}
+	return s.changeStatus(id, status.Status, status.ByPatient)
+}
+
+// changeStatus cambia el estado de un turno, byPatient indica que el cambio lo pidio el paciente
+func (s *appointmentService) changeStatus(id int, status string, byPatient bool) (domain.Appointment, error) {
+	a, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	if a.Status == status {
+		return a, nil
+	}
+	if !contains(transitions[a.Status], status) {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("appointment %d can't change from %s to %s", id, a.Status, status))
+	}
+	for _, g := range s.guards {
+		err := g.CanChangeStatus(a, status)
+		if err != nil {
+			return domain.Appointment{}, err
+		}
+	}
+	updated, err := s.r.UpdateStatus(id, status)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	updated.CancelledByPatient = byPatient && status == domain.AppointmentCancelled
+	for _, l := range s.listeners {
+		err := l.AppointmentStatusChanged(a, updated)
+		if err != nil {
+			log.Printf("error notifying status change of appointment %d: %s", id, err.Error())
+		}
+	}
+	return updated, nil
+}
+
+// Confirm confirma un turno, si ya estaba confirmado lo devuelve sin cambios
+func (s *appointmentService) Confirm(id int) (domain.Appointment, error) {
+	return s.changeStatus(id, domain.AppointmentConfirmed, false)
+}
+
+// Cancel cancela un turno, si ya estaba cancelado lo devuelve sin cambios
+func (s *appointmentService) Cancel(id int) (domain.Appointment, error) {
+	return s.changeStatus(id, domain.AppointmentCancelled, false)
+}
+
+// CancelByPatient cancela un turno a pedido del paciente respetando la anticipacion minima
+func (s *appointmentService) CancelByPatient(id int) (domain.Appointment, error) {
+	a, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	if a.Status == domain.AppointmentCancelled {
+		return a, nil
+	}
+	start, err := a.Start()
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	if time.Until(start) < s.cancellationCutoff {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("appointments can't be cancelled less than %v before they start", s.cancellationCutoff))
+	}
+	return s.changeStatus(id, domain.AppointmentCancelled, true)
+}
+
+// OnStatusChange registra un listener de cambios de estado
+func (s *appointmentService) OnStatusChange(l StatusListener) {
+	s.listeners = append(s.listeners, l)
+}
+
+// BeforeStatusChange registra una validacion que se ejecuta antes de cada cambio de estado
+func (s *appointmentService) BeforeStatusChange(g StatusGuard) {
+	s.guards = append(s.guards, g)
+}
+
+// AddAdvisor registra un advisor cuyos avisos se agregan al crear, buscar o modificar un turno
+func (s *appointmentService) AddAdvisor(a Advisor) {
+	s.advisors = append(s.advisors, a)
+}
+
+// SetEstimator registra quien calcula la cobertura que se muestra al crear, buscar o modificar un turno
+func (s *appointmentService) SetEstimator(e Estimator) {
+	s.estimator = e
 }
 
 // Delete busca un turno por su id y lo elimina
@@ -75,3 +226,40 @@ func (s *appointmentService) Delete(id int) error {
 	}
 	return nil
 }
+
+// withWarnings agrega al turno los avisos de los advisors, un advisor que falla no impide devolverlo
+func (s *appointmentService) withWarnings(a domain.Appointment) domain.Appointment {
+	for _, advisor := range s.advisors {
+		warnings, err := advisor.AppointmentWarnings(a)
+		if err != nil {
+			log.Printf("error checking warnings of appointment %d: %s", a.Id, err.Error())
+			continue
+		}
+		a.Warnings = append(a.Warnings, warnings...)
+	}
+	return a
+}
+
+// withEstimate agrega al turno la cobertura estimada, si falla el calculo lo devuelve sin ella
+func (s *appointmentService) withEstimate(a domain.Appointment) domain.Appointment {
+	if s.estimator == nil {
+		return a
+	}
+	estimate, err := s.estimator.CoverageEstimate(a)
+	if err != nil {
+		log.Printf("error estimating coverage of appointment %d: %s", a.Id, err.Error())
+		return a
+	}
+	a.Coverage = estimate
+	return a
+}
+
+// contains indica si un estado esta en la lista
+func contains(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,79 @@
+package appointment
+
+import (
+	"dental_clinic_go/internal/domain"
+	"testing"
+	"time"
+)
+
+// fakeRepository guarda un unico turno, los metodos que no usan los cambios de estado quedan sin implementar
+type fakeRepository struct {
+	AppointmentRepository
+	appointment domain.Appointment
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.Appointment, error) {
+	return r.appointment, nil
+}
+
+func (r *fakeRepository) UpdateStatus(id int, status string) (domain.Appointment, error) {
+	r.appointment.Status = status
+	return r.appointment, nil
+}
+
+// fakeListener guarda el ultimo cambio de estado recibido
+type fakeListener struct {
+	after *domain.Appointment
+}
+
+func (l *fakeListener) AppointmentStatusChanged(before domain.Appointment, after domain.Appointment) error {
+	l.after = &after
+	return nil
+}
+
+func TestCancellationByPatient(t *testing.T) {
+	start := time.Now().Add(2 * time.Hour)
+	soon := domain.Appointment{Id: 1, Date: start.Format("2006-01-02"), Hour: start.Format("15:04:05"), Status: domain.AppointmentScheduled}
+	tests := []struct {
+		name      string
+		change    func(s AppointmentService) (domain.Appointment, error)
+		byPatient bool
+		err       string
+	}{
+		{name: "staff cancels for the patient", change: func(s AppointmentService) (domain.Appointment, error) {
+			return s.UpdateStatus(1, domain.AppointmentStatus{Status: domain.AppointmentCancelled, ByPatient: true})
+		}, byPatient: true},
+		{name: "clinic cancels", change: func(s AppointmentService) (domain.Appointment, error) {
+			return s.UpdateStatus(1, domain.AppointmentStatus{Status: domain.AppointmentCancelled})
+		}},
+		{name: "by_patient on another status", change: func(s AppointmentService) (domain.Appointment, error) {
+			return s.UpdateStatus(1, domain.AppointmentStatus{Status: domain.AppointmentConfirmed, ByPatient: true})
+		}, err: "by_patient only applies to cancellations"},
+		{name: "patient cancels after the cutoff", change: func(s AppointmentService) (domain.Appointment, error) {
+			return s.CancelByPatient(1)
+		}, err: "appointments can't be cancelled less than 24h0m0s before they start"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			l := &fakeListener{}
+			s := NewAppointmentService(&fakeRepository{appointment: soon}, 24*time.Hour)
+			s.OnStatusChange(l)
+			_, err := tt.change(s)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				if l.after != nil {
+					t.Fatalf("expected no status change, got %+v", l.after)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if l.after == nil || l.after.Status != domain.AppointmentCancelled || l.after.CancelledByPatient != tt.byPatient {
+				t.Fatalf("expected a cancellation by patient %v, got %+v", tt.byPatient, l.after)
+			}
+		})
+	}
+}
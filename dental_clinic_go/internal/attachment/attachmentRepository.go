@@ -0,0 +1,101 @@
+package attachment
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type AttachmentRepository interface {
+	GetByID(id int) (domain.Attachment, error)
+	GetByPatient(patientId int) ([]domain.Attachment, error)
+	GetByAppointment(appointmentId int) ([]domain.Attachment, error)
+	GetPatient(patientId int) (domain.Patient, error)
+	GetAppointment(appointmentId int) (domain.Appointment, error)
+	Create(a domain.Attachment) (domain.Attachment, error)
+	Delete(id int) error
+}
+
+type attachmentRepository struct {
+	storage          store.AttachmentStore
+	patientStore     store.PatientStore
+	appointmentStore store.AppointmentStore
+}
+
+// NewAttachmentRepository crea un nuevo repositorio
+func NewAttachmentRepository(storage store.AttachmentStore, patientStore store.PatientStore,
+	appointmentStore store.AppointmentStore) AttachmentRepository {
+	return &attachmentRepository{storage, patientStore, appointmentStore}
+}
+
+// GetByID busca la metadata de un archivo por su id
+func (r *attachmentRepository) GetByID(id int) (domain.Attachment, error) {
+	a, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.Attachment{}, errors.New(fmt.Sprintf("file %d not found", id))
+	}
+	return a, nil
+}
+
+// GetByPatient busca los archivos de un paciente
+func (r *attachmentRepository) GetByPatient(patientId int) ([]domain.Attachment, error) {
+	_, err := r.GetPatient(patientId)
+	if err != nil {
+		return []domain.Attachment{}, err
+	}
+	attachments, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.Attachment{}, errors.New(fmt.Sprintf("files of patient %d not found", patientId))
+	}
+	return attachments, nil
+}
+
+// GetByAppointment busca los archivos de un turno
+func (r *attachmentRepository) GetByAppointment(appointmentId int) ([]domain.Attachment, error) {
+	_, err := r.GetAppointment(appointmentId)
+	if err != nil {
+		return []domain.Attachment{}, err
+	}
+	attachments, err := r.storage.GetByAppointment(appointmentId)
+	if err != nil {
+		return []domain.Attachment{}, errors.New(fmt.Sprintf("files of appointment %d not found", appointmentId))
+	}
+	return attachments, nil
+}
+
+// GetPatient busca el paciente de un archivo
+func (r *attachmentRepository) GetPatient(patientId int) (domain.Patient, error) {
+	patient, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return domain.Patient{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	return patient, nil
+}
+
+// GetAppointment busca el turno de un archivo
+func (r *attachmentRepository) GetAppointment(appointmentId int) (domain.Appointment, error) {
+	appointment, err := r.appointmentStore.GetByID(appointmentId)
+	if err != nil {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("appointment %d not found", appointmentId))
+	}
+	return appointment, nil
+}
+
+// Create agrega la metadata de un archivo
+func (r *attachmentRepository) Create(a domain.Attachment) (domain.Attachment, error) {
+	attachment, err := r.storage.Create(a)
+	if err != nil {
+		return domain.Attachment{}, errors.New("error saving file")
+	}
+	return attachment, nil
+}
+
+// Delete elimina la metadata de un archivo
+func (r *attachmentRepository) Delete(id int) error {
+	err := r.storage.Delete(id)
+	if err != nil {
+		return errors.New(fmt.Sprintf("error deleting file %d", id))
+	}
+	return nil
+}
@@ -0,0 +1,249 @@
+package attachment
+
+import (
+	"bufio"
+	"bytes"
+	"crypto/rand"
+	"crypto/sha256"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/blob"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"path/filepath"
+	"strings"
+)
+
+// allowedTypes son los tipos de archivo que se aceptan, se detectan por el contenido y no por la extension
+var allowedTypes = map[string]bool{
+	"image/jpeg":        true,
+	"image/png":         true,
+	"image/gif":         true,
+	"image/webp":        true,
+	"image/bmp":         true,
+	"application/pdf":   true,
+	"application/dicom": true,
+}
+
+// thumbnailSuffix se agrega a la clave del archivo para guardar su miniatura
+const thumbnailSuffix = ".thumb.jpg"
+
+type Service interface {
+	GetByID(id int) (domain.Attachment, error)
+	GetByPatient(patientId int) ([]domain.Attachment, error)
+	GetByAppointment(appointmentId int) ([]domain.Attachment, error)
+	Upload(a domain.Attachment, content io.Reader) (domain.Attachment, error)
+	Open(id int) (domain.Attachment, io.ReadCloser, error)
+	OpenThumbnail(id int) (io.ReadCloser, error)
+	Delete(id int) error
+	MaxSize() int64
+}
+
+type service struct {
+	r       AttachmentRepository
+	storage blob.Storage
+	maxSize int64
+}
+
+// NewAttachmentService crea un nuevo servicio, maxSize es el tamaño maximo de un archivo en bytes
+func NewAttachmentService(r AttachmentRepository, storage blob.Storage, maxSize int64) Service {
+	return &service{r, storage, maxSize}
+}
+
+// GetByID busca la metadata de un archivo
+func (s *service) GetByID(id int) (domain.Attachment, error) {
+	return s.r.GetByID(id)
+}
+
+// GetByPatient busca los archivos de un paciente
+func (s *service) GetByPatient(patientId int) ([]domain.Attachment, error) {
+	return s.r.GetByPatient(patientId)
+}
+
+// GetByAppointment busca los archivos de un turno
+func (s *service) GetByAppointment(appointmentId int) ([]domain.Attachment, error) {
+	return s.r.GetByAppointment(appointmentId)
+}
+
+// Upload guarda un archivo de un paciente o de un turno. El tipo se detecta por el contenido,
+// se calcula el sha256 mientras se guarda y para las imagenes se genera una miniatura.
+func (s *service) Upload(a domain.Attachment, content io.Reader) (domain.Attachment, error) {
+	if a.AppointmentId != 0 {
+		appointment, err := s.r.GetAppointment(a.AppointmentId)
+		if err != nil {
+			return domain.Attachment{}, err
+		}
+		a.PatientId = appointment.Patient.Id
+	} else if _, err := s.r.GetPatient(a.PatientId); err != nil {
+		return domain.Attachment{}, err
+	}
+	a.FileName = cleanFileName(a.FileName)
+
+	reader := bufio.NewReaderSize(content, 512)
+	head, err := reader.Peek(512)
+	if err != nil && err != io.EOF {
+		return domain.Attachment{}, err
+	}
+	if len(head) == 0 {
+		return domain.Attachment{}, errors.New("file can't be empty")
+	}
+	a.ContentType = detectContentType(head)
+	if !allowedTypes[a.ContentType] {
+		return domain.Attachment{}, errors.New(fmt.Sprintf("file type %s isn't allowed, upload images, pdf or dicom files", a.ContentType))
+	}
+
+	key, err := newKey(a.PatientId)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	hash := sha256.New()
+	counter := &countWriter{}
+	limited := io.LimitReader(reader, s.maxSize+1)
+	err = s.storage.Put(key, io.TeeReader(limited, io.MultiWriter(hash, counter)))
+	if err != nil {
+		return domain.Attachment{}, errors.New("error saving file")
+	}
+	if counter.n > s.maxSize {
+		s.removeBlobs(key)
+		return domain.Attachment{}, errors.New(fmt.Sprintf("file exceeds the maximum size of %d bytes", s.maxSize))
+	}
+	a.StorageKey = key
+	a.Size = counter.n
+	a.Checksum = hex.EncodeToString(hash.Sum(nil))
+	a.HasThumbnail = s.saveThumbnail(a)
+
+	attachment, err := s.r.Create(a)
+	if err != nil {
+		s.removeBlobs(key)
+		return domain.Attachment{}, err
+	}
+	return attachment, nil
+}
+
+// Open abre el contenido de un archivo, quien lo llama debe cerrarlo
+func (s *service) Open(id int) (domain.Attachment, io.ReadCloser, error) {
+	a, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.Attachment{}, nil, err
+	}
+	content, err := s.storage.Get(a.StorageKey)
+	if err != nil {
+		return domain.Attachment{}, nil, errors.New(fmt.Sprintf("content of file %d not found", id))
+	}
+	return a, content, nil
+}
+
+// OpenThumbnail abre la miniatura JPEG de una imagen, quien lo llama debe cerrarla
+func (s *service) OpenThumbnail(id int) (io.ReadCloser, error) {
+	a, err := s.r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	if !a.HasThumbnail {
+		return nil, errors.New(fmt.Sprintf("file %d has no thumbnail", id))
+	}
+	content, err := s.storage.Get(a.StorageKey + thumbnailSuffix)
+	if err != nil {
+		return nil, errors.New(fmt.Sprintf("thumbnail of file %d not found", id))
+	}
+	return content, nil
+}
+
+// Delete elimina la metadata y el contenido de un archivo
+func (s *service) Delete(id int) error {
+	a, err := s.r.GetByID(id)
+	if err != nil {
+		return err
+	}
+	err = s.r.Delete(id)
+	if err != nil {
+		return err
+	}
+	s.removeBlobs(a.StorageKey)
+	return nil
+}
+
+// MaxSize devuelve el tamaño maximo de un archivo en bytes
+func (s *service) MaxSize() int64 {
+	return s.maxSize
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// saveThumbnail genera y guarda la miniatura de una imagen, si no se puede generar el archivo
+// se guarda igual sin miniatura
+func (s *service) saveThumbnail(a domain.Attachment) bool {
+	if !thumbnailTypes[a.ContentType] {
+		return false
+	}
+	content, err := s.storage.Get(a.StorageKey)
+	if err != nil {
+		return false
+	}
+	defer content.Close()
+	var buf bytes.Buffer
+	err = writeThumbnail(&buf, content)
+	if err != nil {
+		log.Printf("thumbnail of %s: %s", a.StorageKey, err.Error())
+		return false
+	}
+	err = s.storage.Put(a.StorageKey+thumbnailSuffix, &buf)
+	return err == nil
+}
+
+// removeBlobs elimina el contenido de un archivo y su miniatura
+func (s *service) removeBlobs(key string) {
+	for _, k := range []string{key, key + thumbnailSuffix} {
+		if err := s.storage.Delete(k); err != nil {
+			log.Printf("error deleting blob %s: %s", k, err.Error())
+		}
+	}
+}
+
+// detectContentType detecta el tipo de archivo por su contenido, ademas de los tipos que
+// reconoce net/http detecta los archivos DICOM de los equipos de rayos
+func detectContentType(head []byte) string {
+	if len(head) >= 132 && string(head[128:132]) == "DICM" {
+		return "application/dicom"
+	}
+	contentType := http.DetectContentType(head)
+	if i := strings.Index(contentType, ";"); i >= 0 {
+		contentType = contentType[:i]
+	}
+	return contentType
+}
+
+// newKey genera una clave aleatoria para guardar un archivo de un paciente
+func newKey(patientId int) (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("patients/%d/%s", patientId, hex.EncodeToString(b)), nil
+}
+
+// cleanFileName se queda con el nombre del archivo sin la ruta del cliente
+func cleanFileName(name string) string {
+	name = filepath.Base(strings.ReplaceAll(name, "\\", "/"))
+	if name == "." || name == "/" {
+		name = "file"
+	}
+	if len(name) > 255 {
+		name = name[len(name)-255:]
+	}
+	return name
+}
+
+// countWriter cuenta los bytes escritos
+type countWriter struct {
+	n int64
+}
+
+func (w *countWriter) Write(p []byte) (int, error) {
+	w.n += int64(len(p))
+	return len(p), nil
+}
@@ -0,0 +1,120 @@
+package attachment
+
+import (
+	"bytes"
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"image"
+	"image/png"
+	"io"
+	"strings"
+	"testing"
+)
+
+// fakeRepository guarda los archivos creados, el turno 5 es del paciente 2
+type fakeRepository struct {
+	AttachmentRepository
+	created []domain.Attachment
+}
+
+func (r *fakeRepository) GetPatient(patientId int) (domain.Patient, error) {
+	if patientId == 0 {
+		return domain.Patient{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	return domain.Patient{Id: patientId}, nil
+}
+
+func (r *fakeRepository) GetAppointment(appointmentId int) (domain.Appointment, error) {
+	return domain.Appointment{Id: appointmentId, Patient: domain.Patient{Id: 2}}, nil
+}
+
+func (r *fakeRepository) Create(a domain.Attachment) (domain.Attachment, error) {
+	a.Id = len(r.created) + 1
+	r.created = append(r.created, a)
+	return a, nil
+}
+
+// fakeStorage es un blob storage en memoria
+type fakeStorage map[string][]byte
+
+func (s fakeStorage) Put(key string, r io.Reader) error {
+	content, err := io.ReadAll(r)
+	s[key] = content
+	return err
+}
+
+func (s fakeStorage) Get(key string) (io.ReadCloser, error) {
+	content, ok := s[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (s fakeStorage) Delete(key string) error {
+	delete(s, key)
+	return nil
+}
+
+func TestUpload(t *testing.T) {
+	var photo bytes.Buffer
+	if err := png.Encode(&photo, image.NewRGBA(image.Rect(0, 0, 400, 300))); err != nil {
+		t.Fatalf("encoding png: %s", err)
+	}
+	dicom := append(make([]byte, 128), []byte("DICM rayos")...)
+	pdf := []byte("%PDF-1.4 consentimiento")
+
+	tests := []struct {
+		name          string
+		attachment    domain.Attachment
+		content       []byte
+		contentType   string
+		patientId     int
+		fileName      string
+		thumbnail     bool
+		maxSize       int64
+		err           string
+		savedContents int
+	}{
+		{name: "photo gets a thumbnail", attachment: domain.Attachment{PatientId: 1, FileName: "sonrisa.png"}, content: photo.Bytes(), contentType: "image/png", patientId: 1, fileName: "sonrisa.png", thumbnail: true, savedContents: 2},
+		{name: "pdf of an appointment belongs to its patient", attachment: domain.Attachment{PatientId: 9, AppointmentId: 5, FileName: `C:\scans\consentimiento.pdf`}, content: pdf, contentType: "application/pdf", patientId: 2, fileName: "consentimiento.pdf", savedContents: 1},
+		{name: "dicom x-ray", attachment: domain.Attachment{PatientId: 1, FileName: "../../rx.dcm"}, content: dicom, contentType: "application/dicom", patientId: 1, fileName: "rx.dcm", savedContents: 1},
+		{name: "type detected by content, not by extension", attachment: domain.Attachment{PatientId: 1, FileName: "rx.png"}, content: []byte("MZ ejecutable"), err: "file type text/plain isn't allowed, upload images, pdf or dicom files"},
+		{name: "empty file", attachment: domain.Attachment{PatientId: 1, FileName: "vacio.pdf"}, err: "file can't be empty"},
+		{name: "too big", attachment: domain.Attachment{PatientId: 1, FileName: "grande.pdf"}, content: append(pdf, make([]byte, 100)...), maxSize: 64, err: "file exceeds the maximum size of 64 bytes"},
+		{name: "unknown patient", attachment: domain.Attachment{FileName: "rx.pdf"}, content: pdf, err: "patient 0 not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{}
+			storage := fakeStorage{}
+			maxSize := tt.maxSize
+			if maxSize == 0 {
+				maxSize = 1 << 20
+			}
+			a, err := NewAttachmentService(r, storage, maxSize).Upload(tt.attachment, bytes.NewReader(tt.content))
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				if len(storage) != 0 || len(r.created) != 0 {
+					t.Fatalf("expected nothing to be saved, got %d blobs and %+v", len(storage), r.created)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if a.ContentType != tt.contentType || a.PatientId != tt.patientId || a.FileName != tt.fileName || a.HasThumbnail != tt.thumbnail {
+				t.Fatalf("unexpected attachment %+v", a)
+			}
+			if a.Size != int64(len(tt.content)) || len(a.Checksum) != 64 || !strings.HasPrefix(a.StorageKey, fmt.Sprintf("patients/%d/", tt.patientId)) {
+				t.Fatalf("unexpected size, checksum or key in %+v", a)
+			}
+			if len(storage) != tt.savedContents || !bytes.Equal(storage[a.StorageKey], tt.content) {
+				t.Fatalf("expected %d blobs with the file content, got %d", tt.savedContents, len(storage))
+			}
+		})
+	}
+}
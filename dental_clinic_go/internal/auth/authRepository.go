@@ -0,0 +1,190 @@
+package auth
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type AuthRepository interface {
+	GetAll() ([]domain.User, error)
+	GetByID(id int) (domain.User, error)
+	GetByUsername(username string) (domain.User, string, error)
+	GetByDentist(dentistId int) (domain.User, error)
+	GetDentist(id int) (domain.Dentist, error)
+	Count() (int, error)
+	CountActive(role string) (int, error)
+	Create(user domain.User, passwordHash string) (domain.User, error)
+	Update(user domain.User) (domain.User, error)
+	UpdatePassword(id int, passwordHash string) error
+	CreateSession(userId int, refreshHash string, expiresAt time.Time) (int, error)
+	GetSession(refreshHash string, now time.Time) (domain.UserSession, error)
+	GetActiveSession(id int, now time.Time) (domain.UserSession, error)
+	RotateSession(id int, previousHash string, refreshHash string, expiresAt time.Time) error
+	RevokeSession(id int) error
+	RevokeSessions(userId int, exceptId int) error
+}
+
+type authRepository struct {
+	storage      store.UserStore
+	dentistStore store.DentistStore
+}
+
+// NewAuthRepository crea un nuevo repositorio
+func NewAuthRepository(storage store.UserStore, dentistStore store.DentistStore) AuthRepository {
+	return &authRepository{storage, dentistStore}
+}
+
+// GetAll busca todos los usuarios
+func (r *authRepository) GetAll() ([]domain.User, error) {
+	users, err := r.storage.GetAll()
+	if err != nil {
+		return []domain.User{}, errors.New("error getting users")
+	}
+	return users, nil
+}
+
+// GetByID busca un usuario por su id
+func (r *authRepository) GetByID(id int) (domain.User, error) {
+	user, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.User{}, errors.New(fmt.Sprintf("user %d not found", id))
+	}
+	return user, nil
+}
+
+// GetByUsername busca un usuario y el hash de su contraseña
+func (r *authRepository) GetByUsername(username string) (domain.User, string, error) {
+	user, passwordHash, err := r.storage.GetByUsername(username)
+	if err != nil {
+		return domain.User{}, "", errors.New(fmt.Sprintf("user %s not found", username))
+	}
+	return user, passwordHash, nil
+}
+
+// GetByDentist busca el usuario vinculado a un dentista
+func (r *authRepository) GetByDentist(dentistId int) (domain.User, error) {
+	user, err := r.storage.GetByDentist(dentistId)
+	if err != nil {
+		return domain.User{}, errors.New(fmt.Sprintf("dentist %d has no user", dentistId))
+	}
+	return user, nil
+}
+
+// GetDentist busca un dentista por su id
+func (r *authRepository) GetDentist(id int) (domain.Dentist, error) {
+	dentist, err := r.dentistStore.GetByID(id)
+	if err != nil {
+		return domain.Dentist{}, errors.New(fmt.Sprintf("dentist %d not found", id))
+	}
+	return dentist, nil
+}
+
+// Count cuenta los usuarios
+func (r *authRepository) Count() (int, error) {
+	count, err := r.storage.Count()
+	if err != nil {
+		return 0, errors.New("error counting users")
+	}
+	return count, nil
+}
+
+// CountActive cuenta los usuarios activos de un rol
+func (r *authRepository) CountActive(role string) (int, error) {
+	count, err := r.storage.CountActive(role)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("error counting %s users", role))
+	}
+	return count, nil
+}
+
+// Create agrega un usuario verificando que no exista otro con el mismo nombre de usuario
+func (r *authRepository) Create(user domain.User, passwordHash string) (domain.User, error) {
+	_, _, err := r.storage.GetByUsername(user.Username)
+	if err == nil {
+		return domain.User{}, errors.New(fmt.Sprintf("username %s already exists", user.Username))
+	}
+	u, err := r.storage.Create(user, passwordHash)
+	if err != nil {
+		return domain.User{}, errors.New("error creating user")
+	}
+	return r.GetByID(u.Id)
+}
+
+// Update actualiza un usuario verificando que exista
+func (r *authRepository) Update(user domain.User) (domain.User, error) {
+	_, err := r.GetByID(user.Id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	err = r.storage.Update(user)
+	if err != nil {
+		return domain.User{}, errors.New(fmt.Sprintf("error updating user %d", user.Id))
+	}
+	return r.GetByID(user.Id)
+}
+
+// UpdatePassword reemplaza la contraseña de un usuario
+func (r *authRepository) UpdatePassword(id int, passwordHash string) error {
+	err := r.storage.UpdatePassword(id, passwordHash)
+	if err != nil {
+		return errors.New(fmt.Sprintf("error updating password of user %d", id))
+	}
+	return nil
+}
+
+// CreateSession abre una sesion
+func (r *authRepository) CreateSession(userId int, refreshHash string, expiresAt time.Time) (int, error) {
+	id, err := r.storage.CreateSession(userId, refreshHash, expiresAt)
+	if err != nil {
+		return 0, errors.New("error creating session")
+	}
+	return id, nil
+}
+
+// GetSession busca la sesion de un refresh token
+func (r *authRepository) GetSession(refreshHash string, now time.Time) (domain.UserSession, error) {
+	session, err := r.storage.GetSession(refreshHash, now)
+	if err != nil {
+		return domain.UserSession{}, errors.New("invalid refresh token")
+	}
+	return session, nil
+}
+
+// GetActiveSession busca una sesion que no vencio ni fue revocada
+func (r *authRepository) GetActiveSession(id int, now time.Time) (domain.UserSession, error) {
+	session, err := r.storage.GetActiveSession(id, now)
+	if err != nil {
+		return domain.UserSession{}, errors.New("session expired or revoked")
+	}
+	return session, nil
+}
+
+// RotateSession reemplaza el refresh token de una sesion
+func (r *authRepository) RotateSession(id int, previousHash string, refreshHash string, expiresAt time.Time) error {
+	err := r.storage.RotateSession(id, previousHash, refreshHash, expiresAt)
+	if err != nil {
+		return errors.New("invalid refresh token")
+	}
+	return nil
+}
+
+// RevokeSession cierra una sesion
+func (r *authRepository) RevokeSession(id int) error {
+	err := r.storage.RevokeSession(id)
+	if err != nil {
+		return errors.New(fmt.Sprintf("error revoking session %d", id))
+	}
+	return nil
+}
+
+// RevokeSessions cierra las sesiones de un usuario salvo una
+func (r *authRepository) RevokeSessions(userId int, exceptId int) error {
+	err := r.storage.RevokeSessions(userId, exceptId)
+	if err != nil {
+		return errors.New(fmt.Sprintf("error revoking sessions of user %d", userId))
+	}
+	return nil
+}
@@ -0,0 +1,388 @@
+package auth
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/token"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"strconv"
+	"strings"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+const (
+	minPasswordLength = 8
+	// bcrypt ignora lo que sigue a los primeros 72 bytes
+	maxPasswordLength = 72
+)
+
+// dummyHash se compara cuando el usuario no existe, para que el login tarde lo mismo y no revele que usuarios hay
+const dummyHash = "$2a$10$/Vxoijm3q2PZTp2439manOYSar5A6XnDMn11SeNBBRZnAicudqPIm"
+
+// Config son las duraciones de los tokens de una sesion
+type Config struct {
+	AccessTTL  time.Duration
+	RefreshTTL time.Duration
+}
+
+type Service interface {
+	Login(username string, password string) (domain.AuthTokens, error)
+	Refresh(refreshToken string) (domain.AuthTokens, error)
+	Logout(sessionId int) error
+	ValidateToken(accessToken string) (token.Claims, error)
+	HasPermission(role string, permission string) bool
+	GetRoles() []domain.Role
+	Bootstrap(username string, password string) error
+	GetAll() ([]domain.User, error)
+	GetByID(id int) (domain.User, error)
+	GetCurrent(id int) (domain.User, error)
+	Create(user domain.User) (domain.User, error)
+	Update(id int, user domain.User) (domain.User, error)
+	ChangePassword(userId int, sessionId int, change domain.PasswordChange) error
+	SetPassword(id int, password string) error
+	RevokeSessions(id int) error
+}
+
+type service struct {
+	r      AuthRepository
+	jwt    *token.JWT
+	config Config
+}
+
+// NewAuthService crea un nuevo servicio
+func NewAuthService(r AuthRepository, jwt *token.JWT, config Config) Service {
+	return &service{r, jwt, config}
+}
+
+// Login valida el usuario y la contraseña y abre una sesion
+func (s *service) Login(username string, password string) (domain.AuthTokens, error) {
+	user, passwordHash, err := s.r.GetByUsername(strings.TrimSpace(username))
+	if err != nil {
+		bcrypt.CompareHashAndPassword([]byte(dummyHash), []byte(password))
+		return domain.AuthTokens{}, errors.New("invalid username or password")
+	}
+	if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(password)) != nil {
+		return domain.AuthTokens{}, errors.New("invalid username or password")
+	}
+	if !user.Active {
+		return domain.AuthTokens{}, errors.New(fmt.Sprintf("user %s is disabled", user.Username))
+	}
+	refreshToken, err := generateToken()
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	now := time.Now().UTC()
+	sessionId, err := s.r.CreateSession(user.Id, hash(refreshToken), now.Add(s.config.RefreshTTL))
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	return s.tokens(user, sessionId, refreshToken, now)
+}
+
+// Refresh cambia un refresh token por nuevas credenciales de la misma sesion. Cada refresh token sirve
+// una sola vez: si se vuelve a usar cualquiera de los ya rotados, o dos pedidos usan el mismo a la vez, se
+// asume que fue robado y se revoca la sesion.
+func (s *service) Refresh(refreshToken string) (domain.AuthTokens, error) {
+	now := time.Now().UTC()
+	session, err := s.r.GetSession(hash(refreshToken), now)
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	if session.Revoked {
+		return domain.AuthTokens{}, errors.New("session revoked")
+	}
+	if session.Rotated {
+		err := s.r.RevokeSession(session.Id)
+		if err != nil {
+			log.Printf("revoking session %d: %s", session.Id, err.Error())
+		}
+		return domain.AuthTokens{}, errors.New("refresh token already used, session revoked")
+	}
+	user, err := s.r.GetByID(session.UserId)
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	if !user.Active {
+		return domain.AuthTokens{}, errors.New(fmt.Sprintf("user %s is disabled", user.Username))
+	}
+	newToken, err := generateToken()
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	err = s.r.RotateSession(session.Id, hash(refreshToken), hash(newToken), now.Add(s.config.RefreshTTL))
+	if err != nil {
+		// otro pedido roto el mismo token primero
+		if err := s.r.RevokeSession(session.Id); err != nil {
+			log.Printf("revoking session %d: %s", session.Id, err.Error())
+		}
+		return domain.AuthTokens{}, err
+	}
+	return s.tokens(user, session.Id, newToken, now)
+}
+
+// Logout revoca una sesion, sus access tokens dejan de valer aunque no hayan vencido
+func (s *service) Logout(sessionId int) error {
+	return s.r.RevokeSession(sessionId)
+}
+
+// ValidateToken valida un access token y que su sesion siga abierta. El rol se toma del usuario y no
+// del token, para que un cambio de rol o la baja del usuario apliquen en el siguiente pedido.
+func (s *service) ValidateToken(accessToken string) (token.Claims, error) {
+	claims, err := s.jwt.Parse(accessToken, time.Now())
+	if err != nil {
+		return token.Claims{}, err
+	}
+	session, err := s.r.GetActiveSession(claims.SessionId, time.Now().UTC())
+	if err != nil {
+		return token.Claims{}, err
+	}
+	if session.UserId != claims.UserId() {
+		return token.Claims{}, errors.New("invalid token")
+	}
+	claims.Role = session.Role
+	claims.DentistId = session.DentistId
+	return claims, nil
+}
+
+// HasPermission indica si un rol tiene un permiso de la matriz
+func (s *service) HasPermission(role string, permission string) bool {
+	return allowed(role, permission)
+}
+
+// GetRoles devuelve los roles con sus permisos
+func (s *service) GetRoles() []domain.Role {
+	return roles
+}
+
+// Bootstrap crea el primer usuario cuando no hay ninguno, para poder entrar a una base nueva
+func (s *service) Bootstrap(username string, password string) error {
+	count, err := s.r.Count()
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return nil
+	}
+	if username == "" || password == "" {
+		return errors.New("there are no users, ADMIN_USERNAME and ADMIN_PASSWORD are required to create the first one")
+	}
+	_, err = s.Create(domain.User{Username: username, Name: username, Password: password, Role: domain.RoleAdmin})
+	return err
+}
+
+// GetAll busca todos los usuarios
+func (s *service) GetAll() ([]domain.User, error) {
+	return s.r.GetAll()
+}
+
+// GetByID busca un usuario por su id
+func (s *service) GetByID(id int) (domain.User, error) {
+	return s.r.GetByID(id)
+}
+
+// GetCurrent busca el usuario de una sesion junto con los permisos de su rol
+func (s *service) GetCurrent(id int) (domain.User, error) {
+	user, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	role, _ := findRole(user.Role)
+	user.Permissions = role.Permissions
+	return user, nil
+}
+
+// Create agrega un usuario activo guardando solo el hash de su contraseña
+func (s *service) Create(user domain.User) (domain.User, error) {
+	user.Username = strings.TrimSpace(user.Username)
+	if user.Username == "" || strings.ContainsAny(user.Username, " \t") {
+		return domain.User{}, errors.New("username can't be empty or have spaces")
+	}
+	if _, ok := findRole(user.Role); !ok {
+		return domain.User{}, errors.New(fmt.Sprintf("invalid role %s, must be one of: %s", user.Role, roleNames()))
+	}
+	err := s.checkDentist(0, user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	passwordHash, err := hashPassword(user.Password)
+	if err != nil {
+		return domain.User{}, err
+	}
+	user.Password = ""
+	user.Active = true
+	return s.r.Create(user, passwordHash)
+}
+
+// Update actualiza el nombre, el email, el rol y el estado de un usuario. Desactivarlo cierra sus sesiones.
+// No se puede quitar el rol ni desactivar al ultimo admin activo, porque nadie podria administrar usuarios.
+func (s *service) Update(id int, user domain.User) (domain.User, error) {
+	if _, ok := findRole(user.Role); !ok {
+		return domain.User{}, errors.New(fmt.Sprintf("invalid role %s, must be one of: %s", user.Role, roleNames()))
+	}
+	current, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.User{}, err
+	}
+	err = s.checkDentist(id, user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if current.Active && current.Role == domain.RoleAdmin && (!user.Active || user.Role != domain.RoleAdmin) {
+		admins, err := s.r.CountActive(domain.RoleAdmin)
+		if err != nil {
+			return domain.User{}, err
+		}
+		if admins <= 1 {
+			return domain.User{}, errors.New(fmt.Sprintf("user %s is the last active admin", current.Username))
+		}
+	}
+	user.Id = id
+	user, err = s.r.Update(user)
+	if err != nil {
+		return domain.User{}, err
+	}
+	if !user.Active {
+		err = s.r.RevokeSessions(id, 0)
+		if err != nil {
+			return domain.User{}, err
+		}
+	}
+	return user, nil
+}
+
+// ChangePassword cambia la contraseña del usuario que inicio la sesion y cierra sus otras sesiones
+func (s *service) ChangePassword(userId int, sessionId int, change domain.PasswordChange) error {
+	user, err := s.r.GetByID(userId)
+	if err != nil {
+		return err
+	}
+	_, passwordHash, err := s.r.GetByUsername(user.Username)
+	if err != nil {
+		return err
+	}
+	if bcrypt.CompareHashAndPassword([]byte(passwordHash), []byte(change.CurrentPassword)) != nil {
+		return errors.New("invalid current_password")
+	}
+	newHash, err := hashPassword(change.NewPassword)
+	if err != nil {
+		return err
+	}
+	err = s.r.UpdatePassword(userId, newHash)
+	if err != nil {
+		return err
+	}
+	return s.r.RevokeSessions(userId, sessionId)
+}
+
+// SetPassword reemplaza la contraseña de otro usuario, que tiene que volver a hacer login
+func (s *service) SetPassword(id int, password string) error {
+	_, err := s.r.GetByID(id)
+	if err != nil {
+		return err
+	}
+	passwordHash, err := hashPassword(password)
+	if err != nil {
+		return err
+	}
+	err = s.r.UpdatePassword(id, passwordHash)
+	if err != nil {
+		return err
+	}
+	return s.r.RevokeSessions(id, 0)
+}
+
+// RevokeSessions cierra todas las sesiones de un usuario
+func (s *service) RevokeSessions(id int) error {
+	_, err := s.r.GetByID(id)
+	if err != nil {
+		return err
+	}
+	return s.r.RevokeSessions(id, 0)
+}
+
+// checkDentist valida el dentista de un usuario: los usuarios con rol dentist tienen que tener uno, y un
+// dentista no puede estar vinculado a dos usuarios porque firmarian sus notas
+func (s *service) checkDentist(userId int, user domain.User) error {
+	if user.DentistId == 0 {
+		if user.Role == domain.RoleDentist {
+			return errors.New("users with role dentist must have a dentist_id")
+		}
+		return nil
+	}
+	_, err := s.r.GetDentist(user.DentistId)
+	if err != nil {
+		return err
+	}
+	linked, err := s.r.GetByDentist(user.DentistId)
+	if err == nil && linked.Id != userId {
+		return errors.New(fmt.Sprintf("dentist %d is already linked to user %s", user.DentistId, linked.Username))
+	}
+	return nil
+}
+
+// tokens emite el access token de una sesion junto con su refresh token
+func (s *service) tokens(user domain.User, sessionId int, refreshToken string, now time.Time) (domain.AuthTokens, error) {
+	accessToken, err := s.jwt.Issue(token.Claims{
+		Subject:   strconv.Itoa(user.Id),
+		SessionId: sessionId,
+		Username:  user.Username,
+		Role:      user.Role,
+		IssuedAt:  now.Unix(),
+		ExpiresAt: now.Add(s.config.AccessTTL).Unix(),
+	})
+	if err != nil {
+		return domain.AuthTokens{}, err
+	}
+	return domain.AuthTokens{
+		AccessToken:      accessToken,
+		TokenType:        "Bearer",
+		ExpiresIn:        int(s.config.AccessTTL.Seconds()),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: now.Add(s.config.RefreshTTL).Format(time.RFC3339),
+	}, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// hashPassword valida el largo de una contraseña y devuelve su hash bcrypt
+func hashPassword(password string) (string, error) {
+	if len(password) < minPasswordLength || len(password) > maxPasswordLength {
+		return "", errors.New(fmt.Sprintf("password must have between %d and %d characters", minPasswordLength, maxPasswordLength))
+	}
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.DefaultCost)
+	if err != nil {
+		return "", err
+	}
+	return string(passwordHash), nil
+}
+
+// roleNames lista los nombres de los roles para los mensajes de error
+func roleNames() string {
+	names := []string{}
+	for _, role := range roles {
+		names = append(names, role.Name)
+	}
+	return strings.Join(names, ", ")
+}
+
+// generateToken genera un refresh token aleatorio
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hash devuelve el sha256 de un refresh token, nunca se guardan en claro
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
@@ -0,0 +1,219 @@
+package auth
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/token"
+	"errors"
+	"testing"
+	"time"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// fakeSession guarda una sesion como la tabla user_session y sus refresh tokens como user_session_token
+type fakeSession struct {
+	userId      int
+	refreshHash string
+	tokens      []string
+	expiresAt   time.Time
+	revoked     bool
+}
+
+// fakeRepository es un AuthRepository en memoria con las mismas reglas de sesiones que el store SQL,
+// los metodos que no usan los tests quedan sin implementar
+type fakeRepository struct {
+	AuthRepository
+	user         domain.User
+	passwordHash string
+	sessions     map[int]*fakeSession
+	// rotateRace simula que otro pedido roto el refresh token entre GetSession y RotateSession
+	rotateRace bool
+}
+
+func newFakeRepository(t *testing.T, user domain.User, password string) *fakeRepository {
+	passwordHash, err := bcrypt.GenerateFromPassword([]byte(password), bcrypt.MinCost)
+	if err != nil {
+		t.Fatalf("hashing password: %s", err)
+	}
+	return &fakeRepository{user: user, passwordHash: string(passwordHash), sessions: map[int]*fakeSession{}}
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.User, error) {
+	if id != r.user.Id {
+		return domain.User{}, errors.New("user not found")
+	}
+	return r.user, nil
+}
+
+func (r *fakeRepository) GetByUsername(username string) (domain.User, string, error) {
+	if username != r.user.Username {
+		return domain.User{}, "", errors.New("user not found")
+	}
+	return r.user, r.passwordHash, nil
+}
+
+func (r *fakeRepository) CreateSession(userId int, refreshHash string, expiresAt time.Time) (int, error) {
+	id := len(r.sessions) + 1
+	r.sessions[id] = &fakeSession{userId: userId, refreshHash: refreshHash, tokens: []string{refreshHash}, expiresAt: expiresAt}
+	return id, nil
+}
+
+func (r *fakeRepository) GetSession(refreshHash string, now time.Time) (domain.UserSession, error) {
+	for id, s := range r.sessions {
+		for _, used := range s.tokens {
+			if used == refreshHash && s.expiresAt.After(now) {
+				return domain.UserSession{Id: id, UserId: s.userId, Rotated: s.refreshHash != refreshHash, Revoked: s.revoked}, nil
+			}
+		}
+	}
+	return domain.UserSession{}, sql.ErrNoRows
+}
+
+func (r *fakeRepository) GetActiveSession(id int, now time.Time) (domain.UserSession, error) {
+	s, ok := r.sessions[id]
+	if !ok || s.revoked || !s.expiresAt.After(now) || !r.user.Active {
+		return domain.UserSession{}, sql.ErrNoRows
+	}
+	return domain.UserSession{Id: id, UserId: s.userId, Role: r.user.Role, DentistId: r.user.DentistId}, nil
+}
+
+func (r *fakeRepository) RotateSession(id int, previousHash string, refreshHash string, expiresAt time.Time) error {
+	s, ok := r.sessions[id]
+	if !ok || s.revoked || s.refreshHash != previousHash || r.rotateRace {
+		return errors.New("session already rotated")
+	}
+	s.refreshHash, s.expiresAt = refreshHash, expiresAt
+	s.tokens = append(s.tokens, refreshHash)
+	return nil
+}
+
+func (r *fakeRepository) RevokeSession(id int) error {
+	if s, ok := r.sessions[id]; ok {
+		s.revoked = true
+	}
+	return nil
+}
+
+func TestRefresh(t *testing.T) {
+	// Cada paso usa el refresh token de un login o refresh anterior, por su indice (0 es el login)
+	type step struct {
+		use int
+		err string
+	}
+	tests := []struct {
+		name    string
+		steps   []step
+		revoked bool
+	}{
+		{"rotates on every use", []step{{0, ""}, {1, ""}, {2, ""}}, false},
+		{"reused token revokes the session", []step{{0, ""}, {0, "refresh token already used, session revoked"}}, true},
+		{"current token fails after a reuse", []step{{0, ""}, {0, "refresh token already used, session revoked"}, {1, "session revoked"}}, true},
+		{"first token reused after two rotations", []step{{0, ""}, {1, ""}, {0, "refresh token already used, session revoked"}}, true},
+		{"any old token reused after many rotations", []step{{0, ""}, {1, ""}, {2, ""}, {3, ""}, {1, "refresh token already used, session revoked"}}, true},
+		{"previous token reused after two rotations", []step{{0, ""}, {1, ""}, {1, "refresh token already used, session revoked"}}, true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRepository(t, domain.User{Id: 1, Username: "ana", Role: domain.RoleAdmin, Active: true}, "password123")
+			s := NewAuthService(r, token.NewJWT("secret"), Config{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+			login, err := s.Login("ana", "password123")
+			if err != nil {
+				t.Fatalf("login: %s", err)
+			}
+			issued := []domain.AuthTokens{login}
+			for i, st := range tt.steps {
+				tokens, err := s.Refresh(issued[st.use].RefreshToken)
+				if st.err != "" {
+					if err == nil || err.Error() != st.err {
+						t.Fatalf("step %d: expected error %q, got %v", i+1, st.err, err)
+					}
+					continue
+				}
+				if err != nil {
+					t.Fatalf("step %d: unexpected error: %s", i+1, err)
+				}
+				if tokens.RefreshToken == issued[st.use].RefreshToken {
+					t.Fatalf("step %d: refresh token wasn't rotated", i+1)
+				}
+				issued = append(issued, tokens)
+			}
+			_, err = s.ValidateToken(issued[len(issued)-1].AccessToken)
+			if tt.revoked && err == nil {
+				t.Fatal("expected the access token of the revoked session to be rejected")
+			}
+			if !tt.revoked && err != nil {
+				t.Fatalf("expected the access token to be valid, got %s", err)
+			}
+		})
+	}
+}
+
+func TestRefreshRejects(t *testing.T) {
+	tests := []struct {
+		name    string
+		modify  func(r *fakeRepository)
+		token   func(login domain.AuthTokens) string
+		err     string
+		revoked bool
+	}{
+		{"unknown token", nil, func(domain.AuthTokens) string { return "unknown" }, "sql: no rows in result set", false},
+		{"expired session", func(r *fakeRepository) { r.sessions[1].expiresAt = time.Now().Add(-time.Minute) }, nil, "sql: no rows in result set", false},
+		{"revoked session", func(r *fakeRepository) { r.sessions[1].revoked = true }, nil, "session revoked", true},
+		{"disabled user", func(r *fakeRepository) { r.user.Active = false }, nil, "user ana is disabled", false},
+		{"token rotated at the same time by another request", func(r *fakeRepository) { r.rotateRace = true }, nil, "session already rotated", true},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRepository(t, domain.User{Id: 1, Username: "ana", Role: domain.RoleAdmin, Active: true}, "password123")
+			s := NewAuthService(r, token.NewJWT("secret"), Config{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+			login, err := s.Login("ana", "password123")
+			if err != nil {
+				t.Fatalf("login: %s", err)
+			}
+			if tt.modify != nil {
+				tt.modify(r)
+			}
+			refreshToken := login.RefreshToken
+			if tt.token != nil {
+				refreshToken = tt.token(login)
+			}
+			_, err = s.Refresh(refreshToken)
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("expected error %q, got %v", tt.err, err)
+			}
+			if r.sessions[1].revoked != tt.revoked {
+				t.Fatalf("expected session revoked %v, got %v", tt.revoked, r.sessions[1].revoked)
+			}
+		})
+	}
+}
+
+func TestValidateToken(t *testing.T) {
+	r := newFakeRepository(t, domain.User{Id: 1, Username: "ana", Role: domain.RoleDentist, DentistId: 4, Active: true}, "password123")
+	s := NewAuthService(r, token.NewJWT("secret"), Config{AccessTTL: time.Minute, RefreshTTL: time.Hour})
+	login, err := s.Login("ana", "password123")
+	if err != nil {
+		t.Fatalf("login: %s", err)
+	}
+	// El rol vigente del usuario reemplaza al del token
+	r.user.Role = domain.RoleReceptionist
+	claims, err := s.ValidateToken(login.AccessToken)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if claims.UserId() != 1 || claims.Role != domain.RoleReceptionist || claims.DentistId != 4 {
+		t.Fatalf("unexpected claims %+v", claims)
+	}
+	err = s.Logout(claims.SessionId)
+	if err != nil {
+		t.Fatalf("logout: %s", err)
+	}
+	if _, err = s.ValidateToken(login.AccessToken); err == nil {
+		t.Fatal("expected the access token of a closed session to be rejected")
+	}
+	forged, _ := token.NewJWT("other").Issue(token.Claims{Subject: "1", SessionId: 1, ExpiresAt: time.Now().Add(time.Minute).Unix()})
+	if _, err = s.ValidateToken(forged); err == nil || err.Error() != "invalid token" {
+		t.Fatalf("expected invalid token, got %v", err)
+	}
+}
@@ -0,0 +1,86 @@
+package auth
+
+import "dental_clinic_go/internal/domain"
+
+// Permisos que se exigen por ruta en main.go, con el formato recurso:accion
+const (
+	UsersManage           = "users:manage"
+	DentistsWrite         = "dentists:write"
+	DentistsDelete        = "dentists:delete"
+	ProceduresWrite       = "procedures:write"
+	PricingRead           = "pricing:read"
+	PricingWrite          = "pricing:write"
+	PatientsRead          = "patients:read"
+	PatientsWrite         = "patients:write"
+	PatientsDelete        = "patients:delete"
+	AppointmentsRead      = "appointments:read"
+	AppointmentsWrite     = "appointments:write"
+	AppointmentsDelete    = "appointments:delete"
+	ClinicalRead          = "clinical:read"
+	ClinicalWrite         = "clinical:write"
+	ClinicalSign          = "clinical:sign"
+	PrescriptionsWrite    = "prescriptions:write"
+	TreatmentWrite        = "treatment:write"
+	ConsentsRead          = "consents:read"
+	ConsentsWrite         = "consents:write"
+	ConsentTemplatesWrite = "consent-templates:write"
+	BillingRead           = "billing:read"
+	BillingWrite          = "billing:write"
+	PaymentsWrite         = "payments:write"
+	InsuranceRead         = "insurance:read"
+	InsuranceWrite        = "insurance:write"
+	CommissionsRead       = "commissions:read"
+	CommissionsWrite      = "commissions:write"
+)
+
+// roles es la matriz de permisos, en el orden en que se listan los roles. El admin tiene todos.
+var roles = []domain.Role{
+	{Name: domain.RoleAdmin, Permissions: []string{
+		UsersManage, DentistsWrite, DentistsDelete, ProceduresWrite, PricingRead, PricingWrite,
+		PatientsRead, PatientsWrite, PatientsDelete, AppointmentsRead, AppointmentsWrite, AppointmentsDelete,
+		ClinicalRead, ClinicalWrite, ClinicalSign, PrescriptionsWrite, TreatmentWrite,
+		ConsentsRead, ConsentsWrite, ConsentTemplatesWrite, BillingRead, BillingWrite, PaymentsWrite,
+		InsuranceRead, InsuranceWrite, CommissionsRead, CommissionsWrite,
+	}},
+	{Name: domain.RoleDentist, Permissions: []string{
+		PricingRead, PatientsRead, PatientsWrite, AppointmentsRead, AppointmentsWrite,
+		ClinicalRead, ClinicalWrite, ClinicalSign, PrescriptionsWrite, TreatmentWrite,
+		ConsentsRead, ConsentsWrite, InsuranceRead,
+	}},
+	{Name: domain.RoleHygienist, Permissions: []string{
+		PricingRead, PatientsRead, PatientsWrite, AppointmentsRead, AppointmentsWrite,
+		ClinicalRead, ClinicalWrite, ConsentsRead, ConsentsWrite,
+	}},
+	{Name: domain.RoleReceptionist, Permissions: []string{
+		PricingRead, PatientsRead, PatientsWrite, AppointmentsRead, AppointmentsWrite, AppointmentsDelete,
+		ConsentsRead, ConsentsWrite, BillingRead, PaymentsWrite, InsuranceRead,
+	}},
+	{Name: domain.RoleBilling, Permissions: []string{
+		PricingRead, PricingWrite, PatientsRead, AppointmentsRead, BillingRead, BillingWrite, PaymentsWrite,
+		InsuranceRead, InsuranceWrite, CommissionsRead,
+	}},
+}
+
+// findRole busca un rol de la matriz por su nombre
+func findRole(name string) (domain.Role, bool) {
+	for _, role := range roles {
+		if role.Name == name {
+			return role, true
+		}
+	}
+	return domain.Role{}, false
+}
+
+// allowed indica si un rol tiene un permiso, un rol desconocido no tiene ninguno
+func allowed(roleName string, permission string) bool {
+	role, ok := findRole(roleName)
+	if !ok {
+		return false
+	}
+	for _, p := range role.Permissions {
+		if p == permission {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,74 @@
+package auth
+
+import (
+	"dental_clinic_go/internal/domain"
+	"testing"
+)
+
+func TestAllowed(t *testing.T) {
+	tests := []struct {
+		role       string
+		permission string
+		want       bool
+	}{
+		{domain.RoleAdmin, UsersManage, true},
+		{domain.RoleAdmin, CommissionsWrite, true},
+		{domain.RoleDentist, ClinicalSign, true},
+		{domain.RoleDentist, PrescriptionsWrite, true},
+		{domain.RoleDentist, BillingRead, false},
+		{domain.RoleDentist, UsersManage, false},
+		{domain.RoleHygienist, ClinicalWrite, true},
+		{domain.RoleHygienist, ClinicalSign, false},
+		{domain.RoleHygienist, PrescriptionsWrite, false},
+		{domain.RoleHygienist, AppointmentsDelete, false},
+		{domain.RoleReceptionist, AppointmentsDelete, true},
+		{domain.RoleReceptionist, PaymentsWrite, true},
+		{domain.RoleReceptionist, ClinicalRead, false},
+		{domain.RoleReceptionist, BillingWrite, false},
+		{domain.RoleBilling, BillingWrite, true},
+		{domain.RoleBilling, CommissionsRead, true},
+		{domain.RoleBilling, CommissionsWrite, false},
+		{domain.RoleBilling, ClinicalRead, false},
+		{domain.RoleBilling, PatientsWrite, false},
+		{"", PatientsRead, false},
+		{"superuser", PatientsRead, false},
+		{domain.RoleAdmin, "patients:everything", false},
+	}
+	for _, tt := range tests {
+		if got := allowed(tt.role, tt.permission); got != tt.want {
+			t.Errorf("allowed(%q, %q): expected %t, got %t", tt.role, tt.permission, tt.want, got)
+		}
+	}
+}
+
+func TestRoles(t *testing.T) {
+	admin, ok := findRole(domain.RoleAdmin)
+	if !ok {
+		t.Fatal("admin role not found")
+	}
+	granted := map[string]bool{}
+	for _, p := range admin.Permissions {
+		if granted[p] {
+			t.Errorf("admin has permission %s twice", p)
+		}
+		granted[p] = true
+	}
+	for _, role := range roles {
+		seen := map[string]bool{}
+		for _, p := range role.Permissions {
+			if seen[p] {
+				t.Errorf("role %s has permission %s twice", role.Name, p)
+			}
+			seen[p] = true
+			// El admin tiene todos los permisos que otorga cualquier otro rol
+			if !granted[p] {
+				t.Errorf("role %s has permission %s that admin doesn't have", role.Name, p)
+			}
+		}
+	}
+	for _, name := range []string{domain.RoleAdmin, domain.RoleDentist, domain.RoleHygienist, domain.RoleReceptionist, domain.RoleBilling} {
+		if _, ok := findRole(name); !ok {
+			t.Errorf("role %s is missing from the matrix", name)
+		}
+	}
+}
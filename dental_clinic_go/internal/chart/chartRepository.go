@@ -0,0 +1,56 @@
+package chart
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type ChartRepository interface {
+	GetByPatient(patientId int) ([]domain.ChartEntry, error)
+	Create(entry domain.ChartEntry) (domain.ChartEntry, error)
+}
+
+type chartRepository struct {
+	storage      store.ChartStore
+	patientStore store.PatientStore
+	dentistStore store.DentistStore
+}
+
+// NewChartRepository crea un nuevo repositorio
+func NewChartRepository(storage store.ChartStore, patientStore store.PatientStore,
+	dentistStore store.DentistStore) ChartRepository {
+	return &chartRepository{storage, patientStore, dentistStore}
+}
+
+// GetByPatient busca el historial del odontograma de un paciente
+func (r *chartRepository) GetByPatient(patientId int) ([]domain.ChartEntry, error) {
+	_, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return []domain.ChartEntry{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	entries, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.ChartEntry{}, errors.New(fmt.Sprintf("chart of patient %d not found", patientId))
+	}
+	return entries, nil
+}
+
+// Create agrega una entrada al odontograma
+func (r *chartRepository) Create(entry domain.ChartEntry) (domain.ChartEntry, error) {
+	_, err := r.patientStore.GetByID(entry.PatientId)
+	if err != nil {
+		return domain.ChartEntry{}, errors.New(fmt.Sprintf("patient %d not found", entry.PatientId))
+	}
+	dentist, err := r.dentistStore.GetByID(entry.Dentist.Id)
+	if err != nil {
+		return domain.ChartEntry{}, errors.New(fmt.Sprintf("dentist %d not found", entry.Dentist.Id))
+	}
+	entry.Dentist = dentist
+	e, err := r.storage.Create(entry)
+	if err != nil {
+		return domain.ChartEntry{}, errors.New("error creating chart entry")
+	}
+	return e, nil
+}
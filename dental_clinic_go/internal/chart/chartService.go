@@ -0,0 +1,148 @@
+package chart
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/procedure"
+	"dental_clinic_go/pkg/fdi"
+	"errors"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// wholeToothConditions son las condiciones que se registran sobre la pieza completa
+var wholeToothConditions = map[string]bool{
+	domain.ConditionMissing: true,
+	domain.ConditionImplant: true,
+}
+
+// surfaceConditions son las condiciones que necesitan indicar las caras afectadas
+var surfaceConditions = map[string]bool{
+	domain.ConditionCaries:  true,
+	domain.ConditionFilling: true,
+}
+
+type Service interface {
+	GetChart(patientId int) (domain.Chart, error)
+	GetHistory(patientId int, tooth int) ([]domain.ChartEntry, error)
+	Create(entry domain.ChartEntry) (domain.ChartEntry, error)
+}
+
+type service struct {
+	r ChartRepository
+	p procedure.Service
+}
+
+// NewChartService crea un nuevo servicio
+func NewChartService(r ChartRepository, p procedure.Service) Service {
+	return &service{r, p}
+}
+
+// GetChart devuelve el estado actual de cada pieza con condiciones registradas
+func (s *service) GetChart(patientId int) (domain.Chart, error) {
+	entries, err := s.r.GetByPatient(patientId)
+	if err != nil {
+		return domain.Chart{}, err
+	}
+	// teeth guarda por pieza la ultima condicion de cada cara, "" es la pieza completa
+	teeth := map[int]map[string]domain.ChartEntry{}
+	for _, e := range entries {
+		state, ok := teeth[e.Tooth]
+		if !ok {
+			state = map[string]domain.ChartEntry{}
+			teeth[e.Tooth] = state
+		}
+		if e.Surfaces == "" {
+			for key := range state {
+				delete(state, key)
+			}
+			if e.Condition != domain.ConditionHealthy {
+				state[""] = e
+			}
+			continue
+		}
+		for _, surface := range e.Surfaces {
+			if e.Condition == domain.ConditionHealthy {
+				delete(state, string(surface))
+			} else {
+				state[string(surface)] = e
+			}
+		}
+	}
+	chart := domain.Chart{PatientId: patientId, Teeth: []domain.ToothChart{}}
+	for tooth, state := range teeth {
+		if len(state) == 0 {
+			continue
+		}
+		toothChart := domain.ToothChart{Tooth: tooth, Dentition: fdi.Dentition(tooth)}
+		for surface, e := range state {
+			toothChart.Conditions = append(toothChart.Conditions, domain.ToothCondition{
+				Surface:   surface,
+				Condition: e.Condition,
+				Date:      e.Date,
+				EntryId:   e.Id,
+			})
+		}
+		sort.Slice(toothChart.Conditions, func(i, j int) bool {
+			return strings.Index(fdi.Surfaces, toothChart.Conditions[i].Surface) < strings.Index(fdi.Surfaces, toothChart.Conditions[j].Surface)
+		})
+		chart.Teeth = append(chart.Teeth, toothChart)
+	}
+	sort.Slice(chart.Teeth, func(i, j int) bool {
+		return chart.Teeth[i].Tooth < chart.Teeth[j].Tooth
+	})
+	return chart, nil
+}
+
+// GetHistory devuelve todas las entradas del odontograma, si tooth no es 0 solo las de esa pieza
+func (s *service) GetHistory(patientId int, tooth int) ([]domain.ChartEntry, error) {
+	entries, err := s.r.GetByPatient(patientId)
+	if err != nil {
+		return []domain.ChartEntry{}, err
+	}
+	if tooth == 0 {
+		return entries, nil
+	}
+	filtered := []domain.ChartEntry{}
+	for _, e := range entries {
+		if e.Tooth == tooth {
+			filtered = append(filtered, e)
+		}
+	}
+	return filtered, nil
+}
+
+// Create valida y agrega una entrada al odontograma, las entradas nunca se modifican
+// asi que para corregir una condicion se registra una nueva
+func (s *service) Create(entry domain.ChartEntry) (domain.ChartEntry, error) {
+	if !fdi.ValidTooth(entry.Tooth) {
+		return domain.ChartEntry{}, errors.New(fmt.Sprintf("invalid tooth %d, must be a FDI tooth number", entry.Tooth))
+	}
+	surfaces, err := fdi.NormalizeSurfaces(entry.Surfaces)
+	if err != nil {
+		return domain.ChartEntry{}, err
+	}
+	entry.Surfaces = surfaces
+	switch {
+	case entry.Condition != domain.ConditionHealthy && entry.Condition != domain.ConditionCrown &&
+		!wholeToothConditions[entry.Condition] && !surfaceConditions[entry.Condition]:
+		return domain.ChartEntry{}, errors.New("invalid condition, must be one of: healthy, caries, filling, crown, missing, implant")
+	case wholeToothConditions[entry.Condition] && entry.Surfaces != "":
+		return domain.ChartEntry{}, errors.New(fmt.Sprintf("%s applies to the whole tooth, surfaces must be empty", entry.Condition))
+	case surfaceConditions[entry.Condition] && entry.Surfaces == "":
+		return domain.ChartEntry{}, errors.New(fmt.Sprintf("%s needs at least one surface", entry.Condition))
+	}
+	if entry.ProcedureCode != "" {
+		if _, err := s.p.Validate(entry.ProcedureCode, entry.Tooth, entry.Surfaces); err != nil {
+			return domain.ChartEntry{}, err
+		}
+	}
+	if entry.Date == "" {
+		entry.Date = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", entry.Date); err != nil {
+		return domain.ChartEntry{}, errors.New("invalid date, must be in format: yyyy-mm-dd")
+	}
+	return s.r.Create(entry)
+}
@@ -0,0 +1,96 @@
+package chart
+
+import (
+	"dental_clinic_go/internal/domain"
+	"testing"
+)
+
+// fakeRepository es un ChartRepository en memoria
+type fakeRepository struct {
+	entries []domain.ChartEntry
+}
+
+func (r *fakeRepository) GetByPatient(patientId int) ([]domain.ChartEntry, error) {
+	return r.entries, nil
+}
+
+func (r *fakeRepository) Create(entry domain.ChartEntry) (domain.ChartEntry, error) {
+	entry.Id = len(r.entries) + 1
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name     string
+		entry    domain.ChartEntry
+		surfaces string
+		err      string
+	}{
+		{name: "caries on surfaces", entry: domain.ChartEntry{Tooth: 16, Surfaces: "do", Condition: domain.ConditionCaries}, surfaces: "OD"},
+		{name: "missing deciduous tooth", entry: domain.ChartEntry{Tooth: 75, Condition: domain.ConditionMissing}},
+		{name: "invalid tooth", entry: domain.ChartEntry{Tooth: 19, Condition: domain.ConditionMissing}, err: "invalid tooth 19, must be a FDI tooth number"},
+		{name: "deciduous tooth out of range", entry: domain.ChartEntry{Tooth: 57, Condition: domain.ConditionMissing}, err: "invalid tooth 57, must be a FDI tooth number"},
+		{name: "invalid surface", entry: domain.ChartEntry{Tooth: 16, Surfaces: "X", Condition: domain.ConditionCaries}, err: "invalid surfaces, must be any of: M, O, D, B, L"},
+		{name: "invalid condition", entry: domain.ChartEntry{Tooth: 16, Condition: "broken"}, err: "invalid condition, must be one of: healthy, caries, filling, crown, missing, implant"},
+		{name: "whole tooth condition with surfaces", entry: domain.ChartEntry{Tooth: 16, Surfaces: "O", Condition: domain.ConditionImplant}, err: "implant applies to the whole tooth, surfaces must be empty"},
+		{name: "surface condition without surfaces", entry: domain.ChartEntry{Tooth: 16, Condition: domain.ConditionFilling}, err: "filling needs at least one surface"},
+		{name: "invalid date", entry: domain.ChartEntry{Tooth: 16, Condition: domain.ConditionCrown, Date: "19/10/2026"}, err: "invalid date, must be in format: yyyy-mm-dd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := NewChartService(&fakeRepository{}, nil).Create(tt.entry)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if entry.Surfaces != tt.surfaces || entry.Date == "" {
+				t.Fatalf("unexpected entry %+v", entry)
+			}
+		})
+	}
+}
+
+func TestGetChart(t *testing.T) {
+	r := &fakeRepository{entries: []domain.ChartEntry{
+		{Id: 1, Tooth: 16, Surfaces: "OD", Condition: domain.ConditionCaries},
+		{Id: 2, Tooth: 16, Surfaces: "O", Condition: domain.ConditionFilling},
+		{Id: 3, Tooth: 21, Condition: domain.ConditionCrown},
+		{Id: 4, Tooth: 21, Condition: domain.ConditionHealthy},
+		{Id: 5, Tooth: 36, Surfaces: "M", Condition: domain.ConditionCaries},
+		{Id: 6, Tooth: 36, Condition: domain.ConditionMissing},
+		{Id: 7, Tooth: 55, Surfaces: "B", Condition: domain.ConditionCaries},
+	}}
+	chart, err := NewChartService(r, nil).GetChart(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// 21 volvio a estar sana, en 36 la pieza completa reemplaza a las caras y en 16 la obturacion a la caries oclusal
+	want := []domain.ToothChart{
+		{Tooth: 16, Dentition: domain.DentitionPermanent, Conditions: []domain.ToothCondition{
+			{Surface: "O", Condition: domain.ConditionFilling, EntryId: 2},
+			{Surface: "D", Condition: domain.ConditionCaries, EntryId: 1},
+		}},
+		{Tooth: 36, Dentition: domain.DentitionPermanent, Conditions: []domain.ToothCondition{{Condition: domain.ConditionMissing, EntryId: 6}}},
+		{Tooth: 55, Dentition: domain.DentitionDeciduous, Conditions: []domain.ToothCondition{{Surface: "B", Condition: domain.ConditionCaries, EntryId: 7}}},
+	}
+	if len(chart.Teeth) != len(want) {
+		t.Fatalf("expected %d teeth, got %+v", len(want), chart.Teeth)
+	}
+	for i, tooth := range want {
+		got := chart.Teeth[i]
+		if got.Tooth != tooth.Tooth || got.Dentition != tooth.Dentition || len(got.Conditions) != len(tooth.Conditions) {
+			t.Fatalf("expected %+v, got %+v", tooth, got)
+		}
+		for j, c := range tooth.Conditions {
+			if got.Conditions[j] != c {
+				t.Fatalf("tooth %d: expected %+v, got %+v", tooth.Tooth, c, got.Conditions[j])
+			}
+		}
+	}
+}
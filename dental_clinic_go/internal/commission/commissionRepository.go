@@ -0,0 +1,76 @@
+package commission
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type CommissionRepository interface {
+	GetPlan(dentistId int) (domain.CommissionPlan, error)
+	SavePlan(plan domain.CommissionPlan) (domain.CommissionPlan, error)
+	GetCompleted(from string, to string) ([]domain.DentistProduction, error)
+	GetBilled(from string, to string) ([]domain.BilledItem, error)
+}
+
+type commissionRepository struct {
+	storage        store.CommissionStore
+	dentistStore   store.DentistStore
+	procedureStore store.ProcedureStore
+}
+
+// NewCommissionRepository crea un nuevo repositorio
+func NewCommissionRepository(storage store.CommissionStore, dentistStore store.DentistStore, procedureStore store.ProcedureStore) CommissionRepository {
+	return &commissionRepository{storage, dentistStore, procedureStore}
+}
+
+// GetPlan busca las reglas de comision de un dentista verificando que exista
+func (r *commissionRepository) GetPlan(dentistId int) (domain.CommissionPlan, error) {
+	_, err := r.dentistStore.GetByID(dentistId)
+	if err != nil {
+		return domain.CommissionPlan{}, errors.New(fmt.Sprintf("dentist %d not found", dentistId))
+	}
+	plan, err := r.storage.GetPlan(dentistId)
+	if err != nil {
+		return domain.CommissionPlan{}, errors.New(fmt.Sprintf("error getting commission of dentist %d", dentistId))
+	}
+	return plan, nil
+}
+
+// SavePlan reemplaza las reglas de comision de un dentista verificando que existan los procedimientos
+func (r *commissionRepository) SavePlan(plan domain.CommissionPlan) (domain.CommissionPlan, error) {
+	_, err := r.dentistStore.GetByID(plan.DentistId)
+	if err != nil {
+		return domain.CommissionPlan{}, errors.New(fmt.Sprintf("dentist %d not found", plan.DentistId))
+	}
+	for _, rule := range plan.Rules {
+		_, err := r.procedureStore.GetByCode(rule.ProcedureCode)
+		if err != nil {
+			return domain.CommissionPlan{}, errors.New(fmt.Sprintf("procedure %s not found", rule.ProcedureCode))
+		}
+	}
+	err = r.storage.SavePlan(plan)
+	if err != nil {
+		return domain.CommissionPlan{}, errors.New(fmt.Sprintf("error saving commission of dentist %d", plan.DentistId))
+	}
+	return r.GetPlan(plan.DentistId)
+}
+
+// GetCompleted busca los turnos completados por dentista en un periodo
+func (r *commissionRepository) GetCompleted(from string, to string) ([]domain.DentistProduction, error) {
+	dentists, err := r.storage.GetCompleted(from, to)
+	if err != nil {
+		return []domain.DentistProduction{}, errors.New("error getting completed appointments")
+	}
+	return dentists, nil
+}
+
+// GetBilled busca lo facturado por los turnos completados en un periodo
+func (r *commissionRepository) GetBilled(from string, to string) ([]domain.BilledItem, error) {
+	items, err := r.storage.GetBilled(from, to)
+	if err != nil {
+		return []domain.BilledItem{}, errors.New("error getting billed procedures")
+	}
+	return items, nil
+}
@@ -0,0 +1,222 @@
+package commission
+
+import (
+	"bytes"
+	"dental_clinic_go/internal/domain"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"math"
+	"strconv"
+	"time"
+)
+
+// csvHeader son las columnas del reporte exportado, una fila por dentista y procedimiento
+var csvHeader = []string{"from", "to", "dentist_id", "license", "last_name", "name", "completed_appointments", "billed_appointments",
+	"procedure_code", "description", "quantity", "amount", "percent", "commission"}
+
+type Service interface {
+	GetPlan(dentistId int) (domain.CommissionPlan, error)
+	SavePlan(dentistId int, plan domain.CommissionPlan) (domain.CommissionPlan, error)
+	Report(from string, to string, dentistId int) (domain.ProductionReport, error)
+	Export(from string, to string, dentistId int) (domain.Document, error)
+}
+
+type service struct {
+	r CommissionRepository
+}
+
+// NewCommissionService crea un nuevo servicio
+func NewCommissionService(r CommissionRepository) Service {
+	return &service{r}
+}
+
+// GetPlan busca las reglas de comision de un dentista
+func (s *service) GetPlan(dentistId int) (domain.CommissionPlan, error) {
+	return s.r.GetPlan(dentistId)
+}
+
+// SavePlan reemplaza el porcentaje por defecto y las reglas por procedimiento de un dentista
+func (s *service) SavePlan(dentistId int, plan domain.CommissionPlan) (domain.CommissionPlan, error) {
+	plan.DentistId = dentistId
+	if plan.DefaultPercent < 0 || plan.DefaultPercent > 100 {
+		return domain.CommissionPlan{}, errors.New("default_percent must be between 0 and 100")
+	}
+	if plan.Rules == nil {
+		plan.Rules = []domain.CommissionRule{}
+	}
+	seen := map[string]bool{}
+	for i, rule := range plan.Rules {
+		switch {
+		case rule.ProcedureCode == "":
+			return domain.CommissionPlan{}, errors.New(fmt.Sprintf("invalid rule %d: procedure_code can't be empty", i+1))
+		case seen[rule.ProcedureCode]:
+			return domain.CommissionPlan{}, errors.New(fmt.Sprintf("procedure %s is repeated", rule.ProcedureCode))
+		case rule.Percent < 0 || rule.Percent > 100:
+			return domain.CommissionPlan{}, errors.New(fmt.Sprintf("invalid rule %d: percent must be between 0 and 100", i+1))
+		}
+		seen[rule.ProcedureCode] = true
+	}
+	return s.r.SavePlan(plan)
+}
+
+// Report calcula por dentista los turnos completados, lo facturado por esos turnos y la comision que
+// le corresponde entre dos fechas. Sin fechas calcula el mes en curso, dentistId 0 incluye a todos.
+func (s *service) Report(from string, to string, dentistId int) (domain.ProductionReport, error) {
+	from, to, err := period(from, to)
+	if err != nil {
+		return domain.ProductionReport{}, err
+	}
+	completed, err := s.r.GetCompleted(from, to)
+	if err != nil {
+		return domain.ProductionReport{}, err
+	}
+	items, err := s.r.GetBilled(from, to)
+	if err != nil {
+		return domain.ProductionReport{}, err
+	}
+
+	report := domain.ProductionReport{From: from, To: to, Dentists: []domain.DentistProduction{}}
+	positions := map[int]int{}
+	for _, d := range completed {
+		if dentistId != 0 && d.DentistId != dentistId {
+			continue
+		}
+		d.Procedures = []domain.ProcedureProduction{}
+		positions[d.DentistId] = len(report.Dentists)
+		report.Dentists = append(report.Dentists, d)
+	}
+
+	plans := map[int]domain.CommissionPlan{}
+	billed := map[int]bool{}
+	for _, item := range items {
+		n, ok := positions[item.Dentist.Id]
+		if !ok {
+			continue
+		}
+		plan, ok := plans[item.Dentist.Id]
+		if !ok {
+			plan, err = s.r.GetPlan(item.Dentist.Id)
+			if err != nil {
+				return domain.ProductionReport{}, err
+			}
+			plans[item.Dentist.Id] = plan
+		}
+		d := &report.Dentists[n]
+		if !billed[item.AppointmentId] {
+			billed[item.AppointmentId] = true
+			d.BilledAppointments++
+		}
+		p := findProcedure(d, item)
+		p.Quantity += item.Quantity
+		p.Amount = round(p.Amount + item.Amount)
+		p.Percent = percent(plan, item.ProcedureCode)
+	}
+
+	for n := range report.Dentists {
+		d := &report.Dentists[n]
+		for i := range d.Procedures {
+			p := &d.Procedures[i]
+			p.Commission = round(p.Amount * p.Percent / 100)
+			d.Production = round(d.Production + p.Amount)
+			d.Commission = round(d.Commission + p.Commission)
+		}
+		report.Production = round(report.Production + d.Production)
+		report.Commission = round(report.Commission + d.Commission)
+	}
+	return report, nil
+}
+
+// Export genera el reporte en csv con una fila por dentista y procedimiento, los dentistas sin
+// facturacion en el periodo tienen una fila sin procedimiento
+func (s *service) Export(from string, to string, dentistId int) (domain.Document, error) {
+	report, err := s.Report(from, to, dentistId)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	err = w.Write(csvHeader)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	for _, d := range report.Dentists {
+		dentist := []string{report.From, report.To, strconv.Itoa(d.DentistId), d.License, d.LastName, d.Name,
+			strconv.Itoa(d.CompletedAppointments), strconv.Itoa(d.BilledAppointments)}
+		procedures := d.Procedures
+		if len(procedures) == 0 {
+			procedures = []domain.ProcedureProduction{{}}
+		}
+		for _, p := range procedures {
+			err := w.Write(append(dentist, p.ProcedureCode, p.Description, strconv.Itoa(p.Quantity),
+				formatAmount(p.Amount), formatAmount(p.Percent), formatAmount(p.Commission)))
+			if err != nil {
+				return domain.Document{}, err
+			}
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return domain.Document{}, err
+	}
+	return domain.Document{
+		Name:        fmt.Sprintf("produccion-%s-%s.csv", report.From, report.To),
+		ContentType: "text/csv; charset=utf-8",
+		Content:     buf.Bytes(),
+	}, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// period valida las fechas del reporte, sin from empieza el primer dia del mes de to y sin to termina hoy
+func period(from string, to string) (string, string, error) {
+	if to == "" {
+		to = time.Now().Format("2006-01-02")
+	}
+	end, err := time.Parse("2006-01-02", to)
+	if err != nil {
+		return "", "", errors.New("invalid to, must be in format: yyyy-mm-dd")
+	}
+	if from == "" {
+		from = end.Format("2006-01") + "-01"
+	}
+	if _, err := time.Parse("2006-01-02", from); err != nil {
+		return "", "", errors.New("invalid from, must be in format: yyyy-mm-dd")
+	}
+	if from > to {
+		return "", "", errors.New("from can't be after to")
+	}
+	return from, to, nil
+}
+
+// findProcedure busca la fila del procedimiento de un item en la produccion del dentista y la agrega si
+// no existe. Los items sin codigo se agrupan por descripcion.
+func findProcedure(d *domain.DentistProduction, item domain.BilledItem) *domain.ProcedureProduction {
+	for i, p := range d.Procedures {
+		if p.ProcedureCode == item.ProcedureCode && (item.ProcedureCode != "" || p.Description == item.Description) {
+			return &d.Procedures[i]
+		}
+	}
+	d.Procedures = append(d.Procedures, domain.ProcedureProduction{ProcedureCode: item.ProcedureCode, Description: item.Description})
+	return &d.Procedures[len(d.Procedures)-1]
+}
+
+// percent devuelve la comision de un procedimiento, la de su regla o la por defecto del dentista
+func percent(plan domain.CommissionPlan, procedureCode string) float64 {
+	for _, rule := range plan.Rules {
+		if rule.ProcedureCode == procedureCode {
+			return rule.Percent
+		}
+	}
+	return plan.DefaultPercent
+}
+
+// formatAmount escribe un importe con dos decimales
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
+
+// round redondea un monto a centavos
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
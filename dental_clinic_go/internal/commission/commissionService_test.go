@@ -0,0 +1,171 @@
+package commission
+
+import (
+	"bytes"
+	"dental_clinic_go/internal/domain"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+// fakeRepository tiene la produccion de mayo: la dentista 1 cobra 30% y 50% de ortodoncia, el dentista 2
+// cobra 40% y la dentista 3 completo turnos que todavia no se facturaron
+type fakeRepository struct {
+	plans map[int]domain.CommissionPlan
+}
+
+func (r *fakeRepository) GetPlan(dentistId int) (domain.CommissionPlan, error) {
+	return r.plans[dentistId], nil
+}
+
+func (r *fakeRepository) SavePlan(plan domain.CommissionPlan) (domain.CommissionPlan, error) {
+	r.plans[plan.DentistId] = plan
+	return plan, nil
+}
+
+func (r *fakeRepository) GetCompleted(from string, to string) ([]domain.DentistProduction, error) {
+	return []domain.DentistProduction{
+		{DentistId: 1, Name: "Ana", LastName: "Lopez", License: "MP-1", CompletedAppointments: 3},
+		{DentistId: 2, Name: "Luis", LastName: "Diaz", License: "MP-2", CompletedAppointments: 1},
+		{DentistId: 3, Name: "Eva", LastName: "Ruiz", License: "MP-3", CompletedAppointments: 2},
+	}, nil
+}
+
+func (r *fakeRepository) GetBilled(from string, to string) ([]domain.BilledItem, error) {
+	return []domain.BilledItem{
+		{Dentist: domain.Dentist{Id: 1}, AppointmentId: 1, ProcedureCode: "CON01", Description: "Consulta", Quantity: 1, Amount: 1000},
+		{Dentist: domain.Dentist{Id: 1}, AppointmentId: 1, ProcedureCode: "ORT01", Description: "Brackets", Quantity: 1, Amount: 2000},
+		{Dentist: domain.Dentist{Id: 1}, AppointmentId: 2, ProcedureCode: "CON01", Description: "Consulta", Quantity: 1, Amount: 1000},
+		{Dentist: domain.Dentist{Id: 1}, AppointmentId: 3, Description: "Material", Quantity: 2, Amount: 333.33},
+		{Dentist: domain.Dentist{Id: 2}, AppointmentId: 4, ProcedureCode: "CON01", Description: "Consulta", Quantity: 1, Amount: 1500},
+		{Dentist: domain.Dentist{Id: 9}, AppointmentId: 5, ProcedureCode: "CON01", Description: "Consulta", Quantity: 1, Amount: 1500},
+	}, nil
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{plans: map[int]domain.CommissionPlan{
+		1: {DentistId: 1, DefaultPercent: 30, Rules: []domain.CommissionRule{{ProcedureCode: "ORT01", Percent: 50}}},
+		2: {DentistId: 2, DefaultPercent: 40, Rules: []domain.CommissionRule{}},
+	}}
+}
+
+func TestSavePlan(t *testing.T) {
+	tests := []struct {
+		name string
+		plan domain.CommissionPlan
+		err  string
+	}{
+		{name: "default percent only", plan: domain.CommissionPlan{DefaultPercent: 35}},
+		{name: "with rules", plan: domain.CommissionPlan{DefaultPercent: 35, Rules: []domain.CommissionRule{{ProcedureCode: "ORT01", Percent: 50}}}},
+		{name: "invalid default percent", plan: domain.CommissionPlan{DefaultPercent: 101}, err: "default_percent must be between 0 and 100"},
+		{name: "rule without procedure", plan: domain.CommissionPlan{Rules: []domain.CommissionRule{{Percent: 50}}}, err: "invalid rule 1: procedure_code can't be empty"},
+		{name: "repeated procedure", plan: domain.CommissionPlan{Rules: []domain.CommissionRule{{ProcedureCode: "ORT01", Percent: 50}, {ProcedureCode: "ORT01", Percent: 40}}}, err: "procedure ORT01 is repeated"},
+		{name: "invalid rule percent", plan: domain.CommissionPlan{Rules: []domain.CommissionRule{{ProcedureCode: "ORT01", Percent: -5}}}, err: "invalid rule 1: percent must be between 0 and 100"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := NewCommissionService(newFakeRepository()).SavePlan(3, tt.plan)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if plan.DentistId != 3 || plan.Rules == nil {
+				t.Fatalf("unexpected plan %+v", plan)
+			}
+		})
+	}
+}
+
+func TestReport(t *testing.T) {
+	report, err := NewCommissionService(newFakeRepository()).Report("2026-05-01", "2026-05-31", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// el dentista 9 no completo turnos en el periodo y no aparece
+	if len(report.Dentists) != 3 || report.Production != 5833.33 || report.Commission != 2300 {
+		t.Fatalf("unexpected report %+v", report)
+	}
+	ana := report.Dentists[0]
+	if ana.BilledAppointments != 3 || ana.Production != 4333.33 || ana.Commission != 1700 || len(ana.Procedures) != 3 {
+		t.Fatalf("unexpected production of dentist 1 %+v", ana)
+	}
+	expected := []domain.ProcedureProduction{
+		{ProcedureCode: "CON01", Description: "Consulta", Quantity: 2, Amount: 2000, Percent: 30, Commission: 600},
+		{ProcedureCode: "ORT01", Description: "Brackets", Quantity: 1, Amount: 2000, Percent: 50, Commission: 1000},
+		{Description: "Material", Quantity: 2, Amount: 333.33, Percent: 30, Commission: 100},
+	}
+	for i, p := range expected {
+		if ana.Procedures[i] != p {
+			t.Fatalf("expected procedure %+v, got %+v", p, ana.Procedures[i])
+		}
+	}
+	eva := report.Dentists[2]
+	if eva.CompletedAppointments != 2 || eva.BilledAppointments != 0 || len(eva.Procedures) != 0 || eva.Commission != 0 {
+		t.Fatalf("unexpected production of dentist 3 %+v", eva)
+	}
+}
+
+func TestReportPeriod(t *testing.T) {
+	tests := []struct {
+		name      string
+		from      string
+		to        string
+		dentistId int
+		expected  string
+		dentists  int
+		err       string
+	}{
+		{name: "one dentist", from: "2026-05-01", to: "2026-05-31", dentistId: 2, expected: "2026-05-01", dentists: 1},
+		{name: "from the start of the month", to: "2026-05-20", expected: "2026-05-01", dentists: 3},
+		{name: "invalid to", to: "20/05/2026", err: "invalid to, must be in format: yyyy-mm-dd"},
+		{name: "invalid from", from: "mayo", to: "2026-05-20", err: "invalid from, must be in format: yyyy-mm-dd"},
+		{name: "from after to", from: "2026-06-01", to: "2026-05-20", err: "from can't be after to"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			report, err := NewCommissionService(newFakeRepository()).Report(tt.from, tt.to, tt.dentistId)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if report.From != tt.expected || len(report.Dentists) != tt.dentists {
+				t.Fatalf("unexpected report %+v", report)
+			}
+		})
+	}
+}
+
+func TestExport(t *testing.T) {
+	doc, err := NewCommissionService(newFakeRepository()).Export("2026-05-01", "2026-05-31", 0)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if doc.Name != "produccion-2026-05-01-2026-05-31.csv" || doc.ContentType != "text/csv; charset=utf-8" {
+		t.Fatalf("unexpected document %s %s", doc.Name, doc.ContentType)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(doc.Content)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %s", err)
+	}
+	// 3 procedimientos de la dentista 1, 1 del dentista 2 y una fila vacia de la dentista 3
+	if len(rows) != 6 || strings.Join(rows[0], ",") != strings.Join(csvHeader, ",") {
+		t.Fatalf("expected a header and 5 rows, got %q", rows)
+	}
+	expected := []string{
+		"2026-05-01,2026-05-31,1,MP-1,Lopez,Ana,3,3,ORT01,Brackets,1,2000.00,50.00,1000.00",
+		"2026-05-01,2026-05-31,3,MP-3,Ruiz,Eva,2,0,,,0,0.00,0.00,0.00",
+	}
+	if strings.Join(rows[2], ",") != expected[0] || strings.Join(rows[5], ",") != expected[1] {
+		t.Fatalf("expected rows %q, got %q", expected, rows)
+	}
+}
@@ -0,0 +1,186 @@
+package consent
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type ConsentRepository interface {
+	GetTemplates() ([]domain.ConsentTemplate, error)
+	GetTemplateByID(id int) (domain.ConsentTemplate, error)
+	GetRequiredTemplates(procedureCode string) ([]domain.ConsentTemplate, error)
+	CreateTemplate(t domain.ConsentTemplate) (domain.ConsentTemplate, error)
+	UpdateTemplate(t domain.ConsentTemplate) (domain.ConsentTemplate, error)
+	GetByID(id int) (domain.Consent, error)
+	GetByPatient(patientId int) ([]domain.Consent, error)
+	Create(c domain.Consent) (domain.Consent, error)
+	Sign(c domain.Consent) error
+	HasSigned(templateId int, appointmentId int) (bool, error)
+	GetAppointment(appointmentId int) (domain.Appointment, error)
+	GetTreatmentPlan(planId int) (domain.TreatmentPlan, error)
+	GetItemsByAppointment(appointmentId int) ([]domain.TreatmentItem, error)
+}
+
+type consentRepository struct {
+	storage          store.ConsentStore
+	patientStore     store.PatientStore
+	appointmentStore store.AppointmentStore
+	treatmentStore   store.TreatmentStore
+	procedureStore   store.ProcedureStore
+}
+
+// NewConsentRepository crea un nuevo repositorio
+func NewConsentRepository(storage store.ConsentStore, patientStore store.PatientStore, appointmentStore store.AppointmentStore,
+	treatmentStore store.TreatmentStore, procedureStore store.ProcedureStore) ConsentRepository {
+	return &consentRepository{storage, patientStore, appointmentStore, treatmentStore, procedureStore}
+}
+
+// GetTemplates busca todas las plantillas
+func (r *consentRepository) GetTemplates() ([]domain.ConsentTemplate, error) {
+	templates, err := r.storage.GetTemplates()
+	if err != nil {
+		return []domain.ConsentTemplate{}, errors.New("consent templates not found")
+	}
+	return templates, nil
+}
+
+// GetTemplateByID busca una plantilla por su id
+func (r *consentRepository) GetTemplateByID(id int) (domain.ConsentTemplate, error) {
+	t, err := r.storage.GetTemplateByID(id)
+	if err != nil {
+		return domain.ConsentTemplate{}, errors.New(fmt.Sprintf("consent template %d not found", id))
+	}
+	return t, nil
+}
+
+// GetRequiredTemplates busca las plantillas que requiere un procedimiento
+func (r *consentRepository) GetRequiredTemplates(procedureCode string) ([]domain.ConsentTemplate, error) {
+	templates, err := r.storage.GetRequiredTemplates(procedureCode)
+	if err != nil {
+		return []domain.ConsentTemplate{}, errors.New(fmt.Sprintf("consent templates of procedure %s not found", procedureCode))
+	}
+	return templates, nil
+}
+
+// CreateTemplate agrega una plantilla
+func (r *consentRepository) CreateTemplate(t domain.ConsentTemplate) (domain.ConsentTemplate, error) {
+	err := r.checkProcedures(t.Procedures)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	template, err := r.storage.CreateTemplate(t)
+	if err != nil {
+		return domain.ConsentTemplate{}, errors.New("error creating consent template")
+	}
+	return template, nil
+}
+
+// UpdateTemplate reemplaza una plantilla
+func (r *consentRepository) UpdateTemplate(t domain.ConsentTemplate) (domain.ConsentTemplate, error) {
+	_, err := r.GetTemplateByID(t.Id)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	err = r.checkProcedures(t.Procedures)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	template, err := r.storage.UpdateTemplate(t)
+	if err != nil {
+		return domain.ConsentTemplate{}, errors.New(fmt.Sprintf("error updating consent template %d", t.Id))
+	}
+	return template, nil
+}
+
+// GetByID busca un consentimiento por su id
+func (r *consentRepository) GetByID(id int) (domain.Consent, error) {
+	c, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.Consent{}, errors.New(fmt.Sprintf("consent %d not found", id))
+	}
+	return c, nil
+}
+
+// GetByPatient busca los consentimientos de un paciente
+func (r *consentRepository) GetByPatient(patientId int) ([]domain.Consent, error) {
+	_, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return []domain.Consent{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	consents, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.Consent{}, errors.New(fmt.Sprintf("consents of patient %d not found", patientId))
+	}
+	return consents, nil
+}
+
+// Create agrega un consentimiento pendiente de firma
+func (r *consentRepository) Create(c domain.Consent) (domain.Consent, error) {
+	_, err := r.patientStore.GetByID(c.PatientId)
+	if err != nil {
+		return domain.Consent{}, errors.New(fmt.Sprintf("patient %d not found", c.PatientId))
+	}
+	consent, err := r.storage.Create(c)
+	if err != nil {
+		return domain.Consent{}, errors.New("error creating consent")
+	}
+	return consent, nil
+}
+
+// Sign guarda la firma de un consentimiento
+func (r *consentRepository) Sign(c domain.Consent) error {
+	err := r.storage.Sign(c)
+	if err != nil {
+		return errors.New(fmt.Sprintf("consent %d is already signed", c.Id))
+	}
+	return nil
+}
+
+// HasSigned indica si el turno tiene firmado el consentimiento de la plantilla
+func (r *consentRepository) HasSigned(templateId int, appointmentId int) (bool, error) {
+	signed, err := r.storage.HasSigned(templateId, appointmentId)
+	if err != nil {
+		return false, errors.New(fmt.Sprintf("consents of appointment %d not found", appointmentId))
+	}
+	return signed, nil
+}
+
+// GetAppointment busca el turno de un consentimiento
+func (r *consentRepository) GetAppointment(appointmentId int) (domain.Appointment, error) {
+	appointment, err := r.appointmentStore.GetByID(appointmentId)
+	if err != nil {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("appointment %d not found", appointmentId))
+	}
+	return appointment, nil
+}
+
+// GetTreatmentPlan busca el plan de tratamiento de un consentimiento
+func (r *consentRepository) GetTreatmentPlan(planId int) (domain.TreatmentPlan, error) {
+	plan, err := r.treatmentStore.GetByID(planId)
+	if err != nil {
+		return domain.TreatmentPlan{}, errors.New(fmt.Sprintf("treatment plan %d not found", planId))
+	}
+	return plan, nil
+}
+
+// GetItemsByAppointment busca los procedimientos de planes agendados en un turno
+func (r *consentRepository) GetItemsByAppointment(appointmentId int) ([]domain.TreatmentItem, error) {
+	items, err := r.treatmentStore.GetItemsByAppointment(appointmentId)
+	if err != nil {
+		return []domain.TreatmentItem{}, errors.New(fmt.Sprintf("error getting treatment items of appointment %d", appointmentId))
+	}
+	return items, nil
+}
+
+// checkProcedures verifica que existan los procedimientos de una plantilla
+func (r *consentRepository) checkProcedures(codes []string) error {
+	for _, code := range codes {
+		_, err := r.procedureStore.GetByCode(code)
+		if err != nil {
+			return errors.New(fmt.Sprintf("procedure %s not found", code))
+		}
+	}
+	return nil
+}
@@ -0,0 +1,304 @@
+package consent
+
+import (
+	"bytes"
+	"crypto/rand"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/blob"
+	"encoding/base64"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+)
+
+// maxSignatureSize es el tamaño maximo de la imagen de la firma en bytes
+const maxSignatureSize = 512 << 10
+
+type Service interface {
+	GetTemplates() ([]domain.ConsentTemplate, error)
+	GetTemplateByID(id int) (domain.ConsentTemplate, error)
+	CreateTemplate(t domain.ConsentTemplate) (domain.ConsentTemplate, error)
+	UpdateTemplate(id int, t domain.ConsentTemplate) (domain.ConsentTemplate, error)
+	GetByID(id int) (domain.Consent, error)
+	GetByPatient(patientId int) ([]domain.Consent, error)
+	Create(c domain.Consent) (domain.Consent, error)
+	Sign(id int, signature domain.ConsentSignature, ip string) (domain.Consent, error)
+	GetSignature(id int) (string, []byte, error)
+	CanChangeStatus(a domain.Appointment, status string) error
+}
+
+type service struct {
+	r       ConsentRepository
+	storage blob.Storage
+}
+
+// NewConsentService crea un nuevo servicio, las firmas se guardan en el blob storage
+func NewConsentService(r ConsentRepository, storage blob.Storage) Service {
+	return &service{r, storage}
+}
+
+// GetTemplates busca todas las plantillas
+func (s *service) GetTemplates() ([]domain.ConsentTemplate, error) {
+	return s.r.GetTemplates()
+}
+
+// GetTemplateByID busca una plantilla por su id
+func (s *service) GetTemplateByID(id int) (domain.ConsentTemplate, error) {
+	return s.r.GetTemplateByID(id)
+}
+
+// CreateTemplate valida y agrega una plantilla activa
+func (s *service) CreateTemplate(t domain.ConsentTemplate) (domain.ConsentTemplate, error) {
+	t, err := validateTemplate(t)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	t.Active = true
+	template, err := s.r.CreateTemplate(t)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	return s.r.GetTemplateByID(template.Id)
+}
+
+// UpdateTemplate reemplaza una plantilla, los consentimientos ya creados conservan el texto anterior
+func (s *service) UpdateTemplate(id int, t domain.ConsentTemplate) (domain.ConsentTemplate, error) {
+	t, err := validateTemplate(t)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	t.Id = id
+	_, err = s.r.UpdateTemplate(t)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	return s.r.GetTemplateByID(id)
+}
+
+// GetByID busca un consentimiento por su id
+func (s *service) GetByID(id int) (domain.Consent, error) {
+	return s.r.GetByID(id)
+}
+
+// GetByPatient busca los consentimientos de un paciente
+func (s *service) GetByPatient(patientId int) ([]domain.Consent, error) {
+	return s.r.GetByPatient(patientId)
+}
+
+// Create prepara un consentimiento de un paciente para un turno o un plan de tratamiento
+// copiando el texto actual de la plantilla
+func (s *service) Create(c domain.Consent) (domain.Consent, error) {
+	template, err := s.r.GetTemplateByID(c.TemplateId)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	if !template.Active {
+		return domain.Consent{}, errors.New(fmt.Sprintf("consent template %d isn't active", c.TemplateId))
+	}
+	switch {
+	case c.AppointmentId != 0 && c.TreatmentPlanId != 0:
+		return domain.Consent{}, errors.New("consent must be for an appointment or a treatment plan, not both")
+	case c.AppointmentId != 0:
+		appointment, err := s.r.GetAppointment(c.AppointmentId)
+		if err != nil {
+			return domain.Consent{}, err
+		}
+		if appointment.Patient.Id != c.PatientId {
+			return domain.Consent{}, errors.New(fmt.Sprintf("appointment %d isn't of patient %d", c.AppointmentId, c.PatientId))
+		}
+	case c.TreatmentPlanId != 0:
+		plan, err := s.r.GetTreatmentPlan(c.TreatmentPlanId)
+		if err != nil {
+			return domain.Consent{}, err
+		}
+		if plan.PatientId != c.PatientId {
+			return domain.Consent{}, errors.New(fmt.Sprintf("treatment plan %d isn't of patient %d", c.TreatmentPlanId, c.PatientId))
+		}
+	default:
+		return domain.Consent{}, errors.New("appointment_id or treatment_plan_id can't be empty")
+	}
+	c.Body = template.Body
+	consent, err := s.r.Create(c)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	return s.r.GetByID(consent.Id)
+}
+
+// Sign guarda la firma dibujada del paciente junto con la fecha y la ip desde donde firmo
+func (s *service) Sign(id int, signature domain.ConsentSignature, ip string) (domain.Consent, error) {
+	c, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	if c.Status != domain.ConsentPending {
+		return domain.Consent{}, errors.New(fmt.Sprintf("consent %d is already signed", id))
+	}
+	c.SignerName = strings.TrimSpace(signature.SignerName)
+	if c.SignerName == "" {
+		return domain.Consent{}, errors.New("signer_name can't be empty")
+	}
+	image, err := decodeSignature(signature.Signature)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	// Cada intento guarda la firma en una clave propia, si dos firmas llegan a la vez la que no se registra
+	// borra solo su imagen
+	c.SignatureKey, err = newSignatureKey(id)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	err = s.storage.Put(c.SignatureKey, bytes.NewReader(image))
+	if err != nil {
+		return domain.Consent{}, errors.New("error saving signature")
+	}
+	c.SignedIp = ip
+	err = s.r.Sign(c)
+	if err != nil {
+		if err := s.storage.Delete(c.SignatureKey); err != nil {
+			log.Printf("error deleting signature of consent %d: %s", id, err.Error())
+		}
+		return domain.Consent{}, err
+	}
+	return s.r.GetByID(id)
+}
+
+// GetSignature devuelve el tipo y la imagen de la firma de un consentimiento
+func (s *service) GetSignature(id int) (string, []byte, error) {
+	c, err := s.r.GetByID(id)
+	if err != nil {
+		return "", nil, err
+	}
+	if c.Status != domain.ConsentSigned {
+		return "", nil, errors.New(fmt.Sprintf("consent %d isn't signed", id))
+	}
+	content, err := s.storage.Get(c.SignatureKey)
+	if err != nil {
+		return "", nil, errors.New(fmt.Sprintf("signature of consent %d not found", id))
+	}
+	defer content.Close()
+	image, err := io.ReadAll(io.LimitReader(content, maxSignatureSize))
+	if err != nil {
+		return "", nil, err
+	}
+	return http.DetectContentType(image), image, nil
+}
+
+// CanChangeStatus impide empezar un turno si su procedimiento, o alguno de los procedimientos de planes
+// agendados en el, requiere consentimientos que no estan firmados. Tambien se controla al completarlo sin
+// haberlo empezado.
+func (s *service) CanChangeStatus(a domain.Appointment, status string) error {
+	starting := status == domain.AppointmentInProgress ||
+		(status == domain.AppointmentCompleted && a.Status != domain.AppointmentInProgress)
+	if !starting {
+		return nil
+	}
+	codes := []string{}
+	if a.ProcedureCode != "" {
+		codes = append(codes, a.ProcedureCode)
+	}
+	items, err := s.r.GetItemsByAppointment(a.Id)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.ProcedureCode != "" && !contains(codes, item.ProcedureCode) {
+			codes = append(codes, item.ProcedureCode)
+		}
+	}
+	missing := []string{}
+	checked := map[int]bool{}
+	for _, code := range codes {
+		templates, err := s.r.GetRequiredTemplates(code)
+		if err != nil {
+			return err
+		}
+		for _, t := range templates {
+			if checked[t.Id] {
+				continue
+			}
+			checked[t.Id] = true
+			signed, err := s.r.HasSigned(t.Id, a.Id)
+			if err != nil {
+				return err
+			}
+			if !signed {
+				missing = append(missing, t.Name)
+			}
+		}
+	}
+	if len(missing) > 0 {
+		return errors.New(fmt.Sprintf("appointment %d needs a signed consent before starting: %s", a.Id, strings.Join(missing, ", ")))
+	}
+	return nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// validateTemplate valida una plantilla y normaliza sus codigos de procedimiento
+func validateTemplate(t domain.ConsentTemplate) (domain.ConsentTemplate, error) {
+	t.Name = strings.TrimSpace(t.Name)
+	switch {
+	case t.Name == "":
+		return domain.ConsentTemplate{}, errors.New("name can't be empty")
+	case strings.TrimSpace(t.Body) == "":
+		return domain.ConsentTemplate{}, errors.New("body can't be empty")
+	}
+	codes := []string{}
+	seen := map[string]bool{}
+	for _, code := range t.Procedures {
+		code = strings.TrimSpace(code)
+		if code != "" && !seen[code] {
+			seen[code] = true
+			codes = append(codes, code)
+		}
+	}
+	t.Procedures = codes
+	return t, nil
+}
+
+// contains indica si un codigo esta en la lista
+func contains(codes []string, code string) bool {
+	for _, c := range codes {
+		if c == code {
+			return true
+		}
+	}
+	return false
+}
+
+// newSignatureKey genera una clave aleatoria para guardar la firma de un consentimiento
+func newSignatureKey(id int) (string, error) {
+	b := make([]byte, 16)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("consents/%d/%s", id, hex.EncodeToString(b)), nil
+}
+
+// decodeSignature decodifica la imagen de la firma y verifica que sea PNG o JPEG
+func decodeSignature(signature string) ([]byte, error) {
+	if i := strings.Index(signature, ","); strings.HasPrefix(signature, "data:") && i >= 0 {
+		signature = signature[i+1:]
+	}
+	if signature == "" {
+		return nil, errors.New("signature can't be empty")
+	}
+	if base64.StdEncoding.DecodedLen(len(signature)) > maxSignatureSize {
+		return nil, errors.New(fmt.Sprintf("signature exceeds the maximum size of %d bytes", maxSignatureSize))
+	}
+	image, err := base64.StdEncoding.DecodeString(signature)
+	if err != nil {
+		return nil, errors.New("invalid signature, must be a base64 image")
+	}
+	contentType := http.DetectContentType(image)
+	if contentType != "image/png" && contentType != "image/jpeg" {
+		return nil, errors.New("invalid signature, must be a png or jpeg image")
+	}
+	return image, nil
+}
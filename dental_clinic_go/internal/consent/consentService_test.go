@@ -0,0 +1,116 @@
+package consent
+
+import (
+	"bytes"
+	"dental_clinic_go/internal/domain"
+	"encoding/base64"
+	"errors"
+	"fmt"
+	"io"
+	"testing"
+)
+
+// fakeRepository guarda un unico consentimiento, stale simula que otra firma lo leyo antes de que se firmara
+type fakeRepository struct {
+	ConsentRepository
+	consent domain.Consent
+	stale   bool
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.Consent, error) {
+	if r.stale {
+		return domain.Consent{Id: id, Status: domain.ConsentPending}, nil
+	}
+	return r.consent, nil
+}
+
+func (r *fakeRepository) Sign(c domain.Consent) error {
+	if r.consent.Status != domain.ConsentPending {
+		return errors.New(fmt.Sprintf("consent %d is already signed", c.Id))
+	}
+	c.Status = domain.ConsentSigned
+	r.consent = c
+	return nil
+}
+
+// fakeStorage es un blob storage en memoria
+type fakeStorage map[string][]byte
+
+func (s fakeStorage) Put(key string, r io.Reader) error {
+	content, err := io.ReadAll(r)
+	s[key] = content
+	return err
+}
+
+func (s fakeStorage) Get(key string) (io.ReadCloser, error) {
+	content, ok := s[key]
+	if !ok {
+		return nil, errors.New("not found")
+	}
+	return io.NopCloser(bytes.NewReader(content)), nil
+}
+
+func (s fakeStorage) Delete(key string) error {
+	delete(s, key)
+	return nil
+}
+
+var png = base64.StdEncoding.EncodeToString([]byte("\x89PNG\r\n\x1a\nfirma"))
+
+func TestSign(t *testing.T) {
+	tests := []struct {
+		name      string
+		signature domain.ConsentSignature
+		err       string
+	}{
+		{name: "png signature", signature: domain.ConsentSignature{SignerName: " Ana Diaz ", Signature: "data:image/png;base64," + png}},
+		{name: "without signer", signature: domain.ConsentSignature{Signature: png}, err: "signer_name can't be empty"},
+		{name: "empty signature", signature: domain.ConsentSignature{SignerName: "Ana Diaz"}, err: "signature can't be empty"},
+		{name: "not base64", signature: domain.ConsentSignature{SignerName: "Ana Diaz", Signature: "firma"}, err: "invalid signature, must be a base64 image"},
+		{name: "not an image", signature: domain.ConsentSignature{SignerName: "Ana Diaz", Signature: base64.StdEncoding.EncodeToString([]byte("firma"))}, err: "invalid signature, must be a png or jpeg image"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{consent: domain.Consent{Id: 1, Status: domain.ConsentPending}}
+			storage := fakeStorage{}
+			c, err := NewConsentService(r, storage).Sign(1, tt.signature, "10.0.0.1")
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				if len(storage) != 0 {
+					t.Fatalf("expected no saved signatures, got %d", len(storage))
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if c.Status != domain.ConsentSigned || c.SignerName != "Ana Diaz" || c.SignedIp != "10.0.0.1" {
+				t.Fatalf("unexpected consent %+v", c)
+			}
+			if _, ok := storage[c.SignatureKey]; !ok || len(storage) != 1 {
+				t.Fatalf("expected the signature at %s, got %d signatures", c.SignatureKey, len(storage))
+			}
+		})
+	}
+}
+
+func TestSignTwiceKeepsTheFirstSignature(t *testing.T) {
+	r := &fakeRepository{consent: domain.Consent{Id: 1, Status: domain.ConsentPending}, stale: true}
+	storage := fakeStorage{}
+	s := NewConsentService(r, storage)
+	signature := domain.ConsentSignature{SignerName: "Ana Diaz", Signature: png}
+
+	_, err := s.Sign(1, signature, "10.0.0.1")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	_, err = s.Sign(1, signature, "10.0.0.2")
+	if err == nil || err.Error() != "consent 1 is already signed" {
+		t.Fatalf("expected the second signature to fail, got %v", err)
+	}
+	if _, ok := storage[r.consent.SignatureKey]; !ok || len(storage) != 1 {
+		t.Fatalf("expected only the first signature at %s, got %d signatures", r.consent.SignatureKey, len(storage))
+	}
+}
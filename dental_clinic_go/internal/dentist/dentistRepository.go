@@ -8,6 +8,7 @@ import (
 )
 
 type DentistRepository interface {
+	GetAll(specialty string) ([]domain.Dentist, error)
 	GetByID(id int) (domain.Dentist, error)
 	Create(p domain.Dentist) (domain.Dentist, error)
 	Update(id int, updatedDentist domain.Dentist) (domain.Dentist, error)
@@ -23,6 +24,15 @@ func NewDentistRepository(storage store.DentistStore) DentistRepository {
 	return &dentistRepository{storage}
 }
 
+// GetAll busca los dentistas, si specialty no esta vacia solo los que tienen esa especialidad
+func (r *dentistRepository) GetAll(specialty string) ([]domain.Dentist, error) {
+	dentists, err := r.storage.GetAll(specialty)
+	if err != nil {
+		return []domain.Dentist{}, errors.New("error getting dentists")
+	}
+	return dentists, nil
+}
+
 // GetByID busca un dentista por su id
 func (r *dentistRepository) GetByID(id int) (domain.Dentist, error) {
 	dentist, err := r.storage.GetByID(id)
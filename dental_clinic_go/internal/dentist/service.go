@@ -2,9 +2,13 @@ package dentist
 
 import (
 	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"strings"
 )
 
 type Service interface {
+	GetAll(specialty string) ([]domain.Dentist, error)
 	GetByID(id int) (domain.Dentist, error)
 	Create(p domain.Dentist) (domain.Dentist, error)
 	Update(id int, updatedDentist domain.Dentist) (domain.Dentist, error)
@@ -20,6 +24,15 @@ func NewDentistService(r DentistRepository) Service {
 	return &service{r}
 }
 
+// GetAll busca los dentistas, si specialty no esta vacia solo los que tienen esa especialidad
+func (s *service) GetAll(specialty string) ([]domain.Dentist, error) {
+	specialty = strings.ToLower(strings.TrimSpace(specialty))
+	if specialty != "" && !domain.ValidSpecialty(specialty) {
+		return []domain.Dentist{}, errors.New(fmt.Sprintf("invalid specialty %s", specialty))
+	}
+	return s.r.GetAll(specialty)
+}
+
 // GetByID busca un dentista por su id
 func (s *service) GetByID(id int) (domain.Dentist, error) {
 	p, err := s.r.GetByID(id)
@@ -31,7 +44,12 @@ func (s *service) GetByID(id int) (domain.Dentist, error) {
 
 // Create agrega un nuevo dentista
 func (s *service) Create(p domain.Dentist) (domain.Dentist, error) {
-	p, err := s.r.Create(p)
+	specialties, err := normalizeSpecialties(p.Specialties)
+	if err != nil {
+		return domain.Dentist{}, err
+	}
+	p.Specialties = specialties
+	p, err = s.r.Create(p)
 	if err != nil {
 		return domain.Dentist{}, err
 	}
@@ -40,6 +58,11 @@ func (s *service) Create(p domain.Dentist) (domain.Dentist, error) {
 
 // UpdateDentist actualiza un dentista
 func (s *service) Update(id int, updatedDentist domain.Dentist) (domain.Dentist, error) {
+	specialties, err := normalizeSpecialties(updatedDentist.Specialties)
+	if err != nil {
+		return domain.Dentist{}, err
+	}
+	updatedDentist.Specialties = specialties
 	p, err := s.r.Update(id, updatedDentist)
 	if err != nil {
 		return domain.Dentist{}, err
@@ -55,3 +78,24 @@ func (s *service) Delete(id int) error {
 	}
 	return nil
 }
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// normalizeSpecialties valida las especialidades de un dentista y quita las repetidas,
+// nil se mantiene para que un update no las modifique
+func normalizeSpecialties(specialties []string) ([]string, error) {
+	if specialties == nil {
+		return nil, nil
+	}
+	normalized := []string{}
+	for _, specialty := range specialties {
+		specialty = strings.ToLower(strings.TrimSpace(specialty))
+		if !domain.ValidSpecialty(specialty) {
+			return nil, errors.New(fmt.Sprintf("invalid specialty %q, must be one of %s", specialty, strings.Join(domain.Specialties, ", ")))
+		}
+		if !(domain.Dentist{Specialties: normalized}).HasSpecialty(specialty) {
+			normalized = append(normalized, specialty)
+		}
+	}
+	return normalized, nil
+}
@@ -0,0 +1,102 @@
+package dentist
+
+import (
+	"dental_clinic_go/internal/domain"
+	"testing"
+)
+
+// fakeRepository devuelve los dentistas tal como le llegan y guarda el filtro de GetAll
+type fakeRepository struct {
+	DentistRepository
+	specialty string
+}
+
+func (r *fakeRepository) GetAll(specialty string) ([]domain.Dentist, error) {
+	r.specialty = specialty
+	return []domain.Dentist{}, nil
+}
+
+func (r *fakeRepository) Create(p domain.Dentist) (domain.Dentist, error) {
+	return p, nil
+}
+
+func (r *fakeRepository) Update(id int, p domain.Dentist) (domain.Dentist, error) {
+	p.Id = id
+	return p, nil
+}
+
+func TestSpecialties(t *testing.T) {
+	tests := []struct {
+		name        string
+		specialties []string
+		expected    []string
+		err         string
+	}{
+		{name: "general dentist", specialties: []string{}, expected: []string{}},
+		{name: "normalized and without repeated", specialties: []string{" Orthodontics", "endodontics", "ORTHODONTICS"}, expected: []string{"orthodontics", "endodontics"}},
+		{name: "unknown specialty", specialties: []string{"cardiology"}, err: `invalid specialty "cardiology", must be one of orthodontics, endodontics, periodontics, oral_surgery, pediatric`},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			d, err := NewDentistService(&fakeRepository{}).Create(domain.Dentist{Name: "Ana", Specialties: tt.specialties})
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(d.Specialties) != len(tt.expected) {
+				t.Fatalf("expected specialties %v, got %v", tt.expected, d.Specialties)
+			}
+			for i, specialty := range tt.expected {
+				if d.Specialties[i] != specialty {
+					t.Fatalf("expected specialties %v, got %v", tt.expected, d.Specialties)
+				}
+			}
+		})
+	}
+}
+
+func TestUpdateWithoutSpecialtiesKeepsThem(t *testing.T) {
+	d, err := NewDentistService(&fakeRepository{}).Update(1, domain.Dentist{Name: "Ana"})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if d.Specialties != nil {
+		t.Fatalf("expected nil specialties so the update keeps them, got %v", d.Specialties)
+	}
+}
+
+func TestGetAllBySpecialty(t *testing.T) {
+	tests := []struct {
+		name      string
+		specialty string
+		filter    string
+		err       string
+	}{
+		{name: "all dentists"},
+		{name: "normalized specialty", specialty: " Endodontics ", filter: "endodontics"},
+		{name: "unknown specialty", specialty: "cardiology", err: "invalid specialty cardiology"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{}
+			_, err := NewDentistService(r).GetAll(tt.specialty)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if r.specialty != tt.filter {
+				t.Fatalf("expected filter %q, got %q", tt.filter, r.specialty)
+			}
+		})
+	}
+}
@@ -0,0 +1,367 @@
+package document
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/pdf"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// Margenes y alto de renglon de los documentos, en puntos
+const (
+	margin     = 50.0
+	lineHeight = 14.0
+)
+
+// column es una columna de una tabla, las de importes se alinean a la derecha
+type column struct {
+	title string
+	width float64
+	right bool
+}
+
+var invoiceColumns = []column{
+	{"Código", 40, false},
+	{"Descripción", 135, false},
+	{"Pieza", 30, true},
+	{"Cant.", 30, true},
+	{"Precio", 65, true},
+	{"Desc.", 60, true},
+	{"IVA", 60, true},
+	{"Total", 75, true},
+}
+
+var statementColumns = []column{
+	{"Fecha", 65, false},
+	{"Concepto", 200, false},
+	{"Debe", 75, true},
+	{"Haber", 75, true},
+	{"Saldo", 80, true},
+}
+
+var invoiceBalanceColumns = []column{
+	{"Factura", 120, false},
+	{"Fecha", 85, false},
+	{"Total", 100, true},
+	{"Pagado", 95, true},
+	{"Pendiente", 95, true},
+}
+
+// entryNames y methodNames traducen los tipos de movimiento y medios de pago
+var entryNames = map[string]string{
+	domain.EntryCharge:     "Cargo",
+	domain.EntryPayment:    "Pago",
+	domain.EntryRefund:     "Reintegro",
+	domain.EntryAdjustment: "Ajuste",
+}
+
+var methodNames = map[string]string{
+	domain.PaymentCash:     "Efectivo",
+	domain.PaymentCard:     "Tarjeta",
+	domain.PaymentTransfer: "Transferencia",
+}
+
+// layout escribe un documento de arriba hacia abajo y pasa a otra hoja cuando se llena,
+// repitiendo el encabezado de la clinica
+type layout struct {
+	doc    *pdf.Document
+	clinic Clinic
+	title  string
+	y      float64
+}
+
+// renderInvoice arma el PDF de una factura
+func renderInvoice(clinic Clinic, i domain.Invoice, patient domain.Patient, dentist domain.Dentist) ([]byte, error) {
+	title := "Factura " + i.FullNumber()
+	if i.Status == domain.InvoiceDraft {
+		title = fmt.Sprintf("Borrador de factura %d", i.Id)
+	}
+	l := newLayout(clinic, title)
+	l.field("Fecha", i.IssueDate)
+	if i.Status == domain.InvoiceVoid {
+		l.field("Estado", "ANULADA - "+i.VoidReason)
+	}
+	l.patient(patient)
+	if i.BillTo.ContactId != 0 && i.BillTo.ContactId != patient.Id {
+		l.field("Facturar a", fmt.Sprintf("%s %s (%s)", i.BillTo.Name, i.BillTo.LastName, i.BillTo.Relationship))
+	}
+	l.dentist(dentist)
+	l.space()
+
+	rows := [][]string{}
+	for _, item := range i.Items {
+		tooth := ""
+		if item.Tooth != 0 {
+			tooth = strconv.Itoa(item.Tooth)
+		}
+		rows = append(rows, []string{item.ProcedureCode, item.Description, tooth, strconv.Itoa(item.Quantity),
+			money(item.UnitPrice), money(item.Discount), money(item.Tax), money(item.Total)})
+	}
+	l.table(invoiceColumns, rows)
+	l.space()
+	l.total("Subtotal", money(i.Subtotal), false)
+	l.total("Descuentos", money(i.Discount), false)
+	l.total("IVA", money(i.Tax), false)
+	l.total("Total", money(i.Total), true)
+	if i.Notes != "" {
+		l.space()
+		l.field("Observaciones", i.Notes)
+	}
+	return l.doc.Bytes()
+}
+
+// renderReceipt arma el PDF del recibo de un pago
+func renderReceipt(clinic Clinic, entry domain.LedgerEntry, invoices []domain.Invoice, patient domain.Patient, dentist domain.Dentist) ([]byte, error) {
+	l := newLayout(clinic, fmt.Sprintf("Recibo %08d", entry.Id))
+	l.field("Fecha", entry.Date)
+	l.patient(patient)
+	l.dentist(dentist)
+	l.space()
+	l.field("Recibimos la suma de", money(entry.Amount))
+	l.field("Medio de pago", methodNames[entry.Method])
+	if entry.Reference != "" {
+		l.field("Referencia", entry.Reference)
+	}
+	if entry.Description != "" {
+		l.field("Concepto", entry.Description)
+	}
+	if len(entry.Allocations) > 0 {
+		l.space()
+		rows := [][]string{}
+		for n, a := range entry.Allocations {
+			rows = append(rows, []string{invoices[n].FullNumber(), invoices[n].IssueDate, money(invoices[n].Total), money(a.Amount)})
+		}
+		l.table([]column{invoiceBalanceColumns[0], invoiceBalanceColumns[1], invoiceBalanceColumns[2], {"Imputado", 95, true}}, rows)
+	}
+	l.space()
+	l.space()
+	l.space()
+	l.ensure(lineHeight * 2)
+	l.doc.Line(pdf.PageWidth-margin-180, l.y, pdf.PageWidth-margin, l.y)
+	l.y += lineHeight
+	l.doc.TextRight(pdf.PageWidth-margin, l.y, 9, false, "Firma y aclaración")
+	return l.doc.Bytes()
+}
+
+// renderStatement arma el PDF del resumen de cuenta de un paciente
+func renderStatement(clinic Clinic, statement domain.Statement) ([]byte, error) {
+	l := newLayout(clinic, "Resumen de cuenta")
+	period := "Hasta " + statement.To
+	if statement.From != "" {
+		period = fmt.Sprintf("Del %s al %s", statement.From, statement.To)
+	}
+	l.field("Período", period)
+	l.patient(statement.Patient)
+	l.space()
+
+	rows := [][]string{}
+	if statement.From != "" {
+		rows = append(rows, []string{statement.From, "Saldo anterior", "", "", money(statement.OpeningBalance)})
+	}
+	for _, e := range statement.Entries {
+		concept := entryNames[e.Type]
+		if e.Description != "" {
+			concept += " - " + e.Description
+		}
+		rows = append(rows, []string{e.Date, concept, amount(e.Debit), amount(e.Credit), money(e.Balance)})
+	}
+	l.table(statementColumns, rows)
+	l.space()
+	label := "Saldo a pagar"
+	if statement.ClosingBalance < 0 {
+		label = "Saldo a favor"
+	}
+	l.total(label, money(math.Abs(statement.ClosingBalance)), true)
+
+	if len(statement.Invoices) > 0 {
+		l.space()
+		l.heading("Facturas pendientes")
+		rows = [][]string{}
+		for _, i := range statement.Invoices {
+			rows = append(rows, []string{i.Number, i.IssueDate, money(i.Total), money(i.Paid), money(i.Due)})
+		}
+		l.table(invoiceBalanceColumns, rows)
+		l.space()
+		l.total("0 a 30 días", money(statement.Aging.Days0To30), false)
+		l.total("31 a 60 días", money(statement.Aging.Days31To60), false)
+		l.total("61 a 90 días", money(statement.Aging.Days61To90), false)
+		l.total("Más de 90 días", money(statement.Aging.Over90), false)
+	}
+	return l.doc.Bytes()
+}
+
+// newLayout crea un documento con el encabezado de la clinica en la primera hoja
+func newLayout(clinic Clinic, title string) *layout {
+	l := &layout{doc: pdf.New(), clinic: clinic, title: title}
+	l.header()
+	return l
+}
+
+// header escribe los datos de la clinica a la izquierda y el titulo del documento a la derecha
+func (l *layout) header() {
+	l.y = margin + 16
+	l.doc.Text(margin, l.y, 16, true, l.clinic.Name)
+	l.doc.TextRight(pdf.PageWidth-margin, l.y, 14, true, l.title)
+	for _, line := range []string{l.clinic.Address, l.clinic.Phone, cuit(l.clinic.TaxId)} {
+		if line == "" {
+			continue
+		}
+		l.y += lineHeight - 2
+		l.doc.Text(margin, l.y, 9, false, line)
+	}
+	l.y += 10
+	l.doc.Line(margin, l.y, pdf.PageWidth-margin, l.y)
+	l.y += lineHeight + 6
+}
+
+// ensure pasa a otra hoja si no entra un bloque del alto indicado, devuelve si paso de hoja
+func (l *layout) ensure(height float64) bool {
+	if l.y+height <= pdf.PageHeight-margin {
+		return false
+	}
+	l.doc.AddPage()
+	l.header()
+	return true
+}
+
+// space deja un renglon en blanco
+func (l *layout) space() {
+	l.y += lineHeight
+}
+
+// heading escribe un subtitulo
+func (l *layout) heading(text string) {
+	l.ensure(lineHeight * 2)
+	l.doc.Text(margin, l.y, 11, true, text)
+	l.y += lineHeight + 2
+}
+
+// field escribe un dato con su etiqueta en negrita
+func (l *layout) field(label string, value string) {
+	l.ensure(lineHeight)
+	label += ": "
+	l.doc.Text(margin, l.y, 10, true, label)
+	x := margin + pdf.Width(label, 10, true)
+	l.doc.Text(x, l.y, 10, false, pdf.Fit(value, 10, false, pdf.PageWidth-margin-x))
+	l.y += lineHeight
+}
+
+// patient escribe el nombre, el documento y el domicilio del paciente
+func (l *layout) patient(p domain.Patient) {
+	l.field("Paciente", fmt.Sprintf("%s %s", p.Name, p.LastName))
+	l.field("DNI", strconv.Itoa(p.Dni))
+	if address := address(p.Address); address != "" {
+		l.field("Domicilio", address)
+	}
+}
+
+// dentist escribe el nombre y la matricula del dentista, nada si el documento no tiene dentista
+func (l *layout) dentist(d domain.Dentist) {
+	if d.Empty() {
+		return
+	}
+	l.field("Profesional", fmt.Sprintf("Dr/a. %s %s - Matrícula %s", d.Name, d.LastName, d.License))
+}
+
+// table escribe una tabla con sus titulos, que se repiten si la tabla sigue en otra hoja
+func (l *layout) table(columns []column, rows [][]string) {
+	titles := func() {
+		l.ensure(lineHeight * 2)
+		l.row(columns, nil, true)
+		l.doc.Line(margin, l.y-lineHeight+4, pdf.PageWidth-margin, l.y-lineHeight+4)
+	}
+	titles()
+	for _, r := range rows {
+		if l.ensure(lineHeight) {
+			titles()
+		}
+		l.row(columns, r, false)
+	}
+}
+
+// row escribe un renglon de una tabla, sin valores escribe los titulos de las columnas
+func (l *layout) row(columns []column, values []string, bold bool) {
+	width := 0.0
+	for _, c := range columns {
+		width += c.width
+	}
+	// las columnas se estiran para ocupar todo el ancho de la hoja
+	scale := (pdf.PageWidth - 2*margin) / width
+	x := margin
+	for n, c := range columns {
+		text := c.title
+		if values != nil {
+			text = values[n]
+		}
+		w := c.width * scale
+		text = pdf.Fit(text, 9, bold, w-6)
+		if c.right {
+			l.doc.TextRight(x+w, l.y, 9, bold, text)
+		} else {
+			l.doc.Text(x, l.y, 9, bold, text)
+		}
+		x += w
+	}
+	l.y += lineHeight
+}
+
+// total escribe un importe con su etiqueta alineados a la derecha
+func (l *layout) total(label string, value string, bold bool) {
+	l.ensure(lineHeight)
+	size := 10.0
+	if bold {
+		size = 12
+	}
+	l.doc.TextRight(pdf.PageWidth-margin-110, l.y, size, bold, label)
+	l.doc.TextRight(pdf.PageWidth-margin, l.y, size, bold, value)
+	l.y += lineHeight + 2
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// money da formato de pesos a un importe: $ 12.345,67
+func money(value float64) string {
+	sign := ""
+	if value < 0 {
+		sign, value = "-", -value
+	}
+	cents := int64(math.Round(value * 100))
+	digits := strconv.FormatInt(cents/100, 10)
+	var b strings.Builder
+	for n, d := range digits {
+		if n > 0 && (len(digits)-n)%3 == 0 {
+			b.WriteByte('.')
+		}
+		b.WriteRune(d)
+	}
+	return fmt.Sprintf("%s$ %s,%02d", sign, b.String(), cents%100)
+}
+
+// amount da formato a un importe del debe o el haber, vacio si es cero
+func amount(value float64) string {
+	if value == 0 {
+		return ""
+	}
+	return money(value)
+}
+
+// address arma el domicilio en una linea
+func address(a domain.Address) string {
+	parts := []string{}
+	for _, p := range []string{strings.TrimSpace(a.Street + " " + a.Number), a.City, a.Province, a.PostalCode} {
+		if p != "" {
+			parts = append(parts, p)
+		}
+	}
+	return strings.Join(parts, ", ")
+}
+
+// cuit agrega la etiqueta al numero de CUIT de la clinica, vacio si no esta configurado
+func cuit(taxId string) string {
+	if taxId == "" {
+		return ""
+	}
+	return "CUIT " + taxId
+}
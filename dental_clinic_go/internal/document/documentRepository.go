@@ -0,0 +1,41 @@
+package document
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type DocumentRepository interface {
+	GetPatient(id int) (domain.Patient, error)
+	GetAppointment(id int) (domain.Appointment, error)
+}
+
+type documentRepository struct {
+	patientStore     store.PatientStore
+	appointmentStore store.AppointmentStore
+}
+
+// NewDocumentRepository crea un nuevo repositorio
+func NewDocumentRepository(patientStore store.PatientStore, appointmentStore store.AppointmentStore) DocumentRepository {
+	return &documentRepository{patientStore, appointmentStore}
+}
+
+// GetPatient busca el paciente al que se le emite el documento
+func (r *documentRepository) GetPatient(id int) (domain.Patient, error) {
+	patient, err := r.patientStore.GetByID(id)
+	if err != nil {
+		return domain.Patient{}, errors.New(fmt.Sprintf("patient %d not found", id))
+	}
+	return patient, nil
+}
+
+// GetAppointment busca el turno de una factura con el dentista que atendio
+func (r *documentRepository) GetAppointment(id int) (domain.Appointment, error) {
+	appointment, err := r.appointmentStore.GetByID(id)
+	if err != nil {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("appointment %d not found", id))
+	}
+	return appointment, nil
+}
@@ -0,0 +1,161 @@
+package document
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/invoice"
+	"dental_clinic_go/internal/ledger"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// Clinic son los datos de la clinica que van en el encabezado de los documentos
+type Clinic struct {
+	Name    string
+	Address string
+	Phone   string
+	TaxId   string
+}
+
+type Service interface {
+	Invoice(id int) (domain.Document, error)
+	Receipt(entryId int) (domain.Document, error)
+	Statement(patientId int, from string, to string) (domain.Document, error)
+}
+
+type service struct {
+	r        DocumentRepository
+	invoices invoice.Service
+	ledger   ledger.Service
+	clinic   Clinic
+}
+
+// NewDocumentService crea un nuevo servicio
+func NewDocumentService(r DocumentRepository, invoices invoice.Service, ledger ledger.Service, clinic Clinic) Service {
+	return &service{r, invoices, ledger, clinic}
+}
+
+// Invoice genera el PDF de una factura con el dentista del turno que la origina
+func (s *service) Invoice(id int) (domain.Document, error) {
+	i, err := s.invoices.GetByID(id)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	patient, err := s.r.GetPatient(i.PatientId)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	dentist, err := s.dentist([]int{i.AppointmentId})
+	if err != nil {
+		return domain.Document{}, err
+	}
+	content, err := renderInvoice(s.clinic, i, patient, dentist)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	name := fmt.Sprintf("factura-%s.pdf", i.FullNumber())
+	if i.Status == domain.InvoiceDraft {
+		name = fmt.Sprintf("borrador-%d.pdf", i.Id)
+	}
+	return domain.Document{Name: name, ContentType: "application/pdf", Content: content}, nil
+}
+
+// Receipt genera el recibo de un pago con las facturas a las que se imputo
+func (s *service) Receipt(entryId int) (domain.Document, error) {
+	entry, err := s.ledger.GetByID(entryId)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	if entry.Type != domain.EntryPayment {
+		return domain.Document{}, errors.New(fmt.Sprintf("ledger entry %d is not a payment", entryId))
+	}
+	patient, err := s.r.GetPatient(entry.PatientId)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	invoices := []domain.Invoice{}
+	appointments := []int{}
+	for _, a := range entry.Allocations {
+		i, err := s.invoices.GetByID(a.InvoiceId)
+		if err != nil {
+			return domain.Document{}, err
+		}
+		invoices = append(invoices, i)
+		appointments = append(appointments, i.AppointmentId)
+	}
+	dentist, err := s.dentist(appointments)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	content, err := renderReceipt(s.clinic, entry, invoices, patient, dentist)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	return domain.Document{Name: fmt.Sprintf("recibo-%08d.pdf", entry.Id), ContentType: "application/pdf", Content: content}, nil
+}
+
+// Statement genera el resumen de cuenta de un paciente entre dos fechas con el saldo inicial, los movimientos
+// y las facturas pendientes. Sin from incluye toda la cuenta y sin to llega hasta hoy.
+func (s *service) Statement(patientId int, from string, to string) (domain.Document, error) {
+	if to == "" {
+		to = time.Now().Format("2006-01-02")
+	}
+	for _, date := range []string{from, to} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			return domain.Document{}, errors.New("invalid from or to, must be in format: yyyy-mm-dd")
+		}
+	}
+	if from > to {
+		return domain.Document{}, errors.New("from can't be after to")
+	}
+	patient, err := s.r.GetPatient(patientId)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	entries, err := s.ledger.GetByPatient(patientId)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	balance, err := s.ledger.GetBalance(patientId)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	statement := domain.Statement{Patient: patient, From: from, To: to, Entries: []domain.LedgerEntry{}, Invoices: []domain.InvoiceBalance{}, Aging: balance.Aging}
+	for _, e := range entries {
+		switch {
+		case e.Date < from:
+			statement.OpeningBalance = e.Balance
+		case e.Date <= to:
+			statement.Entries = append(statement.Entries, e)
+		}
+	}
+	statement.ClosingBalance = statement.OpeningBalance
+	if len(statement.Entries) > 0 {
+		statement.ClosingBalance = statement.Entries[len(statement.Entries)-1].Balance
+	}
+	for _, i := range balance.Invoices {
+		if i.Due > 0 {
+			statement.Invoices = append(statement.Invoices, i)
+		}
+	}
+	content, err := renderStatement(s.clinic, statement)
+	if err != nil {
+		return domain.Document{}, err
+	}
+	return domain.Document{Name: fmt.Sprintf("resumen-%d-%s.pdf", patientId, to), ContentType: "application/pdf", Content: content}, nil
+}
+
+// dentist busca el dentista del primer turno, vacio si ningun documento viene de un turno
+func (s *service) dentist(appointmentIds []int) (domain.Dentist, error) {
+	for _, id := range appointmentIds {
+		if id == 0 {
+			continue
+		}
+		appointment, err := s.r.GetAppointment(id)
+		if err != nil {
+			return domain.Dentist{}, err
+		}
+		return appointment.Dentist, nil
+	}
+	return domain.Dentist{}, nil
+}
@@ -0,0 +1,191 @@
+package document
+
+import (
+	"bytes"
+	"compress/zlib"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/invoice"
+	"dental_clinic_go/internal/ledger"
+	"errors"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// fakeRepository devuelve cualquier paciente y los turnos con la dentista 1
+type fakeRepository struct{}
+
+func (r fakeRepository) GetPatient(id int) (domain.Patient, error) {
+	return domain.Patient{Id: id, Name: "Juan", LastName: "Perez", Dni: 30111222}, nil
+}
+
+func (r fakeRepository) GetAppointment(id int) (domain.Appointment, error) {
+	return domain.Appointment{Id: id, Dentist: domain.Dentist{Id: 1, Name: "Ana", LastName: "Lopez", License: "MP-1"}}, nil
+}
+
+// fakeInvoices devuelve las facturas emitidas, cada una de un turno
+type fakeInvoices struct {
+	invoice.Service
+}
+
+func (i fakeInvoices) GetByID(id int) (domain.Invoice, error) {
+	return domain.Invoice{Id: id, Series: "A", Number: id, PatientId: 1, AppointmentId: 7, Status: domain.InvoiceIssued, IssueDate: "2026-06-20", Total: 100}, nil
+}
+
+// fakeLedger es la cuenta del paciente 1, que termina con 50 a favor
+type fakeLedger struct {
+	ledger.Service
+}
+
+var entries = []domain.LedgerEntry{
+	{Id: 1, PatientId: 1, Type: domain.EntryCharge, Date: "2026-03-01", Debit: 100, Amount: 100, Balance: 100, Description: "Factura A-00000001"},
+	{Id: 2, PatientId: 1, Type: domain.EntryCharge, Date: "2026-05-15", Debit: 50, Amount: 50, Balance: 150, Description: "Cancelacion tardia"},
+	{Id: 3, PatientId: 1, Type: domain.EntryPayment, Date: "2026-06-25", Credit: 200, Amount: 200, Balance: -50, Method: domain.PaymentCard,
+		Allocations: []domain.PaymentAllocation{{InvoiceId: 1, Amount: 100}}},
+}
+
+func (l fakeLedger) GetByID(id int) (domain.LedgerEntry, error) {
+	for _, e := range entries {
+		if e.Id == id {
+			return e, nil
+		}
+	}
+	return domain.LedgerEntry{}, errors.New(fmt.Sprintf("ledger entry %d not found", id))
+}
+
+func (l fakeLedger) GetByPatient(patientId int) ([]domain.LedgerEntry, error) {
+	return entries, nil
+}
+
+func (l fakeLedger) GetBalance(patientId int) (domain.PatientBalance, error) {
+	return domain.PatientBalance{PatientId: patientId, Balance: -50, Invoices: []domain.InvoiceBalance{}}, nil
+}
+
+func newService() Service {
+	return NewDocumentService(fakeRepository{}, fakeInvoices{}, fakeLedger{}, Clinic{Name: "Clinica Dental", TaxId: "30-12345678-9"})
+}
+
+// text devuelve los textos escritos en todas las hojas de un PDF
+func text(t *testing.T, file []byte) string {
+	t.Helper()
+	var b strings.Builder
+	for _, m := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllSubmatch(file, -1) {
+		r, err := zlib.NewReader(bytes.NewReader(m[1]))
+		if err != nil {
+			t.Fatalf("invalid page stream: %s", err)
+		}
+		content, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("invalid page stream: %s", err)
+		}
+		for _, s := range regexp.MustCompile(`\((.*?)\) Tj`).FindAllSubmatch(content, -1) {
+			b.Write(s[1])
+			b.WriteByte('\n')
+		}
+	}
+	return b.String()
+}
+
+func TestReceipt(t *testing.T) {
+	tests := []struct {
+		name     string
+		entryId  int
+		contains []string
+		err      string
+	}{
+		{name: "payment with its invoices", entryId: 3, contains: []string{"Recibo 00000003", "$ 200,00", "Tarjeta", "A-00000001", "$ 100,00", "Dr/a. Ana Lopez"}},
+		{name: "not a payment", entryId: 1, err: "ledger entry 1 is not a payment"},
+		{name: "unknown entry", entryId: 9, err: "ledger entry 9 not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := newService().Receipt(tt.entryId)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if doc.Name != "recibo-00000003.pdf" || doc.ContentType != "application/pdf" {
+				t.Fatalf("unexpected document %s %s", doc.Name, doc.ContentType)
+			}
+			content := text(t, doc.Content)
+			for _, s := range tt.contains {
+				if !strings.Contains(content, s) {
+					t.Fatalf("expected the receipt to contain %q, got %q", s, content)
+				}
+			}
+		})
+	}
+}
+
+func TestStatement(t *testing.T) {
+	tests := []struct {
+		name     string
+		from     string
+		to       string
+		contains []string
+		excludes []string
+		err      string
+	}{
+		{name: "period with opening balance", from: "2026-05-01", to: "2026-05-31",
+			contains: []string{"Del 2026-05-01 al 2026-05-31", "Saldo anterior", "Cargo - Cancelacion tardia", "Saldo a pagar", "$ 150,00"},
+			excludes: []string{"Factura A-00000001", "Pago"}},
+		{name: "whole account", to: "2026-06-30",
+			contains: []string{"Hasta 2026-06-30", "Cargo - Factura A-00000001", "Saldo a favor", "$ 50,00"},
+			excludes: []string{"Saldo anterior"}},
+		{name: "invalid date", from: "01/05/2026", to: "2026-05-31", err: "invalid from or to, must be in format: yyyy-mm-dd"},
+		{name: "from after to", from: "2026-06-01", to: "2026-05-31", err: "from can't be after to"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := newService().Statement(1, tt.from, tt.to)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if doc.Name != "resumen-1-"+tt.to+".pdf" {
+				t.Fatalf("unexpected document name %s", doc.Name)
+			}
+			content := text(t, doc.Content)
+			for _, s := range tt.contains {
+				if !strings.Contains(content, s) {
+					t.Fatalf("expected the statement to contain %q, got %q", s, content)
+				}
+			}
+			for _, s := range tt.excludes {
+				if strings.Contains(content, s) {
+					t.Fatalf("expected the statement not to contain %q, got %q", s, content)
+				}
+			}
+		})
+	}
+}
+
+func TestMoney(t *testing.T) {
+	tests := []struct {
+		value    float64
+		expected string
+	}{
+		{0, "$ 0,00"},
+		{5.5, "$ 5,50"},
+		{1234567.891, "$ 1.234.567,89"},
+		{999.999, "$ 1.000,00"},
+		{-50, "-$ 50,00"},
+	}
+	for _, tt := range tests {
+		if m := money(tt.value); m != tt.expected {
+			t.Fatalf("expected %v to be %q, got %q", tt.value, tt.expected, m)
+		}
+	}
+}
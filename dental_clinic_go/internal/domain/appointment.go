@@ -1,10 +1,64 @@
 package domain
 
+import "time"
+
+// Estados posibles de un turno
+const (
+	AppointmentScheduled  = "scheduled"
+	AppointmentConfirmed  = "confirmed"
+	AppointmentCancelled  = "cancelled"
+	AppointmentInProgress = "in_progress"
+	AppointmentCompleted  = "completed"
+	AppointmentNoShow     = "no_show"
+)
+
 type Appointment struct {
-	Id          int     `json:"id"`
-	Date        string  `json:"date"`
-	Hour        string  `json:"hour"`
-	Description string  `json:"description"`
-	Patient     Patient `json:"patient"`
-	Dentist     Dentist `json:"dentist"`
+	Id            int     `json:"id"`
+	Date          string  `json:"date"`
+	Hour          string  `json:"hour"`
+	Description   string  `json:"description"`
+	ProcedureCode string  `json:"procedure_code"`
+	Status        string  `json:"status"`
+	Patient       Patient `json:"patient"`
+	Dentist       Dentist `json:"dentist"`
+	// Alerts resume la historia medica del paciente, solo se completa al buscar un turno por id
+	Alerts []MedicalAlert `json:"alerts,omitempty"`
+	// Warnings son avisos de otros modulos que no impiden el turno, como un trabajo de laboratorio que no llega
+	Warnings []string `json:"warnings,omitempty"`
+	// Coverage estima lo que cubre la obra social o prepaga del paciente
+	Coverage *CoverageEstimate `json:"coverage,omitempty"`
+	// CancelledByPatient indica que el turno lo cancelo el paciente desde el portal o un link, solo se
+	// completa en el turno que devuelve la cancelacion y el que reciben los listeners
+	CancelledByPatient bool `json:"cancelled_by_patient,omitempty"`
+}
+
+// Start devuelve la fecha y hora de inicio del turno en la hora local
+func (a Appointment) Start() (time.Time, error) {
+	return time.ParseInLocation("2006-01-02 15:04:05", a.Date+" "+a.Hour, time.Local)
+}
+
+type AppointmentStatus struct {
+	Status string `json:"status"`
+	// ByPatient indica que el paciente pidio la cancelacion, cuenta para la politica de cancelaciones tardias
+	ByPatient bool `json:"by_patient"`
+}
+
+type AppointmentLinks struct {
+	ConfirmUrl string `json:"confirm_url"`
+	CancelUrl  string `json:"cancel_url"`
+	ExpiresAt  string `json:"expires_at"`
+}
+
+type AppointmentLink struct {
+	Action      string            `json:"action"`
+	Appointment LinkedAppointment `json:"appointment"`
+}
+
+// LinkedAppointment es lo que se muestra de un turno a quien abre un link firmado. Los links se reenvian,
+// asi que no incluye datos del paciente ni su historia medica.
+type LinkedAppointment struct {
+	Date    string `json:"date"`
+	Hour    string `json:"hour"`
+	Dentist string `json:"dentist"`
+	Status  string `json:"status"`
 }
@@ -0,0 +1,17 @@
+package domain
+
+// Attachment es la metadata de un archivo de un paciente (radiografia, foto, documento),
+// el contenido se guarda en el blob storage bajo StorageKey
+type Attachment struct {
+	Id            int    `json:"id"`
+	PatientId     int    `json:"patient_id"`
+	AppointmentId int    `json:"appointment_id"`
+	FileName      string `json:"file_name"`
+	Description   string `json:"description"`
+	ContentType   string `json:"content_type"`
+	Size          int64  `json:"size"`
+	Checksum      string `json:"checksum"`
+	StorageKey    string `json:"-"`
+	HasThumbnail  bool   `json:"has_thumbnail"`
+	CreatedAt     string `json:"created_at"`
+}
@@ -0,0 +1,48 @@
+package domain
+
+// Condiciones que se pueden registrar en el odontograma, healthy limpia una condicion previa
+const (
+	ConditionHealthy = "healthy"
+	ConditionCaries  = "caries"
+	ConditionFilling = "filling"
+	ConditionCrown   = "crown"
+	ConditionMissing = "missing"
+	ConditionImplant = "implant"
+)
+
+// Denticiones segun la numeracion FDI
+const (
+	DentitionPermanent = "permanent"
+	DentitionDeciduous = "deciduous"
+)
+
+type ChartEntry struct {
+	Id            int     `json:"id"`
+	PatientId     int     `json:"patient_id"`
+	Tooth         int     `json:"tooth"`
+	Surfaces      string  `json:"surfaces"`
+	Condition     string  `json:"condition"`
+	ProcedureCode string  `json:"procedure_code"`
+	Date          string  `json:"date"`
+	Notes         string  `json:"notes"`
+	Dentist       Dentist `json:"dentist"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+type ToothCondition struct {
+	Surface   string `json:"surface,omitempty"`
+	Condition string `json:"condition"`
+	Date      string `json:"date"`
+	EntryId   int    `json:"entry_id"`
+}
+
+type ToothChart struct {
+	Tooth      int              `json:"tooth"`
+	Dentition  string           `json:"dentition"`
+	Conditions []ToothCondition `json:"conditions"`
+}
+
+type Chart struct {
+	PatientId int          `json:"patient_id"`
+	Teeth     []ToothChart `json:"teeth"`
+}
@@ -0,0 +1,57 @@
+package domain
+
+// CommissionPlan es lo que cobra un dentista sobre lo que produce. DefaultPercent se aplica a los
+// procedimientos que no tienen una regla propia.
+type CommissionPlan struct {
+	DentistId      int              `json:"dentist_id"`
+	DefaultPercent float64          `json:"default_percent"`
+	Rules          []CommissionRule `json:"rules"`
+}
+
+type CommissionRule struct {
+	ProcedureCode string  `json:"procedure_code"`
+	Percent       float64 `json:"percent"`
+}
+
+// BilledItem es un item de una factura emitida por un turno completado, con el dentista que lo hizo
+type BilledItem struct {
+	Dentist       Dentist
+	AppointmentId int
+	Date          string
+	ProcedureCode string
+	Description   string
+	Quantity      int
+	// Amount es el importe del item sin impuestos y con los descuentos aplicados
+	Amount float64
+}
+
+type ProductionReport struct {
+	From     string              `json:"from"`
+	To       string              `json:"to"`
+	Dentists []DentistProduction `json:"dentists"`
+	// Production y Commission suman los de todos los dentistas
+	Production float64 `json:"production"`
+	Commission float64 `json:"commission"`
+}
+
+type DentistProduction struct {
+	DentistId int    `json:"dentist_id"`
+	Name      string `json:"name"`
+	LastName  string `json:"last_name"`
+	License   string `json:"license"`
+	// CompletedAppointments son los turnos completados del periodo, BilledAppointments los que tienen factura emitida
+	CompletedAppointments int                   `json:"completed_appointments"`
+	BilledAppointments    int                   `json:"billed_appointments"`
+	Procedures            []ProcedureProduction `json:"procedures"`
+	Production            float64               `json:"production"`
+	Commission            float64               `json:"commission"`
+}
+
+type ProcedureProduction struct {
+	ProcedureCode string  `json:"procedure_code"`
+	Description   string  `json:"description"`
+	Quantity      int     `json:"quantity"`
+	Amount        float64 `json:"amount"`
+	Percent       float64 `json:"percent"`
+	Commission    float64 `json:"commission"`
+}
@@ -0,0 +1,43 @@
+package domain
+
+// Estados de un consentimiento
+const (
+	ConsentPending = "pending"
+	ConsentSigned  = "signed"
+)
+
+// ConsentTemplate es el texto de un consentimiento informado y los procedimientos que lo requieren
+type ConsentTemplate struct {
+	Id         int      `json:"id"`
+	Name       string   `json:"name"`
+	Body       string   `json:"body"`
+	Procedures []string `json:"procedures"`
+	Active     bool     `json:"active"`
+	CreatedAt  string   `json:"created_at"`
+}
+
+// Consent es un consentimiento de un paciente para un turno o un plan de tratamiento.
+// Body guarda el texto de la plantilla al momento de crearlo, asi los cambios posteriores
+// de la plantilla no alteran lo que el paciente firmo.
+type Consent struct {
+	Id              int    `json:"id"`
+	TemplateId      int    `json:"template_id"`
+	PatientId       int    `json:"patient_id"`
+	AppointmentId   int    `json:"appointment_id"`
+	TreatmentPlanId int    `json:"treatment_plan_id"`
+	Name            string `json:"name"`
+	Body            string `json:"body"`
+	Status          string `json:"status"`
+	SignerName      string `json:"signer_name"`
+	SignatureKey    string `json:"-"`
+	SignedAt        string `json:"signed_at"`
+	SignedIp        string `json:"signed_ip"`
+	CreatedAt       string `json:"created_at"`
+}
+
+// ConsentSignature es la firma dibujada por el paciente, Signature es una imagen PNG o JPEG
+// en base64, se acepta con el prefijo data:image/png;base64,
+type ConsentSignature struct {
+	SignerName string `json:"signer_name"`
+	Signature  string `json:"signature"`
+}
@@ -1,8 +1,42 @@
 package domain
 
+// Especialidades de los dentistas, un dentista sin especialidades es odontologo general
+const (
+	SpecialtyOrthodontics = "orthodontics"
+	SpecialtyEndodontics  = "endodontics"
+	SpecialtyPeriodontics = "periodontics"
+	SpecialtyOralSurgery  = "oral_surgery"
+	SpecialtyPediatric    = "pediatric"
+)
+
+// Specialties son los codigos de especialidad validos, los mismos que la tabla specialty
+var Specialties = []string{SpecialtyOrthodontics, SpecialtyEndodontics, SpecialtyPeriodontics, SpecialtyOralSurgery, SpecialtyPediatric}
+
 type Dentist struct {
 	Id       int    `json:"id"`
 	Name     string `json:"name" `
 	LastName string `json:"last_name" `
 	License  string `json:"license" `
+	// Specialties solo se completa al buscar el dentista, nil en un update las deja como estaban
+	Specialties []string `json:"specialties,omitempty" `
+}
+
+// Empty indica si el dentista no tiene ningun dato cargado
+func (d Dentist) Empty() bool {
+	return d.Id == 0 && d.Name == "" && d.LastName == "" && d.License == ""
+}
+
+// HasSpecialty indica si el dentista tiene la especialidad
+func (d Dentist) HasSpecialty(specialty string) bool {
+	for _, s := range d.Specialties {
+		if s == specialty {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidSpecialty indica si el codigo es una especialidad conocida
+func ValidSpecialty(specialty string) bool {
+	return Dentist{Specialties: Specialties}.HasSpecialty(specialty)
 }
@@ -0,0 +1,21 @@
+package domain
+
+// Document es un archivo generado por la clinica, como una factura o un recibo en PDF
+type Document struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
+
+// Statement es el resumen de cuenta de un paciente en un periodo
+type Statement struct {
+	Patient        Patient
+	From           string
+	To             string
+	OpeningBalance float64
+	Entries        []LedgerEntry
+	ClosingBalance float64
+	// Invoices son las facturas con saldo pendiente al momento de generar el resumen
+	Invoices []InvoiceBalance
+	Aging    AgingBuckets
+}
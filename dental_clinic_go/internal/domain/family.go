@@ -0,0 +1,54 @@
+package domain
+
+// Relaciones entre pacientes, Related es el guardian, padre o conyuge de Patient
+const (
+	RelationGuardian = "guardian"
+	RelationParent   = "parent"
+	RelationSpouse   = "spouse"
+	// RelationSelf indica que el paciente es su propio contacto
+	RelationSelf = "self"
+)
+
+type PatientRelation struct {
+	Id           int     `json:"id"`
+	PatientId    int     `json:"patient_id"`
+	RelatedId    int     `json:"related_id"`
+	Related      Patient `json:"related"`
+	Relationship string  `json:"relationship"`
+	// Responsible indica que el familiar recibe los recordatorios y las facturas del paciente,
+	// cada paciente tiene como maximo un responsable
+	Responsible bool   `json:"responsible"`
+	CreatedAt   string `json:"created_at"`
+}
+
+// Contact es la persona a la que se envian los recordatorios y las facturas de un paciente
+type Contact struct {
+	PatientId    int    `json:"patient_id"`
+	ContactId    int    `json:"contact_id"`
+	Name         string `json:"name"`
+	LastName     string `json:"last_name"`
+	Email        string `json:"email"`
+	Relationship string `json:"relationship"`
+}
+
+type ContactDesignation struct {
+	// RelationId es la relacion que pasa a ser responsable, 0 para que el paciente sea su propio contacto
+	RelationId int `json:"relation_id"`
+}
+
+type FamilyMember struct {
+	Patient Patient `json:"patient"`
+	Contact Contact `json:"contact"`
+}
+
+type Family struct {
+	PatientId int               `json:"patient_id"`
+	Members   []FamilyMember    `json:"members"`
+	Relations []PatientRelation `json:"relations"`
+}
+
+type AppointmentReminder struct {
+	AppointmentId int              `json:"appointment_id"`
+	Contact       Contact          `json:"contact"`
+	Links         AppointmentLinks `json:"links"`
+}
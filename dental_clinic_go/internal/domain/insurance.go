@@ -0,0 +1,135 @@
+package domain
+
+// Tipos de financiador
+const (
+	InsurerObraSocial = "obra_social"
+	InsurerPrepaga    = "prepaga"
+)
+
+// Formatos en los que se exportan los lotes de prestaciones
+const (
+	ClaimFormatCsv        = "csv"
+	ClaimFormatFixedWidth = "fixed_width"
+)
+
+// Estados de un lote de prestaciones
+const (
+	ClaimBatchOpen      = "open"
+	ClaimBatchSubmitted = "submitted"
+	ClaimBatchPaid      = "paid"
+)
+
+type Insurer struct {
+	Id   int    `json:"id"`
+	Code string `json:"code"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// ClaimFormat es el formato del archivo con el que se presentan las prestaciones
+	ClaimFormat string          `json:"claim_format"`
+	Plans       []InsurancePlan `json:"plans,omitempty"`
+}
+
+type InsurancePlan struct {
+	Id        int            `json:"id"`
+	InsurerId int            `json:"insurer_id"`
+	Code      string         `json:"code"`
+	Name      string         `json:"name"`
+	Rules     []CoverageRule `json:"rules,omitempty"`
+}
+
+// CoverageRule es lo que cubre un plan de un procedimiento
+type CoverageRule struct {
+	Id            int    `json:"id"`
+	PlanId        int    `json:"plan_id"`
+	ProcedureCode string `json:"procedure_code"`
+	// Percent es el porcentaje del arancel que paga el financiador
+	Percent float64 `json:"percent"`
+	// Copay es el coseguro fijo que paga el paciente y se descuenta de lo que cubre el plan
+	Copay float64 `json:"copay"`
+	// AnnualLimit es la cantidad de veces por año calendario que se cubre, 0 no tiene limite
+	AnnualLimit int `json:"annual_limit"`
+}
+
+// PatientCoverage es la afiliacion de un paciente a un plan
+type PatientCoverage struct {
+	Id           int    `json:"id"`
+	PatientId    int    `json:"patient_id"`
+	PlanId       int    `json:"plan_id"`
+	PlanCode     string `json:"plan_code"`
+	PlanName     string `json:"plan_name"`
+	InsurerId    int    `json:"insurer_id"`
+	InsurerName  string `json:"insurer_name"`
+	MemberNumber string `json:"member_number"`
+	ValidFrom    string `json:"valid_from"`
+	// ValidTo vacio es una cobertura sin vencimiento
+	ValidTo string `json:"valid_to"`
+}
+
+// Covers indica si la cobertura esta vigente en una fecha yyyy-mm-dd
+func (c PatientCoverage) Covers(date string) bool {
+	return c.ValidFrom <= date && (c.ValidTo == "" || date <= c.ValidTo)
+}
+
+// CoverageEstimate es lo que pagaria el financiador y el paciente por un procedimiento
+type CoverageEstimate struct {
+	CoverageId    int     `json:"coverage_id"`
+	InsurerName   string  `json:"insurer_name"`
+	PlanName      string  `json:"plan_name"`
+	MemberNumber  string  `json:"member_number"`
+	ProcedureCode string  `json:"procedure_code"`
+	Date          string  `json:"date"`
+	Fee           float64 `json:"fee"`
+	Percent       float64 `json:"percent"`
+	Copay         float64 `json:"copay"`
+	Covered       float64 `json:"covered"`
+	PatientPays   float64 `json:"patient_pays"`
+	AnnualLimit   int     `json:"annual_limit"`
+	// Used son las veces que el paciente ya se hizo o agendo el procedimiento en el año
+	Used int `json:"used"`
+	// Notes explica por que el plan no cubre el procedimiento
+	Notes string `json:"notes"`
+}
+
+// Claim es una prestacion presentada a un financiador
+type Claim struct {
+	Id              int     `json:"id"`
+	BatchId         int     `json:"batch_id"`
+	AppointmentId   int     `json:"appointment_id"`
+	CoverageId      int     `json:"coverage_id"`
+	PatientId       int     `json:"patient_id"`
+	PatientName     string  `json:"patient_name"`
+	PatientLastName string  `json:"patient_last_name"`
+	PatientDni      int     `json:"patient_dni"`
+	MemberNumber    string  `json:"member_number"`
+	PlanCode        string  `json:"plan_code"`
+	ProcedureCode   string  `json:"procedure_code"`
+	Date            string  `json:"date"`
+	Fee             float64 `json:"fee"`
+	Copay           float64 `json:"copay"`
+	Amount          float64 `json:"amount"`
+}
+
+// ClaimBatch agrupa las prestaciones de un financiador en un periodo
+type ClaimBatch struct {
+	Id          int     `json:"id"`
+	InsurerId   int     `json:"insurer_id"`
+	InsurerCode string  `json:"insurer_code"`
+	InsurerName string  `json:"insurer_name"`
+	From        string  `json:"from"`
+	To          string  `json:"to"`
+	Status      string  `json:"status"`
+	Claims      []Claim `json:"claims,omitempty"`
+	Total       float64 `json:"total"`
+	CreatedAt   string  `json:"created_at"`
+}
+
+type ClaimBatchStatus struct {
+	Status string `json:"status"`
+}
+
+// ClaimFile es un lote exportado en el formato de su financiador
+type ClaimFile struct {
+	Name        string
+	ContentType string
+	Content     []byte
+}
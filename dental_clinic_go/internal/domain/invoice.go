@@ -0,0 +1,63 @@
+package domain
+
+import "fmt"
+
+// Estados de una factura
+const (
+	InvoiceDraft  = "draft"
+	InvoiceIssued = "issued"
+	InvoiceVoid   = "void"
+)
+
+type Invoice struct {
+	Id int `json:"id"`
+	// Series y Number se asignan al emitir la factura, los borradores no consumen numeros
+	Series    string `json:"series"`
+	Number    int    `json:"number"`
+	PatientId int    `json:"patient_id"`
+	// BillTo es el familiar responsable o el mismo paciente al momento de crear la factura
+	BillTo        Contact       `json:"bill_to"`
+	AppointmentId int           `json:"appointment_id"`
+	Status        string        `json:"status"`
+	IssueDate     string        `json:"issue_date"`
+	Items         []InvoiceItem `json:"items"`
+	Subtotal      float64       `json:"subtotal"`
+	Discount      float64       `json:"discount"`
+	Tax           float64       `json:"tax"`
+	Total         float64       `json:"total"`
+	Notes         string        `json:"notes"`
+	VoidReason    string        `json:"void_reason"`
+	CreatedAt     string        `json:"created_at"`
+}
+
+type InvoiceItem struct {
+	Id            int    `json:"id"`
+	ProcedureCode string `json:"procedure_code"`
+	Description   string `json:"description"`
+	Tooth         int    `json:"tooth"`
+	Quantity      int    `json:"quantity"`
+	// UnitPrice vacio toma el precio de la lista vigente
+	UnitPrice float64 `json:"unit_price"`
+	// DiscountPercent y TaxRate son porcentajes
+	DiscountPercent float64 `json:"discount_percent"`
+	// DiscountFixed es un monto que se descuenta por unidad ademas de DiscountPercent
+	DiscountFixed float64 `json:"discount_fixed"`
+	// TaxRate sin informar toma la alicuota por defecto de la clinica, 0 es un item exento
+	TaxRate  *float64 `json:"tax_rate"`
+	Subtotal float64  `json:"subtotal"`
+	Discount float64  `json:"discount"`
+	Tax      float64  `json:"tax"`
+	Total    float64  `json:"total"`
+}
+
+type InvoiceVoidRequest struct {
+	Reason string `json:"reason"`
+}
+
+// FullNumber devuelve la serie y el numero de una factura emitida, vacio si es un borrador
+func (i Invoice) FullNumber() string {
+	if i.Number == 0 {
+		return ""
+	}
+	return fmt.Sprintf("%s-%08d", i.Series, i.Number)
+}
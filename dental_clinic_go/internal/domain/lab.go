@@ -0,0 +1,34 @@
+package domain
+
+// Estados de una orden de laboratorio
+const (
+	LabSent          = "sent"
+	LabInFabrication = "in_fabrication"
+	LabReceived      = "received"
+	LabFitted        = "fitted"
+)
+
+type LabOrder struct {
+	Id              int     `json:"id"`
+	PatientId       int     `json:"patient_id"`
+	Dentist         Dentist `json:"dentist"`
+	TreatmentItemId int     `json:"treatment_item_id"`
+	Lab             string  `json:"lab"`
+	Description     string  `json:"description"`
+	Status          string  `json:"status"`
+	SentDate        string  `json:"sent_date"`
+	ExpectedDate    string  `json:"expected_date"`
+	ReceivedDate    string  `json:"received_date"`
+	FittedDate      string  `json:"fitted_date"`
+	Notes           string  `json:"notes"`
+	// FittingAppointmentId es el turno en el que esta agendado el procedimiento del plan, 0 si no esta agendado
+	FittingAppointmentId int    `json:"fitting_appointment_id"`
+	FittingDate          string `json:"fitting_date"`
+	// Warning avisa si la colocacion esta agendada antes de la fecha de entrega del laboratorio
+	Warning   string `json:"warning,omitempty"`
+	CreatedAt string `json:"created_at"`
+}
+
+type LabOrderStatus struct {
+	Status string `json:"status"`
+}
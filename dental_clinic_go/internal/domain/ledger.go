@@ -0,0 +1,88 @@
+package domain
+
+// Tipos de movimiento de la cuenta de un paciente
+const (
+	EntryCharge     = "charge"
+	EntryPayment    = "payment"
+	EntryRefund     = "refund"
+	EntryAdjustment = "adjustment"
+)
+
+// Medios de pago de los pagos y reintegros
+const (
+	PaymentCash     = "cash"
+	PaymentCard     = "card"
+	PaymentTransfer = "transfer"
+)
+
+// LedgerEntry es un movimiento de la cuenta de un paciente. Los cargos y reintegros van al debe,
+// los pagos al haber y los ajustes a uno u otro segun su signo. El saldo es debe menos haber.
+type LedgerEntry struct {
+	Id        int    `json:"id"`
+	PatientId int    `json:"patient_id"`
+	Type      string `json:"type"`
+	Date      string `json:"date"`
+	// Amount es positivo salvo en los ajustes, donde un importe negativo es a favor del paciente
+	Amount float64 `json:"amount"`
+	Debit  float64 `json:"debit"`
+	Credit float64 `json:"credit"`
+	Method string  `json:"method"`
+	// InvoiceId es la factura que origina un cargo o cuya anulacion origina un ajuste
+	InvoiceId   int                 `json:"invoice_id"`
+	Reference   string              `json:"reference"`
+	Description string              `json:"description"`
+	Allocations []PaymentAllocation `json:"allocations,omitempty"`
+	// Balance es el saldo de la cuenta despues del movimiento, solo se completa al listar la cuenta
+	Balance   float64 `json:"balance"`
+	CreatedAt string  `json:"created_at"`
+}
+
+// PaymentAllocation imputa parte de un pago a una factura
+type PaymentAllocation struct {
+	Id        int     `json:"id"`
+	EntryId   int     `json:"entry_id"`
+	InvoiceId int     `json:"invoice_id"`
+	Amount    float64 `json:"amount"`
+}
+
+type AgingBuckets struct {
+	Days0To30  float64 `json:"days_0_30"`
+	Days31To60 float64 `json:"days_31_60"`
+	Days61To90 float64 `json:"days_61_90"`
+	Over90     float64 `json:"days_over_90"`
+	Total      float64 `json:"total"`
+}
+
+type InvoiceBalance struct {
+	InvoiceId int     `json:"invoice_id"`
+	Number    string  `json:"number"`
+	IssueDate string  `json:"issue_date"`
+	Total     float64 `json:"total"`
+	Paid      float64 `json:"paid"`
+	Due       float64 `json:"due"`
+}
+
+type PatientBalance struct {
+	PatientId   int     `json:"patient_id"`
+	Charges     float64 `json:"charges"`
+	Payments    float64 `json:"payments"`
+	Refunds     float64 `json:"refunds"`
+	Adjustments float64 `json:"adjustments"`
+	// Balance positivo es lo que debe el paciente, negativo es saldo a su favor
+	Balance  float64          `json:"balance"`
+	Aging    AgingBuckets     `json:"aging"`
+	Invoices []InvoiceBalance `json:"invoices"`
+}
+
+type PatientAging struct {
+	PatientId int          `json:"patient_id"`
+	Name      string       `json:"name"`
+	LastName  string       `json:"last_name"`
+	Aging     AgingBuckets `json:"aging"`
+}
+
+type AgingReport struct {
+	Date     string         `json:"date"`
+	Total    AgingBuckets   `json:"total"`
+	Patients []PatientAging `json:"patients"`
+}
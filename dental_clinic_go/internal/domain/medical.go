@@ -0,0 +1,63 @@
+package domain
+
+// Severidad de una alergia
+const (
+	SeverityMild     = "mild"
+	SeverityModerate = "moderate"
+	SeveritySevere   = "severe"
+)
+
+// Tipos de alerta medica que se muestran con el turno
+const (
+	AlertAllergy    = "allergy"
+	AlertMedication = "medication"
+	AlertCondition  = "condition"
+)
+
+type Allergy struct {
+	Id        int    `json:"id"`
+	PatientId int    `json:"patient_id"`
+	Substance string `json:"substance"`
+	Severity  string `json:"severity"`
+	Reaction  string `json:"reaction"`
+	CreatedAt string `json:"created_at"`
+}
+
+type Medication struct {
+	Id        int    `json:"id"`
+	PatientId int    `json:"patient_id"`
+	Name      string `json:"name"`
+	Dose      string `json:"dose"`
+	Frequency string `json:"frequency"`
+	CreatedAt string `json:"created_at"`
+}
+
+// MedicalCondition es una enfermedad o situacion a tener en cuenta, por ejemplo diabetes,
+// tratamiento con anticoagulantes o embarazo
+type MedicalCondition struct {
+	Id        int    `json:"id"`
+	PatientId int    `json:"patient_id"`
+	Name      string `json:"name"`
+	Notes     string `json:"notes"`
+	CreatedAt string `json:"created_at"`
+}
+
+type MedicalHistory struct {
+	PatientId    int                `json:"patient_id"`
+	Allergies    []Allergy          `json:"allergies"`
+	Medications  []Medication       `json:"medications"`
+	Conditions   []MedicalCondition `json:"conditions"`
+	LastReviewed string             `json:"last_reviewed"`
+}
+
+// MedicalReview registra la fecha en que se reviso la historia medica con el paciente
+type MedicalReview struct {
+	Date string `json:"date"`
+}
+
+// MedicalAlert resume un dato de la historia medica a tener en cuenta antes de atender
+type MedicalAlert struct {
+	Type        string `json:"type"`
+	Description string `json:"description"`
+	Severity    string `json:"severity,omitempty"`
+}
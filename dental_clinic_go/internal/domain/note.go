@@ -0,0 +1,33 @@
+package domain
+
+// Estados de una nota clinica, una nota firmada ya no se puede modificar
+const (
+	NoteDraft  = "draft"
+	NoteSigned = "signed"
+)
+
+// ClinicalNote es la nota de evolucion de un turno con secciones SOAP
+type ClinicalNote struct {
+	Id            int `json:"id"`
+	AppointmentId int `json:"appointment_id"`
+	// Dentist es el autor, el dentista vinculado al usuario que la escribe
+	Dentist    Dentist            `json:"dentist"`
+	Subjective string             `json:"subjective"`
+	Objective  string             `json:"objective"`
+	Assessment string             `json:"assessment"`
+	Plan       string             `json:"plan"`
+	Status     string             `json:"status"`
+	CreatedAt  string             `json:"created_at"`
+	SignedAt   string             `json:"signed_at"`
+	Addenda    []ClinicalAddendum `json:"addenda"`
+}
+
+// ClinicalAddendum es una correccion o agregado a una nota firmada
+type ClinicalAddendum struct {
+	Id     int `json:"id"`
+	NoteId int `json:"note_id"`
+	// Dentist es el autor, el dentista vinculado al usuario que la escribe
+	Dentist   Dentist `json:"dentist"`
+	Text      string  `json:"text"`
+	CreatedAt string  `json:"created_at"`
+}
@@ -1,11 +1,98 @@
 package domain
 
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// Tipos de telefono de un paciente
+const (
+	PhoneMobile = "mobile"
+	PhoneHome   = "home"
+	PhoneWork   = "work"
+)
+
+// Canales por los que el paciente prefiere que lo contacten
+const (
+	ChannelEmail    = "email"
+	ChannelSms      = "sms"
+	ChannelPhone    = "phone"
+	ChannelWhatsapp = "whatsapp"
+)
+
 type Patient struct {
-	Id            int    `json:"id"`
-	Name          string `json:"name" `
-	LastName      string `json:"last_name" `
-	Domicilio     string `json:"domicilio" `
-	Dni           int    `json:"dni" `
-	Email         string `json:"email" `
-	AdmissionDate string `json:"admission_date" `
+	Id       int     `json:"id"`
+	Name     string  `json:"name" `
+	LastName string  `json:"last_name" `
+	Address  Address `json:"address" `
+	Dni      int     `json:"dni" `
+	// BirthDate es opcional, sin ella Age queda en 0
+	BirthDate          string             `json:"birth_date" `
+	Age                int                `json:"age"`
+	Email              string             `json:"email" `
+	Phones             []Phone            `json:"phones,omitempty" `
+	PreferredLanguage  string             `json:"preferred_language" `
+	ContactPreferences ContactPreferences `json:"contact_preferences" `
+	AdmissionDate      string             `json:"admission_date" `
+	// Campos calculados por la politica de ausencias, no se guardan en la tabla patient
+	NoShows           int  `json:"no_shows"`
+	LateCancellations int  `json:"late_cancellations"`
+	BookingBlocked    bool `json:"booking_blocked"`
+}
+
+type Address struct {
+	Street     string `json:"street"`
+	Number     string `json:"number"`
+	City       string `json:"city"`
+	Province   string `json:"province"`
+	PostalCode string `json:"postal_code"`
+}
+
+type Phone struct {
+	Type    string `json:"type"`
+	Number  string `json:"number"`
+	Primary bool   `json:"primary"`
+}
+
+type ContactPreferences struct {
+	Channel string `json:"channel"`
+	// Reminders indica si el paciente acepta recordatorios de turnos, nil en un update lo deja como estaba
+	Reminders *bool `json:"reminders"`
+}
+
+// Line devuelve la calle y el numero en una sola linea, como se guardaba el domicilio antes de separarlo
+func (a Address) Line() string {
+	return strings.TrimSpace(a.Street + " " + a.Number)
+}
+
+// MarshalJSON agrega domicilio, el campo de las versiones anteriores de la API, armado desde Address. Es de
+// solo lectura y se va a quitar, los clientes tienen que usar address.
+func (p Patient) MarshalJSON() ([]byte, error) {
+	type patient Patient
+	return json.Marshal(struct {
+		patient
+		Domicilio string `json:"domicilio"`
+	}{patient(p), p.Address.Line()})
+}
+
+// Empty indica si el paciente no tiene ningun dato cargado
+func (p Patient) Empty() bool {
+	return p.Id == 0 && p.Name == "" && p.LastName == "" && p.Dni == 0 && p.Email == ""
+}
+
+// AgeAt devuelve la edad del paciente en una fecha, 0 si no tiene fecha de nacimiento
+func (p Patient) AgeAt(now time.Time) int {
+	birth, err := time.Parse("2006-01-02", p.BirthDate)
+	if err != nil {
+		return 0
+	}
+	age := now.Year() - birth.Year()
+	if now.Month() < birth.Month() || (now.Month() == birth.Month() && now.Day() < birth.Day()) {
+		age--
+	}
+	if age < 0 {
+		return 0
+	}
+	return age
 }
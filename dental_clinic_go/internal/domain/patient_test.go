@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+)
+
+func TestPatientDomicilio(t *testing.T) {
+	tests := []struct {
+		name      string
+		address   Address
+		domicilio string
+	}{
+		{name: "street and number", address: Address{Street: "Av. Siempre Viva", Number: "742", City: "Springfield"}, domicilio: "Av. Siempre Viva 742"},
+		{name: "street only", address: Address{Street: "Ruta 2 km 40"}, domicilio: "Ruta 2 km 40"},
+		{name: "empty", domicilio: ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			body, err := json.Marshal(Patient{Id: 1, Name: "Ana", Address: tt.address})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			var fields map[string]interface{}
+			if err = json.Unmarshal(body, &fields); err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if fields["domicilio"] != tt.domicilio || fields["name"] != "Ana" || fields["address"] == nil {
+				t.Fatalf("expected domicilio %q next to the other fields, got %s", tt.domicilio, body)
+			}
+		})
+	}
+
+	// domicilio es de solo lectura, al recibirlo no cambia el domicilio
+	var p Patient
+	err := json.Unmarshal([]byte(`{"name": "Ana", "domicilio": "Calle 123"}`), &p)
+	if err != nil || p.Address != (Address{}) {
+		t.Fatalf("expected domicilio to be ignored, got %+v (%v)", p.Address, err)
+	}
+}
+
+func TestPatientAgeAt(t *testing.T) {
+	now := time.Date(2026, 10, 19, 0, 0, 0, 0, time.UTC)
+	tests := []struct {
+		birthDate string
+		age       int
+	}{
+		{"1990-10-19", 36},
+		{"1990-10-20", 35},
+		{"2030-01-01", 0},
+		{"", 0},
+	}
+	for _, tt := range tests {
+		if age := (Patient{BirthDate: tt.birthDate}).AgeAt(now); age != tt.age {
+			t.Errorf("birth date %q: expected age %d, got %d", tt.birthDate, tt.age, age)
+		}
+	}
+}
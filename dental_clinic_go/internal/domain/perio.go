@@ -0,0 +1,78 @@
+package domain
+
+// PerioSites son los seis sitios de sondaje de cada pieza: mesiovestibular, vestibular,
+// distovestibular, mesiolingual, lingual y distolingual
+var PerioSites = []string{"MB", "B", "DB", "ML", "L", "DL"}
+
+type PerioExam struct {
+	Id        int          `json:"id"`
+	PatientId int          `json:"patient_id"`
+	Dentist   Dentist      `json:"dentist"`
+	Date      string       `json:"date"`
+	Notes     string       `json:"notes"`
+	Teeth     []PerioTooth `json:"teeth"`
+	CreatedAt string       `json:"created_at"`
+}
+
+type PerioTooth struct {
+	Tooth int `json:"tooth"`
+	// Mobility es el grado de movilidad de 0 a 3
+	Mobility int         `json:"mobility"`
+	Sites    []PerioSite `json:"sites"`
+}
+
+// PerioSite guarda las medidas en milimetros de un sitio, la recesion es negativa
+// cuando el margen gingival queda por encima del limite amelocementario
+type PerioSite struct {
+	Site      string `json:"site"`
+	Depth     int    `json:"depth"`
+	Recession int    `json:"recession"`
+	Bleeding  bool   `json:"bleeding"`
+	// AttachmentLoss es la perdida de insercion clinica, profundidad mas recesion
+	AttachmentLoss int `json:"attachment_loss"`
+}
+
+type PerioStats struct {
+	ExamId          int     `json:"exam_id"`
+	Date            string  `json:"date"`
+	Sites           int     `json:"sites"`
+	MeanDepth       float64 `json:"mean_depth"`
+	MeanAttachment  float64 `json:"mean_attachment_loss"`
+	BleedingPercent float64 `json:"bleeding_percent"`
+	// DeepSites son los sitios con bolsas de 5 mm o mas
+	DeepSites int `json:"deep_sites"`
+}
+
+type PerioSiteChange struct {
+	Tooth            int    `json:"tooth"`
+	Site             string `json:"site"`
+	DepthBefore      int    `json:"depth_before"`
+	DepthAfter       int    `json:"depth_after"`
+	AttachmentBefore int    `json:"attachment_loss_before"`
+	AttachmentAfter  int    `json:"attachment_loss_after"`
+	BleedingBefore   bool   `json:"bleeding_before"`
+	BleedingAfter    bool   `json:"bleeding_after"`
+	Worsened         bool   `json:"worsened"`
+}
+
+type PerioMobilityChange struct {
+	Tooth  int `json:"tooth"`
+	Before int `json:"before"`
+	After  int `json:"after"`
+}
+
+type PerioComparison struct {
+	PatientId int                   `json:"patient_id"`
+	From      PerioStats            `json:"from"`
+	To        PerioStats            `json:"to"`
+	Sites     []PerioSiteChange     `json:"sites"`
+	Mobility  []PerioMobilityChange `json:"mobility"`
+}
+
+type PerioSummary struct {
+	PatientId int `json:"patient_id"`
+	// Exams es la evolucion de los indices de cada examen en orden cronologico
+	Exams    []PerioStats          `json:"exams"`
+	Worsened []PerioSiteChange     `json:"worsened"`
+	Mobility []PerioMobilityChange `json:"mobility"`
+}
@@ -0,0 +1,22 @@
+package domain
+
+// Tipos de eventos de la politica de ausencias
+const (
+	PolicyNoShow           = "no_show"
+	PolicyLateCancellation = "late_cancellation"
+)
+
+type PolicyEvent struct {
+	Id            int     `json:"id"`
+	PatientId     int     `json:"patient_id"`
+	AppointmentId int     `json:"appointment_id"`
+	Type          string  `json:"type"`
+	Fee           float64 `json:"fee"`
+	CreatedAt     string  `json:"created_at"`
+}
+
+type PolicySummary struct {
+	NoShows           int  `json:"no_shows"`
+	LateCancellations int  `json:"late_cancellations"`
+	BookingBlocked    bool `json:"booking_blocked"`
+}
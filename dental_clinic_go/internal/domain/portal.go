@@ -0,0 +1,28 @@
+package domain
+
+type PortalCode struct {
+	Dni int `json:"dni"`
+}
+
+type PortalLogin struct {
+	Dni  int    `json:"dni"`
+	Code string `json:"code"`
+}
+
+type PortalSession struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+type PortalBooking struct {
+	License     string `json:"license"`
+	Date        string `json:"date"`
+	Hour        string `json:"hour"`
+	Description string `json:"description"`
+}
+
+type Availability struct {
+	Date    string   `json:"date"`
+	Dentist Dentist  `json:"dentist"`
+	Slots   []string `json:"slots"`
+}
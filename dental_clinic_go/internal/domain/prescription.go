@@ -0,0 +1,24 @@
+package domain
+
+type Prescription struct {
+	Id            int `json:"id"`
+	PatientId     int `json:"patient_id"`
+	AppointmentId int `json:"appointment_id"`
+	// Dentist es quien la firma, el dentista vinculado al usuario que la emite
+	Dentist Dentist            `json:"dentist"`
+	Date    string             `json:"date"`
+	Notes   string             `json:"notes"`
+	Items   []PrescriptionItem `json:"items"`
+	// AllergyOverride confirma que el dentista emite la receta aunque choque con una alergia registrada
+	AllergyOverride bool   `json:"allergy_override"`
+	CreatedAt       string `json:"created_at"`
+}
+
+type PrescriptionItem struct {
+	Id        int    `json:"id"`
+	Drug      string `json:"drug"`
+	Dose      string `json:"dose"`
+	Frequency string `json:"frequency"`
+	Duration  string `json:"duration"`
+	Notes     string `json:"notes"`
+}
@@ -0,0 +1,67 @@
+package domain
+
+// Tipos de descuento
+const (
+	DiscountPercentage = "percentage"
+	DiscountFixed      = "fixed"
+	// DiscountFamily es un porcentaje para los pacientes con familiares atendidos en la clinica
+	DiscountFamily = "family"
+)
+
+// PriceList es una version de los aranceles que rige desde EffectiveFrom hasta que entra en vigencia la siguiente
+type PriceList struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	// InsurerId es el financiador de una lista especial, 0 es la lista de pacientes particulares
+	InsurerId     int     `json:"insurer_id"`
+	EffectiveFrom string  `json:"effective_from"`
+	Prices        []Price `json:"prices,omitempty"`
+	// BaseListId e Increase crean la lista copiando los precios de otra con un aumento en porcentaje
+	BaseListId int     `json:"base_list_id,omitempty"`
+	Increase   float64 `json:"increase,omitempty"`
+	CreatedAt  string  `json:"created_at"`
+}
+
+type Price struct {
+	ProcedureCode string  `json:"procedure_code"`
+	Amount        float64 `json:"amount"`
+}
+
+type DiscountRule struct {
+	Id   int    `json:"id"`
+	Name string `json:"name"`
+	Type string `json:"type"`
+	// Value es un porcentaje para percentage y family, y un monto por unidad para fixed
+	Value float64 `json:"value"`
+	// ProcedureCode vacio aplica a todos los procedimientos
+	ProcedureCode string `json:"procedure_code"`
+	// MinFamilyMembers es la cantidad de integrantes de la familia, con el paciente, que pide un descuento family
+	MinFamilyMembers int    `json:"min_family_members"`
+	ValidFrom        string `json:"valid_from"`
+	// ValidTo vacio es un descuento sin vencimiento
+	ValidTo string `json:"valid_to"`
+	Active  bool   `json:"active"`
+}
+
+// Applies indica si el descuento esta activo en una fecha para un procedimiento
+func (d DiscountRule) Applies(procedureCode string, date string) bool {
+	return d.Active && (d.ProcedureCode == "" || d.ProcedureCode == procedureCode) &&
+		(d.ValidFrom == "" || d.ValidFrom <= date) && (d.ValidTo == "" || date <= d.ValidTo)
+}
+
+// PriceQuote es el precio de un procedimiento en una fecha con el mejor descuento que le corresponde al paciente
+type PriceQuote struct {
+	ProcedureCode string `json:"procedure_code"`
+	Date          string `json:"date"`
+	PatientId     int    `json:"patient_id"`
+	InsurerId     int    `json:"insurer_id"`
+	// PriceListId es 0 cuando ninguna lista tiene el procedimiento y se usa el arancel del catalogo
+	PriceListId   int     `json:"price_list_id"`
+	ListPrice     float64 `json:"list_price"`
+	DiscountId    int     `json:"discount_id"`
+	DiscountName  string  `json:"discount_name"`
+	DiscountType  string  `json:"discount_type"`
+	DiscountValue float64 `json:"discount_value"`
+	Discount      float64 `json:"discount"`
+	Price         float64 `json:"price"`
+}
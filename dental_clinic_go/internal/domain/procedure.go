@@ -0,0 +1,22 @@
+package domain
+
+type Procedure struct {
+	Code     string  `json:"code"`
+	Name     string  `json:"name"`
+	Category string  `json:"category"`
+	Fee      float64 `json:"default_fee"`
+	// Duration es la duracion por defecto en minutos
+	Duration int `json:"default_duration"`
+	// Teeth son las piezas FDI en las que se aplica separadas por coma, tambien acepta
+	// permanent o deciduous, vacio se aplica a cualquier pieza o a ninguna
+	Teeth string `json:"teeth"`
+	// Surfaces son las caras en las que se aplica, vacio se aplica a la pieza completa
+	Surfaces string `json:"surfaces"`
+	// Specialty es la especialidad que debe tener el dentista que lo realiza, vacio lo puede hacer cualquiera
+	Specialty string `json:"specialty"`
+}
+
+type ProcedureImport struct {
+	Created int `json:"created"`
+	Updated int `json:"updated"`
+}
@@ -0,0 +1,46 @@
+package domain
+
+// Estados de una derivacion
+const (
+	ReferralPending   = "pending"
+	ReferralAccepted  = "accepted"
+	ReferralRejected  = "rejected"
+	ReferralScheduled = "scheduled"
+	ReferralCancelled = "cancelled"
+)
+
+// Urgencias de una derivacion, de menor a mayor
+const (
+	UrgencyRoutine  = "routine"
+	UrgencyPriority = "priority"
+	UrgencyUrgent   = "urgent"
+)
+
+type Referral struct {
+	Id        int `json:"id"`
+	PatientId int `json:"patient_id"`
+	// FromDentist es el dentista vinculado al usuario que la crea
+	FromDentist Dentist `json:"from_dentist"`
+	// ToDentist es opcional si se deriva a una especialidad, se completa con el dentista que la acepta
+	ToDentist Dentist `json:"to_dentist"`
+	Specialty string  `json:"specialty"`
+	Reason    string  `json:"reason"`
+	Urgency   string  `json:"urgency"`
+	Status    string  `json:"status"`
+	// Response es el comentario del dentista que la acepta o el motivo del rechazo
+	Response string `json:"response"`
+	// AppointmentId es el turno agendado a partir de la derivacion, 0 si todavia no se agendo
+	AppointmentId int    `json:"appointment_id"`
+	CreatedAt     string `json:"created_at"`
+}
+
+type ReferralStatus struct {
+	Status   string `json:"status"`
+	Response string `json:"response"`
+}
+
+type ReferralAppointment struct {
+	Date          string `json:"date"`
+	Hour          string `json:"hour"`
+	ProcedureCode string `json:"procedure_code"`
+}
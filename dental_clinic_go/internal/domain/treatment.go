@@ -0,0 +1,59 @@
+package domain
+
+// Estados de un plan de tratamiento
+const (
+	PlanProposed   = "proposed"
+	PlanAccepted   = "accepted"
+	PlanInProgress = "in_progress"
+	PlanCompleted  = "completed"
+	PlanCancelled  = "cancelled"
+)
+
+// Estados de un procedimiento del plan
+const (
+	ItemPending   = "pending"
+	ItemScheduled = "scheduled"
+	ItemCompleted = "completed"
+	ItemCancelled = "cancelled"
+)
+
+type TreatmentPlan struct {
+	Id        int               `json:"id"`
+	PatientId int               `json:"patient_id"`
+	Dentist   Dentist           `json:"dentist"`
+	Status    string            `json:"status"`
+	Notes     string            `json:"notes"`
+	CreatedAt string            `json:"created_at"`
+	Items     []TreatmentItem   `json:"items"`
+	Progress  TreatmentProgress `json:"progress"`
+}
+
+type TreatmentItem struct {
+	Id            int     `json:"id"`
+	PlanId        int     `json:"plan_id"`
+	Position      int     `json:"position"`
+	Tooth         int     `json:"tooth"`
+	Surfaces      string  `json:"surfaces"`
+	ProcedureCode string  `json:"procedure_code"`
+	Description   string  `json:"description"`
+	EstimatedCost float64 `json:"estimated_cost"`
+	Status        string  `json:"status"`
+	AppointmentId int     `json:"appointment_id"`
+}
+
+type TreatmentProgress struct {
+	Total         int     `json:"total"`
+	Completed     int     `json:"completed"`
+	Percentage    float64 `json:"percentage"`
+	EstimatedCost float64 `json:"estimated_cost"`
+	CompletedCost float64 `json:"completed_cost"`
+}
+
+type TreatmentPlanStatus struct {
+	Status string `json:"status"`
+}
+
+type TreatmentItemAppointment struct {
+	Date string `json:"date"`
+	Hour string `json:"hour"`
+}
@@ -0,0 +1,73 @@
+package domain
+
+// Roles del personal, cada uno tiene un conjunto fijo de permisos
+const (
+	RoleAdmin        = "admin"
+	RoleDentist      = "dentist"
+	RoleHygienist    = "hygienist"
+	RoleReceptionist = "receptionist"
+	RoleBilling      = "billing"
+)
+
+// User es una cuenta del personal de la clinica
+type User struct {
+	Id       int    `json:"id"`
+	Username string `json:"username"`
+	Name     string `json:"name"`
+	LastName string `json:"last_name"`
+	Email    string `json:"email"`
+	Role     string `json:"role"`
+	// DentistId es el dentista que corresponde al usuario, 0 si no es dentista. Con el firma sus notas.
+	DentistId int `json:"dentist_id"`
+	// Password solo se usa al crear el usuario, nunca se devuelve
+	Password string `json:"password,omitempty"`
+	// Active en false impide el login y cierra las sesiones abiertas
+	Active    bool   `json:"active"`
+	CreatedAt string `json:"created_at"`
+	// Permissions son los permisos del rol, solo se devuelven al consultar el usuario actual
+	Permissions []string `json:"permissions,omitempty"`
+}
+
+// Role es un rol con los permisos que otorga
+type Role struct {
+	Name        string   `json:"name"`
+	Permissions []string `json:"permissions"`
+}
+
+// UserSession es un login de un usuario, su refresh token cambia cada vez que se usa
+type UserSession struct {
+	Id     int
+	UserId int
+	// Role es el rol actual del usuario, un cambio de rol aplica sin esperar a que venza el access token
+	Role string
+	// DentistId es el dentista vinculado al usuario, 0 si no tiene
+	DentistId int
+	// Rotated indica que la sesion se encontro por un refresh token que ya fue reemplazado
+	Rotated bool
+	Revoked bool
+}
+
+type LoginRequest struct {
+	Username string `json:"username"`
+	Password string `json:"password"`
+}
+
+type RefreshRequest struct {
+	RefreshToken string `json:"refresh_token"`
+}
+
+type PasswordChange struct {
+	// CurrentPassword no se pide cuando un usuario le cambia la contraseña a otro
+	CurrentPassword string `json:"current_password"`
+	NewPassword     string `json:"new_password"`
+}
+
+// AuthTokens son las credenciales de una sesion: el access token va en el header Authorization de cada
+// pedido y el refresh token sirve una sola vez para pedir nuevas credenciales
+type AuthTokens struct {
+	AccessToken      string `json:"access_token"`
+	TokenType        string `json:"token_type"`
+	ExpiresIn        int    `json:"expires_in"`
+	RefreshToken     string `json:"refresh_token"`
+	RefreshExpiresAt string `json:"refresh_expires_at"`
+}
@@ -0,0 +1,115 @@
+package family
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type FamilyRepository interface {
+	GetPatient(patientId int) (domain.Patient, error)
+	GetByID(id int) (domain.PatientRelation, error)
+	GetByPatient(patientId int) ([]domain.PatientRelation, error)
+	GetByMember(patientId int) ([]domain.PatientRelation, error)
+	Create(relation domain.PatientRelation) (domain.PatientRelation, error)
+	SetResponsible(patientId int, relationId int) error
+	Delete(id int) error
+	GetAppointments(patient domain.Patient) ([]domain.Appointment, error)
+}
+
+type familyRepository struct {
+	storage          store.FamilyStore
+	patientStore     store.PatientStore
+	appointmentStore store.AppointmentStore
+}
+
+// NewFamilyRepository crea un nuevo repositorio
+func NewFamilyRepository(storage store.FamilyStore, patientStore store.PatientStore, appointmentStore store.AppointmentStore) FamilyRepository {
+	return &familyRepository{storage, patientStore, appointmentStore}
+}
+
+// GetPatient busca un paciente por su id
+func (r *familyRepository) GetPatient(patientId int) (domain.Patient, error) {
+	patient, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return domain.Patient{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	return patient, nil
+}
+
+// GetByID busca una relacion por su id
+func (r *familyRepository) GetByID(id int) (domain.PatientRelation, error) {
+	relation, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.PatientRelation{}, errors.New(fmt.Sprintf("relation %d not found", id))
+	}
+	return relation, nil
+}
+
+// GetByPatient busca los familiares registrados de un paciente
+func (r *familyRepository) GetByPatient(patientId int) ([]domain.PatientRelation, error) {
+	_, err := r.GetPatient(patientId)
+	if err != nil {
+		return []domain.PatientRelation{}, err
+	}
+	relations, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.PatientRelation{}, errors.New(fmt.Sprintf("relations of patient %d not found", patientId))
+	}
+	return relations, nil
+}
+
+// GetByMember busca las relaciones en las que participa un paciente
+func (r *familyRepository) GetByMember(patientId int) ([]domain.PatientRelation, error) {
+	relations, err := r.storage.GetByMember(patientId)
+	if err != nil {
+		return []domain.PatientRelation{}, errors.New(fmt.Sprintf("relations of patient %d not found", patientId))
+	}
+	return relations, nil
+}
+
+// Create agrega una relacion entre dos pacientes existentes
+func (r *familyRepository) Create(relation domain.PatientRelation) (domain.PatientRelation, error) {
+	_, err := r.GetPatient(relation.PatientId)
+	if err != nil {
+		return domain.PatientRelation{}, err
+	}
+	related, err := r.GetPatient(relation.RelatedId)
+	if err != nil {
+		return domain.PatientRelation{}, err
+	}
+	relation.Related = related
+	rel, err := r.storage.Create(relation)
+	if err != nil {
+		return domain.PatientRelation{}, errors.New("error creating relation")
+	}
+	return rel, nil
+}
+
+// SetResponsible cambia el responsable de un paciente
+func (r *familyRepository) SetResponsible(patientId int, relationId int) error {
+	err := r.storage.SetResponsible(patientId, relationId)
+	if err != nil {
+		return errors.New(fmt.Sprintf("error updating contact of patient %d", patientId))
+	}
+	return nil
+}
+
+// Delete elimina una relacion
+func (r *familyRepository) Delete(id int) error {
+	err := r.storage.Delete(id)
+	if err != nil {
+		return errors.New(fmt.Sprintf("relation %d not found", id))
+	}
+	return nil
+}
+
+// GetAppointments busca los turnos de un paciente
+func (r *familyRepository) GetAppointments(patient domain.Patient) ([]domain.Appointment, error) {
+	appointments, err := r.appointmentStore.GetByDni(patient.Dni)
+	if err != nil {
+		return []domain.Appointment{}, errors.New(fmt.Sprintf("appointments of patient %d not found", patient.Id))
+	}
+	return appointments, nil
+}
@@ -0,0 +1,211 @@
+package family
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"sort"
+)
+
+// relationships son las relaciones que se pueden registrar
+var relationships = map[string]bool{
+	domain.RelationGuardian: true,
+	domain.RelationParent:   true,
+	domain.RelationSpouse:   true,
+}
+
+type Service interface {
+	GetRelations(patientId int) ([]domain.PatientRelation, error)
+	CreateRelation(relation domain.PatientRelation) (domain.PatientRelation, error)
+	DeleteRelation(patientId int, id int) error
+	GetContact(patientId int) (domain.Contact, error)
+	SetContact(patientId int, relationId int) (domain.Contact, error)
+	GetFamily(patientId int) (domain.Family, error)
+	GetFamilyAppointments(patientId int) ([]domain.Appointment, error)
+}
+
+type service struct {
+	r FamilyRepository
+}
+
+// NewFamilyService crea un nuevo servicio
+func NewFamilyService(r FamilyRepository) Service {
+	return &service{r}
+}
+
+// GetRelations devuelve los guardianes, padres y conyuges registrados de un paciente
+func (s *service) GetRelations(patientId int) ([]domain.PatientRelation, error) {
+	return s.r.GetByPatient(patientId)
+}
+
+// CreateRelation valida y agrega una relacion, cada par de pacientes se puede relacionar una sola vez
+func (s *service) CreateRelation(relation domain.PatientRelation) (domain.PatientRelation, error) {
+	if !relationships[relation.Relationship] {
+		return domain.PatientRelation{}, errors.New("invalid relationship, must be one of: guardian, parent, spouse")
+	}
+	if relation.PatientId == relation.RelatedId {
+		return domain.PatientRelation{}, errors.New("a patient can't be related to itself")
+	}
+	existing, err := s.r.GetByMember(relation.PatientId)
+	if err != nil {
+		return domain.PatientRelation{}, err
+	}
+	for _, e := range existing {
+		if e.PatientId == relation.RelatedId || e.RelatedId == relation.RelatedId {
+			return domain.PatientRelation{}, errors.New(fmt.Sprintf("patients %d and %d are already related", relation.PatientId, relation.RelatedId))
+		}
+	}
+	return s.r.Create(relation)
+}
+
+// DeleteRelation elimina una relacion del paciente
+func (s *service) DeleteRelation(patientId int, id int) error {
+	relation, err := s.r.GetByID(id)
+	if err != nil {
+		return err
+	}
+	if relation.PatientId != patientId {
+		return errors.New(fmt.Sprintf("relation %d not found for patient %d", id, patientId))
+	}
+	return s.r.Delete(id)
+}
+
+// GetContact devuelve a quien se envian los recordatorios y facturas del paciente,
+// el familiar responsable si tiene uno o el mismo paciente
+func (s *service) GetContact(patientId int) (domain.Contact, error) {
+	patient, err := s.r.GetPatient(patientId)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	relations, err := s.r.GetByPatient(patientId)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	return contact(patient, relations), nil
+}
+
+// SetContact designa la relacion responsable del paciente, con 0 el paciente es su propio contacto
+func (s *service) SetContact(patientId int, relationId int) (domain.Contact, error) {
+	if relationId != 0 {
+		relation, err := s.r.GetByID(relationId)
+		if err != nil {
+			return domain.Contact{}, err
+		}
+		if relation.PatientId != patientId {
+			return domain.Contact{}, errors.New(fmt.Sprintf("relation %d not found for patient %d", relationId, patientId))
+		}
+	}
+	_, err := s.r.GetPatient(patientId)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	err = s.r.SetResponsible(patientId, relationId)
+	if err != nil {
+		return domain.Contact{}, err
+	}
+	return s.GetContact(patientId)
+}
+
+// GetFamily devuelve todos los pacientes relacionados directa o indirectamente con el paciente
+// y el contacto de cada uno
+func (s *service) GetFamily(patientId int) (domain.Family, error) {
+	patient, err := s.r.GetPatient(patientId)
+	if err != nil {
+		return domain.Family{}, err
+	}
+	family := domain.Family{PatientId: patientId, Members: []domain.FamilyMember{}, Relations: []domain.PatientRelation{}}
+	members := map[int]domain.Patient{patientId: patient}
+	seen := map[int]bool{}
+	pending := []int{patientId}
+	for len(pending) > 0 {
+		id := pending[0]
+		pending = pending[1:]
+		relations, err := s.r.GetByMember(id)
+		if err != nil {
+			return domain.Family{}, err
+		}
+		for _, relation := range relations {
+			if seen[relation.Id] {
+				continue
+			}
+			seen[relation.Id] = true
+			family.Relations = append(family.Relations, relation)
+			for _, memberId := range []int{relation.PatientId, relation.RelatedId} {
+				if _, ok := members[memberId]; ok {
+					continue
+				}
+				member, err := s.r.GetPatient(memberId)
+				if err != nil {
+					return domain.Family{}, err
+				}
+				members[memberId] = member
+				pending = append(pending, memberId)
+			}
+		}
+	}
+	for _, member := range members {
+		var own []domain.PatientRelation
+		for _, relation := range family.Relations {
+			if relation.PatientId == member.Id {
+				own = append(own, relation)
+			}
+		}
+		family.Members = append(family.Members, domain.FamilyMember{Patient: member, Contact: contact(member, own)})
+	}
+	sort.Slice(family.Members, func(i, j int) bool {
+		return family.Members[i].Patient.Id < family.Members[j].Patient.Id
+	})
+	sort.Slice(family.Relations, func(i, j int) bool {
+		return family.Relations[i].Id < family.Relations[j].Id
+	})
+	return family, nil
+}
+
+// GetFamilyAppointments devuelve los turnos de todos los miembros de la familia en orden cronologico
+func (s *service) GetFamilyAppointments(patientId int) ([]domain.Appointment, error) {
+	family, err := s.GetFamily(patientId)
+	if err != nil {
+		return []domain.Appointment{}, err
+	}
+	appointments := []domain.Appointment{}
+	for _, member := range family.Members {
+		found, err := s.r.GetAppointments(member.Patient)
+		if err != nil {
+			return []domain.Appointment{}, err
+		}
+		appointments = append(appointments, found...)
+	}
+	sort.Slice(appointments, func(i, j int) bool {
+		if appointments[i].Date != appointments[j].Date {
+			return appointments[i].Date < appointments[j].Date
+		}
+		return appointments[i].Hour < appointments[j].Hour
+	})
+	return appointments, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// contact devuelve el contacto de un paciente a partir de sus relaciones
+func contact(patient domain.Patient, relations []domain.PatientRelation) domain.Contact {
+	for _, relation := range relations {
+		if relation.Responsible {
+			return domain.Contact{
+				PatientId:    patient.Id,
+				ContactId:    relation.Related.Id,
+				Name:         relation.Related.Name,
+				LastName:     relation.Related.LastName,
+				Email:        relation.Related.Email,
+				Relationship: relation.Relationship,
+			}
+		}
+	}
+	return domain.Contact{
+		PatientId:    patient.Id,
+		ContactId:    patient.Id,
+		Name:         patient.Name,
+		LastName:     patient.LastName,
+		Email:        patient.Email,
+		Relationship: domain.RelationSelf,
+	}
+}
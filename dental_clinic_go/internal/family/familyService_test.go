@@ -0,0 +1,214 @@
+package family
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeRepository es un FamilyRepository en memoria, los turnos se guardan por paciente
+type fakeRepository struct {
+	patients     map[int]domain.Patient
+	relations    map[int]domain.PatientRelation
+	appointments map[int][]domain.Appointment
+}
+
+func (r *fakeRepository) GetPatient(patientId int) (domain.Patient, error) {
+	patient, ok := r.patients[patientId]
+	if !ok {
+		return domain.Patient{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	return patient, nil
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.PatientRelation, error) {
+	relation, ok := r.relations[id]
+	if !ok {
+		return domain.PatientRelation{}, errors.New(fmt.Sprintf("relation %d not found", id))
+	}
+	return relation, nil
+}
+
+func (r *fakeRepository) GetByPatient(patientId int) ([]domain.PatientRelation, error) {
+	relations := []domain.PatientRelation{}
+	for _, relation := range r.relations {
+		if relation.PatientId == patientId {
+			relations = append(relations, relation)
+		}
+	}
+	return relations, nil
+}
+
+func (r *fakeRepository) GetByMember(patientId int) ([]domain.PatientRelation, error) {
+	relations := []domain.PatientRelation{}
+	for _, relation := range r.relations {
+		if relation.PatientId == patientId || relation.RelatedId == patientId {
+			relations = append(relations, relation)
+		}
+	}
+	return relations, nil
+}
+
+func (r *fakeRepository) Create(relation domain.PatientRelation) (domain.PatientRelation, error) {
+	relation.Id = len(r.relations) + 1
+	relation.Related = r.patients[relation.RelatedId]
+	r.relations[relation.Id] = relation
+	return relation, nil
+}
+
+func (r *fakeRepository) SetResponsible(patientId int, relationId int) error {
+	for id, relation := range r.relations {
+		if relation.PatientId == patientId {
+			relation.Responsible = id == relationId
+			r.relations[id] = relation
+		}
+	}
+	return nil
+}
+
+func (r *fakeRepository) Delete(id int) error {
+	delete(r.relations, id)
+	return nil
+}
+
+func (r *fakeRepository) GetAppointments(patient domain.Patient) ([]domain.Appointment, error) {
+	return r.appointments[patient.Id], nil
+}
+
+// newFakeRepository arma una familia: 1 y 2 son hijos de 3, 3 es conyuge de 4 y 5 no tiene relaciones
+func newFakeRepository() *fakeRepository {
+	patients := map[int]domain.Patient{
+		1: {Id: 1, Name: "Juan", Email: "juan@mail.com"},
+		2: {Id: 2, Name: "Ana", Email: "ana@mail.com"},
+		3: {Id: 3, Name: "Laura", Email: "laura@mail.com"},
+		4: {Id: 4, Name: "Pedro", Email: "pedro@mail.com"},
+		5: {Id: 5, Name: "Sofia", Email: "sofia@mail.com"},
+	}
+	return &fakeRepository{
+		patients: patients,
+		relations: map[int]domain.PatientRelation{
+			1: {Id: 1, PatientId: 1, RelatedId: 3, Related: patients[3], Relationship: domain.RelationGuardian, Responsible: true},
+			2: {Id: 2, PatientId: 2, RelatedId: 3, Related: patients[3], Relationship: domain.RelationParent},
+			3: {Id: 3, PatientId: 3, RelatedId: 4, Related: patients[4], Relationship: domain.RelationSpouse},
+		},
+		appointments: map[int][]domain.Appointment{
+			1: {{Id: 1, Date: "2026-10-21", Hour: "10:00:00"}},
+			2: {{Id: 2, Date: "2026-10-20", Hour: "11:00:00"}},
+			4: {{Id: 3, Date: "2026-10-20", Hour: "09:00:00"}},
+			5: {{Id: 4, Date: "2026-10-19", Hour: "09:00:00"}},
+		},
+	}
+}
+
+func TestCreateRelation(t *testing.T) {
+	tests := []struct {
+		name     string
+		relation domain.PatientRelation
+		err      string
+	}{
+		{name: "new relation", relation: domain.PatientRelation{PatientId: 5, RelatedId: 3, Relationship: domain.RelationGuardian}},
+		{name: "invalid relationship", relation: domain.PatientRelation{PatientId: 5, RelatedId: 3, Relationship: "cousin"}, err: "invalid relationship, must be one of: guardian, parent, spouse"},
+		{name: "related to itself", relation: domain.PatientRelation{PatientId: 5, RelatedId: 5, Relationship: domain.RelationParent}, err: "a patient can't be related to itself"},
+		{name: "already related", relation: domain.PatientRelation{PatientId: 1, RelatedId: 3, Relationship: domain.RelationParent}, err: "patients 1 and 3 are already related"},
+		{name: "already related the other way", relation: domain.PatientRelation{PatientId: 4, RelatedId: 3, Relationship: domain.RelationSpouse}, err: "patients 4 and 3 are already related"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := NewFamilyService(newFakeRepository()).CreateRelation(tt.relation)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+		})
+	}
+}
+
+func TestDeleteRelationOfAnotherPatient(t *testing.T) {
+	r := newFakeRepository()
+	err := NewFamilyService(r).DeleteRelation(2, 1)
+	if err == nil || err.Error() != "relation 1 not found for patient 2" {
+		t.Fatalf("expected error %q, got %v", "relation 1 not found for patient 2", err)
+	}
+	if _, ok := r.relations[1]; !ok {
+		t.Fatalf("expected relation 1 to be kept")
+	}
+}
+
+func TestContact(t *testing.T) {
+	tests := []struct {
+		name         string
+		patientId    int
+		relationId   int
+		set          bool
+		contactId    int
+		email        string
+		relationship string
+		err          string
+	}{
+		{name: "guardian is the contact", patientId: 1, contactId: 3, email: "laura@mail.com", relationship: domain.RelationGuardian},
+		{name: "patient without responsible", patientId: 2, contactId: 2, email: "ana@mail.com", relationship: domain.RelationSelf},
+		{name: "designate a parent", patientId: 2, relationId: 2, set: true, contactId: 3, email: "laura@mail.com", relationship: domain.RelationParent},
+		{name: "back to the patient", patientId: 1, set: true, contactId: 1, email: "juan@mail.com", relationship: domain.RelationSelf},
+		{name: "relation of another patient", patientId: 2, relationId: 1, set: true, err: "relation 1 not found for patient 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewFamilyService(newFakeRepository())
+			var c domain.Contact
+			var err error
+			if tt.set {
+				c, err = s.SetContact(tt.patientId, tt.relationId)
+			} else {
+				c, err = s.GetContact(tt.patientId)
+			}
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if c.PatientId != tt.patientId || c.ContactId != tt.contactId || c.Email != tt.email || c.Relationship != tt.relationship {
+				t.Fatalf("unexpected contact %+v", c)
+			}
+		})
+	}
+}
+
+func TestGetFamily(t *testing.T) {
+	family, err := NewFamilyService(newFakeRepository()).GetFamily(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// 4 solo esta relacionado con 1 a traves de 3
+	if len(family.Members) != 4 || len(family.Relations) != 3 {
+		t.Fatalf("expected 4 members and 3 relations, got %+v", family)
+	}
+	for i, id := range []int{1, 2, 3, 4} {
+		if family.Members[i].Patient.Id != id {
+			t.Fatalf("expected members 1 to 4 in order, got %+v", family.Members)
+		}
+	}
+	if family.Members[0].Contact.ContactId != 3 || family.Members[1].Contact.ContactId != 2 {
+		t.Fatalf("unexpected contacts %+v and %+v", family.Members[0].Contact, family.Members[1].Contact)
+	}
+}
+
+func TestGetFamilyAppointments(t *testing.T) {
+	appointments, err := NewFamilyService(newFakeRepository()).GetFamilyAppointments(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// el turno 4 es de un paciente fuera de la familia
+	if len(appointments) != 3 || appointments[0].Id != 3 || appointments[1].Id != 2 || appointments[2].Id != 1 {
+		t.Fatalf("expected appointments 3, 2 and 1, got %+v", appointments)
+	}
+}
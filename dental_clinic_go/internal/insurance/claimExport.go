@@ -0,0 +1,86 @@
+package insurance
+
+import (
+	"bytes"
+	"dental_clinic_go/internal/domain"
+	"encoding/csv"
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+)
+
+// exporter escribe un lote en el formato que pide un financiador
+type exporter struct {
+	contentType string
+	extension   string
+	write       func(batch domain.ClaimBatch) ([]byte, error)
+}
+
+// exporters son los formatos soportados, indexados por el claim_format del financiador
+var exporters = map[string]exporter{
+	domain.ClaimFormatCsv:        {"text/csv; charset=utf-8", "csv", writeCsv},
+	domain.ClaimFormatFixedWidth: {"text/plain; charset=us-ascii", "txt", writeFixedWidth},
+}
+
+// csvHeader son las columnas del formato csv
+var csvHeader = []string{"batch_id", "insurer_code", "member_number", "plan_code", "patient_dni", "patient_last_name", "patient_name",
+	"date", "procedure_code", "fee", "copay", "amount"}
+
+// writeCsv escribe una prestacion por fila con encabezado
+func writeCsv(batch domain.ClaimBatch) ([]byte, error) {
+	var buf bytes.Buffer
+	w := csv.NewWriter(&buf)
+	err := w.Write(csvHeader)
+	if err != nil {
+		return nil, err
+	}
+	for _, c := range batch.Claims {
+		err := w.Write([]string{
+			strconv.Itoa(batch.Id), batch.InsurerCode, c.MemberNumber, c.PlanCode, strconv.Itoa(c.PatientDni), c.PatientLastName, c.PatientName,
+			c.Date, c.ProcedureCode, formatAmount(c.Fee), formatAmount(c.Copay), formatAmount(c.Amount),
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+	w.Flush()
+	if err := w.Error(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// writeFixedWidth escribe el formato de ancho fijo que usan la mayoria de las obras sociales: un registro
+// de cabecera H con el financiador, el periodo, la cantidad y el total, y un registro D por prestacion.
+// Las fechas van como aaaammdd, los montos en centavos y las lineas terminan en CRLF.
+func writeFixedWidth(batch domain.ClaimBatch) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "H%s%s%s%06d%012d\r\n", fixed(batch.InsurerCode, 10), compactDate(batch.From), compactDate(batch.To),
+		len(batch.Claims), cents(batch.Total))
+	for _, c := range batch.Claims {
+		fmt.Fprintf(&buf, "D%s%s%010d%s%s%012d\r\n", fixed(c.MemberNumber, 20), fixed(c.PlanCode, 10), c.PatientDni,
+			compactDate(c.Date), fixed(c.ProcedureCode, 10), cents(c.Amount))
+	}
+	return buf.Bytes(), nil
+}
+
+// fixed completa con espacios a la derecha o corta un texto al ancho del campo
+func fixed(value string, width int) string {
+	return fmt.Sprintf("%-*.*s", width, width, value)
+}
+
+// compactDate pasa una fecha yyyy-mm-dd a aaaammdd
+func compactDate(date string) string {
+	return strings.ReplaceAll(date, "-", "")
+}
+
+// cents pasa un monto a centavos
+func cents(amount float64) int64 {
+	return int64(math.Round(amount * 100))
+}
+
+// formatAmount escribe un monto con dos decimales y punto decimal
+func formatAmount(amount float64) string {
+	return strconv.FormatFloat(amount, 'f', 2, 64)
+}
@@ -0,0 +1,73 @@
+package insurance
+
+import (
+	"bytes"
+	"dental_clinic_go/internal/domain"
+	"encoding/csv"
+	"strings"
+	"testing"
+)
+
+// exportRepository devuelve un lote de dos prestaciones del financiador indicado
+func exportRepository(insurerId int) *fakeRepository {
+	r := newFakeRepository()
+	r.batches[1] = domain.ClaimBatch{Id: 1, InsurerId: insurerId, InsurerCode: r.insurers[insurerId].Code, From: "2026-05-01", To: "2026-05-31", Total: 1990, Claims: []domain.Claim{
+		{MemberNumber: "12345/01", PlanCode: "210", PatientDni: 30111222, PatientName: "Juan", PatientLastName: "Perez, Gomez",
+			Date: "2026-05-10", ProcedureCode: "CON01", Fee: 1000, Amount: 1000},
+		{MemberNumber: "12345/01", PlanCode: "210", PatientDni: 30111222, PatientName: "Juan", PatientLastName: "Perez, Gomez",
+			Date: "2026-05-12", ProcedureCode: "LIM01", Fee: 2000, Copay: 10, Amount: 990},
+	}}
+	return r
+}
+
+func TestExportFixedWidth(t *testing.T) {
+	file, err := NewInsuranceService(exportRepository(1), fakePrices{}).Export(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if file.Name != "OSDE-000001.txt" || file.ContentType != "text/plain; charset=us-ascii" {
+		t.Fatalf("unexpected file %s %s", file.Name, file.ContentType)
+	}
+	lines := strings.Split(strings.TrimSuffix(string(file.Content), "\r\n"), "\r\n")
+	expected := []string{
+		"HOSDE      2026050120260531000002000000199000",
+		"D12345/01            210       00301112222026051" + "0CON01     000000100000",
+		"D12345/01            210       00301112222026051" + "2LIM01     000000099000",
+	}
+	if len(lines) != len(expected) {
+		t.Fatalf("expected %d lines, got %q", len(expected), lines)
+	}
+	for i := range expected {
+		if lines[i] != expected[i] {
+			t.Fatalf("expected line %d to be %q, got %q", i+1, expected[i], lines[i])
+		}
+	}
+}
+
+func TestExportCsv(t *testing.T) {
+	file, err := NewInsuranceService(exportRepository(2), fakePrices{}).Export(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if file.Name != "SWISS-000001.csv" {
+		t.Fatalf("unexpected file name %s", file.Name)
+	}
+	rows, err := csv.NewReader(bytes.NewReader(file.Content)).ReadAll()
+	if err != nil {
+		t.Fatalf("invalid csv: %s", err)
+	}
+	if len(rows) != 3 || strings.Join(rows[0], ",") != strings.Join(csvHeader, ",") {
+		t.Fatalf("expected a header and 2 rows, got %q", rows)
+	}
+	// el apellido con coma queda entre comillas y los montos con dos decimales
+	if rows[2][5] != "Perez, Gomez" || rows[2][9] != "2000.00" || rows[2][10] != "10.00" || rows[2][11] != "990.00" {
+		t.Fatalf("unexpected row %q", rows[2])
+	}
+}
+
+func TestExportUnsupportedFormat(t *testing.T) {
+	_, err := NewInsuranceService(exportRepository(3), fakePrices{}).Export(1)
+	if err == nil || err.Error() != "claim format xml of insurer IOMA not supported" {
+		t.Fatalf("expected error %q, got %v", "claim format xml of insurer IOMA not supported", err)
+	}
+}
@@ -0,0 +1,254 @@
+package insurance
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type InsuranceRepository interface {
+	GetInsurers() ([]domain.Insurer, error)
+	GetInsurer(id int) (domain.Insurer, error)
+	CreateInsurer(insurer domain.Insurer) (domain.Insurer, error)
+	UpdateInsurer(insurer domain.Insurer) (domain.Insurer, error)
+	GetPlan(id int) (domain.InsurancePlan, error)
+	CreatePlan(plan domain.InsurancePlan) (domain.InsurancePlan, error)
+	SaveRule(rule domain.CoverageRule) (domain.InsurancePlan, error)
+	DeleteRule(planId int, procedureCode string) error
+	GetCoverage(id int) (domain.PatientCoverage, error)
+	GetCoverages(patientId int) ([]domain.PatientCoverage, error)
+	CreateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error)
+	UpdateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error)
+	DeleteCoverage(patientId int, id int) error
+	CountProcedures(patientId int, procedureCode string, date string, appointmentId int) (int, error)
+	GetProcedure(code string) (domain.Procedure, error)
+	GetClaimable(insurerId int, from string, to string) ([]domain.Claim, error)
+	GetBatch(id int) (domain.ClaimBatch, error)
+	GetBatches(insurerId int, status string) ([]domain.ClaimBatch, error)
+	CreateBatch(batch domain.ClaimBatch) (domain.ClaimBatch, error)
+	UpdateBatchStatus(id int, status string) (domain.ClaimBatch, error)
+}
+
+type insuranceRepository struct {
+	storage        store.InsuranceStore
+	patientStore   store.PatientStore
+	procedureStore store.ProcedureStore
+}
+
+// NewInsuranceRepository crea un nuevo repositorio
+func NewInsuranceRepository(storage store.InsuranceStore, patientStore store.PatientStore, procedureStore store.ProcedureStore) InsuranceRepository {
+	return &insuranceRepository{storage, patientStore, procedureStore}
+}
+
+// GetInsurers busca todos los financiadores
+func (r *insuranceRepository) GetInsurers() ([]domain.Insurer, error) {
+	insurers, err := r.storage.GetInsurers()
+	if err != nil {
+		return []domain.Insurer{}, errors.New("error getting insurers")
+	}
+	return insurers, nil
+}
+
+// GetInsurer busca un financiador por su id
+func (r *insuranceRepository) GetInsurer(id int) (domain.Insurer, error) {
+	insurer, err := r.storage.GetInsurer(id)
+	if err != nil {
+		return domain.Insurer{}, errors.New(fmt.Sprintf("insurer %d not found", id))
+	}
+	return insurer, nil
+}
+
+// CreateInsurer agrega un financiador, el codigo no se puede repetir
+func (r *insuranceRepository) CreateInsurer(insurer domain.Insurer) (domain.Insurer, error) {
+	i, err := r.storage.CreateInsurer(insurer)
+	if err != nil {
+		return domain.Insurer{}, errors.New(fmt.Sprintf("error creating insurer %s, the code may already exist", insurer.Code))
+	}
+	return r.GetInsurer(i.Id)
+}
+
+// UpdateInsurer actualiza un financiador
+func (r *insuranceRepository) UpdateInsurer(insurer domain.Insurer) (domain.Insurer, error) {
+	_, err := r.GetInsurer(insurer.Id)
+	if err != nil {
+		return domain.Insurer{}, err
+	}
+	err = r.storage.UpdateInsurer(insurer)
+	if err != nil {
+		return domain.Insurer{}, errors.New(fmt.Sprintf("error updating insurer %d", insurer.Id))
+	}
+	return r.GetInsurer(insurer.Id)
+}
+
+// GetPlan busca un plan por su id
+func (r *insuranceRepository) GetPlan(id int) (domain.InsurancePlan, error) {
+	plan, err := r.storage.GetPlan(id)
+	if err != nil {
+		return domain.InsurancePlan{}, errors.New(fmt.Sprintf("insurance plan %d not found", id))
+	}
+	return plan, nil
+}
+
+// CreatePlan agrega un plan verificando que exista el financiador
+func (r *insuranceRepository) CreatePlan(plan domain.InsurancePlan) (domain.InsurancePlan, error) {
+	_, err := r.GetInsurer(plan.InsurerId)
+	if err != nil {
+		return domain.InsurancePlan{}, err
+	}
+	p, err := r.storage.CreatePlan(plan)
+	if err != nil {
+		return domain.InsurancePlan{}, errors.New(fmt.Sprintf("error creating insurance plan %s, the code may already exist", plan.Code))
+	}
+	return r.GetPlan(p.Id)
+}
+
+// SaveRule guarda la regla de un procedimiento y devuelve el plan con sus reglas
+func (r *insuranceRepository) SaveRule(rule domain.CoverageRule) (domain.InsurancePlan, error) {
+	_, err := r.GetPlan(rule.PlanId)
+	if err != nil {
+		return domain.InsurancePlan{}, err
+	}
+	_, err = r.GetProcedure(rule.ProcedureCode)
+	if err != nil {
+		return domain.InsurancePlan{}, err
+	}
+	err = r.storage.SaveRule(rule)
+	if err != nil {
+		return domain.InsurancePlan{}, errors.New(fmt.Sprintf("error saving rule of procedure %s", rule.ProcedureCode))
+	}
+	return r.GetPlan(rule.PlanId)
+}
+
+// DeleteRule elimina la regla de un procedimiento de un plan
+func (r *insuranceRepository) DeleteRule(planId int, procedureCode string) error {
+	err := r.storage.DeleteRule(planId, procedureCode)
+	if err != nil {
+		return errors.New(fmt.Sprintf("rule of procedure %s not found in insurance plan %d", procedureCode, planId))
+	}
+	return nil
+}
+
+// GetCoverage busca una cobertura por su id
+func (r *insuranceRepository) GetCoverage(id int) (domain.PatientCoverage, error) {
+	coverage, err := r.storage.GetCoverage(id)
+	if err != nil {
+		return domain.PatientCoverage{}, errors.New(fmt.Sprintf("coverage %d not found", id))
+	}
+	return coverage, nil
+}
+
+// GetCoverages busca las coberturas de un paciente
+func (r *insuranceRepository) GetCoverages(patientId int) ([]domain.PatientCoverage, error) {
+	_, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return []domain.PatientCoverage{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	coverages, err := r.storage.GetCoverages(patientId)
+	if err != nil {
+		return []domain.PatientCoverage{}, errors.New(fmt.Sprintf("coverages of patient %d not found", patientId))
+	}
+	return coverages, nil
+}
+
+// CreateCoverage agrega una cobertura verificando que existan el paciente y el plan
+func (r *insuranceRepository) CreateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error) {
+	_, err := r.patientStore.GetByID(coverage.PatientId)
+	if err != nil {
+		return domain.PatientCoverage{}, errors.New(fmt.Sprintf("patient %d not found", coverage.PatientId))
+	}
+	_, err = r.GetPlan(coverage.PlanId)
+	if err != nil {
+		return domain.PatientCoverage{}, err
+	}
+	c, err := r.storage.CreateCoverage(coverage)
+	if err != nil {
+		return domain.PatientCoverage{}, errors.New("error creating coverage")
+	}
+	return r.GetCoverage(c.Id)
+}
+
+// UpdateCoverage actualiza una cobertura de un paciente
+func (r *insuranceRepository) UpdateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error) {
+	_, err := r.GetPlan(coverage.PlanId)
+	if err != nil {
+		return domain.PatientCoverage{}, err
+	}
+	err = r.storage.UpdateCoverage(coverage)
+	if err != nil {
+		return domain.PatientCoverage{}, errors.New(fmt.Sprintf("error updating coverage %d", coverage.Id))
+	}
+	return r.GetCoverage(coverage.Id)
+}
+
+// DeleteCoverage elimina una cobertura de un paciente
+func (r *insuranceRepository) DeleteCoverage(patientId int, id int) error {
+	err := r.storage.DeleteCoverage(patientId, id)
+	if err != nil {
+		return errors.New(fmt.Sprintf("coverage %d not found in patient %d", id, patientId))
+	}
+	return nil
+}
+
+// CountProcedures cuenta las veces que un paciente se hizo o agendo un procedimiento en el año antes de un turno
+func (r *insuranceRepository) CountProcedures(patientId int, procedureCode string, date string, appointmentId int) (int, error) {
+	count, err := r.storage.CountProcedures(patientId, procedureCode, date, appointmentId)
+	if err != nil {
+		return 0, errors.New(fmt.Sprintf("error counting procedures %s of patient %d", procedureCode, patientId))
+	}
+	return count, nil
+}
+
+// GetProcedure busca un procedimiento del nomenclador
+func (r *insuranceRepository) GetProcedure(code string) (domain.Procedure, error) {
+	procedure, err := r.procedureStore.GetByCode(code)
+	if err != nil {
+		return domain.Procedure{}, errors.New(fmt.Sprintf("procedure %s not found", code))
+	}
+	return procedure, nil
+}
+
+// GetClaimable busca los turnos de un financiador que se pueden presentar
+func (r *insuranceRepository) GetClaimable(insurerId int, from string, to string) ([]domain.Claim, error) {
+	claims, err := r.storage.GetClaimable(insurerId, from, to)
+	if err != nil {
+		return []domain.Claim{}, errors.New(fmt.Sprintf("error getting claimable appointments of insurer %d", insurerId))
+	}
+	return claims, nil
+}
+
+// GetBatch busca un lote por su id
+func (r *insuranceRepository) GetBatch(id int) (domain.ClaimBatch, error) {
+	batch, err := r.storage.GetBatch(id)
+	if err != nil {
+		return domain.ClaimBatch{}, errors.New(fmt.Sprintf("claim batch %d not found", id))
+	}
+	return batch, nil
+}
+
+// GetBatches busca los lotes de un financiador en un estado
+func (r *insuranceRepository) GetBatches(insurerId int, status string) ([]domain.ClaimBatch, error) {
+	batches, err := r.storage.GetBatches(insurerId, status)
+	if err != nil {
+		return []domain.ClaimBatch{}, errors.New("error getting claim batches")
+	}
+	return batches, nil
+}
+
+// CreateBatch agrega un lote con sus prestaciones
+func (r *insuranceRepository) CreateBatch(batch domain.ClaimBatch) (domain.ClaimBatch, error) {
+	b, err := r.storage.CreateBatch(batch)
+	if err != nil {
+		return domain.ClaimBatch{}, errors.New("error creating claim batch")
+	}
+	return r.GetBatch(b.Id)
+}
+
+// UpdateBatchStatus cambia el estado de un lote
+func (r *insuranceRepository) UpdateBatchStatus(id int, status string) (domain.ClaimBatch, error) {
+	err := r.storage.UpdateBatchStatus(id, status)
+	if err != nil {
+		return domain.ClaimBatch{}, errors.New(fmt.Sprintf("error updating claim batch %d", id))
+	}
+	return r.GetBatch(id)
+}
@@ -0,0 +1,429 @@
+package insurance
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// batchTransitions indica a que estados puede pasar un lote desde cada estado
+var batchTransitions = map[string][]string{
+	domain.ClaimBatchOpen:      {domain.ClaimBatchSubmitted},
+	domain.ClaimBatchSubmitted: {domain.ClaimBatchPaid},
+}
+
+// PriceResolver devuelve el precio de lista de un procedimiento para un financiador
+type PriceResolver interface {
+	ListPrice(procedureCode string, date string, insurerId int) (float64, error)
+}
+
+type Service interface {
+	GetInsurers() ([]domain.Insurer, error)
+	GetInsurer(id int) (domain.Insurer, error)
+	CreateInsurer(insurer domain.Insurer) (domain.Insurer, error)
+	UpdateInsurer(id int, insurer domain.Insurer) (domain.Insurer, error)
+	GetPlan(id int) (domain.InsurancePlan, error)
+	CreatePlan(plan domain.InsurancePlan) (domain.InsurancePlan, error)
+	SaveRule(rule domain.CoverageRule) (domain.InsurancePlan, error)
+	DeleteRule(planId int, procedureCode string) error
+	GetCoverages(patientId int) ([]domain.PatientCoverage, error)
+	CreateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error)
+	UpdateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error)
+	DeleteCoverage(patientId int, id int) error
+	Estimate(patientId int, procedureCode string, date string) (domain.CoverageEstimate, error)
+	CoverageEstimate(a domain.Appointment) (*domain.CoverageEstimate, error)
+	GetBatches(insurerId int, status string) ([]domain.ClaimBatch, error)
+	GetBatch(id int) (domain.ClaimBatch, error)
+	CreateBatch(batch domain.ClaimBatch) (domain.ClaimBatch, error)
+	UpdateBatchStatus(id int, status string) (domain.ClaimBatch, error)
+	Export(id int) (domain.ClaimFile, error)
+}
+
+type service struct {
+	r      InsuranceRepository
+	prices PriceResolver
+}
+
+// NewInsuranceService crea un nuevo servicio
+func NewInsuranceService(r InsuranceRepository, prices PriceResolver) Service {
+	return &service{r, prices}
+}
+
+// GetInsurers busca todos los financiadores
+func (s *service) GetInsurers() ([]domain.Insurer, error) {
+	return s.r.GetInsurers()
+}
+
+// GetInsurer busca un financiador con sus planes
+func (s *service) GetInsurer(id int) (domain.Insurer, error) {
+	return s.r.GetInsurer(id)
+}
+
+// CreateInsurer agrega un financiador, si no indica formato presenta los lotes en csv
+func (s *service) CreateInsurer(insurer domain.Insurer) (domain.Insurer, error) {
+	insurer, err := normalizeInsurer(insurer)
+	if err != nil {
+		return domain.Insurer{}, err
+	}
+	return s.r.CreateInsurer(insurer)
+}
+
+// UpdateInsurer actualiza los datos de un financiador, sus planes se modifican aparte
+func (s *service) UpdateInsurer(id int, insurer domain.Insurer) (domain.Insurer, error) {
+	insurer.Id = id
+	insurer, err := normalizeInsurer(insurer)
+	if err != nil {
+		return domain.Insurer{}, err
+	}
+	return s.r.UpdateInsurer(insurer)
+}
+
+// GetPlan busca un plan con sus reglas de cobertura
+func (s *service) GetPlan(id int) (domain.InsurancePlan, error) {
+	return s.r.GetPlan(id)
+}
+
+// CreatePlan agrega un plan a un financiador
+func (s *service) CreatePlan(plan domain.InsurancePlan) (domain.InsurancePlan, error) {
+	plan.Code, plan.Name = strings.TrimSpace(plan.Code), strings.TrimSpace(plan.Name)
+	if plan.Code == "" || plan.Name == "" {
+		return domain.InsurancePlan{}, errors.New("code and name of the plan can't be empty")
+	}
+	return s.r.CreatePlan(plan)
+}
+
+// SaveRule agrega o reemplaza lo que cubre un plan de un procedimiento
+func (s *service) SaveRule(rule domain.CoverageRule) (domain.InsurancePlan, error) {
+	if rule.ProcedureCode == "" {
+		return domain.InsurancePlan{}, errors.New("procedure_code can't be empty")
+	}
+	if rule.Percent < 0 || rule.Percent > 100 {
+		return domain.InsurancePlan{}, errors.New("percent must be between 0 and 100")
+	}
+	if rule.Copay < 0 || rule.AnnualLimit < 0 {
+		return domain.InsurancePlan{}, errors.New("copay and annual_limit can't be negative")
+	}
+	rule.Copay = round(rule.Copay)
+	return s.r.SaveRule(rule)
+}
+
+// DeleteRule elimina la regla de un procedimiento, el plan deja de cubrirlo
+func (s *service) DeleteRule(planId int, procedureCode string) error {
+	return s.r.DeleteRule(planId, procedureCode)
+}
+
+// GetCoverages busca las coberturas de un paciente, vigentes o no
+func (s *service) GetCoverages(patientId int) ([]domain.PatientCoverage, error) {
+	return s.r.GetCoverages(patientId)
+}
+
+// CreateCoverage afilia un paciente a un plan, sin fecha de inicio la cobertura empieza hoy
+func (s *service) CreateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error) {
+	coverage, err := normalizeCoverage(coverage)
+	if err != nil {
+		return domain.PatientCoverage{}, err
+	}
+	return s.r.CreateCoverage(coverage)
+}
+
+// UpdateCoverage actualiza el plan, el numero de afiliado o la vigencia de una cobertura
+func (s *service) UpdateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error) {
+	current, err := s.r.GetCoverage(coverage.Id)
+	if err != nil || current.PatientId != coverage.PatientId {
+		return domain.PatientCoverage{}, errors.New(fmt.Sprintf("coverage %d not found in patient %d", coverage.Id, coverage.PatientId))
+	}
+	coverage, err = normalizeCoverage(coverage)
+	if err != nil {
+		return domain.PatientCoverage{}, err
+	}
+	return s.r.UpdateCoverage(coverage)
+}
+
+// DeleteCoverage elimina una cobertura cargada por error, para darla de baja se le pone fecha de fin
+func (s *service) DeleteCoverage(patientId int, id int) error {
+	return s.r.DeleteCoverage(patientId, id)
+}
+
+// Estimate calcula lo que cubre la mejor cobertura vigente de un paciente para un procedimiento en una fecha,
+// sin fecha calcula para hoy
+func (s *service) Estimate(patientId int, procedureCode string, date string) (domain.CoverageEstimate, error) {
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return domain.CoverageEstimate{}, errors.New("invalid date, must be in format: yyyy-mm-dd")
+	}
+	if procedureCode == "" {
+		return domain.CoverageEstimate{}, errors.New("procedure_code can't be empty")
+	}
+	estimate, err := s.estimate(patientId, procedureCode, date, 0)
+	if err != nil {
+		return domain.CoverageEstimate{}, err
+	}
+	if estimate == nil {
+		return domain.CoverageEstimate{}, errors.New(fmt.Sprintf("patient %d has no coverage on %s", patientId, date))
+	}
+	return *estimate, nil
+}
+
+// CoverageEstimate calcula lo que cubre el seguro del paciente en un turno, nil si el turno no tiene
+// procedimiento o el paciente no tiene cobertura en esa fecha
+func (s *service) CoverageEstimate(a domain.Appointment) (*domain.CoverageEstimate, error) {
+	if a.ProcedureCode == "" || a.Patient.Id == 0 {
+		return nil, nil
+	}
+	return s.estimate(a.Patient.Id, a.ProcedureCode, a.Date, a.Id)
+}
+
+// GetBatches busca los lotes de un financiador en un estado, sin filtros devuelve todos
+func (s *service) GetBatches(insurerId int, status string) ([]domain.ClaimBatch, error) {
+	if status != "" && status != domain.ClaimBatchOpen && status != domain.ClaimBatchSubmitted && status != domain.ClaimBatchPaid {
+		return []domain.ClaimBatch{}, errors.New("invalid status, must be one of: open, submitted, paid")
+	}
+	return s.r.GetBatches(insurerId, status)
+}
+
+// GetBatch busca un lote con sus prestaciones
+func (s *service) GetBatch(id int) (domain.ClaimBatch, error) {
+	return s.r.GetBatch(id)
+}
+
+// CreateBatch arma un lote con los turnos completados del periodo de pacientes con cobertura del
+// financiador que todavia no se presentaron. Las prestaciones que el plan no cubre quedan afuera.
+func (s *service) CreateBatch(batch domain.ClaimBatch) (domain.ClaimBatch, error) {
+	from, err := time.Parse("2006-01-02", batch.From)
+	if err != nil {
+		return domain.ClaimBatch{}, errors.New("invalid from, must be in format: yyyy-mm-dd")
+	}
+	to, err := time.Parse("2006-01-02", batch.To)
+	if err != nil {
+		return domain.ClaimBatch{}, errors.New("invalid to, must be in format: yyyy-mm-dd")
+	}
+	if to.Before(from) {
+		return domain.ClaimBatch{}, errors.New("to can't be before from")
+	}
+	_, err = s.r.GetInsurer(batch.InsurerId)
+	if err != nil {
+		return domain.ClaimBatch{}, err
+	}
+	claimable, err := s.r.GetClaimable(batch.InsurerId, batch.From, batch.To)
+	if err != nil {
+		return domain.ClaimBatch{}, err
+	}
+	batch.Status, batch.Total, batch.Claims = domain.ClaimBatchOpen, 0, []domain.Claim{}
+	plans := map[int]domain.InsurancePlan{}
+	claimed := map[int]bool{}
+	for _, claim := range claimable {
+		// un turno con dos coberturas del mismo financiador se presenta con la mas reciente
+		if claimed[claim.AppointmentId] {
+			continue
+		}
+		coverage, err := s.r.GetCoverage(claim.CoverageId)
+		if err != nil {
+			return domain.ClaimBatch{}, err
+		}
+		plan, ok := plans[coverage.PlanId]
+		if !ok {
+			plan, err = s.r.GetPlan(coverage.PlanId)
+			if err != nil {
+				return domain.ClaimBatch{}, err
+			}
+			plans[coverage.PlanId] = plan
+		}
+		fee, err := s.prices.ListPrice(claim.ProcedureCode, claim.Date, coverage.InsurerId)
+		if err != nil {
+			return domain.ClaimBatch{}, err
+		}
+		used, err := s.r.CountProcedures(claim.PatientId, claim.ProcedureCode, claim.Date, claim.AppointmentId)
+		if err != nil {
+			return domain.ClaimBatch{}, err
+		}
+		estimate := calculate(coverage, plan, claim.ProcedureCode, fee, claim.Date, used)
+		if estimate.Covered == 0 {
+			continue
+		}
+		claim.Fee, claim.Copay, claim.Amount = estimate.Fee, estimate.Copay, estimate.Covered
+		batch.Claims = append(batch.Claims, claim)
+		batch.Total += claim.Amount
+		claimed[claim.AppointmentId] = true
+	}
+	if len(batch.Claims) == 0 {
+		return domain.ClaimBatch{}, errors.New(fmt.Sprintf("no claimable appointments of insurer %d between %s and %s", batch.InsurerId, batch.From, batch.To))
+	}
+	batch.Total = round(batch.Total)
+	return s.r.CreateBatch(batch)
+}
+
+// UpdateBatchStatus marca un lote como presentado o pagado
+func (s *service) UpdateBatchStatus(id int, status string) (domain.ClaimBatch, error) {
+	batch, err := s.r.GetBatch(id)
+	if err != nil {
+		return domain.ClaimBatch{}, err
+	}
+	if batch.Status == status {
+		return batch, nil
+	}
+	if !contains(batchTransitions[batch.Status], status) {
+		return domain.ClaimBatch{}, errors.New(fmt.Sprintf("claim batch %d can't change from %s to %s", id, batch.Status, status))
+	}
+	return s.r.UpdateBatchStatus(id, status)
+}
+
+// Export genera el archivo de un lote en el formato de su financiador
+func (s *service) Export(id int) (domain.ClaimFile, error) {
+	batch, err := s.r.GetBatch(id)
+	if err != nil {
+		return domain.ClaimFile{}, err
+	}
+	insurer, err := s.r.GetInsurer(batch.InsurerId)
+	if err != nil {
+		return domain.ClaimFile{}, err
+	}
+	e, ok := exporters[insurer.ClaimFormat]
+	if !ok {
+		return domain.ClaimFile{}, errors.New(fmt.Sprintf("claim format %s of insurer %s not supported", insurer.ClaimFormat, insurer.Code))
+	}
+	content, err := e.write(batch)
+	if err != nil {
+		return domain.ClaimFile{}, err
+	}
+	return domain.ClaimFile{
+		Name:        fmt.Sprintf("%s-%06d.%s", insurer.Code, batch.Id, e.extension),
+		ContentType: e.contentType,
+		Content:     content,
+	}, nil
+}
+
+// estimate calcula lo que cubre cada cobertura vigente del paciente y se queda con la que mas paga,
+// nil si no tiene ninguna vigente en la fecha
+func (s *service) estimate(patientId int, procedureCode string, date string, appointmentId int) (*domain.CoverageEstimate, error) {
+	coverages, err := s.r.GetCoverages(patientId)
+	if err != nil {
+		return nil, err
+	}
+	var best *domain.CoverageEstimate
+	used := -1
+	for _, coverage := range coverages {
+		if !coverage.Covers(date) {
+			continue
+		}
+		if used < 0 {
+			used, err = s.r.CountProcedures(patientId, procedureCode, date, appointmentId)
+			if err != nil {
+				return nil, err
+			}
+		}
+		fee, err := s.prices.ListPrice(procedureCode, date, coverage.InsurerId)
+		if err != nil {
+			return nil, err
+		}
+		plan, err := s.r.GetPlan(coverage.PlanId)
+		if err != nil {
+			return nil, err
+		}
+		estimate := calculate(coverage, plan, procedureCode, fee, date, used)
+		if best == nil || estimate.Covered > best.Covered {
+			best = &estimate
+		}
+	}
+	return best, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// calculate aplica la regla del plan al precio del procedimiento en la lista del financiador, used son
+// las veces que ya se hizo en el año
+func calculate(coverage domain.PatientCoverage, plan domain.InsurancePlan, procedureCode string, fee float64, date string, used int) domain.CoverageEstimate {
+	estimate := domain.CoverageEstimate{
+		CoverageId:    coverage.Id,
+		InsurerName:   coverage.InsurerName,
+		PlanName:      coverage.PlanName,
+		MemberNumber:  coverage.MemberNumber,
+		ProcedureCode: procedureCode,
+		Date:          date,
+		Fee:           fee,
+		Used:          used,
+	}
+	rule, ok := findRule(plan.Rules, procedureCode)
+	if !ok {
+		estimate.Notes = fmt.Sprintf("procedure %s is not covered by plan %s", procedureCode, plan.Name)
+	} else {
+		estimate.Percent, estimate.Copay, estimate.AnnualLimit = rule.Percent, rule.Copay, rule.AnnualLimit
+		if rule.AnnualLimit > 0 && used >= rule.AnnualLimit {
+			estimate.Notes = fmt.Sprintf("annual limit of %d reached", rule.AnnualLimit)
+		} else {
+			estimate.Covered = round(math.Max(fee*rule.Percent/100-rule.Copay, 0))
+		}
+	}
+	estimate.PatientPays = round(fee - estimate.Covered)
+	return estimate
+}
+
+// findRule busca la regla de un procedimiento en las reglas de un plan
+func findRule(rules []domain.CoverageRule, procedureCode string) (domain.CoverageRule, bool) {
+	for _, r := range rules {
+		if r.ProcedureCode == procedureCode {
+			return r, true
+		}
+	}
+	return domain.CoverageRule{}, false
+}
+
+// normalizeInsurer valida un financiador y completa el formato por defecto
+func normalizeInsurer(insurer domain.Insurer) (domain.Insurer, error) {
+	insurer.Code, insurer.Name = strings.ToUpper(strings.TrimSpace(insurer.Code)), strings.TrimSpace(insurer.Name)
+	if insurer.Code == "" || insurer.Name == "" {
+		return domain.Insurer{}, errors.New("code and name of the insurer can't be empty")
+	}
+	if insurer.Type != domain.InsurerObraSocial && insurer.Type != domain.InsurerPrepaga {
+		return domain.Insurer{}, errors.New("invalid type, must be one of: obra_social, prepaga")
+	}
+	if insurer.ClaimFormat == "" {
+		insurer.ClaimFormat = domain.ClaimFormatCsv
+	}
+	if _, ok := exporters[insurer.ClaimFormat]; !ok {
+		return domain.Insurer{}, errors.New("invalid claim_format, must be one of: csv, fixed_width")
+	}
+	return insurer, nil
+}
+
+// normalizeCoverage valida el numero de afiliado y la vigencia de una cobertura
+func normalizeCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error) {
+	coverage.MemberNumber = strings.TrimSpace(coverage.MemberNumber)
+	if coverage.PlanId == 0 || coverage.MemberNumber == "" {
+		return domain.PatientCoverage{}, errors.New("plan_id and member_number can't be empty")
+	}
+	if coverage.ValidFrom == "" {
+		coverage.ValidFrom = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", coverage.ValidFrom); err != nil {
+		return domain.PatientCoverage{}, errors.New("invalid valid_from, must be in format: yyyy-mm-dd")
+	}
+	if coverage.ValidTo != "" {
+		if _, err := time.Parse("2006-01-02", coverage.ValidTo); err != nil {
+			return domain.PatientCoverage{}, errors.New("invalid valid_to, must be in format: yyyy-mm-dd")
+		}
+		if coverage.ValidTo < coverage.ValidFrom {
+			return domain.PatientCoverage{}, errors.New("valid_to can't be before valid_from")
+		}
+	}
+	return coverage, nil
+}
+
+// round redondea un monto a centavos
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// contains indica si un estado esta en la lista
+func contains(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
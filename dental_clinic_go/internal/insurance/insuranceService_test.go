@@ -0,0 +1,375 @@
+package insurance
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeRepository es un InsuranceRepository en memoria, used son las veces que el paciente ya se hizo
+// el procedimiento en el año
+type fakeRepository struct {
+	InsuranceRepository
+	insurers  map[int]domain.Insurer
+	plans     map[int]domain.InsurancePlan
+	coverages map[int]domain.PatientCoverage
+	claimable []domain.Claim
+	batches   map[int]domain.ClaimBatch
+	used      int
+}
+
+func (r *fakeRepository) GetInsurer(id int) (domain.Insurer, error) {
+	insurer, ok := r.insurers[id]
+	if !ok {
+		return domain.Insurer{}, errors.New(fmt.Sprintf("insurer %d not found", id))
+	}
+	return insurer, nil
+}
+
+func (r *fakeRepository) CreateInsurer(insurer domain.Insurer) (domain.Insurer, error) {
+	insurer.Id = len(r.insurers) + 1
+	return insurer, nil
+}
+
+func (r *fakeRepository) GetPlan(id int) (domain.InsurancePlan, error) {
+	return r.plans[id], nil
+}
+
+func (r *fakeRepository) SaveRule(rule domain.CoverageRule) (domain.InsurancePlan, error) {
+	plan := r.plans[rule.PlanId]
+	plan.Rules = append(plan.Rules, rule)
+	return plan, nil
+}
+
+func (r *fakeRepository) GetCoverage(id int) (domain.PatientCoverage, error) {
+	coverage, ok := r.coverages[id]
+	if !ok {
+		return domain.PatientCoverage{}, errors.New(fmt.Sprintf("coverage %d not found", id))
+	}
+	return coverage, nil
+}
+
+func (r *fakeRepository) GetCoverages(patientId int) ([]domain.PatientCoverage, error) {
+	coverages := []domain.PatientCoverage{}
+	for id := 1; id <= len(r.coverages); id++ {
+		if r.coverages[id].PatientId == patientId {
+			coverages = append(coverages, r.coverages[id])
+		}
+	}
+	return coverages, nil
+}
+
+func (r *fakeRepository) CreateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error) {
+	coverage.Id = len(r.coverages) + 1
+	return coverage, nil
+}
+
+func (r *fakeRepository) UpdateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error) {
+	return coverage, nil
+}
+
+func (r *fakeRepository) CountProcedures(patientId int, procedureCode string, date string, appointmentId int) (int, error) {
+	return r.used, nil
+}
+
+func (r *fakeRepository) GetClaimable(insurerId int, from string, to string) ([]domain.Claim, error) {
+	return r.claimable, nil
+}
+
+func (r *fakeRepository) GetBatch(id int) (domain.ClaimBatch, error) {
+	batch, ok := r.batches[id]
+	if !ok {
+		return domain.ClaimBatch{}, errors.New(fmt.Sprintf("claim batch %d not found", id))
+	}
+	return batch, nil
+}
+
+func (r *fakeRepository) CreateBatch(batch domain.ClaimBatch) (domain.ClaimBatch, error) {
+	batch.Id = len(r.batches) + 1
+	r.batches[batch.Id] = batch
+	return batch, nil
+}
+
+func (r *fakeRepository) UpdateBatchStatus(id int, status string) (domain.ClaimBatch, error) {
+	batch := r.batches[id]
+	batch.Status = status
+	r.batches[id] = batch
+	return batch, nil
+}
+
+// fakePrices tiene la lista de precios de cada financiador
+type fakePrices struct{}
+
+func (p fakePrices) ListPrice(procedureCode string, date string, insurerId int) (float64, error) {
+	if procedureCode == "CON01" && insurerId == 2 {
+		return 1500, nil
+	}
+	if procedureCode == "CON01" {
+		return 1000, nil
+	}
+	return 2000, nil
+}
+
+// newFakeRepository arma al paciente 1 con el plan 210 del financiador 1, que cubre la consulta completa
+// y la mitad de LIM01 dos veces por año, y con el plan 2 del financiador 2 hasta junio, que cubre el 80%
+// de la consulta
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		insurers: map[int]domain.Insurer{
+			1: {Id: 1, Code: "OSDE", Name: "Osde", Type: domain.InsurerPrepaga, ClaimFormat: domain.ClaimFormatFixedWidth},
+			2: {Id: 2, Code: "SWISS", Name: "Swiss Medical", Type: domain.InsurerPrepaga, ClaimFormat: domain.ClaimFormatCsv},
+			3: {Id: 3, Code: "IOMA", Name: "Ioma", Type: domain.InsurerObraSocial, ClaimFormat: "xml"},
+		},
+		plans: map[int]domain.InsurancePlan{
+			1: {Id: 1, InsurerId: 1, Code: "210", Name: "Plan 210", Rules: []domain.CoverageRule{
+				{PlanId: 1, ProcedureCode: "CON01", Percent: 100},
+				{PlanId: 1, ProcedureCode: "LIM01", Percent: 50, Copay: 10, AnnualLimit: 2},
+			}},
+			2: {Id: 2, InsurerId: 2, Code: "SMG20", Name: "SMG 20", Rules: []domain.CoverageRule{
+				{PlanId: 2, ProcedureCode: "CON01", Percent: 80},
+			}},
+		},
+		coverages: map[int]domain.PatientCoverage{
+			1: {Id: 1, PatientId: 1, PlanId: 1, InsurerId: 1, MemberNumber: "12345/01", ValidFrom: "2026-01-01"},
+			2: {Id: 2, PatientId: 1, PlanId: 2, InsurerId: 2, MemberNumber: "998877", ValidFrom: "2026-01-01", ValidTo: "2026-06-30"},
+			3: {Id: 3, PatientId: 1, PlanId: 1, InsurerId: 1, MemberNumber: "12345/00", ValidFrom: "2025-01-01", ValidTo: "2025-12-31"},
+		},
+		batches: map[int]domain.ClaimBatch{},
+	}
+}
+
+func TestEstimate(t *testing.T) {
+	tests := []struct {
+		name        string
+		patientId   int
+		code        string
+		date        string
+		used        int
+		coverageId  int
+		covered     float64
+		patientPays float64
+		notes       string
+		err         string
+	}{
+		{name: "the coverage that pays more", patientId: 1, code: "CON01", date: "2026-05-01", coverageId: 2, covered: 1200, patientPays: 300},
+		{name: "only the coverage in force", patientId: 1, code: "CON01", date: "2026-07-01", coverageId: 1, covered: 1000},
+		{name: "percent and copay", patientId: 1, code: "LIM01", date: "2026-07-01", used: 1, coverageId: 1, covered: 990, patientPays: 1010},
+		{name: "annual limit reached", patientId: 1, code: "LIM01", date: "2026-07-01", used: 2, coverageId: 1, patientPays: 2000, notes: "annual limit of 2 reached"},
+		{name: "not covered by the plan", patientId: 1, code: "XXX01", date: "2026-07-01", coverageId: 1, patientPays: 2000, notes: "procedure XXX01 is not covered by plan Plan 210"},
+		{name: "patient without coverage", patientId: 2, code: "CON01", date: "2026-05-01", err: "patient 2 has no coverage on 2026-05-01"},
+		{name: "invalid date", patientId: 1, code: "CON01", date: "01/05/2026", err: "invalid date, must be in format: yyyy-mm-dd"},
+		{name: "without procedure", patientId: 1, date: "2026-05-01", err: "procedure_code can't be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRepository()
+			r.used = tt.used
+			e, err := NewInsuranceService(r, fakePrices{}).Estimate(tt.patientId, tt.code, tt.date)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if e.CoverageId != tt.coverageId || e.Covered != tt.covered || e.PatientPays != tt.patientPays || e.Notes != tt.notes {
+				t.Fatalf("unexpected estimate %+v", e)
+			}
+		})
+	}
+}
+
+func TestCoverageEstimateWithoutCoverage(t *testing.T) {
+	s := NewInsuranceService(newFakeRepository(), fakePrices{})
+	for _, a := range []domain.Appointment{
+		{Id: 1, Patient: domain.Patient{Id: 1}, Date: "2026-05-01"},
+		{Id: 2, Patient: domain.Patient{Id: 2}, Date: "2026-05-01", ProcedureCode: "CON01"},
+	} {
+		e, err := s.CoverageEstimate(a)
+		if err != nil || e != nil {
+			t.Fatalf("expected no estimate for appointment %d, got %+v and %v", a.Id, e, err)
+		}
+	}
+}
+
+func TestCreateBatch(t *testing.T) {
+	tests := []struct {
+		name      string
+		batch     domain.ClaimBatch
+		claimable []domain.Claim
+		total     float64
+		claims    int
+		err       string
+	}{
+		{name: "covered claims", batch: domain.ClaimBatch{InsurerId: 1, From: "2026-05-01", To: "2026-05-31"}, claimable: []domain.Claim{
+			{AppointmentId: 1, CoverageId: 1, PatientId: 1, ProcedureCode: "CON01", Date: "2026-05-10"},
+			{AppointmentId: 1, CoverageId: 3, PatientId: 1, ProcedureCode: "CON01", Date: "2026-05-10"},
+			{AppointmentId: 2, CoverageId: 1, PatientId: 1, ProcedureCode: "XXX01", Date: "2026-05-11"},
+			{AppointmentId: 3, CoverageId: 1, PatientId: 1, ProcedureCode: "LIM01", Date: "2026-05-12"},
+		}, total: 1990, claims: 2},
+		{name: "nothing covered", batch: domain.ClaimBatch{InsurerId: 1, From: "2026-05-01", To: "2026-05-31"}, claimable: []domain.Claim{
+			{AppointmentId: 2, CoverageId: 1, PatientId: 1, ProcedureCode: "XXX01", Date: "2026-05-11"},
+		}, err: "no claimable appointments of insurer 1 between 2026-05-01 and 2026-05-31"},
+		{name: "invalid from", batch: domain.ClaimBatch{InsurerId: 1, From: "mayo", To: "2026-05-31"}, err: "invalid from, must be in format: yyyy-mm-dd"},
+		{name: "to before from", batch: domain.ClaimBatch{InsurerId: 1, From: "2026-05-31", To: "2026-05-01"}, err: "to can't be before from"},
+		{name: "unknown insurer", batch: domain.ClaimBatch{InsurerId: 9, From: "2026-05-01", To: "2026-05-31"}, err: "insurer 9 not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRepository()
+			r.claimable = tt.claimable
+			batch, err := NewInsuranceService(r, fakePrices{}).CreateBatch(tt.batch)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if batch.Status != domain.ClaimBatchOpen || batch.Total != tt.total || len(batch.Claims) != tt.claims {
+				t.Fatalf("expected an open batch of %d claims for %.2f, got %+v", tt.claims, tt.total, batch)
+			}
+			if batch.Claims[0].CoverageId != 1 || batch.Claims[1].Copay != 10 || batch.Claims[1].Amount != 990 {
+				t.Fatalf("unexpected claims %+v", batch.Claims)
+			}
+		})
+	}
+}
+
+func TestUpdateBatchStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		err    string
+	}{
+		{name: "submitted", status: domain.ClaimBatchSubmitted},
+		{name: "same status", status: domain.ClaimBatchOpen},
+		{name: "paid before submitted", status: domain.ClaimBatchPaid, err: "claim batch 1 can't change from open to paid"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRepository()
+			r.batches[1] = domain.ClaimBatch{Id: 1, InsurerId: 1, Status: domain.ClaimBatchOpen}
+			batch, err := NewInsuranceService(r, fakePrices{}).UpdateBatchStatus(1, tt.status)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if batch.Status != tt.status {
+				t.Fatalf("expected status %s, got %s", tt.status, batch.Status)
+			}
+		})
+	}
+}
+
+func TestCreateInsurer(t *testing.T) {
+	tests := []struct {
+		name    string
+		insurer domain.Insurer
+		format  string
+		err     string
+	}{
+		{name: "csv by default", insurer: domain.Insurer{Code: " osde ", Name: "Osde", Type: domain.InsurerPrepaga}, format: domain.ClaimFormatCsv},
+		{name: "fixed width", insurer: domain.Insurer{Code: "IOMA", Name: "Ioma", Type: domain.InsurerObraSocial, ClaimFormat: domain.ClaimFormatFixedWidth}, format: domain.ClaimFormatFixedWidth},
+		{name: "without name", insurer: domain.Insurer{Code: "IOMA", Type: domain.InsurerObraSocial}, err: "code and name of the insurer can't be empty"},
+		{name: "invalid type", insurer: domain.Insurer{Code: "IOMA", Name: "Ioma", Type: "mutual"}, err: "invalid type, must be one of: obra_social, prepaga"},
+		{name: "invalid format", insurer: domain.Insurer{Code: "IOMA", Name: "Ioma", Type: domain.InsurerObraSocial, ClaimFormat: "xml"}, err: "invalid claim_format, must be one of: csv, fixed_width"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			insurer, err := NewInsuranceService(newFakeRepository(), fakePrices{}).CreateInsurer(tt.insurer)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if insurer.ClaimFormat != tt.format || insurer.Code != "OSDE" && insurer.Code != "IOMA" {
+				t.Fatalf("unexpected insurer %+v", insurer)
+			}
+		})
+	}
+}
+
+func TestSaveRule(t *testing.T) {
+	tests := []struct {
+		name string
+		rule domain.CoverageRule
+		err  string
+	}{
+		{name: "valid rule", rule: domain.CoverageRule{PlanId: 2, ProcedureCode: "LIM01", Percent: 70, Copay: 5.555}},
+		{name: "without procedure", rule: domain.CoverageRule{PlanId: 2, Percent: 70}, err: "procedure_code can't be empty"},
+		{name: "percent over 100", rule: domain.CoverageRule{PlanId: 2, ProcedureCode: "LIM01", Percent: 120}, err: "percent must be between 0 and 100"},
+		{name: "negative copay", rule: domain.CoverageRule{PlanId: 2, ProcedureCode: "LIM01", Percent: 70, Copay: -1}, err: "copay and annual_limit can't be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			plan, err := NewInsuranceService(newFakeRepository(), fakePrices{}).SaveRule(tt.rule)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if rule, _ := findRule(plan.Rules, "LIM01"); rule.Copay != 5.56 {
+				t.Fatalf("expected the copay rounded to 5.56, got %+v", plan.Rules)
+			}
+		})
+	}
+}
+
+func TestCoverage(t *testing.T) {
+	tests := []struct {
+		name     string
+		coverage domain.PatientCoverage
+		update   bool
+		err      string
+	}{
+		{name: "starts today by default", coverage: domain.PatientCoverage{PatientId: 1, PlanId: 1, MemberNumber: " 555 "}},
+		{name: "without member number", coverage: domain.PatientCoverage{PatientId: 1, PlanId: 1}, err: "plan_id and member_number can't be empty"},
+		{name: "invalid valid_to", coverage: domain.PatientCoverage{PatientId: 1, PlanId: 1, MemberNumber: "555", ValidTo: "2026"}, err: "invalid valid_to, must be in format: yyyy-mm-dd"},
+		{name: "ends before it starts", coverage: domain.PatientCoverage{PatientId: 1, PlanId: 1, MemberNumber: "555", ValidFrom: "2026-05-01", ValidTo: "2026-04-30"}, err: "valid_to can't be before valid_from"},
+		{name: "update of the patient", coverage: domain.PatientCoverage{Id: 1, PatientId: 1, PlanId: 1, MemberNumber: "555", ValidFrom: "2026-01-01"}, update: true},
+		{name: "update of another patient", coverage: domain.PatientCoverage{Id: 1, PatientId: 2, PlanId: 1, MemberNumber: "555"}, update: true, err: "coverage 1 not found in patient 2"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewInsuranceService(newFakeRepository(), fakePrices{})
+			var coverage domain.PatientCoverage
+			var err error
+			if tt.update {
+				coverage, err = s.UpdateCoverage(tt.coverage)
+			} else {
+				coverage, err = s.CreateCoverage(tt.coverage)
+			}
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if coverage.MemberNumber != "555" || coverage.ValidFrom == "" {
+				t.Fatalf("unexpected coverage %+v", coverage)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package invoice
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type InvoiceRepository interface {
+	GetByID(id int) (domain.Invoice, error)
+	GetByPatient(patientId int) ([]domain.Invoice, error)
+	GetByStatus(status string) ([]domain.Invoice, error)
+	GetByAppointment(appointmentId int) ([]domain.Invoice, error)
+	GetProcedure(code string) (domain.Procedure, error)
+	GetTreatmentItems(appointmentId int) ([]domain.TreatmentItem, error)
+	GetCoverages(patientId int) ([]domain.PatientCoverage, error)
+	Create(invoice domain.Invoice) (domain.Invoice, error)
+	Update(invoice domain.Invoice) (domain.Invoice, error)
+	Issue(id int, series string, date string) (domain.Invoice, error)
+	Void(id int, reason string, date string) (domain.Invoice, error)
+}
+
+type invoiceRepository struct {
+	storage        store.InvoiceStore
+	patientStore   store.PatientStore
+	procedureStore store.ProcedureStore
+	treatmentStore store.TreatmentStore
+	insuranceStore store.InsuranceStore
+}
+
+// NewInvoiceRepository crea un nuevo repositorio
+func NewInvoiceRepository(storage store.InvoiceStore, patientStore store.PatientStore, procedureStore store.ProcedureStore,
+	treatmentStore store.TreatmentStore, insuranceStore store.InsuranceStore) InvoiceRepository {
+	return &invoiceRepository{storage, patientStore, procedureStore, treatmentStore, insuranceStore}
+}
+
+// GetByID busca una factura por su id
+func (r *invoiceRepository) GetByID(id int) (domain.Invoice, error) {
+	invoice, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("invoice %d not found", id))
+	}
+	return invoice, nil
+}
+
+// GetByPatient busca las facturas de un paciente
+func (r *invoiceRepository) GetByPatient(patientId int) ([]domain.Invoice, error) {
+	_, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return []domain.Invoice{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	invoices, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.Invoice{}, errors.New(fmt.Sprintf("invoices of patient %d not found", patientId))
+	}
+	return invoices, nil
+}
+
+// GetByStatus busca las facturas en un estado
+func (r *invoiceRepository) GetByStatus(status string) ([]domain.Invoice, error) {
+	invoices, err := r.storage.GetByStatus(status)
+	if err != nil {
+		return []domain.Invoice{}, errors.New("error getting invoices")
+	}
+	return invoices, nil
+}
+
+// GetByAppointment busca las facturas de un turno
+func (r *invoiceRepository) GetByAppointment(appointmentId int) ([]domain.Invoice, error) {
+	invoices, err := r.storage.GetByAppointment(appointmentId)
+	if err != nil {
+		return []domain.Invoice{}, errors.New(fmt.Sprintf("invoices of appointment %d not found", appointmentId))
+	}
+	return invoices, nil
+}
+
+// GetProcedure busca un procedimiento del catalogo
+func (r *invoiceRepository) GetProcedure(code string) (domain.Procedure, error) {
+	procedure, err := r.procedureStore.GetByCode(code)
+	if err != nil {
+		return domain.Procedure{}, errors.New(fmt.Sprintf("procedure %s not found", code))
+	}
+	return procedure, nil
+}
+
+// GetTreatmentItems busca los procedimientos de planes de tratamiento agendados en un turno
+func (r *invoiceRepository) GetTreatmentItems(appointmentId int) ([]domain.TreatmentItem, error) {
+	items, err := r.treatmentStore.GetItemsByAppointment(appointmentId)
+	if err != nil {
+		return []domain.TreatmentItem{}, errors.New(fmt.Sprintf("treatment items of appointment %d not found", appointmentId))
+	}
+	return items, nil
+}
+
+// GetCoverages busca las coberturas de un paciente, vigentes o no
+func (r *invoiceRepository) GetCoverages(patientId int) ([]domain.PatientCoverage, error) {
+	coverages, err := r.insuranceStore.GetCoverages(patientId)
+	if err != nil {
+		return []domain.PatientCoverage{}, errors.New(fmt.Sprintf("coverages of patient %d not found", patientId))
+	}
+	return coverages, nil
+}
+
+// Create agrega una factura verificando que exista el paciente
+func (r *invoiceRepository) Create(invoice domain.Invoice) (domain.Invoice, error) {
+	_, err := r.patientStore.GetByID(invoice.PatientId)
+	if err != nil {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("patient %d not found", invoice.PatientId))
+	}
+	created, err := r.storage.Create(invoice)
+	if err != nil {
+		return domain.Invoice{}, errors.New("error creating invoice")
+	}
+	return r.GetByID(created.Id)
+}
+
+// Update actualiza un borrador
+func (r *invoiceRepository) Update(invoice domain.Invoice) (domain.Invoice, error) {
+	err := r.storage.Update(invoice)
+	if err != nil {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("error updating invoice %d", invoice.Id))
+	}
+	return r.GetByID(invoice.Id)
+}
+
+// Issue emite un borrador con el siguiente numero de la serie
+func (r *invoiceRepository) Issue(id int, series string, date string) (domain.Invoice, error) {
+	_, err := r.storage.Issue(id, series, date)
+	if err != nil {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("error issuing invoice %d", id))
+	}
+	return r.GetByID(id)
+}
+
+// Void anula una factura
+func (r *invoiceRepository) Void(id int, reason string, date string) (domain.Invoice, error) {
+	err := r.storage.Void(id, reason, date)
+	if err != nil {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("error voiding invoice %d", id))
+	}
+	return r.GetByID(id)
+}
@@ -0,0 +1,345 @@
+package invoice
+
+import (
+	"dental_clinic_go/internal/appointment"
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+type Config struct {
+	// Series es la serie de numeracion de las facturas emitidas
+	Series string
+	// TaxRate es la alicuota en porcentaje de los items que no indican una
+	TaxRate float64
+}
+
+// ContactResolver devuelve a quien se facturan los tratamientos de un paciente
+type ContactResolver interface {
+	GetContact(patientId int) (domain.Contact, error)
+}
+
+// PriceResolver cotiza los procedimientos con la lista de precios vigente y los descuentos del paciente
+type PriceResolver interface {
+	Quote(patientId int, procedureCode string, date string, insurerId int) (domain.PriceQuote, error)
+}
+
+type Service interface {
+	GetByID(id int) (domain.Invoice, error)
+	GetByPatient(patientId int) ([]domain.Invoice, error)
+	GetByStatus(status string) ([]domain.Invoice, error)
+	Create(invoice domain.Invoice) (domain.Invoice, error)
+	Update(id int, invoice domain.Invoice) (domain.Invoice, error)
+	Issue(id int) (domain.Invoice, error)
+	IssueFromAppointment(appointmentId int) (domain.Invoice, error)
+	Void(id int, reason string) (domain.Invoice, error)
+}
+
+type service struct {
+	r        InvoiceRepository
+	a        appointment.AppointmentService
+	contacts ContactResolver
+	prices   PriceResolver
+	config   Config
+}
+
+// NewInvoiceService crea un nuevo servicio
+func NewInvoiceService(r InvoiceRepository, a appointment.AppointmentService, contacts ContactResolver, prices PriceResolver, config Config) Service {
+	return &service{r: r, a: a, contacts: contacts, prices: prices, config: config}
+}
+
+// GetByID busca una factura por su id
+func (s *service) GetByID(id int) (domain.Invoice, error) {
+	return s.r.GetByID(id)
+}
+
+// GetByPatient busca las facturas de un paciente
+func (s *service) GetByPatient(patientId int) ([]domain.Invoice, error) {
+	return s.r.GetByPatient(patientId)
+}
+
+// GetByStatus busca las facturas en un estado, sin estado devuelve todas
+func (s *service) GetByStatus(status string) ([]domain.Invoice, error) {
+	if status != "" && status != domain.InvoiceDraft && status != domain.InvoiceIssued && status != domain.InvoiceVoid {
+		return []domain.Invoice{}, errors.New("invalid status, must be one of: draft, issued, void")
+	}
+	return s.r.GetByStatus(status)
+}
+
+// Create agrega un borrador facturado al contacto responsable del paciente. Si es de un turno
+// realizado y no trae items se completan con los procedimientos del turno.
+func (s *service) Create(invoice domain.Invoice) (domain.Invoice, error) {
+	invoice.Status = domain.InvoiceDraft
+	invoice.Series, invoice.Number, invoice.IssueDate, invoice.VoidReason = "", 0, "", ""
+	if invoice.AppointmentId != 0 {
+		a, err := s.invoiceableAppointment(invoice.AppointmentId)
+		if err != nil {
+			return domain.Invoice{}, err
+		}
+		if invoice.PatientId != 0 && invoice.PatientId != a.Patient.Id {
+			return domain.Invoice{}, errors.New(fmt.Sprintf("appointment %d doesn't belong to patient %d", a.Id, invoice.PatientId))
+		}
+		invoice.PatientId = a.Patient.Id
+		if len(invoice.Items) == 0 {
+			invoice.Items, err = s.appointmentItems(a)
+			if err != nil {
+				return domain.Invoice{}, err
+			}
+		}
+	}
+	if invoice.PatientId == 0 {
+		return domain.Invoice{}, errors.New("patient_id or appointment_id can't be empty")
+	}
+	invoice, err := s.prepare(invoice)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	return s.r.Create(invoice)
+}
+
+// Update reemplaza los items y las notas de un borrador, las facturas emitidas no se modifican
+func (s *service) Update(id int, invoice domain.Invoice) (domain.Invoice, error) {
+	current, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	if current.Status != domain.InvoiceDraft {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("invoice %d is %s, only drafts can be updated", id, current.Status))
+	}
+	current.Items = invoice.Items
+	current.Notes = invoice.Notes
+	current, err = s.prepare(current)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	return s.r.Update(current)
+}
+
+// Issue emite un borrador asignandole el siguiente numero de la serie y la fecha de hoy, junto
+// con el cargo en la cuenta del paciente
+func (s *service) Issue(id int) (domain.Invoice, error) {
+	invoice, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	if invoice.Status != domain.InvoiceDraft {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("invoice %d is %s, only drafts can be issued", id, invoice.Status))
+	}
+	if len(invoice.Items) == 0 {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("invoice %d has no items", id))
+	}
+	return s.r.Issue(id, s.config.Series, time.Now().Format("2006-01-02"))
+}
+
+// IssueFromAppointment factura y emite un turno realizado, si ya tiene un borrador emite ese
+func (s *service) IssueFromAppointment(appointmentId int) (domain.Invoice, error) {
+	invoices, err := s.r.GetByAppointment(appointmentId)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	for _, invoice := range invoices {
+		if invoice.Status == domain.InvoiceDraft {
+			return s.Issue(invoice.Id)
+		}
+	}
+	invoice, err := s.Create(domain.Invoice{AppointmentId: appointmentId})
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	return s.Issue(invoice.Id)
+}
+
+// Void anula una factura, las emitidas conservan su numero para que la numeracion no tenga huecos
+func (s *service) Void(id int, reason string) (domain.Invoice, error) {
+	invoice, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	if invoice.Status == domain.InvoiceVoid {
+		return invoice, nil
+	}
+	if invoice.Status == domain.InvoiceIssued && reason == "" {
+		return domain.Invoice{}, errors.New("reason can't be empty to void an issued invoice")
+	}
+	return s.r.Void(id, reason, time.Now().Format("2006-01-02"))
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// invoiceableAppointment busca un turno realizado que todavia no tenga factura
+func (s *service) invoiceableAppointment(appointmentId int) (domain.Appointment, error) {
+	a, err := s.a.GetByID(appointmentId)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	if a.Status != domain.AppointmentCompleted {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("appointment %d is %s, only completed appointments can be invoiced", appointmentId, a.Status))
+	}
+	invoices, err := s.r.GetByAppointment(appointmentId)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	for _, invoice := range invoices {
+		if invoice.Status != domain.InvoiceVoid {
+			return domain.Appointment{}, errors.New(fmt.Sprintf("appointment %d already has invoice %d", appointmentId, invoice.Id))
+		}
+	}
+	return a, nil
+}
+
+// appointmentItems arma los items de un turno con los procedimientos de planes realizados en el,
+// si no tiene usa el procedimiento del turno
+func (s *service) appointmentItems(a domain.Appointment) ([]domain.InvoiceItem, error) {
+	treatmentItems, err := s.r.GetTreatmentItems(a.Id)
+	if err != nil {
+		return nil, err
+	}
+	items := []domain.InvoiceItem{}
+	for _, t := range treatmentItems {
+		if t.Status != domain.ItemCompleted {
+			continue
+		}
+		items = append(items, domain.InvoiceItem{
+			ProcedureCode: t.ProcedureCode,
+			Description:   t.Description,
+			Tooth:         t.Tooth,
+			Quantity:      1,
+			UnitPrice:     t.EstimatedCost,
+		})
+	}
+	if len(items) == 0 && a.ProcedureCode != "" {
+		items = append(items, domain.InvoiceItem{ProcedureCode: a.ProcedureCode, Quantity: 1})
+	}
+	if len(items) == 0 {
+		return nil, errors.New(fmt.Sprintf("appointment %d has no procedures to invoice", a.Id))
+	}
+	return items, nil
+}
+
+// prepare valida los items de una factura, completa sus valores por defecto, calcula los
+// totales y actualiza el contacto al que se factura
+func (s *service) prepare(invoice domain.Invoice) (domain.Invoice, error) {
+	if invoice.Items == nil {
+		invoice.Items = []domain.InvoiceItem{}
+	}
+	date, insurerId, err := s.quoteTerms(invoice)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	for i, item := range invoice.Items {
+		item, err := s.prepareItem(invoice.PatientId, date, insurerId, item)
+		if err != nil {
+			return domain.Invoice{}, errors.New(fmt.Sprintf("invalid item %d: %s", i+1, err.Error()))
+		}
+		invoice.Items[i] = item
+	}
+	invoice = withTotals(invoice)
+	contact, err := s.contacts.GetContact(invoice.PatientId)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	invoice.BillTo = contact
+	return invoice, nil
+}
+
+// quoteTerms devuelve la fecha y el financiador con que se cotizan los items de una factura: la fecha
+// del turno, o la de hoy si no es de un turno, y la primera cobertura del paciente vigente en esa
+// fecha, 0 si no tiene ninguna
+func (s *service) quoteTerms(invoice domain.Invoice) (string, int, error) {
+	date := time.Now().Format("2006-01-02")
+	if invoice.AppointmentId != 0 {
+		a, err := s.a.GetByID(invoice.AppointmentId)
+		if err != nil {
+			return "", 0, err
+		}
+		date = a.Date
+	}
+	coverages, err := s.r.GetCoverages(invoice.PatientId)
+	if err != nil {
+		return "", 0, err
+	}
+	for _, coverage := range coverages {
+		if coverage.Covers(date) {
+			return date, coverage.InsurerId, nil
+		}
+	}
+	return date, 0, nil
+}
+
+// prepareItem completa la descripcion de un item con el procedimiento del catalogo, el precio con la
+// lista del financiador vigente en la fecha y, si no trae descuento, el mayor descuento que le
+// corresponde al paciente. Calcula sus importes.
+func (s *service) prepareItem(patientId int, date string, insurerId int, item domain.InvoiceItem) (domain.InvoiceItem, error) {
+	if item.ProcedureCode != "" {
+		procedure, err := s.r.GetProcedure(item.ProcedureCode)
+		if err != nil {
+			return domain.InvoiceItem{}, err
+		}
+		if item.Description == "" {
+			item.Description = procedure.Name
+		}
+		quote, err := s.prices.Quote(patientId, item.ProcedureCode, date, insurerId)
+		if err != nil {
+			return domain.InvoiceItem{}, err
+		}
+		if item.UnitPrice == 0 {
+			item.UnitPrice = quote.ListPrice
+		}
+		if item.DiscountPercent == 0 && item.DiscountFixed == 0 {
+			if quote.DiscountType == domain.DiscountFixed {
+				item.DiscountFixed = quote.DiscountValue
+			} else {
+				item.DiscountPercent = quote.DiscountValue
+			}
+		}
+	}
+	if item.Quantity == 0 {
+		item.Quantity = 1
+	}
+	if item.TaxRate == nil {
+		taxRate := s.config.TaxRate
+		item.TaxRate = &taxRate
+	}
+	switch {
+	case item.Description == "":
+		return domain.InvoiceItem{}, errors.New("description or procedure_code can't be empty")
+	case item.Quantity < 0:
+		return domain.InvoiceItem{}, errors.New("quantity can't be negative")
+	case item.UnitPrice < 0:
+		return domain.InvoiceItem{}, errors.New("unit_price can't be negative")
+	case item.DiscountPercent < 0 || item.DiscountPercent > 100:
+		return domain.InvoiceItem{}, errors.New("discount_percent must be between 0 and 100")
+	case item.DiscountFixed < 0:
+		return domain.InvoiceItem{}, errors.New("discount_fixed can't be negative")
+	case *item.TaxRate < 0:
+		return domain.InvoiceItem{}, errors.New("tax_rate can't be negative")
+	}
+	item.Subtotal = round(float64(item.Quantity) * item.UnitPrice)
+	item.Discount = round(math.Min(item.Subtotal*item.DiscountPercent/100+float64(item.Quantity)*item.DiscountFixed, item.Subtotal))
+	item.Tax = round((item.Subtotal - item.Discount) * *item.TaxRate / 100)
+	item.Total = round(item.Subtotal - item.Discount + item.Tax)
+	return item, nil
+}
+
+// withTotals suma los importes de los items de una factura
+func withTotals(invoice domain.Invoice) domain.Invoice {
+	invoice.Subtotal, invoice.Discount, invoice.Tax, invoice.Total = 0, 0, 0, 0
+	for _, item := range invoice.Items {
+		invoice.Subtotal += item.Subtotal
+		invoice.Discount += item.Discount
+		invoice.Tax += item.Tax
+		invoice.Total += item.Total
+	}
+	invoice.Subtotal = round(invoice.Subtotal)
+	invoice.Discount = round(invoice.Discount)
+	invoice.Tax = round(invoice.Tax)
+	invoice.Total = round(invoice.Total)
+	return invoice
+}
+
+// round redondea un importe a centavos
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
@@ -0,0 +1,229 @@
+package invoice
+
+import (
+	"dental_clinic_go/internal/appointment"
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+)
+
+// fakeRepository es un InvoiceRepository en memoria que numera las facturas como el store: el numero
+// solo se consume cuando la emision termina bien. Los metodos que no usan los tests quedan sin implementar.
+type fakeRepository struct {
+	InvoiceRepository
+	invoices  map[int]domain.Invoice
+	coverages []domain.PatientCoverage
+	last      map[string]int
+	failIssue bool
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.Invoice, error) {
+	invoice, ok := r.invoices[id]
+	if !ok {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("invoice %d not found", id))
+	}
+	return invoice, nil
+}
+
+func (r *fakeRepository) GetProcedure(code string) (domain.Procedure, error) {
+	return domain.Procedure{Code: code, Name: "Procedure " + code}, nil
+}
+
+func (r *fakeRepository) GetCoverages(patientId int) ([]domain.PatientCoverage, error) {
+	return r.coverages, nil
+}
+
+func (r *fakeRepository) Issue(id int, series string, date string) (domain.Invoice, error) {
+	invoice := r.invoices[id]
+	if r.failIssue || invoice.Status != domain.InvoiceDraft {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("error issuing invoice %d", id))
+	}
+	r.last[series]++
+	invoice.Series, invoice.Number, invoice.Status, invoice.IssueDate = series, r.last[series], domain.InvoiceIssued, date
+	r.invoices[id] = invoice
+	return invoice, nil
+}
+
+// fakePrices cotiza siempre lo mismo y guarda con que fecha y financiador se le pidio
+type fakePrices struct {
+	quote     domain.PriceQuote
+	date      string
+	insurerId int
+}
+
+func (p *fakePrices) Quote(patientId int, procedureCode string, date string, insurerId int) (domain.PriceQuote, error) {
+	p.date, p.insurerId = date, insurerId
+	return p.quote, nil
+}
+
+// fakeAppointments devuelve turnos con una fecha fija
+type fakeAppointments struct {
+	appointment.AppointmentService
+	date string
+}
+
+func (a fakeAppointments) GetByID(id int) (domain.Appointment, error) {
+	return domain.Appointment{Id: id, Date: a.date}, nil
+}
+
+func rate(value float64) *float64 {
+	return &value
+}
+
+func TestPrepareItem(t *testing.T) {
+	percentQuote := domain.PriceQuote{ListPrice: 200, DiscountType: domain.DiscountPercentage, DiscountValue: 10}
+	fixedQuote := domain.PriceQuote{ListPrice: 200, DiscountType: domain.DiscountFixed, DiscountValue: 30}
+	tests := []struct {
+		name  string
+		quote domain.PriceQuote
+		item  domain.InvoiceItem
+		want  domain.InvoiceItem
+		err   string
+	}{
+		{name: "list price, percentage discount and default tax", quote: percentQuote,
+			item: domain.InvoiceItem{ProcedureCode: "01.01"},
+			want: domain.InvoiceItem{Description: "Procedure 01.01", Quantity: 1, UnitPrice: 200, DiscountPercent: 10, TaxRate: rate(21), Subtotal: 200, Discount: 20, Tax: 37.8, Total: 217.8}},
+		{name: "fixed discount is per unit", quote: fixedQuote,
+			item: domain.InvoiceItem{ProcedureCode: "01.01", Quantity: 2},
+			want: domain.InvoiceItem{Description: "Procedure 01.01", Quantity: 2, UnitPrice: 200, DiscountFixed: 30, TaxRate: rate(21), Subtotal: 400, Discount: 60, Tax: 71.4, Total: 411.4}},
+		{name: "explicit 0% tax is kept", quote: percentQuote,
+			item: domain.InvoiceItem{ProcedureCode: "01.01", TaxRate: rate(0)},
+			want: domain.InvoiceItem{Description: "Procedure 01.01", Quantity: 1, UnitPrice: 200, DiscountPercent: 10, TaxRate: rate(0), Subtotal: 200, Discount: 20, Tax: 0, Total: 180}},
+		{name: "explicit price and discount win over the quote", quote: percentQuote,
+			item: domain.InvoiceItem{ProcedureCode: "01.01", Description: "Limpieza", UnitPrice: 150, DiscountFixed: 5, TaxRate: rate(10.5)},
+			want: domain.InvoiceItem{Description: "Limpieza", Quantity: 1, UnitPrice: 150, DiscountFixed: 5, TaxRate: rate(10.5), Subtotal: 150, Discount: 5, Tax: 15.23, Total: 160.23}},
+		{name: "discounts never exceed the subtotal", quote: fixedQuote,
+			item: domain.InvoiceItem{Description: "Ajuste", UnitPrice: 50, DiscountPercent: 60, DiscountFixed: 40},
+			want: domain.InvoiceItem{Description: "Ajuste", Quantity: 1, UnitPrice: 50, DiscountPercent: 60, DiscountFixed: 40, TaxRate: rate(21), Subtotal: 50, Discount: 50, Tax: 0, Total: 0}},
+		{name: "free text item isn't quoted", quote: fixedQuote,
+			item: domain.InvoiceItem{Description: "Material", UnitPrice: 12.5, Quantity: 3},
+			want: domain.InvoiceItem{Description: "Material", Quantity: 3, UnitPrice: 12.5, TaxRate: rate(21), Subtotal: 37.5, Discount: 0, Tax: 7.88, Total: 45.38}},
+		{name: "missing description", item: domain.InvoiceItem{UnitPrice: 10}, err: "description or procedure_code can't be empty"},
+		{name: "negative quantity", item: domain.InvoiceItem{Description: "x", Quantity: -1}, err: "quantity can't be negative"},
+		{name: "negative price", item: domain.InvoiceItem{Description: "x", UnitPrice: -1}, err: "unit_price can't be negative"},
+		{name: "discount over 100%", item: domain.InvoiceItem{Description: "x", DiscountPercent: 101}, err: "discount_percent must be between 0 and 100"},
+		{name: "negative fixed discount", item: domain.InvoiceItem{Description: "x", DiscountFixed: -1}, err: "discount_fixed can't be negative"},
+		{name: "negative tax", item: domain.InvoiceItem{Description: "x", TaxRate: rate(-1)}, err: "tax_rate can't be negative"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := &service{r: &fakeRepository{}, prices: &fakePrices{quote: tt.quote}, config: Config{Series: "A", TaxRate: 21}}
+			got, err := s.prepareItem(1, "2026-10-19", 0, tt.item)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got.TaxRate == nil || *got.TaxRate != *tt.want.TaxRate {
+				t.Fatalf("expected tax rate %.2f, got %v", *tt.want.TaxRate, got.TaxRate)
+			}
+			got.ProcedureCode, got.TaxRate, tt.want.TaxRate = "", nil, nil
+			if got != tt.want {
+				t.Fatalf("expected %+v, got %+v", tt.want, got)
+			}
+		})
+	}
+}
+
+func TestQuoteTerms(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+	tests := []struct {
+		name          string
+		appointmentId int
+		coverages     []domain.PatientCoverage
+		wantDate      string
+		wantInsurer   int
+	}{
+		{name: "without appointment quotes today as private", wantDate: today},
+		{name: "appointment date", appointmentId: 3, wantDate: "2026-03-10"},
+		{name: "coverage valid on the appointment date", appointmentId: 3,
+			coverages: []domain.PatientCoverage{{InsurerId: 5, ValidFrom: "2026-01-01", ValidTo: "2026-06-30"}}, wantDate: "2026-03-10", wantInsurer: 5},
+		{name: "coverage expired before the appointment", appointmentId: 3,
+			coverages: []domain.PatientCoverage{{InsurerId: 5, ValidFrom: "2025-01-01", ValidTo: "2026-03-09"}}, wantDate: "2026-03-10"},
+		{name: "coverage starting after the appointment", appointmentId: 3,
+			coverages: []domain.PatientCoverage{{InsurerId: 5, ValidFrom: "2026-03-11"}}, wantDate: "2026-03-10"},
+		{name: "first valid coverage wins", appointmentId: 3,
+			coverages: []domain.PatientCoverage{{InsurerId: 4, ValidFrom: "2027-01-01"}, {InsurerId: 5, ValidFrom: "2026-03-10"}, {InsurerId: 6, ValidFrom: "2020-01-01"}},
+			wantDate:  "2026-03-10", wantInsurer: 5},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			prices := &fakePrices{quote: domain.PriceQuote{ListPrice: 100}}
+			s := &service{r: &fakeRepository{coverages: tt.coverages}, a: fakeAppointments{date: "2026-03-10"}, prices: prices, config: Config{TaxRate: 21}}
+			invoice := domain.Invoice{PatientId: 1, AppointmentId: tt.appointmentId, Items: []domain.InvoiceItem{{ProcedureCode: "01.01"}}}
+			date, insurerId, err := s.quoteTerms(invoice)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if date != tt.wantDate || insurerId != tt.wantInsurer {
+				t.Fatalf("expected %s with insurer %d, got %s with insurer %d", tt.wantDate, tt.wantInsurer, date, insurerId)
+			}
+			_, err = s.prepareItem(invoice.PatientId, date, insurerId, invoice.Items[0])
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if prices.date != tt.wantDate || prices.insurerId != tt.wantInsurer {
+				t.Fatalf("quoted with %s and insurer %d", prices.date, prices.insurerId)
+			}
+		})
+	}
+}
+
+func TestIssueNumbering(t *testing.T) {
+	item := []domain.InvoiceItem{{Description: "Consulta", Quantity: 1, UnitPrice: 100}}
+	r := &fakeRepository{
+		invoices: map[int]domain.Invoice{
+			1: {Id: 1, Status: domain.InvoiceDraft, Items: item},
+			2: {Id: 2, Status: domain.InvoiceDraft},
+			3: {Id: 3, Status: domain.InvoiceDraft, Items: item},
+			4: {Id: 4, Status: domain.InvoiceVoid, Items: item},
+			5: {Id: 5, Status: domain.InvoiceDraft, Items: item},
+			6: {Id: 6, Status: domain.InvoiceDraft, Items: item},
+		},
+		last: map[string]int{},
+	}
+	s := &service{r: r, config: Config{Series: "A"}}
+	// Los pasos corren en orden sobre el mismo repositorio, los que fallan no consumen numero
+	tests := []struct {
+		name      string
+		id        int
+		failIssue bool
+		number    int
+		err       string
+	}{
+		{name: "first draft gets number 1", id: 1, number: 1},
+		{name: "issued invoice can't be issued again", id: 1, err: "invoice 1 is issued, only drafts can be issued"},
+		{name: "draft without items", id: 2, err: "invoice 2 has no items"},
+		{name: "void invoice", id: 4, err: "invoice 4 is void, only drafts can be issued"},
+		{name: "missing invoice", id: 9, err: "invoice 9 not found"},
+		{name: "next draft continues the sequence", id: 3, number: 2},
+		{name: "failed issue doesn't consume a number", id: 5, failIssue: true, err: "error issuing invoice 5"},
+		{name: "retry after a failure", id: 5, number: 3},
+		{name: "no gaps after failures", id: 6, number: 4},
+	}
+	for _, tt := range tests {
+		r.failIssue = tt.failIssue
+		issued, err := s.Issue(tt.id)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Fatalf("%s: expected error %q, got %v", tt.name, tt.err, err)
+			}
+			continue
+		}
+		if err != nil {
+			t.Fatalf("%s: unexpected error: %s", tt.name, err)
+		}
+		if issued.Number != tt.number || issued.FullNumber() != fmt.Sprintf("A-%08d", tt.number) {
+			t.Fatalf("%s: expected number %d, got %s", tt.name, tt.number, issued.FullNumber())
+		}
+		if issued.IssueDate != time.Now().Format("2006-01-02") {
+			t.Fatalf("%s: expected issue date today, got %s", tt.name, issued.IssueDate)
+		}
+	}
+}
@@ -0,0 +1,108 @@
+package lab
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type LabRepository interface {
+	GetByID(id int) (domain.LabOrder, error)
+	GetByPatient(patientId int) ([]domain.LabOrder, error)
+	GetByStatus(status string) ([]domain.LabOrder, error)
+	GetByAppointment(appointmentId int) ([]domain.LabOrder, error)
+	Create(order domain.LabOrder) (domain.LabOrder, error)
+	Update(order domain.LabOrder) (domain.LabOrder, error)
+}
+
+type labRepository struct {
+	storage        store.LabStore
+	patientStore   store.PatientStore
+	dentistStore   store.DentistStore
+	treatmentStore store.TreatmentStore
+}
+
+// NewLabRepository crea un nuevo repositorio
+func NewLabRepository(storage store.LabStore, patientStore store.PatientStore, dentistStore store.DentistStore,
+	treatmentStore store.TreatmentStore) LabRepository {
+	return &labRepository{storage, patientStore, dentistStore, treatmentStore}
+}
+
+// GetByID busca una orden de laboratorio por su id
+func (r *labRepository) GetByID(id int) (domain.LabOrder, error) {
+	order, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.LabOrder{}, errors.New(fmt.Sprintf("lab order %d not found", id))
+	}
+	return order, nil
+}
+
+// GetByPatient busca las ordenes de laboratorio de un paciente
+func (r *labRepository) GetByPatient(patientId int) ([]domain.LabOrder, error) {
+	_, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return []domain.LabOrder{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	orders, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.LabOrder{}, errors.New(fmt.Sprintf("lab orders of patient %d not found", patientId))
+	}
+	return orders, nil
+}
+
+// GetByStatus busca las ordenes de laboratorio en un estado
+func (r *labRepository) GetByStatus(status string) ([]domain.LabOrder, error) {
+	orders, err := r.storage.GetByStatus(status)
+	if err != nil {
+		return []domain.LabOrder{}, errors.New(fmt.Sprintf("lab orders %s not found", status))
+	}
+	return orders, nil
+}
+
+// GetByAppointment busca las ordenes de los procedimientos agendados en un turno
+func (r *labRepository) GetByAppointment(appointmentId int) ([]domain.LabOrder, error) {
+	orders, err := r.storage.GetByAppointment(appointmentId)
+	if err != nil {
+		return []domain.LabOrder{}, errors.New(fmt.Sprintf("lab orders of appointment %d not found", appointmentId))
+	}
+	return orders, nil
+}
+
+// Create agrega una orden de laboratorio, el procedimiento tiene que ser de un plan del paciente
+func (r *labRepository) Create(order domain.LabOrder) (domain.LabOrder, error) {
+	_, err := r.patientStore.GetByID(order.PatientId)
+	if err != nil {
+		return domain.LabOrder{}, errors.New(fmt.Sprintf("patient %d not found", order.PatientId))
+	}
+	dentist, err := r.dentistStore.GetByID(order.Dentist.Id)
+	if err != nil {
+		return domain.LabOrder{}, errors.New(fmt.Sprintf("dentist %d not found", order.Dentist.Id))
+	}
+	order.Dentist = dentist
+	item, err := r.treatmentStore.GetItemByID(order.TreatmentItemId)
+	if err != nil {
+		return domain.LabOrder{}, errors.New(fmt.Sprintf("treatment item %d not found", order.TreatmentItemId))
+	}
+	plan, err := r.treatmentStore.GetByID(item.PlanId)
+	if err != nil || plan.PatientId != order.PatientId {
+		return domain.LabOrder{}, errors.New(fmt.Sprintf("treatment item %d doesn't belong to patient %d", order.TreatmentItemId, order.PatientId))
+	}
+	if item.Status == domain.ItemCancelled {
+		return domain.LabOrder{}, errors.New(fmt.Sprintf("treatment item %d is cancelled", order.TreatmentItemId))
+	}
+	o, err := r.storage.Create(order)
+	if err != nil {
+		return domain.LabOrder{}, errors.New("error creating lab order")
+	}
+	return r.GetByID(o.Id)
+}
+
+// Update actualiza una orden de laboratorio
+func (r *labRepository) Update(order domain.LabOrder) (domain.LabOrder, error) {
+	err := r.storage.Update(order)
+	if err != nil {
+		return domain.LabOrder{}, errors.New(fmt.Sprintf("error updating lab order %d", order.Id))
+	}
+	return r.GetByID(order.Id)
+}
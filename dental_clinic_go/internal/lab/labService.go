@@ -0,0 +1,216 @@
+package lab
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+)
+
+// transitions indica a que estados puede pasar una orden desde cada estado
+var transitions = map[string][]string{
+	domain.LabSent:          {domain.LabInFabrication, domain.LabReceived},
+	domain.LabInFabrication: {domain.LabReceived},
+	domain.LabReceived:      {domain.LabFitted},
+}
+
+// pendingStatuses son los estados en los que el trabajo todavia esta en el laboratorio
+var pendingStatuses = []string{domain.LabSent, domain.LabInFabrication}
+
+type Service interface {
+	GetByID(id int) (domain.LabOrder, error)
+	GetByPatient(patientId int) ([]domain.LabOrder, error)
+	GetByStatus(status string) ([]domain.LabOrder, error)
+	Create(order domain.LabOrder) (domain.LabOrder, error)
+	Update(id int, order domain.LabOrder) (domain.LabOrder, error)
+	UpdateStatus(id int, status string) (domain.LabOrder, error)
+	AppointmentWarnings(a domain.Appointment) ([]string, error)
+}
+
+type service struct {
+	r LabRepository
+}
+
+// NewLabService crea un nuevo servicio
+func NewLabService(r LabRepository) Service {
+	return &service{r}
+}
+
+// GetByID busca una orden de laboratorio por su id
+func (s *service) GetByID(id int) (domain.LabOrder, error) {
+	order, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	return withWarning(order), nil
+}
+
+// GetByPatient busca las ordenes de laboratorio de un paciente
+func (s *service) GetByPatient(patientId int) ([]domain.LabOrder, error) {
+	orders, err := s.r.GetByPatient(patientId)
+	if err != nil {
+		return []domain.LabOrder{}, err
+	}
+	for i := range orders {
+		orders[i] = withWarning(orders[i])
+	}
+	return orders, nil
+}
+
+// GetByStatus busca las ordenes en un estado, sin estado devuelve las que siguen en el laboratorio
+// ordenadas por fecha de entrega
+func (s *service) GetByStatus(status string) ([]domain.LabOrder, error) {
+	statuses := pendingStatuses
+	if status != "" {
+		if _, ok := transitions[status]; !ok && status != domain.LabFitted {
+			return []domain.LabOrder{}, errors.New("invalid status, must be one of: sent, in_fabrication, received, fitted")
+		}
+		statuses = []string{status}
+	}
+	orders := []domain.LabOrder{}
+	for _, st := range statuses {
+		found, err := s.r.GetByStatus(st)
+		if err != nil {
+			return []domain.LabOrder{}, err
+		}
+		for _, order := range found {
+			orders = append(orders, withWarning(order))
+		}
+	}
+	sort.SliceStable(orders, func(i, j int) bool {
+		return orders[i].ExpectedDate < orders[j].ExpectedDate
+	})
+	return orders, nil
+}
+
+// Create valida y agrega una orden de laboratorio, las ordenes nuevas siempre empiezan enviadas
+func (s *service) Create(order domain.LabOrder) (domain.LabOrder, error) {
+	if order.SentDate == "" {
+		order.SentDate = time.Now().Format("2006-01-02")
+	}
+	err := validate(order)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	order.Status = domain.LabSent
+	order, err = s.r.Create(order)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	return withWarning(order), nil
+}
+
+// Update modifica el laboratorio, el trabajo, la fecha de entrega y las notas de una orden,
+// el estado solo se cambia con UpdateStatus
+func (s *service) Update(id int, order domain.LabOrder) (domain.LabOrder, error) {
+	current, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	if current.Status == domain.LabFitted {
+		return domain.LabOrder{}, errors.New(fmt.Sprintf("lab order %d is already fitted", id))
+	}
+	current.Lab = order.Lab
+	current.Description = order.Description
+	current.ExpectedDate = order.ExpectedDate
+	current.Notes = order.Notes
+	err = validate(current)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	current, err = s.r.Update(current)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	return withWarning(current), nil
+}
+
+// UpdateStatus cambia el estado de una orden validando la transicion y registra la fecha
+// de recepcion o de colocacion. Si la orden ya tiene ese estado la devuelve sin cambios.
+func (s *service) UpdateStatus(id int, status string) (domain.LabOrder, error) {
+	order, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	if order.Status == status {
+		return withWarning(order), nil
+	}
+	if !contains(transitions[order.Status], status) {
+		return domain.LabOrder{}, errors.New(fmt.Sprintf("lab order %d can't change from %s to %s", id, order.Status, status))
+	}
+	order.Status = status
+	switch status {
+	case domain.LabReceived:
+		order.ReceivedDate = time.Now().Format("2006-01-02")
+	case domain.LabFitted:
+		order.FittedDate = time.Now().Format("2006-01-02")
+	}
+	order, err = s.r.Update(order)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	return withWarning(order), nil
+}
+
+// AppointmentWarnings avisa cuando un turno de colocacion esta agendado antes de la fecha en la
+// que el laboratorio entrega el trabajo
+func (s *service) AppointmentWarnings(a domain.Appointment) ([]string, error) {
+	orders, err := s.r.GetByAppointment(a.Id)
+	if err != nil {
+		return nil, err
+	}
+	warnings := []string{}
+	for _, order := range orders {
+		order.FittingDate = a.Date
+		if order = withWarning(order); order.Warning != "" {
+			warnings = append(warnings, order.Warning)
+		}
+	}
+	return warnings, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// validate valida los datos obligatorios y las fechas de una orden
+func validate(order domain.LabOrder) error {
+	if order.Lab == "" {
+		return errors.New("lab can't be empty")
+	}
+	if order.Description == "" {
+		return errors.New("description can't be empty")
+	}
+	sent, err := time.Parse("2006-01-02", order.SentDate)
+	if err != nil {
+		return errors.New("invalid sent_date, must be in format: yyyy-mm-dd")
+	}
+	expected, err := time.Parse("2006-01-02", order.ExpectedDate)
+	if err != nil {
+		return errors.New("invalid expected_date, must be in format: yyyy-mm-dd")
+	}
+	if expected.Before(sent) {
+		return errors.New("expected_date can't be before sent_date")
+	}
+	return nil
+}
+
+// withWarning completa el aviso de una orden que sigue en el laboratorio y cuya colocacion
+// esta agendada antes de la fecha de entrega
+func withWarning(order domain.LabOrder) domain.LabOrder {
+	order.Warning = ""
+	if contains(pendingStatuses, order.Status) && order.FittingDate != "" && order.FittingDate < order.ExpectedDate {
+		order.Warning = fmt.Sprintf("fitting appointment on %s is booked before lab order %d (%s) is expected back on %s",
+			order.FittingDate, order.Id, order.Description, order.ExpectedDate)
+	}
+	return order
+}
+
+// contains indica si un estado esta en la lista
+func contains(statuses []string, status string) bool {
+	for _, s := range statuses {
+		if s == status {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,205 @@
+package lab
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeRepository es un LabRepository en memoria, fittings indica el turno de colocacion de cada orden
+type fakeRepository struct {
+	orders   map[int]domain.LabOrder
+	fittings map[int]int
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.LabOrder, error) {
+	order, ok := r.orders[id]
+	if !ok {
+		return domain.LabOrder{}, errors.New(fmt.Sprintf("lab order %d not found", id))
+	}
+	return order, nil
+}
+
+func (r *fakeRepository) GetByPatient(patientId int) ([]domain.LabOrder, error) {
+	return []domain.LabOrder{}, nil
+}
+
+func (r *fakeRepository) GetByStatus(status string) ([]domain.LabOrder, error) {
+	orders := []domain.LabOrder{}
+	for id := 1; id <= len(r.orders); id++ {
+		if r.orders[id].Status == status {
+			orders = append(orders, r.orders[id])
+		}
+	}
+	return orders, nil
+}
+
+func (r *fakeRepository) GetByAppointment(appointmentId int) ([]domain.LabOrder, error) {
+	orders := []domain.LabOrder{}
+	for id, appointmentOf := range r.fittings {
+		if appointmentOf == appointmentId {
+			orders = append(orders, r.orders[id])
+		}
+	}
+	return orders, nil
+}
+
+func (r *fakeRepository) Create(order domain.LabOrder) (domain.LabOrder, error) {
+	order.Id = len(r.orders) + 1
+	r.orders[order.Id] = order
+	return order, nil
+}
+
+func (r *fakeRepository) Update(order domain.LabOrder) (domain.LabOrder, error) {
+	r.orders[order.Id] = order
+	return order, nil
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		orders: map[int]domain.LabOrder{
+			1: {Id: 1, Lab: "Lab Sur", Description: "corona 16", Status: domain.LabSent, SentDate: "2026-10-01", ExpectedDate: "2026-10-20"},
+			2: {Id: 2, Lab: "Lab Sur", Description: "puente", Status: domain.LabInFabrication, SentDate: "2026-10-01", ExpectedDate: "2026-10-10"},
+			3: {Id: 3, Lab: "Lab Sur", Description: "placa", Status: domain.LabReceived, SentDate: "2026-09-01", ExpectedDate: "2026-09-15"},
+			4: {Id: 4, Lab: "Lab Sur", Description: "corona 21", Status: domain.LabFitted, SentDate: "2026-09-01", ExpectedDate: "2026-09-15"},
+		},
+		fittings: map[int]int{1: 10, 3: 10},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name  string
+		order domain.LabOrder
+		err   string
+	}{
+		{name: "valid order", order: domain.LabOrder{Lab: "Lab Sur", Description: "corona", SentDate: "2026-10-01", ExpectedDate: "2026-10-15"}},
+		{name: "sent today by default", order: domain.LabOrder{Lab: "Lab Sur", Description: "corona", ExpectedDate: "2999-01-01"}},
+		{name: "without lab", order: domain.LabOrder{Description: "corona", SentDate: "2026-10-01", ExpectedDate: "2026-10-15"}, err: "lab can't be empty"},
+		{name: "without description", order: domain.LabOrder{Lab: "Lab Sur", SentDate: "2026-10-01", ExpectedDate: "2026-10-15"}, err: "description can't be empty"},
+		{name: "invalid sent date", order: domain.LabOrder{Lab: "Lab Sur", Description: "corona", SentDate: "01/10/2026", ExpectedDate: "2026-10-15"}, err: "invalid sent_date, must be in format: yyyy-mm-dd"},
+		{name: "invalid expected date", order: domain.LabOrder{Lab: "Lab Sur", Description: "corona", SentDate: "2026-10-01"}, err: "invalid expected_date, must be in format: yyyy-mm-dd"},
+		{name: "expected before sent", order: domain.LabOrder{Lab: "Lab Sur", Description: "corona", SentDate: "2026-10-01", ExpectedDate: "2026-09-30"}, err: "expected_date can't be before sent_date"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// el estado del body se ignora, las ordenes nuevas empiezan enviadas
+			tt.order.Status = domain.LabFitted
+			order, err := NewLabService(newFakeRepository()).Create(tt.order)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if order.Status != domain.LabSent || order.SentDate == "" {
+				t.Fatalf("expected a sent order with sent_date, got %+v", order)
+			}
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	tests := []struct {
+		name     string
+		id       int
+		status   string
+		received bool
+		fitted   bool
+		err      string
+	}{
+		{name: "sent to in fabrication", id: 1, status: domain.LabInFabrication},
+		{name: "sent to received", id: 1, status: domain.LabReceived, received: true},
+		{name: "received to fitted", id: 3, status: domain.LabFitted, fitted: true},
+		{name: "same status", id: 2, status: domain.LabInFabrication},
+		{name: "fitted before received", id: 2, status: domain.LabFitted, err: "lab order 2 can't change from in_fabrication to fitted"},
+		{name: "back from fitted", id: 4, status: domain.LabReceived, err: "lab order 4 can't change from fitted to received"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			order, err := NewLabService(newFakeRepository()).UpdateStatus(tt.id, tt.status)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if order.Status != tt.status || (order.ReceivedDate != "") != tt.received || (order.FittedDate != "") != tt.fitted {
+				t.Fatalf("unexpected order %+v", order)
+			}
+		})
+	}
+}
+
+func TestUpdateFittedOrder(t *testing.T) {
+	_, err := NewLabService(newFakeRepository()).Update(4, domain.LabOrder{Lab: "Lab Norte", Description: "corona", ExpectedDate: "2026-09-20"})
+	if err == nil || err.Error() != "lab order 4 is already fitted" {
+		t.Fatalf("expected error %q, got %v", "lab order 4 is already fitted", err)
+	}
+}
+
+func TestGetByStatus(t *testing.T) {
+	tests := []struct {
+		name   string
+		status string
+		ids    []int
+		err    string
+	}{
+		{name: "pending by expected date", ids: []int{2, 1}},
+		{name: "one status", status: domain.LabFitted, ids: []int{4}},
+		{name: "invalid status", status: "lost", err: "invalid status, must be one of: sent, in_fabrication, received, fitted"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			orders, err := NewLabService(newFakeRepository()).GetByStatus(tt.status)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(orders) != len(tt.ids) {
+				t.Fatalf("expected orders %v, got %+v", tt.ids, orders)
+			}
+			for i, id := range tt.ids {
+				if orders[i].Id != id {
+					t.Fatalf("expected orders %v, got %+v", tt.ids, orders)
+				}
+			}
+		})
+	}
+}
+
+func TestAppointmentWarnings(t *testing.T) {
+	tests := []struct {
+		name     string
+		date     string
+		warnings int
+	}{
+		{name: "fitting before the lab delivers", date: "2026-10-18", warnings: 1},
+		{name: "fitting on the delivery date", date: "2026-10-20"},
+		{name: "fitting after the lab delivers", date: "2026-10-25"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			// la orden 3 ya se recibio, nunca genera avisos
+			warnings, err := NewLabService(newFakeRepository()).AppointmentWarnings(domain.Appointment{Id: 10, Date: tt.date})
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(warnings) != tt.warnings {
+				t.Fatalf("expected %d warnings, got %v", tt.warnings, warnings)
+			}
+		})
+	}
+}
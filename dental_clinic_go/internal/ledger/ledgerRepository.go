@@ -0,0 +1,110 @@
+package ledger
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type LedgerRepository interface {
+	GetByID(id int) (domain.LedgerEntry, error)
+	GetByPatient(patientId int) ([]domain.LedgerEntry, error)
+	GetPatients(date string) ([]int, error)
+	GetPatient(id int) (domain.Patient, error)
+	GetInvoice(id int) (domain.Invoice, error)
+	GetInvoices(patientId int) ([]domain.Invoice, error)
+	Create(entry domain.LedgerEntry) (domain.LedgerEntry, error)
+	Allocate(entryId int, allocations []domain.PaymentAllocation) (domain.LedgerEntry, error)
+}
+
+type ledgerRepository struct {
+	storage      store.LedgerStore
+	patientStore store.PatientStore
+	invoiceStore store.InvoiceStore
+}
+
+// NewLedgerRepository crea un nuevo repositorio
+func NewLedgerRepository(storage store.LedgerStore, patientStore store.PatientStore, invoiceStore store.InvoiceStore) LedgerRepository {
+	return &ledgerRepository{storage, patientStore, invoiceStore}
+}
+
+// GetByID busca un movimiento por su id
+func (r *ledgerRepository) GetByID(id int) (domain.LedgerEntry, error) {
+	entry, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.LedgerEntry{}, errors.New(fmt.Sprintf("ledger entry %d not found", id))
+	}
+	return entry, nil
+}
+
+// GetByPatient busca los movimientos de la cuenta de un paciente
+func (r *ledgerRepository) GetByPatient(patientId int) ([]domain.LedgerEntry, error) {
+	_, err := r.GetPatient(patientId)
+	if err != nil {
+		return []domain.LedgerEntry{}, err
+	}
+	entries, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.LedgerEntry{}, errors.New(fmt.Sprintf("ledger of patient %d not found", patientId))
+	}
+	return entries, nil
+}
+
+// GetPatients busca los pacientes con movimientos hasta una fecha
+func (r *ledgerRepository) GetPatients(date string) ([]int, error) {
+	patients, err := r.storage.GetPatients(date)
+	if err != nil {
+		return []int{}, errors.New("error getting patients with ledger entries")
+	}
+	return patients, nil
+}
+
+// GetPatient busca un paciente por su id
+func (r *ledgerRepository) GetPatient(id int) (domain.Patient, error) {
+	patient, err := r.patientStore.GetByID(id)
+	if err != nil {
+		return domain.Patient{}, errors.New(fmt.Sprintf("patient %d not found", id))
+	}
+	return patient, nil
+}
+
+// GetInvoice busca una factura por su id
+func (r *ledgerRepository) GetInvoice(id int) (domain.Invoice, error) {
+	invoice, err := r.invoiceStore.GetByID(id)
+	if err != nil {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("invoice %d not found", id))
+	}
+	return invoice, nil
+}
+
+// GetInvoices busca las facturas de un paciente
+func (r *ledgerRepository) GetInvoices(patientId int) ([]domain.Invoice, error) {
+	invoices, err := r.invoiceStore.GetByPatient(patientId)
+	if err != nil {
+		return []domain.Invoice{}, errors.New(fmt.Sprintf("invoices of patient %d not found", patientId))
+	}
+	return invoices, nil
+}
+
+// Create agrega un movimiento verificando que exista el paciente
+func (r *ledgerRepository) Create(entry domain.LedgerEntry) (domain.LedgerEntry, error) {
+	_, err := r.GetPatient(entry.PatientId)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	created, err := r.storage.Create(entry)
+	if err != nil {
+		return domain.LedgerEntry{}, errors.New("error creating ledger entry")
+	}
+	return r.GetByID(created.Id)
+}
+
+// Allocate imputa un pago a facturas
+func (r *ledgerRepository) Allocate(entryId int, allocations []domain.PaymentAllocation) (domain.LedgerEntry, error) {
+	err := r.storage.Allocate(entryId, allocations)
+	if err != nil {
+		return domain.LedgerEntry{}, errors.New(fmt.Sprintf("error allocating ledger entry %d", entryId))
+	}
+	return r.GetByID(entryId)
+}
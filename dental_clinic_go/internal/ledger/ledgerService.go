@@ -0,0 +1,403 @@
+package ledger
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"math"
+	"time"
+)
+
+var entryTypes = []string{domain.EntryCharge, domain.EntryPayment, domain.EntryRefund, domain.EntryAdjustment}
+
+var paymentMethods = []string{domain.PaymentCash, domain.PaymentCard, domain.PaymentTransfer}
+
+type Service interface {
+	GetByID(id int) (domain.LedgerEntry, error)
+	GetByPatient(patientId int) ([]domain.LedgerEntry, error)
+	Create(entry domain.LedgerEntry) (domain.LedgerEntry, error)
+	Allocate(entryId int, allocations []domain.PaymentAllocation) (domain.LedgerEntry, error)
+	GetBalance(patientId int) (domain.PatientBalance, error)
+	GetAging(date string) (domain.AgingReport, error)
+}
+
+type service struct {
+	r LedgerRepository
+}
+
+// NewLedgerService crea un nuevo servicio
+func NewLedgerService(r LedgerRepository) Service {
+	return &service{r}
+}
+
+// GetByID busca un movimiento por su id
+func (s *service) GetByID(id int) (domain.LedgerEntry, error) {
+	entry, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	return withAmount(entry), nil
+}
+
+// GetByPatient busca los movimientos de la cuenta de un paciente con el saldo despues de cada uno
+func (s *service) GetByPatient(patientId int) ([]domain.LedgerEntry, error) {
+	entries, err := s.r.GetByPatient(patientId)
+	if err != nil {
+		return []domain.LedgerEntry{}, err
+	}
+	balance := 0.0
+	for i := range entries {
+		balance = round(balance + entries[i].Debit - entries[i].Credit)
+		entries[i] = withAmount(entries[i])
+		entries[i].Balance = balance
+	}
+	return entries, nil
+}
+
+// Create valida y registra un cargo, pago, reintegro o ajuste en la cuenta de un paciente.
+// Los pagos se pueden imputar a facturas y los reintegros no pueden superar el saldo a favor.
+func (s *service) Create(entry domain.LedgerEntry) (domain.LedgerEntry, error) {
+	if entry.Date == "" {
+		entry.Date = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", entry.Date); err != nil {
+		return domain.LedgerEntry{}, errors.New("invalid date, must be in format: yyyy-mm-dd")
+	}
+	entry.Amount = round(entry.Amount)
+	switch {
+	case !contains(entryTypes, entry.Type):
+		return domain.LedgerEntry{}, errors.New("invalid type, must be one of: charge, payment, refund, adjustment")
+	case entry.Type == domain.EntryAdjustment && entry.Amount == 0:
+		return domain.LedgerEntry{}, errors.New("amount can't be zero")
+	case entry.Type != domain.EntryAdjustment && entry.Amount <= 0:
+		return domain.LedgerEntry{}, errors.New("amount must be positive")
+	case (entry.Type == domain.EntryCharge || entry.Type == domain.EntryAdjustment) && entry.Description == "":
+		return domain.LedgerEntry{}, errors.New("description can't be empty")
+	case (entry.Type == domain.EntryPayment || entry.Type == domain.EntryRefund) && !contains(paymentMethods, entry.Method):
+		return domain.LedgerEntry{}, errors.New("invalid method, must be one of: cash, card, transfer")
+	case entry.Type != domain.EntryPayment && len(entry.Allocations) > 0:
+		return domain.LedgerEntry{}, errors.New("only payments can be allocated to invoices")
+	}
+	if entry.Type == domain.EntryCharge || entry.Type == domain.EntryAdjustment {
+		entry.Method = ""
+	}
+	// Los movimientos de facturas solo se registran al emitirlas y anularlas
+	entry.InvoiceId = 0
+	entry.Debit, entry.Credit = 0, 0
+	switch {
+	case entry.Type == domain.EntryPayment, entry.Type == domain.EntryAdjustment && entry.Amount < 0:
+		entry.Credit = math.Abs(entry.Amount)
+	default:
+		entry.Debit = entry.Amount
+	}
+	entries, err := s.r.GetByPatient(entry.PatientId)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	if entry.Type == domain.EntryRefund {
+		credit := -balance(entries)
+		if entry.Amount > credit {
+			return domain.LedgerEntry{}, errors.New(fmt.Sprintf("refund can't exceed the patient credit of %.2f", math.Max(credit, 0)))
+		}
+	}
+	if entry.Type == domain.EntryPayment {
+		// Un pago nuevo todavia no tiene nada imputado, sus imputaciones son las que se validan
+		payment := entry
+		payment.Allocations = nil
+		err = s.checkAllocations(payment, entries, entry.Allocations)
+		if err != nil {
+			return domain.LedgerEntry{}, err
+		}
+	}
+	created, err := s.r.Create(entry)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	return withAmount(created), nil
+}
+
+// Allocate imputa a facturas la parte de un pago que todavia no esta imputada
+func (s *service) Allocate(entryId int, allocations []domain.PaymentAllocation) (domain.LedgerEntry, error) {
+	entry, err := s.r.GetByID(entryId)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	if entry.Type != domain.EntryPayment {
+		return domain.LedgerEntry{}, errors.New(fmt.Sprintf("ledger entry %d is a %s, only payments can be allocated to invoices", entryId, entry.Type))
+	}
+	if len(allocations) == 0 {
+		return domain.LedgerEntry{}, errors.New("allocations can't be empty")
+	}
+	entries, err := s.r.GetByPatient(entry.PatientId)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	err = s.checkAllocations(entry, entries, allocations)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	entry, err = s.r.Allocate(entryId, allocations)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	return withAmount(entry), nil
+}
+
+// GetBalance devuelve el saldo de un paciente con la antiguedad de la deuda y las facturas impagas
+func (s *service) GetBalance(patientId int) (domain.PatientBalance, error) {
+	entries, err := s.r.GetByPatient(patientId)
+	if err != nil {
+		return domain.PatientBalance{}, err
+	}
+	result := domain.PatientBalance{PatientId: patientId, Invoices: []domain.InvoiceBalance{}}
+	for _, e := range entries {
+		switch e.Type {
+		case domain.EntryCharge:
+			result.Charges += e.Debit
+		case domain.EntryPayment:
+			result.Payments += e.Credit
+		case domain.EntryRefund:
+			result.Refunds += e.Debit
+		case domain.EntryAdjustment:
+			result.Adjustments += e.Debit - e.Credit
+		}
+	}
+	result.Charges = round(result.Charges)
+	result.Payments = round(result.Payments)
+	result.Refunds = round(result.Refunds)
+	result.Adjustments = round(result.Adjustments)
+	result.Balance = balance(entries)
+	today, _ := time.Parse("2006-01-02", time.Now().Format("2006-01-02"))
+	result.Aging = aging(entries, today)
+
+	invoices, err := s.r.GetInvoices(patientId)
+	if err != nil {
+		return domain.PatientBalance{}, err
+	}
+	paid := invoicePaid(entries)
+	for _, invoice := range invoices {
+		if invoice.Status != domain.InvoiceIssued {
+			continue
+		}
+		due := round(invoice.Total - paid[invoice.Id])
+		if due <= 0 {
+			continue
+		}
+		result.Invoices = append(result.Invoices, domain.InvoiceBalance{
+			InvoiceId: invoice.Id,
+			Number:    invoice.FullNumber(),
+			IssueDate: invoice.IssueDate,
+			Total:     invoice.Total,
+			Paid:      round(paid[invoice.Id]),
+			Due:       due,
+		})
+	}
+	return result, nil
+}
+
+// GetAging devuelve la antiguedad de la deuda de cada paciente a una fecha, por defecto hoy
+func (s *service) GetAging(date string) (domain.AgingReport, error) {
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	asOf, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return domain.AgingReport{}, errors.New("invalid date, must be in format: yyyy-mm-dd")
+	}
+	patients, err := s.r.GetPatients(date)
+	if err != nil {
+		return domain.AgingReport{}, err
+	}
+	report := domain.AgingReport{Date: date, Patients: []domain.PatientAging{}}
+	for _, patientId := range patients {
+		entries, err := s.r.GetByPatient(patientId)
+		if err != nil {
+			return domain.AgingReport{}, err
+		}
+		buckets := aging(entries, asOf)
+		if buckets.Total == 0 {
+			continue
+		}
+		patient, err := s.r.GetPatient(patientId)
+		if err != nil {
+			return domain.AgingReport{}, err
+		}
+		report.Patients = append(report.Patients, domain.PatientAging{
+			PatientId: patientId,
+			Name:      patient.Name,
+			LastName:  patient.LastName,
+			Aging:     buckets,
+		})
+		report.Total.Days0To30 += buckets.Days0To30
+		report.Total.Days31To60 += buckets.Days31To60
+		report.Total.Days61To90 += buckets.Days61To90
+		report.Total.Over90 += buckets.Over90
+		report.Total.Total += buckets.Total
+	}
+	report.Total = roundBuckets(report.Total)
+	return report, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// checkAllocations verifica que las imputaciones no superen lo que queda sin imputar del pago
+// ni lo que se debe de cada factura
+func (s *service) checkAllocations(payment domain.LedgerEntry, entries []domain.LedgerEntry, allocations []domain.PaymentAllocation) error {
+	available := round(payment.Credit - sumAllocations(payment.Allocations))
+	if round(sumAllocations(allocations)) > available {
+		return errors.New(fmt.Sprintf("allocations exceed the unallocated %.2f of the payment", available))
+	}
+	paid := invoicePaid(entries)
+	for _, a := range allocations {
+		if a.Amount <= 0 {
+			return errors.New("allocation amount must be positive")
+		}
+		invoice, err := s.r.GetInvoice(a.InvoiceId)
+		if err != nil {
+			return err
+		}
+		if invoice.PatientId != payment.PatientId {
+			return errors.New(fmt.Sprintf("invoice %d doesn't belong to patient %d", invoice.Id, payment.PatientId))
+		}
+		if invoice.Status != domain.InvoiceIssued {
+			return errors.New(fmt.Sprintf("invoice %d is %s, only issued invoices can be paid", invoice.Id, invoice.Status))
+		}
+		due := round(invoice.Total - paid[invoice.Id])
+		if a.Amount > due {
+			return errors.New(fmt.Sprintf("allocation of %.2f exceeds the %.2f due of invoice %d", a.Amount, due, invoice.Id))
+		}
+		paid[invoice.Id] += a.Amount
+	}
+	return nil
+}
+
+// invoicePaid suma lo imputado a cada factura, los pagos y los ajustes por anulacion
+func invoicePaid(entries []domain.LedgerEntry) map[int]float64 {
+	paid := map[int]float64{}
+	for _, e := range entries {
+		if e.InvoiceId != 0 {
+			paid[e.InvoiceId] += e.Credit
+		}
+		for _, a := range e.Allocations {
+			paid[a.InvoiceId] += a.Amount
+		}
+	}
+	return paid
+}
+
+// aging reparte lo adeudado a una fecha segun la antiguedad de cada cargo. Lo imputado a una
+// factura cancela su cargo y el resto del haber cancela primero los cargos mas antiguos.
+func aging(entries []domain.LedgerEntry, asOf time.Time) domain.AgingBuckets {
+	type debit struct {
+		date      time.Time
+		remaining float64
+	}
+	until := []domain.LedgerEntry{}
+	for _, e := range entries {
+		date, err := time.Parse("2006-01-02", e.Date)
+		if err == nil && !date.After(asOf) {
+			until = append(until, e)
+		}
+	}
+	paid := invoicePaid(until)
+	pool := 0.0
+	for _, e := range until {
+		if e.InvoiceId == 0 {
+			pool += e.Credit
+		}
+		pool -= sumAllocations(e.Allocations)
+	}
+	debits := []debit{}
+	for _, e := range until {
+		if e.Debit == 0 {
+			continue
+		}
+		remaining := e.Debit
+		if e.InvoiceId != 0 {
+			applied := math.Min(paid[e.InvoiceId], remaining)
+			paid[e.InvoiceId] -= applied
+			remaining -= applied
+		}
+		date, _ := time.Parse("2006-01-02", e.Date)
+		debits = append(debits, debit{date, remaining})
+	}
+	for _, extra := range paid {
+		pool += extra
+	}
+	buckets := domain.AgingBuckets{}
+	for _, d := range debits {
+		applied := math.Min(math.Max(pool, 0), d.remaining)
+		pool -= applied
+		remaining := d.remaining - applied
+		if remaining <= 0 {
+			continue
+		}
+		days := int(asOf.Sub(d.date).Hours() / 24)
+		switch {
+		case days <= 30:
+			buckets.Days0To30 += remaining
+		case days <= 60:
+			buckets.Days31To60 += remaining
+		case days <= 90:
+			buckets.Days61To90 += remaining
+		default:
+			buckets.Over90 += remaining
+		}
+		buckets.Total += remaining
+	}
+	return roundBuckets(buckets)
+}
+
+// withAmount completa el importe de un movimiento a partir del debe y el haber
+func withAmount(entry domain.LedgerEntry) domain.LedgerEntry {
+	switch entry.Type {
+	case domain.EntryAdjustment:
+		entry.Amount = round(entry.Debit - entry.Credit)
+	default:
+		entry.Amount = round(entry.Debit + entry.Credit)
+	}
+	return entry
+}
+
+// balance devuelve el debe menos el haber de los movimientos
+func balance(entries []domain.LedgerEntry) float64 {
+	total := 0.0
+	for _, e := range entries {
+		total += e.Debit - e.Credit
+	}
+	return round(total)
+}
+
+// sumAllocations suma los importes imputados
+func sumAllocations(allocations []domain.PaymentAllocation) float64 {
+	total := 0.0
+	for _, a := range allocations {
+		total += a.Amount
+	}
+	return total
+}
+
+// roundBuckets redondea a centavos los importes de cada tramo
+func roundBuckets(b domain.AgingBuckets) domain.AgingBuckets {
+	b.Days0To30 = round(b.Days0To30)
+	b.Days31To60 = round(b.Days31To60)
+	b.Days61To90 = round(b.Days61To90)
+	b.Over90 = round(b.Over90)
+	b.Total = round(b.Total)
+	return b
+}
+
+// round redondea un importe a centavos
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
+
+// contains indica si un valor esta en la lista
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
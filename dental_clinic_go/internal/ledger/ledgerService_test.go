@@ -0,0 +1,264 @@
+package ledger
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeRepository es un LedgerRepository en memoria
+type fakeRepository struct {
+	entries  []domain.LedgerEntry
+	invoices map[int]domain.Invoice
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.LedgerEntry, error) {
+	for _, e := range r.entries {
+		if e.Id == id {
+			return e, nil
+		}
+	}
+	return domain.LedgerEntry{}, errors.New(fmt.Sprintf("ledger entry %d not found", id))
+}
+
+func (r *fakeRepository) GetByPatient(patientId int) ([]domain.LedgerEntry, error) {
+	entries := []domain.LedgerEntry{}
+	for _, e := range r.entries {
+		if e.PatientId == patientId {
+			entries = append(entries, e)
+		}
+	}
+	return entries, nil
+}
+
+func (r *fakeRepository) GetPatients(date string) ([]int, error) {
+	return []int{1, 2}, nil
+}
+
+func (r *fakeRepository) GetPatient(id int) (domain.Patient, error) {
+	return domain.Patient{Id: id, Name: "Juan", LastName: "Perez"}, nil
+}
+
+func (r *fakeRepository) GetInvoice(id int) (domain.Invoice, error) {
+	invoice, ok := r.invoices[id]
+	if !ok {
+		return domain.Invoice{}, errors.New(fmt.Sprintf("invoice %d not found", id))
+	}
+	return invoice, nil
+}
+
+func (r *fakeRepository) GetInvoices(patientId int) ([]domain.Invoice, error) {
+	invoices := []domain.Invoice{}
+	for id := 1; id <= len(r.invoices); id++ {
+		if r.invoices[id].PatientId == patientId {
+			invoices = append(invoices, r.invoices[id])
+		}
+	}
+	return invoices, nil
+}
+
+func (r *fakeRepository) Create(entry domain.LedgerEntry) (domain.LedgerEntry, error) {
+	entry.Id = len(r.entries) + 1
+	r.entries = append(r.entries, entry)
+	return entry, nil
+}
+
+func (r *fakeRepository) Allocate(entryId int, allocations []domain.PaymentAllocation) (domain.LedgerEntry, error) {
+	for i := range r.entries {
+		if r.entries[i].Id == entryId {
+			r.entries[i].Allocations = append(r.entries[i].Allocations, allocations...)
+			return r.entries[i], nil
+		}
+	}
+	return domain.LedgerEntry{}, errors.New(fmt.Sprintf("error allocating ledger entry %d", entryId))
+}
+
+// newFakeRepository arma la cuenta del paciente 1, que debe 110, y la del paciente 2, con 30 a favor.
+// La factura 1 esta pagada, de la 2 se deben 80.
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		entries: []domain.LedgerEntry{
+			{Id: 1, PatientId: 1, Type: domain.EntryCharge, Date: "2026-03-01", Debit: 100, InvoiceId: 1, Description: "Factura A-00000001"},
+			{Id: 2, PatientId: 1, Type: domain.EntryCharge, Date: "2026-05-15", Debit: 50, Description: "Cancelacion tardia"},
+			{Id: 3, PatientId: 1, Type: domain.EntryCharge, Date: "2026-06-20", Debit: 80, InvoiceId: 2, Description: "Factura A-00000002"},
+			{Id: 4, PatientId: 1, Type: domain.EntryPayment, Date: "2026-06-25", Credit: 120, Method: domain.PaymentCash,
+				Allocations: []domain.PaymentAllocation{{InvoiceId: 1, Amount: 100}}},
+			{Id: 5, PatientId: 2, Type: domain.EntryPayment, Date: "2026-06-01", Credit: 30, Method: domain.PaymentCard},
+		},
+		invoices: map[int]domain.Invoice{
+			1: {Id: 1, Series: "A", Number: 1, PatientId: 1, Status: domain.InvoiceIssued, IssueDate: "2026-03-01", Total: 100},
+			2: {Id: 2, Series: "A", Number: 2, PatientId: 1, Status: domain.InvoiceIssued, IssueDate: "2026-06-20", Total: 80},
+			3: {Id: 3, Series: "A", Number: 3, PatientId: 1, Status: domain.InvoiceVoid, IssueDate: "2026-06-20", Total: 40},
+			4: {Id: 4, Series: "A", Number: 4, PatientId: 2, Status: domain.InvoiceIssued, IssueDate: "2026-06-20", Total: 40},
+		},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name   string
+		entry  domain.LedgerEntry
+		debit  float64
+		credit float64
+		err    string
+	}{
+		{name: "charge", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryCharge, Amount: 25.555, Description: "Material", Method: domain.PaymentCash}, debit: 25.56},
+		{name: "allocated payment", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryPayment, Amount: 50, Method: domain.PaymentCard,
+			Allocations: []domain.PaymentAllocation{{InvoiceId: 2, Amount: 50}}}, credit: 50},
+		{name: "adjustment in favour of the patient", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryAdjustment, Amount: -15, Description: "Descuento"}, credit: 15},
+		{name: "refund of the patient credit", entry: domain.LedgerEntry{PatientId: 2, Type: domain.EntryRefund, Amount: 30, Method: domain.PaymentTransfer}, debit: 30},
+		{name: "invalid date", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryCharge, Date: "01/06/2026", Amount: 10, Description: "Material"}, err: "invalid date, must be in format: yyyy-mm-dd"},
+		{name: "invalid type", entry: domain.LedgerEntry{PatientId: 1, Type: "gift", Amount: 10}, err: "invalid type, must be one of: charge, payment, refund, adjustment"},
+		{name: "zero adjustment", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryAdjustment, Description: "Descuento"}, err: "amount can't be zero"},
+		{name: "negative charge", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryCharge, Amount: -5, Description: "Material"}, err: "amount must be positive"},
+		{name: "charge without description", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryCharge, Amount: 10}, err: "description can't be empty"},
+		{name: "payment without method", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryPayment, Amount: 10}, err: "invalid method, must be one of: cash, card, transfer"},
+		{name: "allocated charge", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryCharge, Amount: 10, Description: "Material",
+			Allocations: []domain.PaymentAllocation{{InvoiceId: 2, Amount: 10}}}, err: "only payments can be allocated to invoices"},
+		{name: "refund without credit", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryRefund, Amount: 10, Method: domain.PaymentCash}, err: "refund can't exceed the patient credit of 0.00"},
+		{name: "refund over the credit", entry: domain.LedgerEntry{PatientId: 2, Type: domain.EntryRefund, Amount: 40, Method: domain.PaymentCash}, err: "refund can't exceed the patient credit of 30.00"},
+		{name: "allocations over the payment", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryPayment, Amount: 50, Method: domain.PaymentCash,
+			Allocations: []domain.PaymentAllocation{{InvoiceId: 2, Amount: 60}}}, err: "allocations exceed the unallocated 50.00 of the payment"},
+		{name: "allocation over the invoice due", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryPayment, Amount: 100, Method: domain.PaymentCash,
+			Allocations: []domain.PaymentAllocation{{InvoiceId: 2, Amount: 90}}}, err: "allocation of 90.00 exceeds the 80.00 due of invoice 2"},
+		{name: "allocation to a paid invoice", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryPayment, Amount: 10, Method: domain.PaymentCash,
+			Allocations: []domain.PaymentAllocation{{InvoiceId: 1, Amount: 10}}}, err: "allocation of 10.00 exceeds the 0.00 due of invoice 1"},
+		{name: "allocation to a void invoice", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryPayment, Amount: 10, Method: domain.PaymentCash,
+			Allocations: []domain.PaymentAllocation{{InvoiceId: 3, Amount: 10}}}, err: "invoice 3 is void, only issued invoices can be paid"},
+		{name: "allocation to an invoice of another patient", entry: domain.LedgerEntry{PatientId: 1, Type: domain.EntryPayment, Amount: 10, Method: domain.PaymentCash,
+			Allocations: []domain.PaymentAllocation{{InvoiceId: 4, Amount: 10}}}, err: "invoice 4 doesn't belong to patient 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := NewLedgerService(newFakeRepository()).Create(tt.entry)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if entry.Debit != tt.debit || entry.Credit != tt.credit || entry.Date == "" {
+				t.Fatalf("expected debit %.2f and credit %.2f, got %+v", tt.debit, tt.credit, entry)
+			}
+			if entry.Type == domain.EntryCharge && entry.Method != "" {
+				t.Fatalf("expected charges without method, got %s", entry.Method)
+			}
+		})
+	}
+}
+
+func TestAllocate(t *testing.T) {
+	tests := []struct {
+		name        string
+		id          int
+		allocations []domain.PaymentAllocation
+		err         string
+	}{
+		{name: "the unallocated part", id: 4, allocations: []domain.PaymentAllocation{{InvoiceId: 2, Amount: 20}}},
+		{name: "more than the unallocated part", id: 4, allocations: []domain.PaymentAllocation{{InvoiceId: 2, Amount: 30}}, err: "allocations exceed the unallocated 20.00 of the payment"},
+		{name: "negative amount", id: 4, allocations: []domain.PaymentAllocation{{InvoiceId: 2, Amount: -5}}, err: "allocation amount must be positive"},
+		{name: "without allocations", id: 4, err: "allocations can't be empty"},
+		{name: "a charge", id: 1, allocations: []domain.PaymentAllocation{{InvoiceId: 2, Amount: 20}}, err: "ledger entry 1 is a charge, only payments can be allocated to invoices"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			entry, err := NewLedgerService(newFakeRepository()).Allocate(tt.id, tt.allocations)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(entry.Allocations) != 2 || entry.Amount != 120 {
+				t.Fatalf("unexpected entry %+v", entry)
+			}
+		})
+	}
+}
+
+func TestGetByPatient(t *testing.T) {
+	entries, err := NewLedgerService(newFakeRepository()).GetByPatient(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	for i, expected := range []float64{100, 150, 230, 110} {
+		if entries[i].Balance != expected {
+			t.Fatalf("expected balance %.2f after entry %d, got %.2f", expected, entries[i].Id, entries[i].Balance)
+		}
+	}
+	if entries[3].Amount != 120 {
+		t.Fatalf("expected the payment amount to be 120, got %.2f", entries[3].Amount)
+	}
+}
+
+func TestGetBalance(t *testing.T) {
+	tests := []struct {
+		name      string
+		patientId int
+		balance   float64
+		invoices  []int
+	}{
+		{name: "patient with debt", patientId: 1, balance: 110, invoices: []int{2}},
+		{name: "patient with credit", patientId: 2, balance: -30, invoices: []int{4}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			b, err := NewLedgerService(newFakeRepository()).GetBalance(tt.patientId)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if b.Balance != tt.balance || round(b.Charges-b.Payments+b.Refunds+b.Adjustments) != tt.balance {
+				t.Fatalf("expected balance %.2f, got %+v", tt.balance, b)
+			}
+			if len(b.Invoices) != len(tt.invoices) {
+				t.Fatalf("expected unpaid invoices %v, got %+v", tt.invoices, b.Invoices)
+			}
+			for i, id := range tt.invoices {
+				if b.Invoices[i].InvoiceId != id || b.Invoices[i].Due != b.Invoices[i].Total-b.Invoices[i].Paid {
+					t.Fatalf("expected unpaid invoices %v, got %+v", tt.invoices, b.Invoices)
+				}
+			}
+		})
+	}
+}
+
+func TestGetAging(t *testing.T) {
+	report, err := NewLedgerService(newFakeRepository()).GetAging("2026-06-30")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	// el paciente 2 tiene saldo a favor y no aparece
+	if len(report.Patients) != 1 || report.Patients[0].PatientId != 1 {
+		t.Fatalf("expected only patient 1, got %+v", report.Patients)
+	}
+	// la factura 1 esta imputada, los 20 sin imputar cancelan parte del cargo de mayo
+	expected := domain.AgingBuckets{Days0To30: 80, Days31To60: 30, Total: 110}
+	if report.Patients[0].Aging != expected || report.Total != expected {
+		t.Fatalf("expected %+v, got %+v and total %+v", expected, report.Patients[0].Aging, report.Total)
+	}
+}
+
+func TestGetAgingBeforeThePayment(t *testing.T) {
+	report, err := NewLedgerService(newFakeRepository()).GetAging("2026-06-21")
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	expected := domain.AgingBuckets{Days0To30: 80, Days31To60: 50, Over90: 100, Total: 230}
+	if len(report.Patients) != 1 || report.Patients[0].Aging != expected {
+		t.Fatalf("expected %+v, got %+v", expected, report.Patients)
+	}
+}
+
+func TestGetAgingInvalidDate(t *testing.T) {
+	_, err := NewLedgerService(newFakeRepository()).GetAging("30/06/2026")
+	if err == nil || err.Error() != "invalid date, must be in format: yyyy-mm-dd" {
+		t.Fatalf("expected error %q, got %v", "invalid date, must be in format: yyyy-mm-dd", err)
+	}
+}
@@ -0,0 +1,170 @@
+package link
+
+import (
+	"dental_clinic_go/internal/appointment"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/notify"
+	"dental_clinic_go/pkg/token"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Acciones que puede ejecutar un link firmado
+const (
+	ActionConfirm = "confirm"
+	ActionCancel  = "cancel"
+)
+
+// ContactResolver devuelve a quien se envian los avisos de un paciente
+type ContactResolver interface {
+	GetContact(patientId int) (domain.Contact, error)
+}
+
+type Service interface {
+	Generate(appointmentId int) (domain.AppointmentLinks, error)
+	Remind(appointmentId int) (domain.AppointmentReminder, error)
+	Preview(t string) (domain.AppointmentLink, error)
+	Apply(t string) (domain.LinkedAppointment, error)
+}
+
+type service struct {
+	a        appointment.AppointmentService
+	signer   *token.Signer
+	baseUrl  string
+	ttl      time.Duration
+	contacts ContactResolver
+	notifier notify.Notifier
+}
+
+// NewLinkService crea un nuevo servicio de links firmados, los links vencen
+// luego de ttl o al comenzar el turno, lo que ocurra primero
+func NewLinkService(a appointment.AppointmentService, signer *token.Signer, baseUrl string, ttl time.Duration,
+	contacts ContactResolver, notifier notify.Notifier) Service {
+	return &service{a, signer, strings.TrimSuffix(baseUrl, "/"), ttl, contacts, notifier}
+}
+
+// Generate crea los links de confirmacion y cancelacion de un turno
+func (s *service) Generate(appointmentId int) (domain.AppointmentLinks, error) {
+	a, err := s.a.GetByID(appointmentId)
+	if err != nil {
+		return domain.AppointmentLinks{}, err
+	}
+	start, err := a.Start()
+	if err != nil {
+		return domain.AppointmentLinks{}, err
+	}
+	expiresAt := time.Now().Add(s.ttl)
+	if start.Before(expiresAt) {
+		expiresAt = start
+	}
+	if !expiresAt.After(time.Now()) {
+		return domain.AppointmentLinks{}, errors.New(fmt.Sprintf("appointment %d already started", appointmentId))
+	}
+	return domain.AppointmentLinks{
+		ConfirmUrl: s.url(s.signer.Sign(payload(a.Id, ActionConfirm), expiresAt)),
+		CancelUrl:  s.url(s.signer.Sign(payload(a.Id, ActionCancel), expiresAt)),
+		ExpiresAt:  expiresAt.Format(time.RFC3339),
+	}, nil
+}
+
+// Remind envia los links del turno al contacto del paciente, que para un menor es su responsable
+func (s *service) Remind(appointmentId int) (domain.AppointmentReminder, error) {
+	links, err := s.Generate(appointmentId)
+	if err != nil {
+		return domain.AppointmentReminder{}, err
+	}
+	a, err := s.a.GetByID(appointmentId)
+	if err != nil {
+		return domain.AppointmentReminder{}, err
+	}
+	if reminders := a.Patient.ContactPreferences.Reminders; reminders != nil && !*reminders {
+		return domain.AppointmentReminder{}, errors.New(fmt.Sprintf("patient %d doesn't accept reminders", a.Patient.Id))
+	}
+	contact, err := s.contacts.GetContact(a.Patient.Id)
+	if err != nil {
+		return domain.AppointmentReminder{}, err
+	}
+	if contact.Email == "" {
+		return domain.AppointmentReminder{}, errors.New(fmt.Sprintf("contact of patient %d has no email", a.Patient.Id))
+	}
+	body := fmt.Sprintf("Hola %s, te recordamos el turno de %s %s el %s a las %s con %s %s.\nConfirmar: %s\nCancelar: %s",
+		contact.Name, a.Patient.Name, a.Patient.LastName, a.Date, a.Hour, a.Dentist.Name, a.Dentist.LastName, links.ConfirmUrl, links.CancelUrl)
+	err = s.notifier.Send(contact.Email, "Recordatorio de turno", body)
+	if err != nil {
+		return domain.AppointmentReminder{}, err
+	}
+	return domain.AppointmentReminder{AppointmentId: a.Id, Contact: contact, Links: links}, nil
+}
+
+// Preview devuelve la accion y el turno de un link sin ejecutarla
+func (s *service) Preview(t string) (domain.AppointmentLink, error) {
+	id, action, err := s.parse(t)
+	if err != nil {
+		return domain.AppointmentLink{}, err
+	}
+	a, err := s.a.GetByID(id)
+	if err != nil {
+		return domain.AppointmentLink{}, err
+	}
+	return domain.AppointmentLink{Action: action, Appointment: linked(a)}, nil
+}
+
+// Apply ejecuta la accion de un link, aplicarlo mas de una vez no tiene efecto
+func (s *service) Apply(t string) (domain.LinkedAppointment, error) {
+	id, action, err := s.parse(t)
+	if err != nil {
+		return domain.LinkedAppointment{}, err
+	}
+	var a domain.Appointment
+	if action == ActionConfirm {
+		a, err = s.a.Confirm(id)
+	} else {
+		a, err = s.a.CancelByPatient(id)
+	}
+	if err != nil {
+		return domain.LinkedAppointment{}, err
+	}
+	return linked(a), nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// parse valida un link y devuelve el id del turno y la accion
+func (s *service) parse(t string) (int, string, error) {
+	p, err := s.signer.Verify(t, time.Now())
+	if err != nil {
+		return 0, "", err
+	}
+	parts := strings.Split(p, ":")
+	if len(parts) != 2 || (parts[1] != ActionConfirm && parts[1] != ActionCancel) {
+		return 0, "", errors.New("invalid token")
+	}
+	id, err := strconv.Atoi(parts[0])
+	if err != nil {
+		return 0, "", errors.New("invalid token")
+	}
+	return id, parts[1], nil
+}
+
+// url arma la url publica de un link
+func (s *service) url(t string) string {
+	return fmt.Sprintf("%s/links/%s", s.baseUrl, t)
+}
+
+// linked reduce un turno a lo que se puede mostrar en un link
+func linked(a domain.Appointment) domain.LinkedAppointment {
+	return domain.LinkedAppointment{
+		Date:    a.Date,
+		Hour:    a.Hour,
+		Dentist: strings.TrimSpace(a.Dentist.Name + " " + a.Dentist.LastName),
+		Status:  a.Status,
+	}
+}
+
+// payload arma el contenido firmado de un link
+func payload(appointmentId int, action string) string {
+	return fmt.Sprintf("%d:%s", appointmentId, action)
+}
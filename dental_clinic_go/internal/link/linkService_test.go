@@ -0,0 +1,191 @@
+package link
+
+import (
+	"dental_clinic_go/internal/appointment"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/token"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeAppointments guarda un unico turno y la ultima accion aplicada
+type fakeAppointments struct {
+	appointment.AppointmentService
+	appointment domain.Appointment
+	action      string
+}
+
+func (a *fakeAppointments) GetByID(id int) (domain.Appointment, error) {
+	return a.appointment, nil
+}
+
+func (a *fakeAppointments) Confirm(id int) (domain.Appointment, error) {
+	a.action = ActionConfirm
+	a.appointment.Status = domain.AppointmentConfirmed
+	return a.appointment, nil
+}
+
+func (a *fakeAppointments) CancelByPatient(id int) (domain.Appointment, error) {
+	a.action = ActionCancel
+	a.appointment.Status = domain.AppointmentCancelled
+	return a.appointment, nil
+}
+
+// fakeContacts devuelve el mismo contacto para cualquier paciente
+type fakeContacts struct {
+	contact domain.Contact
+}
+
+func (c *fakeContacts) GetContact(patientId int) (domain.Contact, error) {
+	c.contact.PatientId = patientId
+	return c.contact, nil
+}
+
+// fakeNotifier guarda los destinatarios y los mensajes enviados
+type fakeNotifier struct {
+	to     []string
+	bodies []string
+}
+
+func (n *fakeNotifier) Send(to string, subject string, body string) error {
+	n.to = append(n.to, to)
+	n.bodies = append(n.bodies, body)
+	return nil
+}
+
+// in devuelve un turno que empieza dentro de d
+func in(d time.Duration) domain.Appointment {
+	start := time.Now().Add(d).Truncate(time.Second)
+	return domain.Appointment{Id: 7, Date: start.Format("2006-01-02"), Hour: start.Format("15:04:05"), Status: domain.AppointmentScheduled}
+}
+
+// linkToken saca el token de la url de un link
+func linkToken(url string) string {
+	return strings.TrimPrefix(url, "https://clinic.test/links/")
+}
+
+func TestGenerate(t *testing.T) {
+	tests := []struct {
+		name        string
+		appointment domain.Appointment
+		expiresIn   time.Duration
+		err         string
+	}{
+		{name: "expires after the ttl", appointment: in(7 * 24 * time.Hour), expiresIn: 72 * time.Hour},
+		{name: "expires when the appointment starts", appointment: in(5 * time.Hour), expiresIn: 5 * time.Hour},
+		{name: "appointment already started", appointment: in(-time.Hour), err: "appointment 7 already started"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewLinkService(&fakeAppointments{appointment: tt.appointment}, token.NewSigner("secret"), "https://clinic.test/", 72*time.Hour, nil, nil)
+			links, err := s.Generate(7)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			expiresAt, err := time.Parse(time.RFC3339, links.ExpiresAt)
+			if err != nil {
+				t.Fatalf("invalid expires_at %s", links.ExpiresAt)
+			}
+			if diff := time.Until(expiresAt) - tt.expiresIn; diff > time.Minute || diff < -time.Minute {
+				t.Fatalf("expected the links to expire in %v, got %s", tt.expiresIn, links.ExpiresAt)
+			}
+			if !strings.HasPrefix(links.ConfirmUrl, "https://clinic.test/links/") || links.ConfirmUrl == links.CancelUrl {
+				t.Fatalf("unexpected links %+v", links)
+			}
+		})
+	}
+}
+
+func TestApply(t *testing.T) {
+	signer := token.NewSigner("secret")
+	expiresAt := time.Now().Add(time.Hour)
+	tests := []struct {
+		name   string
+		token  func(links domain.AppointmentLinks) string
+		action string
+		status string
+		err    string
+	}{
+		{name: "confirm link", token: func(l domain.AppointmentLinks) string { return linkToken(l.ConfirmUrl) }, action: ActionConfirm, status: domain.AppointmentConfirmed},
+		{name: "cancel link cancels as the patient", token: func(l domain.AppointmentLinks) string { return linkToken(l.CancelUrl) }, action: ActionCancel, status: domain.AppointmentCancelled},
+		{name: "signed with another secret", token: func(domain.AppointmentLinks) string { return token.NewSigner("other").Sign("7:confirm", expiresAt) }, err: "invalid token"},
+		{name: "unknown action", token: func(domain.AppointmentLinks) string { return signer.Sign("7:delete", expiresAt) }, err: "invalid token"},
+		{name: "invalid appointment id", token: func(domain.AppointmentLinks) string { return signer.Sign("siete:confirm", expiresAt) }, err: "invalid token"},
+		{name: "expired link", token: func(domain.AppointmentLinks) string { return signer.Sign("7:confirm", time.Now().Add(-time.Minute)) }, err: "expired token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := &fakeAppointments{appointment: in(48 * time.Hour)}
+			s := NewLinkService(a, signer, "https://clinic.test", 72*time.Hour, nil, nil)
+			links, err := s.Generate(7)
+			if err != nil {
+				t.Fatalf("generating links: %s", err)
+			}
+			linked, err := s.Apply(tt.token(links))
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				if a.action != "" {
+					t.Fatalf("expected no action, got %s", a.action)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if a.action != tt.action || linked.Status != tt.status {
+				t.Fatalf("expected %s to leave the appointment %s, got %s and %s", tt.action, tt.status, a.action, linked.Status)
+			}
+		})
+	}
+}
+
+func TestRemind(t *testing.T) {
+	tests := []struct {
+		name    string
+		contact domain.Contact
+		err     string
+	}{
+		{name: "sent to the guardian", contact: domain.Contact{ContactId: 3, Name: "Laura", Email: "laura@mail.com", Relationship: domain.RelationGuardian}},
+		{name: "sent to the patient", contact: domain.Contact{ContactId: 1, Name: "Juan", Email: "juan@mail.com", Relationship: domain.RelationSelf}},
+		{name: "contact without email", contact: domain.Contact{ContactId: 3, Name: "Laura"}, err: "contact of patient 1 has no email"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			a := in(48 * time.Hour)
+			a.Patient = domain.Patient{Id: 1, Name: "Juan", Email: "juan@mail.com"}
+			n := &fakeNotifier{}
+			s := NewLinkService(&fakeAppointments{appointment: a}, token.NewSigner("secret"), "https://clinic.test", 72*time.Hour, &fakeContacts{contact: tt.contact}, n)
+			reminder, err := s.Remind(7)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				if len(n.to) != 0 {
+					t.Fatalf("expected no reminder to be sent, got %v", n.to)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(n.to) != 1 || n.to[0] != tt.contact.Email {
+				t.Fatalf("expected the reminder to be sent to %s, got %v", tt.contact.Email, n.to)
+			}
+			if !strings.Contains(n.bodies[0], "Hola "+tt.contact.Name) || !strings.Contains(n.bodies[0], reminder.Links.CancelUrl) {
+				t.Fatalf("unexpected reminder %q", n.bodies[0])
+			}
+			if reminder.Contact.ContactId != tt.contact.ContactId {
+				t.Fatalf("unexpected reminder contact %+v", reminder.Contact)
+			}
+		})
+	}
+}
@@ -0,0 +1,143 @@
+package medical
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type MedicalRepository interface {
+	GetHistory(patientId int) (domain.MedicalHistory, error)
+	CreateAllergy(allergy domain.Allergy) (domain.Allergy, error)
+	DeleteAllergy(patientId int, id int) error
+	CreateMedication(medication domain.Medication) (domain.Medication, error)
+	DeleteMedication(patientId int, id int) error
+	CreateCondition(condition domain.MedicalCondition) (domain.MedicalCondition, error)
+	DeleteCondition(patientId int, id int) error
+	Review(patientId int, date string) error
+}
+
+type medicalRepository struct {
+	storage      store.MedicalStore
+	patientStore store.PatientStore
+}
+
+// NewMedicalRepository crea un nuevo repositorio
+func NewMedicalRepository(storage store.MedicalStore, patientStore store.PatientStore) MedicalRepository {
+	return &medicalRepository{storage, patientStore}
+}
+
+// GetHistory busca la historia medica completa de un paciente
+func (r *medicalRepository) GetHistory(patientId int) (domain.MedicalHistory, error) {
+	err := r.checkPatient(patientId)
+	if err != nil {
+		return domain.MedicalHistory{}, err
+	}
+	history := domain.MedicalHistory{PatientId: patientId}
+	history.Allergies, err = r.storage.GetAllergies(patientId)
+	if err != nil {
+		return domain.MedicalHistory{}, errors.New(fmt.Sprintf("allergies of patient %d not found", patientId))
+	}
+	history.Medications, err = r.storage.GetMedications(patientId)
+	if err != nil {
+		return domain.MedicalHistory{}, errors.New(fmt.Sprintf("medications of patient %d not found", patientId))
+	}
+	history.Conditions, err = r.storage.GetConditions(patientId)
+	if err != nil {
+		return domain.MedicalHistory{}, errors.New(fmt.Sprintf("conditions of patient %d not found", patientId))
+	}
+	history.LastReviewed, err = r.storage.GetLastReviewed(patientId)
+	if err != nil {
+		return domain.MedicalHistory{}, errors.New(fmt.Sprintf("medical review of patient %d not found", patientId))
+	}
+	return history, nil
+}
+
+// CreateAllergy agrega una alergia a un paciente
+func (r *medicalRepository) CreateAllergy(allergy domain.Allergy) (domain.Allergy, error) {
+	err := r.checkPatient(allergy.PatientId)
+	if err != nil {
+		return domain.Allergy{}, err
+	}
+	a, err := r.storage.CreateAllergy(allergy)
+	if err != nil {
+		return domain.Allergy{}, errors.New("error creating allergy")
+	}
+	return a, nil
+}
+
+// DeleteAllergy elimina una alergia de un paciente
+func (r *medicalRepository) DeleteAllergy(patientId int, id int) error {
+	err := r.storage.DeleteAllergy(patientId, id)
+	if err != nil {
+		return errors.New(fmt.Sprintf("allergy %d not found in patient %d", id, patientId))
+	}
+	return nil
+}
+
+// CreateMedication agrega una medicacion a un paciente
+func (r *medicalRepository) CreateMedication(medication domain.Medication) (domain.Medication, error) {
+	err := r.checkPatient(medication.PatientId)
+	if err != nil {
+		return domain.Medication{}, err
+	}
+	m, err := r.storage.CreateMedication(medication)
+	if err != nil {
+		return domain.Medication{}, errors.New("error creating medication")
+	}
+	return m, nil
+}
+
+// DeleteMedication elimina una medicacion de un paciente
+func (r *medicalRepository) DeleteMedication(patientId int, id int) error {
+	err := r.storage.DeleteMedication(patientId, id)
+	if err != nil {
+		return errors.New(fmt.Sprintf("medication %d not found in patient %d", id, patientId))
+	}
+	return nil
+}
+
+// CreateCondition agrega una condicion medica a un paciente
+func (r *medicalRepository) CreateCondition(condition domain.MedicalCondition) (domain.MedicalCondition, error) {
+	err := r.checkPatient(condition.PatientId)
+	if err != nil {
+		return domain.MedicalCondition{}, err
+	}
+	c, err := r.storage.CreateCondition(condition)
+	if err != nil {
+		return domain.MedicalCondition{}, errors.New("error creating condition")
+	}
+	return c, nil
+}
+
+// DeleteCondition elimina una condicion medica de un paciente
+func (r *medicalRepository) DeleteCondition(patientId int, id int) error {
+	err := r.storage.DeleteCondition(patientId, id)
+	if err != nil {
+		return errors.New(fmt.Sprintf("condition %d not found in patient %d", id, patientId))
+	}
+	return nil
+}
+
+// Review guarda la fecha de revision de la historia medica de un paciente
+func (r *medicalRepository) Review(patientId int, date string) error {
+	err := r.checkPatient(patientId)
+	if err != nil {
+		return err
+	}
+	err = r.storage.Review(patientId, date)
+	if err != nil {
+		return errors.New("error saving medical review")
+	}
+	return nil
+}
+
+// checkPatient verifica que exista el paciente
+func (r *medicalRepository) checkPatient(patientId int) error {
+	_, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	return nil
+}
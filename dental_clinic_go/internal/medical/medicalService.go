@@ -0,0 +1,99 @@
+package medical
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"strings"
+	"time"
+)
+
+type Service interface {
+	GetHistory(patientId int) (domain.MedicalHistory, error)
+	CreateAllergy(allergy domain.Allergy) (domain.Allergy, error)
+	DeleteAllergy(patientId int, id int) error
+	CreateMedication(medication domain.Medication) (domain.Medication, error)
+	DeleteMedication(patientId int, id int) error
+	CreateCondition(condition domain.MedicalCondition) (domain.MedicalCondition, error)
+	DeleteCondition(patientId int, id int) error
+	Review(patientId int, date string) (domain.MedicalHistory, error)
+}
+
+type service struct {
+	r MedicalRepository
+}
+
+// NewMedicalService crea un nuevo servicio
+func NewMedicalService(r MedicalRepository) Service {
+	return &service{r}
+}
+
+// GetHistory busca la historia medica de un paciente
+func (s *service) GetHistory(patientId int) (domain.MedicalHistory, error) {
+	return s.r.GetHistory(patientId)
+}
+
+// CreateAllergy valida y agrega una alergia
+func (s *service) CreateAllergy(allergy domain.Allergy) (domain.Allergy, error) {
+	allergy.Substance = strings.TrimSpace(allergy.Substance)
+	if allergy.Substance == "" {
+		return domain.Allergy{}, errors.New("substance can't be empty")
+	}
+	switch allergy.Severity {
+	case domain.SeverityMild, domain.SeverityModerate, domain.SeveritySevere:
+	default:
+		return domain.Allergy{}, errors.New("invalid severity, must be one of: mild, moderate, severe")
+	}
+	return s.r.CreateAllergy(allergy)
+}
+
+// DeleteAllergy elimina una alergia
+func (s *service) DeleteAllergy(patientId int, id int) error {
+	return s.r.DeleteAllergy(patientId, id)
+}
+
+// CreateMedication valida y agrega una medicacion
+func (s *service) CreateMedication(medication domain.Medication) (domain.Medication, error) {
+	medication.Name = strings.TrimSpace(medication.Name)
+	if medication.Name == "" {
+		return domain.Medication{}, errors.New("name can't be empty")
+	}
+	return s.r.CreateMedication(medication)
+}
+
+// DeleteMedication elimina una medicacion
+func (s *service) DeleteMedication(patientId int, id int) error {
+	return s.r.DeleteMedication(patientId, id)
+}
+
+// CreateCondition valida y agrega una condicion medica
+func (s *service) CreateCondition(condition domain.MedicalCondition) (domain.MedicalCondition, error) {
+	condition.Name = strings.TrimSpace(condition.Name)
+	if condition.Name == "" {
+		return domain.MedicalCondition{}, errors.New("name can't be empty")
+	}
+	return s.r.CreateCondition(condition)
+}
+
+// DeleteCondition elimina una condicion medica
+func (s *service) DeleteCondition(patientId int, id int) error {
+	return s.r.DeleteCondition(patientId, id)
+}
+
+// Review marca la historia medica como revisada, si no se indica fecha se usa la de hoy
+func (s *service) Review(patientId int, date string) (domain.MedicalHistory, error) {
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	reviewedAt, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return domain.MedicalHistory{}, errors.New("invalid date, must be in format: yyyy-mm-dd")
+	}
+	if reviewedAt.After(time.Now()) {
+		return domain.MedicalHistory{}, errors.New("date can't be in the future")
+	}
+	err = s.r.Review(patientId, date)
+	if err != nil {
+		return domain.MedicalHistory{}, err
+	}
+	return s.r.GetHistory(patientId)
+}
@@ -0,0 +1,95 @@
+package medical
+
+import (
+	"dental_clinic_go/internal/domain"
+	"testing"
+	"time"
+)
+
+// fakeRepository guarda lo ultimo que recibe, los metodos que no usan los tests quedan sin implementar
+type fakeRepository struct {
+	MedicalRepository
+	allergies  []domain.Allergy
+	reviewedAt string
+}
+
+func (r *fakeRepository) GetHistory(patientId int) (domain.MedicalHistory, error) {
+	return domain.MedicalHistory{}, nil
+}
+
+func (r *fakeRepository) CreateAllergy(allergy domain.Allergy) (domain.Allergy, error) {
+	r.allergies = append(r.allergies, allergy)
+	return allergy, nil
+}
+
+func (r *fakeRepository) Review(patientId int, date string) error {
+	r.reviewedAt = date
+	return nil
+}
+
+func TestCreateAllergy(t *testing.T) {
+	tests := []struct {
+		name    string
+		allergy domain.Allergy
+		err     string
+	}{
+		{name: "severe allergy", allergy: domain.Allergy{PatientId: 1, Substance: " Penicilina ", Severity: domain.SeveritySevere}},
+		{name: "empty substance", allergy: domain.Allergy{PatientId: 1, Substance: "  ", Severity: domain.SeverityMild}, err: "substance can't be empty"},
+		{name: "without severity", allergy: domain.Allergy{PatientId: 1, Substance: "Latex"}, err: "invalid severity, must be one of: mild, moderate, severe"},
+		{name: "unknown severity", allergy: domain.Allergy{PatientId: 1, Substance: "Latex", Severity: "fatal"}, err: "invalid severity, must be one of: mild, moderate, severe"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{}
+			_, err := NewMedicalService(r).CreateAllergy(tt.allergy)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				if len(r.allergies) != 0 {
+					t.Fatalf("expected nothing to be saved, got %+v", r.allergies)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(r.allergies) != 1 || r.allergies[0].Substance != "Penicilina" {
+				t.Fatalf("expected a trimmed substance, got %+v", r.allergies)
+			}
+		})
+	}
+}
+
+func TestReview(t *testing.T) {
+	today := time.Now().Format("2006-01-02")
+	tests := []struct {
+		name       string
+		date       string
+		reviewedAt string
+		err        string
+	}{
+		{name: "today by default", reviewedAt: today},
+		{name: "past date", date: "2026-01-15", reviewedAt: "2026-01-15"},
+		{name: "future date", date: time.Now().AddDate(0, 0, 2).Format("2006-01-02"), err: "date can't be in the future"},
+		{name: "invalid date", date: "15/01/2026", err: "invalid date, must be in format: yyyy-mm-dd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{}
+			_, err := NewMedicalService(r).Review(1, tt.date)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if r.reviewedAt != tt.reviewedAt {
+				t.Fatalf("expected review at %s, got %s", tt.reviewedAt, r.reviewedAt)
+			}
+		})
+	}
+}
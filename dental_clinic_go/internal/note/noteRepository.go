@@ -0,0 +1,117 @@
+package note
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type NoteRepository interface {
+	GetByID(id int) (domain.ClinicalNote, error)
+	GetByAppointment(appointmentId int) ([]domain.ClinicalNote, error)
+	GetAppointment(appointmentId int) (domain.Appointment, error)
+	Create(note domain.ClinicalNote) (domain.ClinicalNote, error)
+	Update(note domain.ClinicalNote) error
+	Sign(id int) error
+	Delete(id int) error
+	CreateAddendum(addendum domain.ClinicalAddendum) (domain.ClinicalAddendum, error)
+}
+
+type noteRepository struct {
+	storage          store.NoteStore
+	appointmentStore store.AppointmentStore
+	dentistStore     store.DentistStore
+}
+
+// NewNoteRepository crea un nuevo repositorio
+func NewNoteRepository(storage store.NoteStore, appointmentStore store.AppointmentStore,
+	dentistStore store.DentistStore) NoteRepository {
+	return &noteRepository{storage, appointmentStore, dentistStore}
+}
+
+// GetByID busca una nota por su id
+func (r *noteRepository) GetByID(id int) (domain.ClinicalNote, error) {
+	note, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.ClinicalNote{}, errors.New(fmt.Sprintf("clinical note %d not found", id))
+	}
+	return note, nil
+}
+
+// GetByAppointment busca las notas de un turno
+func (r *noteRepository) GetByAppointment(appointmentId int) ([]domain.ClinicalNote, error) {
+	_, err := r.GetAppointment(appointmentId)
+	if err != nil {
+		return []domain.ClinicalNote{}, err
+	}
+	notes, err := r.storage.GetByAppointment(appointmentId)
+	if err != nil {
+		return []domain.ClinicalNote{}, errors.New(fmt.Sprintf("clinical notes of appointment %d not found", appointmentId))
+	}
+	return notes, nil
+}
+
+// GetAppointment busca el turno de una nota
+func (r *noteRepository) GetAppointment(appointmentId int) (domain.Appointment, error) {
+	appointment, err := r.appointmentStore.GetByID(appointmentId)
+	if err != nil {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("appointment %d not found", appointmentId))
+	}
+	return appointment, nil
+}
+
+// Create agrega una nota en borrador
+func (r *noteRepository) Create(note domain.ClinicalNote) (domain.ClinicalNote, error) {
+	dentist, err := r.dentistStore.GetByID(note.Dentist.Id)
+	if err != nil {
+		return domain.ClinicalNote{}, errors.New(fmt.Sprintf("dentist %d not found", note.Dentist.Id))
+	}
+	note.Dentist = dentist
+	n, err := r.storage.Create(note)
+	if err != nil {
+		return domain.ClinicalNote{}, errors.New("error creating clinical note")
+	}
+	return n, nil
+}
+
+// Update modifica una nota en borrador
+func (r *noteRepository) Update(note domain.ClinicalNote) error {
+	err := r.storage.Update(note)
+	if err != nil {
+		return errors.New(fmt.Sprintf("error updating clinical note %d", note.Id))
+	}
+	return nil
+}
+
+// Sign firma una nota en borrador
+func (r *noteRepository) Sign(id int) error {
+	err := r.storage.Sign(id)
+	if err != nil {
+		return errors.New(fmt.Sprintf("clinical note %d is already signed", id))
+	}
+	return nil
+}
+
+// Delete elimina una nota en borrador
+func (r *noteRepository) Delete(id int) error {
+	err := r.storage.Delete(id)
+	if err != nil {
+		return errors.New(fmt.Sprintf("clinical note %d is signed and can't be deleted", id))
+	}
+	return nil
+}
+
+// CreateAddendum agrega una correccion a una nota
+func (r *noteRepository) CreateAddendum(addendum domain.ClinicalAddendum) (domain.ClinicalAddendum, error) {
+	dentist, err := r.dentistStore.GetByID(addendum.Dentist.Id)
+	if err != nil {
+		return domain.ClinicalAddendum{}, errors.New(fmt.Sprintf("dentist %d not found", addendum.Dentist.Id))
+	}
+	addendum.Dentist = dentist
+	a, err := r.storage.CreateAddendum(addendum)
+	if err != nil {
+		return domain.ClinicalAddendum{}, errors.New("error creating addendum")
+	}
+	return a, nil
+}
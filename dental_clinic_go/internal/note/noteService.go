@@ -0,0 +1,153 @@
+package note
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+type Service interface {
+	GetByID(id int) (domain.ClinicalNote, error)
+	GetByAppointment(appointmentId int) ([]domain.ClinicalNote, error)
+	Create(note domain.ClinicalNote, dentistId int) (domain.ClinicalNote, error)
+	Update(id int, note domain.ClinicalNote, dentistId int) (domain.ClinicalNote, error)
+	Sign(id int, dentistId int) (domain.ClinicalNote, error)
+	Delete(id int, dentistId int) error
+	AddAddendum(id int, addendum domain.ClinicalAddendum, dentistId int) (domain.ClinicalNote, error)
+}
+
+type service struct {
+	r NoteRepository
+}
+
+// NewNoteService crea un nuevo servicio
+func NewNoteService(r NoteRepository) Service {
+	return &service{r}
+}
+
+// GetByID busca una nota por su id
+func (s *service) GetByID(id int) (domain.ClinicalNote, error) {
+	return s.r.GetByID(id)
+}
+
+// GetByAppointment busca las notas de un turno
+func (s *service) GetByAppointment(appointmentId int) ([]domain.ClinicalNote, error) {
+	return s.r.GetByAppointment(appointmentId)
+}
+
+// Create agrega una nota en borrador a un turno escrita por el dentista del usuario actual
+func (s *service) Create(note domain.ClinicalNote, dentistId int) (domain.ClinicalNote, error) {
+	if dentistId == 0 {
+		return domain.ClinicalNote{}, errors.New("only users linked to a dentist can write clinical notes")
+	}
+	appointment, err := s.r.GetAppointment(note.AppointmentId)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	if appointment.Status == domain.AppointmentCancelled {
+		return domain.ClinicalNote{}, errors.New(fmt.Sprintf("appointment %d is cancelled", appointment.Id))
+	}
+	if isEmpty(note) {
+		return domain.ClinicalNote{}, errors.New("clinical note can't be empty")
+	}
+	note.Dentist = domain.Dentist{Id: dentistId}
+	n, err := s.r.Create(note)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	return s.r.GetByID(n.Id)
+}
+
+// Update reemplaza las secciones de una nota en borrador, solo la puede cambiar su autor
+func (s *service) Update(id int, note domain.ClinicalNote, dentistId int) (domain.ClinicalNote, error) {
+	n, err := s.getOwnDraft(id, dentistId)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	if isEmpty(note) {
+		return domain.ClinicalNote{}, errors.New("clinical note can't be empty")
+	}
+	n.Subjective = note.Subjective
+	n.Objective = note.Objective
+	n.Assessment = note.Assessment
+	n.Plan = note.Plan
+	err = s.r.Update(n)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	return s.r.GetByID(id)
+}
+
+// Sign firma una nota con el dentista del usuario que la firma, solo la puede firmar el dentista que la
+// escribio y desde ese momento solo admite agregados
+func (s *service) Sign(id int, dentistId int) (domain.ClinicalNote, error) {
+	_, err := s.getOwnDraft(id, dentistId)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	err = s.r.Sign(id)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	return s.r.GetByID(id)
+}
+
+// Delete elimina una nota en borrador, solo la puede eliminar su autor
+func (s *service) Delete(id int, dentistId int) error {
+	_, err := s.getOwnDraft(id, dentistId)
+	if err != nil {
+		return err
+	}
+	return s.r.Delete(id)
+}
+
+// AddAddendum agrega una correccion a una nota firmada escrita por el dentista del usuario actual
+func (s *service) AddAddendum(id int, addendum domain.ClinicalAddendum, dentistId int) (domain.ClinicalNote, error) {
+	if dentistId == 0 {
+		return domain.ClinicalNote{}, errors.New("only users linked to a dentist can write clinical notes")
+	}
+	n, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	if n.Status != domain.NoteSigned {
+		return domain.ClinicalNote{}, errors.New(fmt.Sprintf("clinical note %d isn't signed, update it instead", id))
+	}
+	if strings.TrimSpace(addendum.Text) == "" {
+		return domain.ClinicalNote{}, errors.New("addendum text can't be empty")
+	}
+	addendum.NoteId = id
+	addendum.Dentist = domain.Dentist{Id: dentistId}
+	_, err = s.r.CreateAddendum(addendum)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	return s.r.GetByID(id)
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// getOwnDraft busca una nota que todavia no fue firmada y verifica que la haya escrito el dentista
+// del usuario actual
+func (s *service) getOwnDraft(id int, dentistId int) (domain.ClinicalNote, error) {
+	if dentistId == 0 {
+		return domain.ClinicalNote{}, errors.New("only users linked to a dentist can change clinical notes")
+	}
+	n, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	if n.Status != domain.NoteDraft {
+		return domain.ClinicalNote{}, errors.New(fmt.Sprintf("clinical note %d is signed, add an addendum instead", id))
+	}
+	if n.Dentist.Id != dentistId {
+		return domain.ClinicalNote{}, errors.New(fmt.Sprintf("clinical note %d can only be changed by its author", id))
+	}
+	return n, nil
+}
+
+// isEmpty indica si todas las secciones de la nota estan vacias
+func isEmpty(note domain.ClinicalNote) bool {
+	return strings.TrimSpace(note.Subjective+note.Objective+note.Assessment+note.Plan) == ""
+}
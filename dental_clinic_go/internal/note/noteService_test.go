@@ -0,0 +1,174 @@
+package note
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeRepository es un NoteRepository en memoria
+type fakeRepository struct {
+	notes   map[int]domain.ClinicalNote
+	addenda []domain.ClinicalAddendum
+	status  string
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.ClinicalNote, error) {
+	n, ok := r.notes[id]
+	if !ok {
+		return domain.ClinicalNote{}, errors.New(fmt.Sprintf("clinical note %d not found", id))
+	}
+	return n, nil
+}
+
+func (r *fakeRepository) GetByAppointment(appointmentId int) ([]domain.ClinicalNote, error) {
+	return []domain.ClinicalNote{}, nil
+}
+
+func (r *fakeRepository) GetAppointment(appointmentId int) (domain.Appointment, error) {
+	return domain.Appointment{Id: appointmentId, Dentist: domain.Dentist{Id: 9}, Status: r.status}, nil
+}
+
+func (r *fakeRepository) Create(note domain.ClinicalNote) (domain.ClinicalNote, error) {
+	note.Id = len(r.notes) + 1
+	note.Status = domain.NoteDraft
+	r.notes[note.Id] = note
+	return note, nil
+}
+
+func (r *fakeRepository) Update(note domain.ClinicalNote) error {
+	r.notes[note.Id] = note
+	return nil
+}
+
+func (r *fakeRepository) Sign(id int) error {
+	n := r.notes[id]
+	n.Status = domain.NoteSigned
+	r.notes[id] = n
+	return nil
+}
+
+func (r *fakeRepository) Delete(id int) error {
+	delete(r.notes, id)
+	return nil
+}
+
+func (r *fakeRepository) CreateAddendum(addendum domain.ClinicalAddendum) (domain.ClinicalAddendum, error) {
+	r.addenda = append(r.addenda, addendum)
+	return addendum, nil
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		status: domain.AppointmentScheduled,
+		notes: map[int]domain.ClinicalNote{
+			1: {Id: 1, AppointmentId: 1, Dentist: domain.Dentist{Id: 2}, Subjective: "dolor", Status: domain.NoteDraft},
+			2: {Id: 2, AppointmentId: 1, Dentist: domain.Dentist{Id: 2}, Subjective: "dolor", Status: domain.NoteSigned},
+		},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		note      domain.ClinicalNote
+		dentistId int
+		status    string
+		author    int
+		err       string
+	}{
+		{name: "author is the current dentist", note: domain.ClinicalNote{AppointmentId: 1, Subjective: "dolor"}, dentistId: 2, author: 2},
+		{name: "author in the body is ignored", note: domain.ClinicalNote{AppointmentId: 1, Subjective: "dolor", Dentist: domain.Dentist{Id: 7}}, dentistId: 2, author: 2},
+		{name: "user without dentist", note: domain.ClinicalNote{AppointmentId: 1, Subjective: "dolor"}, err: "only users linked to a dentist can write clinical notes"},
+		{name: "empty note", note: domain.ClinicalNote{AppointmentId: 1, Plan: "  "}, dentistId: 2, err: "clinical note can't be empty"},
+		{name: "cancelled appointment", note: domain.ClinicalNote{AppointmentId: 1, Subjective: "dolor"}, dentistId: 2, status: domain.AppointmentCancelled, err: "appointment 1 is cancelled"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRepository()
+			if tt.status != "" {
+				r.status = tt.status
+			}
+			n, err := NewNoteService(r).Create(tt.note, tt.dentistId)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if n.Dentist.Id != tt.author {
+				t.Fatalf("expected author %d, got %d", tt.author, n.Dentist.Id)
+			}
+		})
+	}
+}
+
+func TestDraftChanges(t *testing.T) {
+	change := domain.ClinicalNote{Subjective: "sin dolor"}
+	tests := []struct {
+		name      string
+		action    func(s Service, id int, dentistId int) error
+		id        int
+		dentistId int
+		err       string
+	}{
+		{"update by author", func(s Service, id, dentistId int) error { _, err := s.Update(id, change, dentistId); return err }, 1, 2, ""},
+		{"update by another dentist", func(s Service, id, dentistId int) error { _, err := s.Update(id, change, dentistId); return err }, 1, 3, "clinical note 1 can only be changed by its author"},
+		{"update without dentist", func(s Service, id, dentistId int) error { _, err := s.Update(id, change, dentistId); return err }, 1, 0, "only users linked to a dentist can change clinical notes"},
+		{"update signed note", func(s Service, id, dentistId int) error { _, err := s.Update(id, change, dentistId); return err }, 2, 2, "clinical note 2 is signed, add an addendum instead"},
+		{"sign by author", func(s Service, id, dentistId int) error { _, err := s.Sign(id, dentistId); return err }, 1, 2, ""},
+		{"sign by another dentist", func(s Service, id, dentistId int) error { _, err := s.Sign(id, dentistId); return err }, 1, 3, "clinical note 1 can only be changed by its author"},
+		{"sign twice", func(s Service, id, dentistId int) error { _, err := s.Sign(id, dentistId); return err }, 2, 2, "clinical note 2 is signed, add an addendum instead"},
+		{"delete by author", func(s Service, id, dentistId int) error { return s.Delete(id, dentistId) }, 1, 2, ""},
+		{"delete by another dentist", func(s Service, id, dentistId int) error { return s.Delete(id, dentistId) }, 1, 3, "clinical note 1 can only be changed by its author"},
+		{"delete signed note", func(s Service, id, dentistId int) error { return s.Delete(id, dentistId) }, 2, 2, "clinical note 2 is signed, add an addendum instead"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := tt.action(NewNoteService(newFakeRepository()), tt.id, tt.dentistId)
+			if tt.err == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Fatalf("expected error %q, got %v", tt.err, err)
+			}
+		})
+	}
+}
+
+func TestAddAddendum(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        int
+		addendum  domain.ClinicalAddendum
+		dentistId int
+		err       string
+	}{
+		{name: "any dentist can add to a signed note", id: 2, addendum: domain.ClinicalAddendum{Text: "corrijo pieza", Dentist: domain.Dentist{Id: 7}}, dentistId: 3},
+		{name: "user without dentist", id: 2, addendum: domain.ClinicalAddendum{Text: "corrijo pieza"}, err: "only users linked to a dentist can write clinical notes"},
+		{name: "draft note", id: 1, addendum: domain.ClinicalAddendum{Text: "corrijo pieza"}, dentistId: 2, err: "clinical note 1 isn't signed, update it instead"},
+		{name: "empty text", id: 2, addendum: domain.ClinicalAddendum{Text: " "}, dentistId: 2, err: "addendum text can't be empty"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRepository()
+			_, err := NewNoteService(r).AddAddendum(tt.id, tt.addendum, tt.dentistId)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(r.addenda) != 1 || r.addenda[0].Dentist.Id != tt.dentistId || r.addenda[0].NoteId != tt.id {
+				t.Fatalf("expected an addendum by dentist %d, got %+v", tt.dentistId, r.addenda)
+			}
+		})
+	}
+}
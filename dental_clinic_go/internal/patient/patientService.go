@@ -2,6 +2,31 @@ package patient
 
 import (
 	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/policy"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// phoneTypes son los tipos de telefono que se pueden registrar
+var phoneTypes = map[string]bool{
+	domain.PhoneMobile: true,
+	domain.PhoneHome:   true,
+	domain.PhoneWork:   true,
+}
+
+// channels son los canales de contacto que puede preferir un paciente
+var channels = map[string]bool{
+	domain.ChannelEmail:    true,
+	domain.ChannelSms:      true,
+	domain.ChannelPhone:    true,
+	domain.ChannelWhatsapp: true,
+}
+
+const (
+	defaultLanguage = "es"
+	defaultChannel  = domain.ChannelEmail
 )
 
 type PatientService interface {
@@ -12,26 +37,44 @@ type PatientService interface {
 }
 
 type patientService struct {
-	r PatientRepository
+	r      PatientRepository
+	policy policy.Service
 }
 
 // NewService crea un nuevo servicio
-func NewPatientService(r PatientRepository) PatientService {
-	return &patientService{r}
+func NewPatientService(r PatientRepository, policy policy.Service) PatientService {
+	return &patientService{r, policy}
 }
 
-// GetByID busca un paciente por su id
+// GetByID busca un paciente por su id junto con sus ausencias y cancelaciones tardias
 func (s *patientService) GetByID(id int) (domain.Patient, error) {
 	p, err := s.r.GetByID(id)
 	if err != nil {
 		return domain.Patient{}, err
 	}
+	summary, err := s.policy.Summary(id)
+	if err != nil {
+		return domain.Patient{}, err
+	}
+	p.NoShows = summary.NoShows
+	p.LateCancellations = summary.LateCancellations
+	p.BookingBlocked = summary.BookingBlocked
 	return p, nil
 }
 
-// Create agrega un nuevo paciente
+// Create agrega un nuevo paciente, sin idioma ni canal de contacto usa los de la clinica
 func (s *patientService) Create(p domain.Patient) (domain.Patient, error) {
-	p, err := s.r.Create(p)
+	if p.PreferredLanguage == "" {
+		p.PreferredLanguage = defaultLanguage
+	}
+	if p.ContactPreferences.Channel == "" {
+		p.ContactPreferences.Channel = defaultChannel
+	}
+	p, err := validate(p)
+	if err != nil {
+		return domain.Patient{}, err
+	}
+	p, err = s.r.Create(p)
 	if err != nil {
 		return domain.Patient{}, err
 	}
@@ -40,6 +83,10 @@ func (s *patientService) Create(p domain.Patient) (domain.Patient, error) {
 
 // UpdatePatient actualiza un paciente
 func (s *patientService) Update(id int, updatedPatient domain.Patient) (domain.Patient, error) {
+	updatedPatient, err := validate(updatedPatient)
+	if err != nil {
+		return domain.Patient{}, err
+	}
 	p, err := s.r.Update(id, updatedPatient)
 	if err != nil {
 		return domain.Patient{}, err
@@ -55,3 +102,43 @@ func (s *patientService) Delete(id int) error {
 	}
 	return nil
 }
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// validate valida los datos demograficos que vienen cargados, si hay telefonos y ninguno
+// es el principal el primero pasa a serlo
+func validate(p domain.Patient) (domain.Patient, error) {
+	if p.BirthDate != "" {
+		birth, err := time.Parse("2006-01-02", p.BirthDate)
+		if err != nil {
+			return domain.Patient{}, errors.New("invalid birth_date, must be in format: yyyy-mm-dd")
+		}
+		if birth.After(time.Now()) {
+			return domain.Patient{}, errors.New("birth_date can't be in the future")
+		}
+	}
+	primary := 0
+	for i, phone := range p.Phones {
+		if !phoneTypes[phone.Type] {
+			return domain.Patient{}, errors.New("invalid phone type, must be one of: mobile, home, work")
+		}
+		number := strings.TrimSpace(phone.Number)
+		if number == "" || strings.Trim(number, "+0123456789 -()") != "" {
+			return domain.Patient{}, errors.New(fmt.Sprintf("invalid phone number %q", phone.Number))
+		}
+		p.Phones[i].Number = number
+		if phone.Primary {
+			primary++
+		}
+	}
+	if primary > 1 {
+		return domain.Patient{}, errors.New("only one phone can be primary")
+	}
+	if primary == 0 && len(p.Phones) > 0 {
+		p.Phones[0].Primary = true
+	}
+	if p.ContactPreferences.Channel != "" && !channels[p.ContactPreferences.Channel] {
+		return domain.Patient{}, errors.New("invalid contact channel, must be one of: email, sms, phone, whatsapp")
+	}
+	return p, nil
+}
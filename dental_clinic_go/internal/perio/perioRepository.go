@@ -0,0 +1,65 @@
+package perio
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type PerioRepository interface {
+	GetByID(id int) (domain.PerioExam, error)
+	GetByPatient(patientId int) ([]domain.PerioExam, error)
+	Create(exam domain.PerioExam) (domain.PerioExam, error)
+}
+
+type perioRepository struct {
+	storage      store.PerioStore
+	patientStore store.PatientStore
+	dentistStore store.DentistStore
+}
+
+// NewPerioRepository crea un nuevo repositorio
+func NewPerioRepository(storage store.PerioStore, patientStore store.PatientStore, dentistStore store.DentistStore) PerioRepository {
+	return &perioRepository{storage, patientStore, dentistStore}
+}
+
+// GetByID busca un examen periodontal por su id
+func (r *perioRepository) GetByID(id int) (domain.PerioExam, error) {
+	exam, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.PerioExam{}, errors.New(fmt.Sprintf("periodontal exam %d not found", id))
+	}
+	return exam, nil
+}
+
+// GetByPatient busca los examenes periodontales de un paciente
+func (r *perioRepository) GetByPatient(patientId int) ([]domain.PerioExam, error) {
+	_, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return []domain.PerioExam{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	exams, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.PerioExam{}, errors.New(fmt.Sprintf("periodontal exams of patient %d not found", patientId))
+	}
+	return exams, nil
+}
+
+// Create agrega un examen periodontal
+func (r *perioRepository) Create(exam domain.PerioExam) (domain.PerioExam, error) {
+	_, err := r.patientStore.GetByID(exam.PatientId)
+	if err != nil {
+		return domain.PerioExam{}, errors.New(fmt.Sprintf("patient %d not found", exam.PatientId))
+	}
+	dentist, err := r.dentistStore.GetByID(exam.Dentist.Id)
+	if err != nil {
+		return domain.PerioExam{}, errors.New(fmt.Sprintf("dentist %d not found", exam.Dentist.Id))
+	}
+	exam.Dentist = dentist
+	e, err := r.storage.Create(exam)
+	if err != nil {
+		return domain.PerioExam{}, errors.New("error creating periodontal exam")
+	}
+	return e, nil
+}
@@ -0,0 +1,271 @@
+package perio
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/fdi"
+	"errors"
+	"fmt"
+	"math"
+	"sort"
+	"strings"
+	"time"
+)
+
+const (
+	// worsenThreshold son los milimetros que tiene que aumentar la profundidad o la perdida
+	// de insercion de un sitio para considerarlo empeorado, por debajo es error de sondaje
+	worsenThreshold = 2
+	// deepPocket es la profundidad a partir de la cual un sitio cuenta como bolsa profunda
+	deepPocket  = 5
+	maxDepth    = 20
+	maxMobility = 3
+)
+
+type Service interface {
+	GetByPatient(patientId int) ([]domain.PerioExam, error)
+	GetByID(patientId int, id int) (domain.PerioExam, error)
+	Create(exam domain.PerioExam) (domain.PerioExam, error)
+	Compare(patientId int, fromId int, toId int) (domain.PerioComparison, error)
+	GetSummary(patientId int) (domain.PerioSummary, error)
+}
+
+type service struct {
+	r PerioRepository
+}
+
+// NewPerioService crea un nuevo servicio
+func NewPerioService(r PerioRepository) Service {
+	return &service{r}
+}
+
+// GetByPatient devuelve los examenes periodontales de un paciente en orden cronologico
+func (s *service) GetByPatient(patientId int) ([]domain.PerioExam, error) {
+	return s.r.GetByPatient(patientId)
+}
+
+// GetByID devuelve un examen periodontal si pertenece al paciente
+func (s *service) GetByID(patientId int, id int) (domain.PerioExam, error) {
+	exam, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.PerioExam{}, err
+	}
+	if exam.PatientId != patientId {
+		return domain.PerioExam{}, errors.New(fmt.Sprintf("periodontal exam %d not found for patient %d", id, patientId))
+	}
+	return exam, nil
+}
+
+// Create valida las medidas y agrega un examen periodontal, los examenes no se modifican
+// para poder compararlos a lo largo del tiempo
+func (s *service) Create(exam domain.PerioExam) (domain.PerioExam, error) {
+	if exam.Date == "" {
+		exam.Date = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", exam.Date); err != nil {
+		return domain.PerioExam{}, errors.New("invalid date, must be in format: yyyy-mm-dd")
+	}
+	if len(exam.Teeth) == 0 {
+		return domain.PerioExam{}, errors.New("a periodontal exam needs at least one tooth")
+	}
+	seen := map[int]bool{}
+	for i := range exam.Teeth {
+		tooth := &exam.Teeth[i]
+		if !fdi.ValidTooth(tooth.Tooth) {
+			return domain.PerioExam{}, errors.New(fmt.Sprintf("invalid tooth %d, must be a FDI tooth number", tooth.Tooth))
+		}
+		if seen[tooth.Tooth] {
+			return domain.PerioExam{}, errors.New(fmt.Sprintf("tooth %d is repeated", tooth.Tooth))
+		}
+		seen[tooth.Tooth] = true
+		if tooth.Mobility < 0 || tooth.Mobility > maxMobility {
+			return domain.PerioExam{}, errors.New(fmt.Sprintf("invalid mobility on tooth %d, must be between 0 and %d", tooth.Tooth, maxMobility))
+		}
+		sites, err := validateSites(tooth.Tooth, tooth.Sites)
+		if err != nil {
+			return domain.PerioExam{}, err
+		}
+		tooth.Sites = sites
+	}
+	sort.Slice(exam.Teeth, func(i, j int) bool {
+		return exam.Teeth[i].Tooth < exam.Teeth[j].Tooth
+	})
+	return s.r.Create(exam)
+}
+
+// Compare compara dos examenes del paciente sitio por sitio, sin ids compara los dos ultimos
+func (s *service) Compare(patientId int, fromId int, toId int) (domain.PerioComparison, error) {
+	var from, to domain.PerioExam
+	if fromId == 0 && toId == 0 {
+		exams, err := s.r.GetByPatient(patientId)
+		if err != nil {
+			return domain.PerioComparison{}, err
+		}
+		if len(exams) < 2 {
+			return domain.PerioComparison{}, errors.New(fmt.Sprintf("patient %d needs at least two periodontal exams to compare", patientId))
+		}
+		from, to = exams[len(exams)-2], exams[len(exams)-1]
+	} else {
+		if fromId == 0 || toId == 0 {
+			return domain.PerioComparison{}, errors.New("from and to must be both set or both empty")
+		}
+		var err error
+		from, err = s.GetByID(patientId, fromId)
+		if err != nil {
+			return domain.PerioComparison{}, err
+		}
+		to, err = s.GetByID(patientId, toId)
+		if err != nil {
+			return domain.PerioComparison{}, err
+		}
+	}
+	return compare(patientId, from, to), nil
+}
+
+// GetSummary devuelve la evolucion de los indices del paciente y los sitios que empeoraron
+// entre los dos ultimos examenes
+func (s *service) GetSummary(patientId int) (domain.PerioSummary, error) {
+	exams, err := s.r.GetByPatient(patientId)
+	if err != nil {
+		return domain.PerioSummary{}, err
+	}
+	summary := domain.PerioSummary{
+		PatientId: patientId,
+		Exams:     []domain.PerioStats{},
+		Worsened:  []domain.PerioSiteChange{},
+		Mobility:  []domain.PerioMobilityChange{},
+	}
+	for _, exam := range exams {
+		summary.Exams = append(summary.Exams, stats(exam))
+	}
+	if len(exams) < 2 {
+		return summary, nil
+	}
+	comparison := compare(patientId, exams[len(exams)-2], exams[len(exams)-1])
+	for _, change := range comparison.Sites {
+		if change.Worsened {
+			summary.Worsened = append(summary.Worsened, change)
+		}
+	}
+	for _, change := range comparison.Mobility {
+		if change.After > change.Before {
+			summary.Mobility = append(summary.Mobility, change)
+		}
+	}
+	return summary, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// validateSites valida las medidas de una pieza y las devuelve en el orden de domain.PerioSites
+func validateSites(tooth int, sites []domain.PerioSite) ([]domain.PerioSite, error) {
+	if len(sites) == 0 {
+		return nil, errors.New(fmt.Sprintf("tooth %d needs at least one site", tooth))
+	}
+	bySite := map[string]domain.PerioSite{}
+	for _, site := range sites {
+		site.Site = strings.ToUpper(site.Site)
+		if siteIndex(site.Site) == -1 {
+			return nil, errors.New(fmt.Sprintf("invalid site %s on tooth %d, must be one of: %s", site.Site, tooth, strings.Join(domain.PerioSites, ", ")))
+		}
+		if _, ok := bySite[site.Site]; ok {
+			return nil, errors.New(fmt.Sprintf("site %s of tooth %d is repeated", site.Site, tooth))
+		}
+		if site.Depth < 0 || site.Depth > maxDepth {
+			return nil, errors.New(fmt.Sprintf("invalid depth on site %s of tooth %d, must be between 0 and %d mm", site.Site, tooth, maxDepth))
+		}
+		if site.Recession < -maxDepth || site.Recession > maxDepth {
+			return nil, errors.New(fmt.Sprintf("invalid recession on site %s of tooth %d, must be between -%d and %d mm", site.Site, tooth, maxDepth, maxDepth))
+		}
+		site.AttachmentLoss = site.Depth + site.Recession
+		bySite[site.Site] = site
+	}
+	validated := []domain.PerioSite{}
+	for _, name := range domain.PerioSites {
+		if site, ok := bySite[name]; ok {
+			validated = append(validated, site)
+		}
+	}
+	return validated, nil
+}
+
+// siteIndex devuelve la posicion de un sitio en domain.PerioSites o -1 si no existe
+func siteIndex(site string) int {
+	for i, name := range domain.PerioSites {
+		if name == site {
+			return i
+		}
+	}
+	return -1
+}
+
+// stats calcula los indices de un examen
+func stats(exam domain.PerioExam) domain.PerioStats {
+	result := domain.PerioStats{ExamId: exam.Id, Date: exam.Date}
+	depth, attachment, bleeding := 0, 0, 0
+	for _, tooth := range exam.Teeth {
+		for _, site := range tooth.Sites {
+			result.Sites++
+			depth += site.Depth
+			attachment += site.AttachmentLoss
+			if site.Bleeding {
+				bleeding++
+			}
+			if site.Depth >= deepPocket {
+				result.DeepSites++
+			}
+		}
+	}
+	if result.Sites == 0 {
+		return result
+	}
+	result.MeanDepth = math.Round(float64(depth)/float64(result.Sites)*100) / 100
+	result.MeanAttachment = math.Round(float64(attachment)/float64(result.Sites)*100) / 100
+	result.BleedingPercent = math.Round(float64(bleeding)/float64(result.Sites)*1000) / 10
+	return result
+}
+
+// compare devuelve los sitios medidos en ambos examenes que cambiaron y los cambios de movilidad
+func compare(patientId int, from domain.PerioExam, to domain.PerioExam) domain.PerioComparison {
+	comparison := domain.PerioComparison{
+		PatientId: patientId,
+		From:      stats(from),
+		To:        stats(to),
+		Sites:     []domain.PerioSiteChange{},
+		Mobility:  []domain.PerioMobilityChange{},
+	}
+	before := map[int]domain.PerioTooth{}
+	for _, tooth := range from.Teeth {
+		before[tooth.Tooth] = tooth
+	}
+	for _, tooth := range to.Teeth {
+		previous, ok := before[tooth.Tooth]
+		if !ok {
+			continue
+		}
+		if tooth.Mobility != previous.Mobility {
+			comparison.Mobility = append(comparison.Mobility, domain.PerioMobilityChange{Tooth: tooth.Tooth, Before: previous.Mobility, After: tooth.Mobility})
+		}
+		previousSites := map[string]domain.PerioSite{}
+		for _, site := range previous.Sites {
+			previousSites[site.Site] = site
+		}
+		for _, site := range tooth.Sites {
+			old, ok := previousSites[site.Site]
+			if !ok || (old.Depth == site.Depth && old.AttachmentLoss == site.AttachmentLoss && old.Bleeding == site.Bleeding) {
+				continue
+			}
+			comparison.Sites = append(comparison.Sites, domain.PerioSiteChange{
+				Tooth:            tooth.Tooth,
+				Site:             site.Site,
+				DepthBefore:      old.Depth,
+				DepthAfter:       site.Depth,
+				AttachmentBefore: old.AttachmentLoss,
+				AttachmentAfter:  site.AttachmentLoss,
+				BleedingBefore:   old.Bleeding,
+				BleedingAfter:    site.Bleeding,
+				Worsened:         site.Depth-old.Depth >= worsenThreshold || site.AttachmentLoss-old.AttachmentLoss >= worsenThreshold,
+			})
+		}
+	}
+	return comparison
+}
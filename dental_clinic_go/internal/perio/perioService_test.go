@@ -0,0 +1,193 @@
+package perio
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeRepository es un PerioRepository en memoria, los examenes quedan en orden cronologico
+type fakeRepository struct {
+	exams []domain.PerioExam
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.PerioExam, error) {
+	for _, exam := range r.exams {
+		if exam.Id == id {
+			return exam, nil
+		}
+	}
+	return domain.PerioExam{}, errors.New(fmt.Sprintf("periodontal exam %d not found", id))
+}
+
+func (r *fakeRepository) GetByPatient(patientId int) ([]domain.PerioExam, error) {
+	exams := []domain.PerioExam{}
+	for _, exam := range r.exams {
+		if exam.PatientId == patientId {
+			exams = append(exams, exam)
+		}
+	}
+	return exams, nil
+}
+
+func (r *fakeRepository) Create(exam domain.PerioExam) (domain.PerioExam, error) {
+	exam.Id = len(r.exams) + 1
+	r.exams = append(r.exams, exam)
+	return exam, nil
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{exams: []domain.PerioExam{
+		{Id: 1, PatientId: 1, Date: "2026-01-10", Teeth: []domain.PerioTooth{
+			{Tooth: 16, Mobility: 0, Sites: []domain.PerioSite{
+				{Site: "MB", Depth: 3, Recession: 0, AttachmentLoss: 3},
+				{Site: "B", Depth: 2, Recession: 0, AttachmentLoss: 2, Bleeding: true},
+				{Site: "DB", Depth: 3, Recession: 1, AttachmentLoss: 4},
+			}},
+			{Tooth: 21, Mobility: 1, Sites: []domain.PerioSite{
+				{Site: "B", Depth: 2, Recession: 0, AttachmentLoss: 2},
+			}},
+		}},
+		{Id: 2, PatientId: 1, Date: "2026-07-10", Teeth: []domain.PerioTooth{
+			{Tooth: 16, Mobility: 1, Sites: []domain.PerioSite{
+				{Site: "MB", Depth: 5, Recession: 0, AttachmentLoss: 5, Bleeding: true},
+				{Site: "B", Depth: 3, Recession: 0, AttachmentLoss: 3, Bleeding: true},
+				{Site: "DB", Depth: 3, Recession: 1, AttachmentLoss: 4},
+			}},
+			{Tooth: 21, Mobility: 0, Sites: []domain.PerioSite{
+				{Site: "B", Depth: 2, Recession: 2, AttachmentLoss: 4},
+			}},
+		}},
+		{Id: 3, PatientId: 2, Date: "2026-07-10", Teeth: []domain.PerioTooth{
+			{Tooth: 11, Sites: []domain.PerioSite{{Site: "B", Depth: 2, AttachmentLoss: 2}}},
+		}},
+	}}
+}
+
+func TestCreate(t *testing.T) {
+	site := []domain.PerioSite{{Site: "B", Depth: 3}}
+	tests := []struct {
+		name  string
+		teeth []domain.PerioTooth
+		date  string
+		err   string
+	}{
+		{name: "valid exam", teeth: []domain.PerioTooth{{Tooth: 21, Sites: site}, {Tooth: 16, Mobility: 2, Sites: site}}},
+		{name: "invalid date", teeth: []domain.PerioTooth{{Tooth: 16, Sites: site}}, date: "10/07/2026", err: "invalid date, must be in format: yyyy-mm-dd"},
+		{name: "without teeth", err: "a periodontal exam needs at least one tooth"},
+		{name: "invalid tooth", teeth: []domain.PerioTooth{{Tooth: 19, Sites: site}}, err: "invalid tooth 19, must be a FDI tooth number"},
+		{name: "repeated tooth", teeth: []domain.PerioTooth{{Tooth: 16, Sites: site}, {Tooth: 16, Sites: site}}, err: "tooth 16 is repeated"},
+		{name: "invalid mobility", teeth: []domain.PerioTooth{{Tooth: 16, Mobility: 4, Sites: site}}, err: "invalid mobility on tooth 16, must be between 0 and 3"},
+		{name: "without sites", teeth: []domain.PerioTooth{{Tooth: 16}}, err: "tooth 16 needs at least one site"},
+		{name: "invalid site", teeth: []domain.PerioTooth{{Tooth: 16, Sites: []domain.PerioSite{{Site: "X"}}}}, err: "invalid site X on tooth 16, must be one of: MB, B, DB, ML, L, DL"},
+		{name: "repeated site", teeth: []domain.PerioTooth{{Tooth: 16, Sites: []domain.PerioSite{{Site: "b"}, {Site: "B"}}}}, err: "site B of tooth 16 is repeated"},
+		{name: "invalid depth", teeth: []domain.PerioTooth{{Tooth: 16, Sites: []domain.PerioSite{{Site: "B", Depth: 21}}}}, err: "invalid depth on site B of tooth 16, must be between 0 and 20 mm"},
+		{name: "invalid recession", teeth: []domain.PerioTooth{{Tooth: 16, Sites: []domain.PerioSite{{Site: "B", Recession: -21}}}}, err: "invalid recession on site B of tooth 16, must be between -20 and 20 mm"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			exam, err := NewPerioService(newFakeRepository()).Create(domain.PerioExam{PatientId: 1, Date: tt.date, Teeth: tt.teeth})
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if exam.Date == "" || exam.Teeth[0].Tooth != 16 || exam.Teeth[1].Tooth != 21 {
+				t.Fatalf("expected the teeth in order and today's date, got %+v", exam)
+			}
+		})
+	}
+}
+
+func TestCreateOrdersSitesAndComputesAttachmentLoss(t *testing.T) {
+	exam, err := NewPerioService(newFakeRepository()).Create(domain.PerioExam{PatientId: 1, Date: "2026-10-01", Teeth: []domain.PerioTooth{
+		{Tooth: 36, Sites: []domain.PerioSite{
+			{Site: "dl", Depth: 4, Recession: -1},
+			{Site: "mb", Depth: 3, Recession: 2},
+		}},
+	}})
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	sites := exam.Teeth[0].Sites
+	if len(sites) != 2 || sites[0].Site != "MB" || sites[1].Site != "DL" {
+		t.Fatalf("expected sites MB and DL in order, got %+v", sites)
+	}
+	if sites[0].AttachmentLoss != 5 || sites[1].AttachmentLoss != 3 {
+		t.Fatalf("expected attachment loss 5 and 3, got %d and %d", sites[0].AttachmentLoss, sites[1].AttachmentLoss)
+	}
+}
+
+func TestCompare(t *testing.T) {
+	tests := []struct {
+		name      string
+		patientId int
+		fromId    int
+		toId      int
+		sites     int
+		err       string
+	}{
+		{name: "last two exams", patientId: 1, sites: 3},
+		{name: "chosen exams", patientId: 1, fromId: 2, toId: 1, sites: 3},
+		{name: "only one id", patientId: 1, fromId: 1, err: "from and to must be both set or both empty"},
+		{name: "exam of another patient", patientId: 1, fromId: 1, toId: 3, err: "periodontal exam 3 not found for patient 1"},
+		{name: "a single exam", patientId: 2, err: "patient 2 needs at least two periodontal exams to compare"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			c, err := NewPerioService(newFakeRepository()).Compare(tt.patientId, tt.fromId, tt.toId)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if len(c.Sites) != tt.sites || len(c.Mobility) != 2 {
+				t.Fatalf("expected %d changed sites and 2 mobility changes, got %+v", tt.sites, c)
+			}
+		})
+	}
+}
+
+func TestGetSummary(t *testing.T) {
+	summary, err := NewPerioService(newFakeRepository()).GetSummary(1)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(summary.Exams) != 2 {
+		t.Fatalf("expected stats of 2 exams, got %+v", summary.Exams)
+	}
+	first, last := summary.Exams[0], summary.Exams[1]
+	if first.Sites != 4 || first.MeanDepth != 2.5 || first.MeanAttachment != 2.75 || first.BleedingPercent != 25 || first.DeepSites != 0 {
+		t.Fatalf("unexpected stats of the first exam %+v", first)
+	}
+	if last.MeanDepth != 3.25 || last.MeanAttachment != 4 || last.BleedingPercent != 50 || last.DeepSites != 1 {
+		t.Fatalf("unexpected stats of the last exam %+v", last)
+	}
+	// 16 B sube 1 mm y queda por debajo del umbral, 16 MB y 21 B empeoran
+	if len(summary.Worsened) != 2 || summary.Worsened[0].Tooth != 16 || summary.Worsened[0].Site != "MB" || summary.Worsened[1].Tooth != 21 {
+		t.Fatalf("expected 16 MB and 21 B to be worsened, got %+v", summary.Worsened)
+	}
+	// la movilidad de 21 baja, solo se informa el aumento de 16
+	if len(summary.Mobility) != 1 || summary.Mobility[0].Tooth != 16 || summary.Mobility[0].After != 1 {
+		t.Fatalf("expected only the mobility increase of 16, got %+v", summary.Mobility)
+	}
+}
+
+func TestGetSummaryWithOneExam(t *testing.T) {
+	summary, err := NewPerioService(newFakeRepository()).GetSummary(2)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(summary.Exams) != 1 || len(summary.Worsened) != 0 || len(summary.Mobility) != 0 {
+		t.Fatalf("expected only the stats of one exam, got %+v", summary)
+	}
+}
@@ -0,0 +1,60 @@
+package policy
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type PolicyRepository interface {
+	GetByPatient(patientId int) ([]domain.PolicyEvent, error)
+	CountByPatient(patientId int) (int, int, error)
+	Create(event domain.PolicyEvent) (domain.PolicyEvent, error)
+	Delete(id int) error
+}
+
+type policyRepository struct {
+	storage store.PolicyStore
+}
+
+// NewPolicyRepository crea un nuevo repositorio
+func NewPolicyRepository(storage store.PolicyStore) PolicyRepository {
+	return &policyRepository{storage}
+}
+
+// GetByPatient busca los eventos de un paciente
+func (r *policyRepository) GetByPatient(patientId int) ([]domain.PolicyEvent, error) {
+	events, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.PolicyEvent{}, errors.New(fmt.Sprintf("policy events of patient %d not found", patientId))
+	}
+	return events, nil
+}
+
+// CountByPatient cuenta las ausencias y cancelaciones tardias de un paciente
+func (r *policyRepository) CountByPatient(patientId int) (int, int, error) {
+	noShows, lateCancellations, err := r.storage.CountByPatient(patientId)
+	if err != nil {
+		return 0, 0, errors.New(fmt.Sprintf("error counting policy events of patient %d", patientId))
+	}
+	return noShows, lateCancellations, nil
+}
+
+// Create agrega un nuevo evento
+func (r *policyRepository) Create(event domain.PolicyEvent) (domain.PolicyEvent, error) {
+	e, err := r.storage.Create(event)
+	if err != nil {
+		return domain.PolicyEvent{}, errors.New("error creating policy event")
+	}
+	return e, nil
+}
+
+// Delete elimina un evento
+func (r *policyRepository) Delete(id int) error {
+	err := r.storage.Delete(id)
+	if err != nil {
+		return errors.New(fmt.Sprintf("policy event %d not found", id))
+	}
+	return nil
+}
@@ -0,0 +1,104 @@
+package policy
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"time"
+)
+
+type Config struct {
+	// NoShowFee y LateCancellationFee son los cargos por cada evento, se registran en la cuenta del paciente.
+	// 0 no aplica cargo
+	NoShowFee           float64
+	LateCancellationFee float64
+	// LateCancellationWindow es la anticipacion por debajo de la cual una cancelacion es tardia, tiene que ser
+	// mayor que el minimo con el que el paciente puede cancelar para que alguna cancelacion cuente
+	LateCancellationWindow time.Duration
+	// BlockThreshold es la cantidad de eventos a partir de la cual se bloquea la reserva online, 0 no bloquea
+	BlockThreshold int
+}
+
+type Service interface {
+	GetByPatient(patientId int) ([]domain.PolicyEvent, error)
+	Summary(patientId int) (domain.PolicySummary, error)
+	CanBook(patientId int) error
+	AppointmentStatusChanged(before domain.Appointment, after domain.Appointment) error
+	Delete(id int) error
+}
+
+type service struct {
+	r      PolicyRepository
+	config Config
+}
+
+// NewPolicyService crea un nuevo servicio
+func NewPolicyService(r PolicyRepository, config Config) Service {
+	return &service{r, config}
+}
+
+// GetByPatient busca los eventos de un paciente
+func (s *service) GetByPatient(patientId int) ([]domain.PolicyEvent, error) {
+	return s.r.GetByPatient(patientId)
+}
+
+// Summary devuelve los contadores de un paciente y si tiene la reserva online bloqueada
+func (s *service) Summary(patientId int) (domain.PolicySummary, error) {
+	noShows, lateCancellations, err := s.r.CountByPatient(patientId)
+	if err != nil {
+		return domain.PolicySummary{}, err
+	}
+	return domain.PolicySummary{
+		NoShows:           noShows,
+		LateCancellations: lateCancellations,
+		BookingBlocked:    s.config.BlockThreshold > 0 && noShows+lateCancellations >= s.config.BlockThreshold,
+	}, nil
+}
+
+// CanBook devuelve un error si el paciente no puede reservar turnos online
+func (s *service) CanBook(patientId int) error {
+	summary, err := s.Summary(patientId)
+	if err != nil {
+		return err
+	}
+	if summary.BookingBlocked {
+		return errors.New("online booking is blocked because of missed or late cancelled appointments, please contact the clinic")
+	}
+	return nil
+}
+
+// AppointmentStatusChanged registra las ausencias y las cancelaciones tardias. Solo cuentan las cancelaciones
+// que pidio el paciente, las que hace la clinica no se le cobran.
+func (s *service) AppointmentStatusChanged(before domain.Appointment, after domain.Appointment) error {
+	switch after.Status {
+	case domain.AppointmentNoShow:
+		return s.record(after, domain.PolicyNoShow, s.config.NoShowFee)
+	case domain.AppointmentCancelled:
+		if !after.CancelledByPatient {
+			return nil
+		}
+		start, err := after.Start()
+		if err != nil {
+			return err
+		}
+		if time.Until(start) < s.config.LateCancellationWindow {
+			return s.record(after, domain.PolicyLateCancellation, s.config.LateCancellationFee)
+		}
+	}
+	return nil
+}
+
+// Delete elimina un evento y revierte su cargo, por ejemplo para perdonar una ausencia justificada
+func (s *service) Delete(id int) error {
+	return s.r.Delete(id)
+}
+
+// record guarda un evento de la politica para un turno, el store registra el cargo en la cuenta del paciente
+func (s *service) record(a domain.Appointment, eventType string, fee float64) error {
+	_, err := s.r.Create(domain.PolicyEvent{
+		PatientId:     a.Patient.Id,
+		AppointmentId: a.Id,
+		Type:          eventType,
+		Fee:           fee,
+	})
+	return err
+}
@@ -0,0 +1,110 @@
+package policy
+
+import (
+	"dental_clinic_go/internal/domain"
+	"testing"
+	"time"
+)
+
+// fakeRepository es un PolicyRepository en memoria
+type fakeRepository struct {
+	events []domain.PolicyEvent
+}
+
+func (r *fakeRepository) GetByPatient(patientId int) ([]domain.PolicyEvent, error) {
+	return r.events, nil
+}
+
+func (r *fakeRepository) CountByPatient(patientId int) (int, int, error) {
+	var noShows, lateCancellations int
+	for _, e := range r.events {
+		if e.Type == domain.PolicyNoShow {
+			noShows++
+		} else {
+			lateCancellations++
+		}
+	}
+	return noShows, lateCancellations, nil
+}
+
+func (r *fakeRepository) Create(event domain.PolicyEvent) (domain.PolicyEvent, error) {
+	event.Id = len(r.events) + 1
+	r.events = append(r.events, event)
+	return event, nil
+}
+
+func (r *fakeRepository) Delete(id int) error {
+	return nil
+}
+
+func TestAppointmentStatusChanged(t *testing.T) {
+	in := func(d time.Duration) domain.Appointment {
+		start := time.Now().Add(d)
+		return domain.Appointment{Id: 7, Patient: domain.Patient{Id: 3}, Date: start.Format("2006-01-02"), Hour: start.Format("15:04:05")}
+	}
+	tests := []struct {
+		name        string
+		appointment domain.Appointment
+		status      string
+		byPatient   bool
+		eventType   string
+		fee         float64
+	}{
+		{name: "no show", appointment: in(-time.Hour), status: domain.AppointmentNoShow, eventType: domain.PolicyNoShow, fee: 20},
+		{name: "patient cancels late", appointment: in(30 * time.Hour), status: domain.AppointmentCancelled, byPatient: true, eventType: domain.PolicyLateCancellation, fee: 10},
+		{name: "patient cancels in time", appointment: in(72 * time.Hour), status: domain.AppointmentCancelled, byPatient: true},
+		{name: "clinic cancels late", appointment: in(30 * time.Hour), status: domain.AppointmentCancelled},
+		{name: "completed", appointment: in(-time.Hour), status: domain.AppointmentCompleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{}
+			s := NewPolicyService(r, Config{NoShowFee: 20, LateCancellationFee: 10, LateCancellationWindow: 48 * time.Hour})
+			after := tt.appointment
+			after.Status = tt.status
+			after.CancelledByPatient = tt.byPatient
+			err := s.AppointmentStatusChanged(tt.appointment, after)
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tt.eventType == "" {
+				if len(r.events) != 0 {
+					t.Fatalf("expected no events, got %+v", r.events)
+				}
+				return
+			}
+			if len(r.events) != 1 {
+				t.Fatalf("expected one event, got %+v", r.events)
+			}
+			e := r.events[0]
+			if e.Type != tt.eventType || e.Fee != tt.fee || e.PatientId != 3 || e.AppointmentId != 7 {
+				t.Fatalf("unexpected event %+v", e)
+			}
+		})
+	}
+}
+
+func TestCanBook(t *testing.T) {
+	tests := []struct {
+		name      string
+		events    int
+		threshold int
+		blocked   bool
+	}{
+		{name: "under the threshold", events: 2, threshold: 3},
+		{name: "at the threshold", events: 3, threshold: 3, blocked: true},
+		{name: "without threshold", events: 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{}
+			for i := 0; i < tt.events; i++ {
+				r.Create(domain.PolicyEvent{Type: domain.PolicyNoShow})
+			}
+			err := NewPolicyService(r, Config{BlockThreshold: tt.threshold}).CanBook(1)
+			if tt.blocked != (err != nil) {
+				t.Fatalf("expected blocked %v, got %v", tt.blocked, err)
+			}
+		})
+	}
+}
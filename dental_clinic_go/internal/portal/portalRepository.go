@@ -0,0 +1,114 @@
+package portal
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+	"time"
+)
+
+type PortalRepository interface {
+	GetPatientByID(id int) (domain.Patient, error)
+	GetPatientByDni(dni int) (domain.Patient, error)
+	GetDentistByLicense(license string) (domain.Dentist, error)
+	CreateCode(patientId int, codeHash string, expiresAt time.Time) error
+	CountCodes(patientId int, window time.Duration) (int, error)
+	UseCode(patientId int, codeHash string, now time.Time, maxAttempts int) error
+	CreateSession(patientId int, tokenHash string, expiresAt time.Time) error
+	GetSession(tokenHash string, now time.Time) (int, error)
+	DeleteSession(tokenHash string) error
+}
+
+type portalRepository struct {
+	storage      store.PortalStore
+	patientStore store.PatientStore
+	dentistStore store.DentistStore
+}
+
+// NewPortalRepository crea un nuevo repositorio
+func NewPortalRepository(storage store.PortalStore, patientStore store.PatientStore,
+	dentistStore store.DentistStore) PortalRepository {
+	return &portalRepository{storage, patientStore, dentistStore}
+}
+
+// GetPatientByID busca un paciente por su id
+func (r *portalRepository) GetPatientByID(id int) (domain.Patient, error) {
+	patient, err := r.patientStore.GetByID(id)
+	if err != nil {
+		return domain.Patient{}, errors.New(fmt.Sprintf("patient %d not found", id))
+	}
+	return patient, nil
+}
+
+// GetPatientByDni busca un paciente por su dni
+func (r *portalRepository) GetPatientByDni(dni int) (domain.Patient, error) {
+	patient, err := r.patientStore.GetByDni(dni)
+	if err != nil {
+		return domain.Patient{}, errors.New(fmt.Sprintf("patient with dni %d not found", dni))
+	}
+	return patient, nil
+}
+
+// GetDentistByLicense busca un dentista por su matricula
+func (r *portalRepository) GetDentistByLicense(license string) (domain.Dentist, error) {
+	dentist, err := r.dentistStore.GetByLicense(license)
+	if err != nil {
+		return domain.Dentist{}, errors.New(fmt.Sprintf("dentist with license %s not found", license))
+	}
+	return dentist, nil
+}
+
+// CreateCode guarda un codigo de un solo uso
+func (r *portalRepository) CreateCode(patientId int, codeHash string, expiresAt time.Time) error {
+	err := r.storage.CreateCode(patientId, codeHash, expiresAt)
+	if err != nil {
+		return errors.New("error creating code")
+	}
+	return nil
+}
+
+// CountCodes cuenta los codigos generados para un paciente en el ultimo window
+func (r *portalRepository) CountCodes(patientId int, window time.Duration) (int, error) {
+	count, err := r.storage.CountCodes(patientId, window)
+	if err != nil {
+		return 0, errors.New("error counting codes")
+	}
+	return count, nil
+}
+
+// UseCode consume un codigo de un solo uso
+func (r *portalRepository) UseCode(patientId int, codeHash string, now time.Time, maxAttempts int) error {
+	err := r.storage.UseCode(patientId, codeHash, now, maxAttempts)
+	if err != nil {
+		return errors.New("invalid code")
+	}
+	return nil
+}
+
+// CreateSession guarda una nueva sesion
+func (r *portalRepository) CreateSession(patientId int, tokenHash string, expiresAt time.Time) error {
+	err := r.storage.CreateSession(patientId, tokenHash, expiresAt)
+	if err != nil {
+		return errors.New("error creating session")
+	}
+	return nil
+}
+
+// GetSession busca el paciente de una sesion vigente
+func (r *portalRepository) GetSession(tokenHash string, now time.Time) (int, error) {
+	patientId, err := r.storage.GetSession(tokenHash, now)
+	if err != nil {
+		return 0, errors.New("invalid session")
+	}
+	return patientId, nil
+}
+
+// DeleteSession elimina una sesion
+func (r *portalRepository) DeleteSession(tokenHash string) error {
+	err := r.storage.DeleteSession(tokenHash)
+	if err != nil {
+		return errors.New("error deleting session")
+	}
+	return nil
+}
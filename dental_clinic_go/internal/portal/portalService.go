@@ -0,0 +1,245 @@
+package portal
+
+import (
+	"crypto/rand"
+	"crypto/sha256"
+	"dental_clinic_go/internal/appointment"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/policy"
+	"dental_clinic_go/pkg/notify"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"log"
+	"math/big"
+	"time"
+)
+
+const (
+	codeTTL    = 10 * time.Minute
+	sessionTTL = 30 * time.Minute
+	// maxCodeAttempts son los intentos fallidos que admite un codigo antes de dejar de valer
+	maxCodeAttempts = 5
+	// maxCodes son los codigos que puede pedir un paciente en codeWindow
+	maxCodes    = 5
+	codeWindow  = time.Hour
+	openingHour = 9
+	closingHour = 18
+	slotMinutes = 30
+)
+
+type Service interface {
+	RequestCode(dni int) error
+	Login(dni int, code string) (domain.PortalSession, error)
+	Logout(token string) error
+	ValidateSession(token string) (int, error)
+	GetAvailability(license string, date string) (domain.Availability, error)
+	GetAppointments(patientId int) ([]domain.Appointment, error)
+	Book(patientId int, booking domain.PortalBooking) (domain.Appointment, error)
+	Confirm(patientId int, appointmentId int) (domain.Appointment, error)
+	Cancel(patientId int, appointmentId int) (domain.Appointment, error)
+}
+
+type service struct {
+	r        PortalRepository
+	a        appointment.AppointmentService
+	policy   policy.Service
+	notifier notify.Notifier
+}
+
+// NewPortalService crea un nuevo servicio
+func NewPortalService(r PortalRepository, a appointment.AppointmentService, policy policy.Service, notifier notify.Notifier) Service {
+	return &service{r, a, policy, notifier}
+}
+
+// RequestCode genera un codigo de un solo uso y se lo envia al paciente, invalidando el anterior.
+// Si el dni no existe o el paciente ya pidio maxCodes codigos en la ultima hora no devuelve error
+// para no revelar que pacientes tiene la clinica.
+func (s *service) RequestCode(dni int) error {
+	patient, err := s.r.GetPatientByDni(dni)
+	if err != nil {
+		return nil
+	}
+	now := time.Now().UTC()
+	count, err := s.r.CountCodes(patient.Id, codeWindow)
+	if err != nil {
+		return err
+	}
+	if count >= maxCodes {
+		log.Printf("patient %d requested too many portal codes", patient.Id)
+		return nil
+	}
+	code, err := generateCode()
+	if err != nil {
+		return err
+	}
+	err = s.r.CreateCode(patient.Id, hash(code), now.Add(codeTTL))
+	if err != nil {
+		return err
+	}
+	body := fmt.Sprintf("Tu codigo de acceso es %s. Vence en %d minutos.", code, int(codeTTL.Minutes()))
+	return s.notifier.Send(patient.Email, "Codigo de acceso", body)
+}
+
+// Login valida el codigo de un solo uso y abre una sesion. Cualquier falla se informa como codigo
+// invalido, para no revelar si el dni existe ni si el codigo vencio o agoto sus intentos.
+func (s *service) Login(dni int, code string) (domain.PortalSession, error) {
+	patient, err := s.r.GetPatientByDni(dni)
+	if err != nil {
+		return domain.PortalSession{}, errors.New("invalid code")
+	}
+	now := time.Now().UTC()
+	err = s.r.UseCode(patient.Id, hash(code), now, maxCodeAttempts)
+	if err != nil {
+		return domain.PortalSession{}, errors.New("invalid code")
+	}
+	token, err := generateToken()
+	if err != nil {
+		return domain.PortalSession{}, err
+	}
+	expiresAt := now.Add(sessionTTL)
+	err = s.r.CreateSession(patient.Id, hash(token), expiresAt)
+	if err != nil {
+		return domain.PortalSession{}, err
+	}
+	return domain.PortalSession{Token: token, ExpiresAt: expiresAt.Format(time.RFC3339)}, nil
+}
+
+// Logout cierra una sesion
+func (s *service) Logout(token string) error {
+	return s.r.DeleteSession(hash(token))
+}
+
+// ValidateSession devuelve el id del paciente dueño de la sesion
+func (s *service) ValidateSession(token string) (int, error) {
+	return s.r.GetSession(hash(token), time.Now().UTC())
+}
+
+// GetAvailability devuelve los horarios libres de un dentista en una fecha
+func (s *service) GetAvailability(license string, date string) (domain.Availability, error) {
+	day, err := time.ParseInLocation("2006-01-02", date, time.Local)
+	if err != nil {
+		return domain.Availability{}, errors.New("invalid date, must be in format: yyyy-mm-dd")
+	}
+	dentist, err := s.r.GetDentistByLicense(license)
+	if err != nil {
+		return domain.Availability{}, err
+	}
+	appointments, err := s.a.GetByDentistAndDate(dentist.Id, date)
+	if err != nil {
+		return domain.Availability{}, err
+	}
+	taken := map[string]bool{}
+	for _, a := range appointments {
+		if a.Status != domain.AppointmentCancelled {
+			taken[a.Hour] = true
+		}
+	}
+	slots := []string{}
+	now := time.Now()
+	start := day.Add(openingHour * time.Hour)
+	end := day.Add(closingHour * time.Hour)
+	for slot := start; slot.Before(end); slot = slot.Add(slotMinutes * time.Minute) {
+		hour := slot.Format("15:04:05")
+		if slot.After(now) && !taken[hour] {
+			slots = append(slots, hour)
+		}
+	}
+	return domain.Availability{Date: date, Dentist: dentist, Slots: slots}, nil
+}
+
+// GetAppointments devuelve los turnos del paciente de la sesion
+func (s *service) GetAppointments(patientId int) ([]domain.Appointment, error) {
+	patient, err := s.r.GetPatientByID(patientId)
+	if err != nil {
+		return []domain.Appointment{}, err
+	}
+	return s.a.GetByDni(patient.Dni)
+}
+
+// Book reserva un turno libre para el paciente de la sesion
+func (s *service) Book(patientId int, booking domain.PortalBooking) (domain.Appointment, error) {
+	patient, err := s.r.GetPatientByID(patientId)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	err = s.policy.CanBook(patientId)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	availability, err := s.GetAvailability(booking.License, booking.Date)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	free := false
+	for _, slot := range availability.Slots {
+		if slot == booking.Hour {
+			free = true
+			break
+		}
+	}
+	if !free {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("slot %s %s is not available", booking.Date, booking.Hour))
+	}
+	description := booking.Description
+	if description == "" {
+		description = "Reserva online"
+	}
+	a := domain.Appointment{Date: booking.Date, Hour: booking.Hour, Description: description}
+	return s.a.CreateByDniAndLicense(patient.Dni, booking.License, a)
+}
+
+// Confirm confirma un turno del paciente de la sesion
+func (s *service) Confirm(patientId int, appointmentId int) (domain.Appointment, error) {
+	_, err := s.getOwnAppointment(patientId, appointmentId)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	return s.a.Confirm(appointmentId)
+}
+
+// Cancel cancela un turno del paciente de la sesion
+func (s *service) Cancel(patientId int, appointmentId int) (domain.Appointment, error) {
+	_, err := s.getOwnAppointment(patientId, appointmentId)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	return s.a.CancelByPatient(appointmentId)
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// getOwnAppointment busca un turno y verifica que sea del paciente.
+// Los turnos de otros pacientes se informan como inexistentes.
+func (s *service) getOwnAppointment(patientId int, appointmentId int) (domain.Appointment, error) {
+	a, err := s.a.GetByID(appointmentId)
+	if err != nil || a.Patient.Id != patientId {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("appointment %d not found", appointmentId))
+	}
+	return a, nil
+}
+
+// generateCode genera un codigo numerico de 6 digitos
+func generateCode() (string, error) {
+	n, err := rand.Int(rand.Reader, big.NewInt(1000000))
+	if err != nil {
+		return "", err
+	}
+	return fmt.Sprintf("%06d", n.Int64()), nil
+}
+
+// generateToken genera un token de sesion aleatorio
+func generateToken() (string, error) {
+	b := make([]byte, 32)
+	_, err := rand.Read(b)
+	if err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(b), nil
+}
+
+// hash devuelve el sha256 de un valor, los codigos y tokens nunca se guardan en claro
+func hash(value string) string {
+	sum := sha256.Sum256([]byte(value))
+	return hex.EncodeToString(sum[:])
+}
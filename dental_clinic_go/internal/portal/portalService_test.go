@@ -0,0 +1,134 @@
+package portal
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+)
+
+// fakeRepository guarda los codigos de un unico paciente con dni 123, los metodos que no usa el login quedan sin implementar
+type fakeRepository struct {
+	PortalRepository
+	codes    []string
+	attempts int
+	sessions int
+}
+
+func (r *fakeRepository) GetPatientByDni(dni int) (domain.Patient, error) {
+	if dni != 123 {
+		return domain.Patient{}, errors.New(fmt.Sprintf("patient with dni %d not found", dni))
+	}
+	return domain.Patient{Id: 1, Dni: dni, Email: "ana@example.com"}, nil
+}
+
+func (r *fakeRepository) CreateCode(patientId int, codeHash string, expiresAt time.Time) error {
+	r.codes = append(r.codes, codeHash)
+	r.attempts = 0
+	return nil
+}
+
+func (r *fakeRepository) CountCodes(patientId int, window time.Duration) (int, error) {
+	return len(r.codes), nil
+}
+
+func (r *fakeRepository) UseCode(patientId int, codeHash string, now time.Time, maxAttempts int) error {
+	if len(r.codes) == 0 || r.attempts >= maxAttempts {
+		return errors.New("code not found")
+	}
+	if r.codes[len(r.codes)-1] != codeHash {
+		r.attempts++
+		return errors.New("wrong code")
+	}
+	r.attempts = maxAttempts
+	return nil
+}
+
+func (r *fakeRepository) CreateSession(patientId int, tokenHash string, expiresAt time.Time) error {
+	r.sessions++
+	return nil
+}
+
+// fakeNotifier guarda el ultimo mensaje enviado
+type fakeNotifier struct {
+	sent int
+	body string
+}
+
+func (n *fakeNotifier) Send(to string, subject string, body string) error {
+	n.sent++
+	n.body = body
+	return nil
+}
+
+// sentCode lee el codigo del ultimo mensaje enviado
+func sentCode(n *fakeNotifier) string {
+	return strings.TrimSuffix(strings.Fields(n.body)[5], ".")
+}
+
+func TestRequestCodeLimit(t *testing.T) {
+	tests := []struct {
+		name     string
+		dni      int
+		requests int
+		sent     int
+	}{
+		{name: "under the limit", dni: 123, requests: maxCodes, sent: maxCodes},
+		{name: "over the limit", dni: 123, requests: maxCodes + 3, sent: maxCodes},
+		{name: "unknown dni", dni: 999, requests: 1},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{}
+			n := &fakeNotifier{}
+			s := NewPortalService(r, nil, nil, n)
+			for i := 0; i < tt.requests; i++ {
+				if err := s.RequestCode(tt.dni); err != nil {
+					t.Fatalf("unexpected error: %s", err)
+				}
+			}
+			if n.sent != tt.sent || len(r.codes) != tt.sent {
+				t.Fatalf("expected %d codes sent, got %d sent and %d saved", tt.sent, n.sent, len(r.codes))
+			}
+		})
+	}
+}
+
+func TestLogin(t *testing.T) {
+	r := &fakeRepository{}
+	n := &fakeNotifier{}
+	s := NewPortalService(r, nil, nil, n)
+	if err := s.RequestCode(123); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	code := sentCode(n)
+
+	for i := 0; i < maxCodeAttempts; i++ {
+		if _, err := s.Login(123, "000000x"); err == nil || err.Error() != "invalid code" {
+			t.Fatalf("expected a wrong code to fail, got %v", err)
+		}
+	}
+	if _, err := s.Login(123, code); err == nil || err.Error() != "invalid code" {
+		t.Fatalf("expected the code to stop working after %d attempts, got %v", maxCodeAttempts, err)
+	}
+	if _, err := s.Login(999, code); err == nil || err.Error() != "invalid code" {
+		t.Fatalf("expected an unknown dni to fail as an invalid code, got %v", err)
+	}
+
+	if err := s.RequestCode(123); err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	code = sentCode(n)
+	session, err := s.Login(123, code)
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if len(session.Token) != 64 || r.sessions != 1 {
+		t.Fatalf("expected a session token, got %+v", session)
+	}
+	if _, err := s.Login(123, code); err == nil {
+		t.Fatal("expected a used code to fail")
+	}
+}
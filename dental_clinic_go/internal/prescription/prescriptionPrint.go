@@ -0,0 +1,56 @@
+package prescription
+
+import (
+	"dental_clinic_go/internal/domain"
+	"html/template"
+)
+
+// printData son los datos que usa printTemplate
+type printData struct {
+	Clinic       string
+	Prescription domain.Prescription
+	Patient      domain.Patient
+}
+
+// printTemplate es la receta imprimible, el navegador la puede guardar como PDF
+var printTemplate = template.Must(template.New("prescription").Parse(`<!DOCTYPE html>
+<html lang="es">
+<head>
+<meta charset="utf-8">
+<title>Receta {{.Prescription.Id}}</title>
+<style>
+  body { font-family: Arial, Helvetica, sans-serif; margin: 2cm; color: #222; }
+  header { border-bottom: 2px solid #222; margin-bottom: 1em; }
+  h1 { font-size: 1.4em; margin: 0 0 .3em; }
+  table { width: 100%; border-collapse: collapse; margin: 1em 0; }
+  th, td { text-align: left; padding: .4em; border-bottom: 1px solid #ccc; vertical-align: top; }
+  .signature { margin-top: 4em; width: 40%; border-top: 1px solid #222; padding-top: .3em; }
+  @media print { body { margin: 1cm; } }
+</style>
+</head>
+<body>
+<header>
+  <h1>{{.Clinic}}</h1>
+  <p>Receta N° {{.Prescription.Id}} - Fecha: {{.Prescription.Date}}</p>
+</header>
+<section>
+  <p><strong>Paciente:</strong> {{.Patient.Name}} {{.Patient.LastName}} - DNI {{.Patient.Dni}}</p>
+</section>
+<table>
+  <thead>
+    <tr><th>Medicamento</th><th>Dosis</th><th>Frecuencia</th><th>Duración</th><th>Indicaciones</th></tr>
+  </thead>
+  <tbody>
+  {{range .Prescription.Items}}
+    <tr><td>{{.Drug}}</td><td>{{.Dose}}</td><td>{{.Frequency}}</td><td>{{.Duration}}</td><td>{{.Notes}}</td></tr>
+  {{end}}
+  </tbody>
+</table>
+{{if .Prescription.Notes}}<p><strong>Observaciones:</strong> {{.Prescription.Notes}}</p>{{end}}
+<div class="signature">
+  Dr/a. {{.Prescription.Dentist.Name}} {{.Prescription.Dentist.LastName}}<br>
+  Matrícula {{.Prescription.Dentist.License}}
+</div>
+</body>
+</html>
+`))
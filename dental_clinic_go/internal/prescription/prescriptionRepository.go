@@ -0,0 +1,98 @@
+package prescription
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type PrescriptionRepository interface {
+	GetByID(id int) (domain.Prescription, error)
+	GetByPatient(patientId int) ([]domain.Prescription, error)
+	Create(p domain.Prescription) (domain.Prescription, error)
+	GetPatient(patientId int) (domain.Patient, error)
+	GetAppointment(appointmentId int) (domain.Appointment, error)
+	GetAllergies(patientId int) ([]domain.Allergy, error)
+}
+
+type prescriptionRepository struct {
+	storage          store.PrescriptionStore
+	patientStore     store.PatientStore
+	dentistStore     store.DentistStore
+	appointmentStore store.AppointmentStore
+	medicalStore     store.MedicalStore
+}
+
+// NewPrescriptionRepository crea un nuevo repositorio
+func NewPrescriptionRepository(storage store.PrescriptionStore, patientStore store.PatientStore, dentistStore store.DentistStore,
+	appointmentStore store.AppointmentStore, medicalStore store.MedicalStore) PrescriptionRepository {
+	return &prescriptionRepository{storage, patientStore, dentistStore, appointmentStore, medicalStore}
+}
+
+// GetByID busca una receta por su id
+func (r *prescriptionRepository) GetByID(id int) (domain.Prescription, error) {
+	p, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.Prescription{}, errors.New(fmt.Sprintf("prescription %d not found", id))
+	}
+	return p, nil
+}
+
+// GetByPatient busca las recetas de un paciente
+func (r *prescriptionRepository) GetByPatient(patientId int) ([]domain.Prescription, error) {
+	_, err := r.GetPatient(patientId)
+	if err != nil {
+		return []domain.Prescription{}, err
+	}
+	prescriptions, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.Prescription{}, errors.New(fmt.Sprintf("prescriptions of patient %d not found", patientId))
+	}
+	return prescriptions, nil
+}
+
+// Create agrega una receta
+func (r *prescriptionRepository) Create(p domain.Prescription) (domain.Prescription, error) {
+	_, err := r.GetPatient(p.PatientId)
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	dentist, err := r.dentistStore.GetByID(p.Dentist.Id)
+	if err != nil {
+		return domain.Prescription{}, errors.New(fmt.Sprintf("dentist %d not found", p.Dentist.Id))
+	}
+	p.Dentist = dentist
+	prescription, err := r.storage.Create(p)
+	if err != nil {
+		return domain.Prescription{}, errors.New("error creating prescription")
+	}
+	return prescription, nil
+}
+
+// GetPatient busca el paciente de una receta
+func (r *prescriptionRepository) GetPatient(patientId int) (domain.Patient, error) {
+	patient, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return domain.Patient{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	return patient, nil
+}
+
+// GetAppointment busca el turno de una receta
+func (r *prescriptionRepository) GetAppointment(appointmentId int) (domain.Appointment, error) {
+	appointment, err := r.appointmentStore.GetByID(appointmentId)
+	if err != nil {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("appointment %d not found", appointmentId))
+	}
+	return appointment, nil
+}
+
+// GetAllergies busca las alergias registradas de un paciente
+func (r *prescriptionRepository) GetAllergies(patientId int) ([]domain.Allergy, error) {
+	allergies, err := r.medicalStore.GetAllergies(patientId)
+	if err != nil {
+		return []domain.Allergy{}, errors.New(fmt.Sprintf("allergies of patient %d not found", patientId))
+	}
+	return allergies, nil
+}
@@ -0,0 +1,164 @@
+package prescription
+
+import (
+	"bytes"
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// drugGroups agrupa los medicamentos que comparten alergia, una alergia a cualquiera
+// de los nombres del grupo alcanza para rechazar a todos los demas
+var drugGroups = [][]string{
+	{"penicilina", "amoxicilina", "ampicilina", "betalactamico"},
+	{"aine", "ibuprofeno", "diclofenac", "ketorolac", "naproxeno", "aspirina", "acido acetilsalicilico"},
+	{"cefalosporina", "cefalexina", "cefadroxilo"},
+	{"sulfa", "sulfonamida", "sulfametoxazol"},
+	{"macrolido", "azitromicina", "claritromicina", "eritromicina"},
+	{"anestesico local", "lidocaina", "articaina", "mepivacaina", "carticaina"},
+}
+
+type Service interface {
+	GetByID(id int) (domain.Prescription, error)
+	GetByPatient(patientId int) ([]domain.Prescription, error)
+	Create(p domain.Prescription, dentistId int) (domain.Prescription, error)
+	Print(id int) ([]byte, error)
+}
+
+type service struct {
+	r          PrescriptionRepository
+	clinicName string
+}
+
+// NewPrescriptionService crea un nuevo servicio, clinicName es el encabezado de las recetas impresas
+func NewPrescriptionService(r PrescriptionRepository, clinicName string) Service {
+	return &service{r, clinicName}
+}
+
+// GetByID busca una receta por su id
+func (s *service) GetByID(id int) (domain.Prescription, error) {
+	return s.r.GetByID(id)
+}
+
+// GetByPatient busca las recetas de un paciente
+func (s *service) GetByPatient(patientId int) ([]domain.Prescription, error) {
+	return s.r.GetByPatient(patientId)
+}
+
+// Create valida y emite una receta firmada por el dentista del usuario actual. Si algun medicamento
+// coincide con una alergia registrada del paciente se rechaza, salvo que el dentista lo confirme con
+// allergy_override.
+func (s *service) Create(p domain.Prescription, dentistId int) (domain.Prescription, error) {
+	if dentistId == 0 {
+		return domain.Prescription{}, errors.New("only users linked to a dentist can issue prescriptions")
+	}
+	p.Dentist = domain.Dentist{Id: dentistId}
+	if len(p.Items) == 0 {
+		return domain.Prescription{}, errors.New("items can't be empty")
+	}
+	for i := range p.Items {
+		p.Items[i].Drug = strings.TrimSpace(p.Items[i].Drug)
+		if p.Items[i].Drug == "" {
+			return domain.Prescription{}, errors.New("item drug can't be empty")
+		}
+		if p.Items[i].Dose == "" || p.Items[i].Frequency == "" {
+			return domain.Prescription{}, errors.New(fmt.Sprintf("%s needs dose and frequency", p.Items[i].Drug))
+		}
+	}
+	if p.AppointmentId != 0 {
+		appointment, err := s.r.GetAppointment(p.AppointmentId)
+		if err != nil {
+			return domain.Prescription{}, err
+		}
+		if appointment.Patient.Id != p.PatientId {
+			return domain.Prescription{}, errors.New(fmt.Sprintf("appointment %d isn't of patient %d", p.AppointmentId, p.PatientId))
+		}
+	}
+	if p.Date == "" {
+		p.Date = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", p.Date); err != nil {
+		return domain.Prescription{}, errors.New("invalid date, must be in format: yyyy-mm-dd")
+	}
+	allergies, err := s.r.GetAllergies(p.PatientId)
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	conflicts := allergyConflicts(p.Items, allergies)
+	if len(conflicts) > 0 && !p.AllergyOverride {
+		return domain.Prescription{}, errors.New(fmt.Sprintf("patient %d is allergic: %s, send allergy_override to prescribe anyway", p.PatientId, strings.Join(conflicts, "; ")))
+	}
+	if len(conflicts) == 0 {
+		p.AllergyOverride = false
+	}
+	prescription, err := s.r.Create(p)
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	return s.r.GetByID(prescription.Id)
+}
+
+// Print arma la receta en HTML lista para imprimir
+func (s *service) Print(id int) ([]byte, error) {
+	p, err := s.r.GetByID(id)
+	if err != nil {
+		return nil, err
+	}
+	patient, err := s.r.GetPatient(p.PatientId)
+	if err != nil {
+		return nil, err
+	}
+	var buf bytes.Buffer
+	err = printTemplate.Execute(&buf, printData{
+		Clinic:       s.clinicName,
+		Prescription: p,
+		Patient:      patient,
+	})
+	if err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// allergyConflicts devuelve los medicamentos que coinciden con alguna alergia del paciente
+func allergyConflicts(items []domain.PrescriptionItem, allergies []domain.Allergy) []string {
+	conflicts := []string{}
+	for _, item := range items {
+		drug := normalizeDrug(item.Drug)
+		for _, allergy := range allergies {
+			substance := normalizeDrug(allergy.Substance)
+			if substance == "" {
+				continue
+			}
+			if strings.Contains(drug, substance) || strings.Contains(substance, drug) || sameGroup(drug, substance) {
+				conflicts = append(conflicts, fmt.Sprintf("%s conflicts with %s allergy (%s)", item.Drug, allergy.Substance, allergy.Severity))
+			}
+		}
+	}
+	return conflicts
+}
+
+// sameGroup indica si el medicamento y la sustancia pertenecen al mismo grupo de drugGroups
+func sameGroup(drug string, substance string) bool {
+	for _, group := range drugGroups {
+		drugIn, substanceIn := false, false
+		for _, name := range group {
+			drugIn = drugIn || strings.Contains(drug, name)
+			substanceIn = substanceIn || strings.Contains(substance, name)
+		}
+		if drugIn && substanceIn {
+			return true
+		}
+	}
+	return false
+}
+
+// normalizeDrug pasa el nombre a minusculas y sin acentos para compararlo
+func normalizeDrug(name string) string {
+	replacer := strings.NewReplacer("á", "a", "é", "e", "í", "i", "ó", "o", "ú", "u", "ü", "u", "ñ", "n")
+	return strings.TrimSpace(replacer.Replace(strings.ToLower(name)))
+}
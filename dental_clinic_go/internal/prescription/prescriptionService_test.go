@@ -0,0 +1,93 @@
+package prescription
+
+import (
+	"dental_clinic_go/internal/domain"
+	"testing"
+)
+
+// fakeRepository es un PrescriptionRepository en memoria con un paciente alergico
+type fakeRepository struct {
+	created   []domain.Prescription
+	allergies []domain.Allergy
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.Prescription, error) {
+	return r.created[id-1], nil
+}
+
+func (r *fakeRepository) GetByPatient(patientId int) ([]domain.Prescription, error) {
+	return r.created, nil
+}
+
+func (r *fakeRepository) Create(p domain.Prescription) (domain.Prescription, error) {
+	r.created = append(r.created, p)
+	p.Id = len(r.created)
+	r.created[p.Id-1].Id = p.Id
+	return p, nil
+}
+
+func (r *fakeRepository) GetPatient(patientId int) (domain.Patient, error) {
+	return domain.Patient{Id: patientId}, nil
+}
+
+func (r *fakeRepository) GetAppointment(appointmentId int) (domain.Appointment, error) {
+	return domain.Appointment{Id: appointmentId, Patient: domain.Patient{Id: 1}, Dentist: domain.Dentist{Id: 9}}, nil
+}
+
+func (r *fakeRepository) GetAllergies(patientId int) ([]domain.Allergy, error) {
+	return r.allergies, nil
+}
+
+func TestCreate(t *testing.T) {
+	item := func(drug string) []domain.PrescriptionItem {
+		return []domain.PrescriptionItem{{Drug: drug, Dose: "500 mg", Frequency: "cada 8 horas"}}
+	}
+	tests := []struct {
+		name      string
+		p         domain.Prescription
+		dentistId int
+		allergies []string
+		err       string
+	}{
+		{name: "prescriber is the current dentist", p: domain.Prescription{PatientId: 1, Items: item("Amoxicilina")}, dentistId: 2},
+		{name: "prescriber in the body is ignored", p: domain.Prescription{PatientId: 1, Dentist: domain.Dentist{Id: 7}, Items: item("Amoxicilina")}, dentistId: 2},
+		{name: "appointment dentist isn't used", p: domain.Prescription{PatientId: 1, AppointmentId: 4, Items: item("Amoxicilina")}, dentistId: 2},
+		{name: "user without dentist", p: domain.Prescription{PatientId: 1, Items: item("Amoxicilina")}, err: "only users linked to a dentist can issue prescriptions"},
+		{name: "appointment of another patient", p: domain.Prescription{PatientId: 5, AppointmentId: 4, Items: item("Amoxicilina")}, dentistId: 2, err: "appointment 4 isn't of patient 5"},
+		{name: "without items", p: domain.Prescription{PatientId: 1}, dentistId: 2, err: "items can't be empty"},
+		{name: "item without dose", p: domain.Prescription{PatientId: 1, Items: []domain.PrescriptionItem{{Drug: "Ibuprofeno", Frequency: "cada 8 horas"}}}, dentistId: 2, err: "Ibuprofeno needs dose and frequency"},
+		{name: "invalid date", p: domain.Prescription{PatientId: 1, Date: "19/10/2026", Items: item("Ibuprofeno")}, dentistId: 2, err: "invalid date, must be in format: yyyy-mm-dd"},
+		{name: "same drug as the allergy", p: domain.Prescription{PatientId: 1, Items: item("Amoxicilina 500")}, dentistId: 2, allergies: []string{"amoxicilina"},
+			err: "patient 1 is allergic: Amoxicilina 500 conflicts with amoxicilina allergy (severe), send allergy_override to prescribe anyway"},
+		{name: "drug of the same group", p: domain.Prescription{PatientId: 1, Items: item("Amoxicilina")}, dentistId: 2, allergies: []string{"Penicilina"},
+			err: "patient 1 is allergic: Amoxicilina conflicts with Penicilina allergy (severe), send allergy_override to prescribe anyway"},
+		{name: "accents and case are ignored", p: domain.Prescription{PatientId: 1, Items: item("LIDOCAÍNA")}, dentistId: 2, allergies: []string{"articaína"},
+			err: "patient 1 is allergic: LIDOCAÍNA conflicts with articaína allergy (severe), send allergy_override to prescribe anyway"},
+		{name: "override allows the conflict", p: domain.Prescription{PatientId: 1, AllergyOverride: true, Items: item("Ibuprofeno")}, dentistId: 2, allergies: []string{"aspirina"}},
+		{name: "unrelated allergy", p: domain.Prescription{PatientId: 1, Items: item("Ibuprofeno")}, dentistId: 2, allergies: []string{"penicilina", "latex"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{}
+			for _, substance := range tt.allergies {
+				r.allergies = append(r.allergies, domain.Allergy{PatientId: 1, Substance: substance, Severity: "severe"})
+			}
+			p, err := NewPrescriptionService(r, "Clinica").Create(tt.p, tt.dentistId)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if p.Dentist.Id != tt.dentistId {
+				t.Fatalf("expected prescriber %d, got %d", tt.dentistId, p.Dentist.Id)
+			}
+			if p.AllergyOverride && len(tt.allergies) == 0 {
+				t.Fatal("expected allergy_override to be cleared without conflicts")
+			}
+		})
+	}
+}
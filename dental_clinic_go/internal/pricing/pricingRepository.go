@@ -0,0 +1,163 @@
+package pricing
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type PricingRepository interface {
+	GetLists(insurerId int) ([]domain.PriceList, error)
+	GetList(id int) (domain.PriceList, error)
+	GetPrice(procedureCode string, date string, insurerId int) (domain.Price, int, error)
+	CreateList(list domain.PriceList) (domain.PriceList, error)
+	UpdateList(list domain.PriceList) (domain.PriceList, error)
+	GetDiscounts() ([]domain.DiscountRule, error)
+	GetDiscount(id int) (domain.DiscountRule, error)
+	CreateDiscount(rule domain.DiscountRule) (domain.DiscountRule, error)
+	UpdateDiscount(rule domain.DiscountRule) (domain.DiscountRule, error)
+	GetProcedure(code string) (domain.Procedure, error)
+}
+
+type pricingRepository struct {
+	storage        store.PricingStore
+	procedureStore store.ProcedureStore
+	insuranceStore store.InsuranceStore
+}
+
+// NewPricingRepository crea un nuevo repositorio
+func NewPricingRepository(storage store.PricingStore, procedureStore store.ProcedureStore, insuranceStore store.InsuranceStore) PricingRepository {
+	return &pricingRepository{storage, procedureStore, insuranceStore}
+}
+
+// GetLists busca las listas de un financiador, negativo busca todas
+func (r *pricingRepository) GetLists(insurerId int) ([]domain.PriceList, error) {
+	lists, err := r.storage.GetLists(insurerId)
+	if err != nil {
+		return []domain.PriceList{}, errors.New("error getting price lists")
+	}
+	return lists, nil
+}
+
+// GetList busca una lista por su id
+func (r *pricingRepository) GetList(id int) (domain.PriceList, error) {
+	list, err := r.storage.GetList(id)
+	if err != nil {
+		return domain.PriceList{}, errors.New(fmt.Sprintf("price list %d not found", id))
+	}
+	return list, nil
+}
+
+// GetPrice busca el precio vigente de un procedimiento para un financiador
+func (r *pricingRepository) GetPrice(procedureCode string, date string, insurerId int) (domain.Price, int, error) {
+	price, listId, err := r.storage.GetPrice(procedureCode, date, insurerId)
+	if err != nil {
+		return domain.Price{}, 0, errors.New(fmt.Sprintf("error getting price of procedure %s", procedureCode))
+	}
+	return price, listId, nil
+}
+
+// CreateList agrega una lista verificando que existan el financiador y los procedimientos
+func (r *pricingRepository) CreateList(list domain.PriceList) (domain.PriceList, error) {
+	err := r.checkList(list)
+	if err != nil {
+		return domain.PriceList{}, err
+	}
+	l, err := r.storage.CreateList(list)
+	if err != nil {
+		return domain.PriceList{}, errors.New("error creating price list")
+	}
+	return r.GetList(l.Id)
+}
+
+// UpdateList actualiza una lista verificando que existan los procedimientos
+func (r *pricingRepository) UpdateList(list domain.PriceList) (domain.PriceList, error) {
+	err := r.checkList(list)
+	if err != nil {
+		return domain.PriceList{}, err
+	}
+	err = r.storage.UpdateList(list)
+	if err != nil {
+		return domain.PriceList{}, errors.New(fmt.Sprintf("error updating price list %d", list.Id))
+	}
+	return r.GetList(list.Id)
+}
+
+// GetDiscounts busca todos los descuentos
+func (r *pricingRepository) GetDiscounts() ([]domain.DiscountRule, error) {
+	discounts, err := r.storage.GetDiscounts()
+	if err != nil {
+		return []domain.DiscountRule{}, errors.New("error getting discounts")
+	}
+	return discounts, nil
+}
+
+// GetDiscount busca un descuento por su id
+func (r *pricingRepository) GetDiscount(id int) (domain.DiscountRule, error) {
+	discount, err := r.storage.GetDiscount(id)
+	if err != nil {
+		return domain.DiscountRule{}, errors.New(fmt.Sprintf("discount %d not found", id))
+	}
+	return discount, nil
+}
+
+// CreateDiscount agrega un descuento verificando que exista el procedimiento
+func (r *pricingRepository) CreateDiscount(rule domain.DiscountRule) (domain.DiscountRule, error) {
+	if rule.ProcedureCode != "" {
+		_, err := r.GetProcedure(rule.ProcedureCode)
+		if err != nil {
+			return domain.DiscountRule{}, err
+		}
+	}
+	d, err := r.storage.CreateDiscount(rule)
+	if err != nil {
+		return domain.DiscountRule{}, errors.New("error creating discount")
+	}
+	return r.GetDiscount(d.Id)
+}
+
+// UpdateDiscount actualiza un descuento verificando que exista el procedimiento
+func (r *pricingRepository) UpdateDiscount(rule domain.DiscountRule) (domain.DiscountRule, error) {
+	_, err := r.GetDiscount(rule.Id)
+	if err != nil {
+		return domain.DiscountRule{}, err
+	}
+	if rule.ProcedureCode != "" {
+		_, err := r.GetProcedure(rule.ProcedureCode)
+		if err != nil {
+			return domain.DiscountRule{}, err
+		}
+	}
+	err = r.storage.UpdateDiscount(rule)
+	if err != nil {
+		return domain.DiscountRule{}, errors.New(fmt.Sprintf("error updating discount %d", rule.Id))
+	}
+	return r.GetDiscount(rule.Id)
+}
+
+// GetProcedure busca un procedimiento del nomenclador
+func (r *pricingRepository) GetProcedure(code string) (domain.Procedure, error) {
+	procedure, err := r.procedureStore.GetByCode(code)
+	if err != nil {
+		return domain.Procedure{}, errors.New(fmt.Sprintf("procedure %s not found", code))
+	}
+	return procedure, nil
+}
+
+// checkList verifica que existan el financiador y los procedimientos de una lista
+func (r *pricingRepository) checkList(list domain.PriceList) error {
+	if list.InsurerId != 0 {
+		_, err := r.insuranceStore.GetInsurer(list.InsurerId)
+		if err != nil {
+			return errors.New(fmt.Sprintf("insurer %d not found", list.InsurerId))
+		}
+	}
+	for _, p := range list.Prices {
+		_, err := r.GetProcedure(p.ProcedureCode)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,305 @@
+package pricing
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+)
+
+// FamilyResolver devuelve los integrantes de la familia de un paciente para los descuentos family
+type FamilyResolver interface {
+	GetFamily(patientId int) (domain.Family, error)
+}
+
+type Service interface {
+	GetLists(insurerId int) ([]domain.PriceList, error)
+	GetList(id int) (domain.PriceList, error)
+	CreateList(list domain.PriceList) (domain.PriceList, error)
+	UpdateList(id int, list domain.PriceList) (domain.PriceList, error)
+	GetDiscounts() ([]domain.DiscountRule, error)
+	GetDiscount(id int) (domain.DiscountRule, error)
+	CreateDiscount(rule domain.DiscountRule) (domain.DiscountRule, error)
+	UpdateDiscount(id int, rule domain.DiscountRule) (domain.DiscountRule, error)
+	ListPrice(procedureCode string, date string, insurerId int) (float64, error)
+	Quote(patientId int, procedureCode string, date string, insurerId int) (domain.PriceQuote, error)
+}
+
+type service struct {
+	r        PricingRepository
+	families FamilyResolver
+}
+
+// NewPricingService crea un nuevo servicio
+func NewPricingService(r PricingRepository, families FamilyResolver) Service {
+	return &service{r, families}
+}
+
+// GetLists busca las listas de un financiador, negativo busca todas
+func (s *service) GetLists(insurerId int) ([]domain.PriceList, error) {
+	return s.r.GetLists(insurerId)
+}
+
+// GetList busca una lista con sus precios
+func (s *service) GetList(id int) (domain.PriceList, error) {
+	return s.r.GetList(id)
+}
+
+// CreateList agrega una nueva version de precios. Si indica base_list_id copia los precios de esa lista
+// aumentados en increase por ciento, los precios que trae reemplazan a los copiados.
+func (s *service) CreateList(list domain.PriceList) (domain.PriceList, error) {
+	if list.BaseListId != 0 {
+		base, err := s.r.GetList(list.BaseListId)
+		if err != nil {
+			return domain.PriceList{}, err
+		}
+		prices := []domain.Price{}
+		for _, p := range base.Prices {
+			if _, ok := findPrice(list.Prices, p.ProcedureCode); !ok {
+				prices = append(prices, domain.Price{ProcedureCode: p.ProcedureCode, Amount: round(p.Amount * (1 + list.Increase/100))})
+			}
+		}
+		list.Prices = append(prices, list.Prices...)
+	}
+	list, err := normalizeList(list)
+	if err != nil {
+		return domain.PriceList{}, err
+	}
+	return s.r.CreateList(list)
+}
+
+// UpdateList reemplaza el nombre, la vigencia y los precios de una lista que todavia no entro en vigencia,
+// las listas vigentes o pasadas no se modifican para no cambiar los precios ya cobrados
+func (s *service) UpdateList(id int, list domain.PriceList) (domain.PriceList, error) {
+	current, err := s.r.GetList(id)
+	if err != nil {
+		return domain.PriceList{}, err
+	}
+	today := time.Now().Format("2006-01-02")
+	if current.EffectiveFrom <= today {
+		return domain.PriceList{}, errors.New(fmt.Sprintf("price list %d is effective since %s, create a new list instead", id, current.EffectiveFrom))
+	}
+	list.Id, list.InsurerId = id, current.InsurerId
+	list, err = normalizeList(list)
+	if err != nil {
+		return domain.PriceList{}, err
+	}
+	if list.EffectiveFrom <= today {
+		return domain.PriceList{}, errors.New("effective_from of a pending list must be after today")
+	}
+	return s.r.UpdateList(list)
+}
+
+// GetDiscounts busca todos los descuentos
+func (s *service) GetDiscounts() ([]domain.DiscountRule, error) {
+	return s.r.GetDiscounts()
+}
+
+// GetDiscount busca un descuento por su id
+func (s *service) GetDiscount(id int) (domain.DiscountRule, error) {
+	return s.r.GetDiscount(id)
+}
+
+// CreateDiscount agrega un descuento
+func (s *service) CreateDiscount(rule domain.DiscountRule) (domain.DiscountRule, error) {
+	rule, err := normalizeDiscount(rule)
+	if err != nil {
+		return domain.DiscountRule{}, err
+	}
+	return s.r.CreateDiscount(rule)
+}
+
+// UpdateDiscount actualiza un descuento, para dejar de aplicarlo se desactiva o se le pone fecha de fin
+func (s *service) UpdateDiscount(id int, rule domain.DiscountRule) (domain.DiscountRule, error) {
+	rule.Id = id
+	rule, err := normalizeDiscount(rule)
+	if err != nil {
+		return domain.DiscountRule{}, err
+	}
+	return s.r.UpdateDiscount(rule)
+}
+
+// ListPrice devuelve el precio de lista de un procedimiento en una fecha para un financiador. Si su lista
+// no incluye el procedimiento usa la lista de particulares y si tampoco el arancel del catalogo.
+func (s *service) ListPrice(procedureCode string, date string, insurerId int) (float64, error) {
+	price, _, err := s.listPrice(procedureCode, date, insurerId)
+	return price, err
+}
+
+// Quote calcula el precio de un procedimiento para un paciente en una fecha, sin fecha calcula para hoy.
+// Los descuentos solo se aplican a los precios de particulares y no se acumulan, se toma el mayor.
+func (s *service) Quote(patientId int, procedureCode string, date string, insurerId int) (domain.PriceQuote, error) {
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	if _, err := time.Parse("2006-01-02", date); err != nil {
+		return domain.PriceQuote{}, errors.New("invalid date, must be in format: yyyy-mm-dd")
+	}
+	price, listId, err := s.listPrice(procedureCode, date, insurerId)
+	if err != nil {
+		return domain.PriceQuote{}, err
+	}
+	quote := domain.PriceQuote{
+		ProcedureCode: procedureCode,
+		Date:          date,
+		PatientId:     patientId,
+		InsurerId:     insurerId,
+		PriceListId:   listId,
+		ListPrice:     price,
+		Price:         price,
+	}
+	if insurerId != 0 {
+		return quote, nil
+	}
+	discounts, err := s.r.GetDiscounts()
+	if err != nil {
+		return domain.PriceQuote{}, err
+	}
+	familySize := -1
+	for _, d := range discounts {
+		if !d.Applies(procedureCode, date) {
+			continue
+		}
+		if d.Type == domain.DiscountFamily {
+			if patientId == 0 {
+				continue
+			}
+			if familySize < 0 {
+				family, err := s.families.GetFamily(patientId)
+				if err != nil {
+					return domain.PriceQuote{}, err
+				}
+				familySize = len(family.Members)
+			}
+			if familySize < d.MinFamilyMembers {
+				continue
+			}
+		}
+		amount := discountAmount(d, price)
+		if amount > quote.Discount {
+			quote.DiscountId, quote.DiscountName, quote.DiscountType, quote.DiscountValue = d.Id, d.Name, d.Type, d.Value
+			quote.Discount = amount
+		}
+	}
+	quote.Price = round(price - quote.Discount)
+	return quote, nil
+}
+
+// listPrice busca el precio en la lista del financiador, despues en la de particulares y por ultimo en el
+// catalogo, devuelve tambien la lista de la que salio o 0 si salio del catalogo
+func (s *service) listPrice(procedureCode string, date string, insurerId int) (float64, int, error) {
+	procedure, err := s.r.GetProcedure(procedureCode)
+	if err != nil {
+		return 0, 0, err
+	}
+	if date == "" {
+		date = time.Now().Format("2006-01-02")
+	}
+	price, listId, err := s.r.GetPrice(procedureCode, date, insurerId)
+	if err != nil {
+		return 0, 0, err
+	}
+	if listId == 0 && insurerId != 0 {
+		price, listId, err = s.r.GetPrice(procedureCode, date, 0)
+		if err != nil {
+			return 0, 0, err
+		}
+	}
+	if listId == 0 {
+		return procedure.Fee, 0, nil
+	}
+	return price.Amount, listId, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// discountAmount calcula el descuento sobre un precio, nunca mayor al precio
+func discountAmount(d domain.DiscountRule, price float64) float64 {
+	amount := d.Value
+	if d.Type != domain.DiscountFixed {
+		amount = price * d.Value / 100
+	}
+	return round(math.Min(amount, price))
+}
+
+// findPrice busca el precio de un procedimiento en una lista de precios
+func findPrice(prices []domain.Price, procedureCode string) (domain.Price, bool) {
+	for _, p := range prices {
+		if p.ProcedureCode == procedureCode {
+			return p, true
+		}
+	}
+	return domain.Price{}, false
+}
+
+// normalizeList valida el nombre, la vigencia y los precios de una lista
+func normalizeList(list domain.PriceList) (domain.PriceList, error) {
+	list.Name = strings.TrimSpace(list.Name)
+	if list.Name == "" {
+		return domain.PriceList{}, errors.New("name can't be empty")
+	}
+	if _, err := time.Parse("2006-01-02", list.EffectiveFrom); err != nil {
+		return domain.PriceList{}, errors.New("invalid effective_from, must be in format: yyyy-mm-dd")
+	}
+	if list.Increase < -100 {
+		return domain.PriceList{}, errors.New("increase can't be less than -100")
+	}
+	seen := map[string]bool{}
+	for i, p := range list.Prices {
+		if p.ProcedureCode == "" {
+			return domain.PriceList{}, errors.New(fmt.Sprintf("invalid price %d: procedure_code can't be empty", i+1))
+		}
+		if seen[p.ProcedureCode] {
+			return domain.PriceList{}, errors.New(fmt.Sprintf("procedure %s is repeated", p.ProcedureCode))
+		}
+		if p.Amount < 0 {
+			return domain.PriceList{}, errors.New(fmt.Sprintf("price of procedure %s can't be negative", p.ProcedureCode))
+		}
+		seen[p.ProcedureCode] = true
+		list.Prices[i].Amount = round(p.Amount)
+	}
+	return list, nil
+}
+
+// normalizeDiscount valida un descuento segun su tipo
+func normalizeDiscount(rule domain.DiscountRule) (domain.DiscountRule, error) {
+	rule.Name = strings.TrimSpace(rule.Name)
+	if rule.Name == "" {
+		return domain.DiscountRule{}, errors.New("name can't be empty")
+	}
+	switch rule.Type {
+	case domain.DiscountPercentage, domain.DiscountFamily:
+		if rule.Value <= 0 || rule.Value > 100 {
+			return domain.DiscountRule{}, errors.New("value of a percentage discount must be between 0 and 100")
+		}
+	case domain.DiscountFixed:
+		if rule.Value <= 0 {
+			return domain.DiscountRule{}, errors.New("value of a fixed discount must be positive")
+		}
+		rule.Value = round(rule.Value)
+	default:
+		return domain.DiscountRule{}, errors.New("invalid type, must be one of: percentage, fixed, family")
+	}
+	if rule.Type == domain.DiscountFamily && rule.MinFamilyMembers == 0 {
+		rule.MinFamilyMembers = 2
+	}
+	if rule.Type != domain.DiscountFamily {
+		rule.MinFamilyMembers = 0
+	}
+	for _, date := range []string{rule.ValidFrom, rule.ValidTo} {
+		if _, err := time.Parse("2006-01-02", date); date != "" && err != nil {
+			return domain.DiscountRule{}, errors.New("invalid valid_from or valid_to, must be in format: yyyy-mm-dd")
+		}
+	}
+	if rule.ValidFrom != "" && rule.ValidTo != "" && rule.ValidTo < rule.ValidFrom {
+		return domain.DiscountRule{}, errors.New("valid_to can't be before valid_from")
+	}
+	return rule, nil
+}
+
+// round redondea un monto a centavos
+func round(amount float64) float64 {
+	return math.Round(amount*100) / 100
+}
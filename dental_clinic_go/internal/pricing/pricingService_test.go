@@ -0,0 +1,137 @@
+package pricing
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"testing"
+)
+
+// fakePrice es un precio de una lista de un financiador, 0 son los particulares
+type fakePrice struct {
+	insurerId     int
+	procedureCode string
+}
+
+// fakeRepository es un PricingRepository en memoria, los metodos que no usa Quote quedan sin implementar
+type fakeRepository struct {
+	PricingRepository
+	fees      map[string]float64
+	prices    map[fakePrice]float64
+	discounts []domain.DiscountRule
+}
+
+func (r *fakeRepository) GetProcedure(code string) (domain.Procedure, error) {
+	fee, ok := r.fees[code]
+	if !ok {
+		return domain.Procedure{}, errors.New("procedure " + code + " not found")
+	}
+	return domain.Procedure{Code: code, Fee: fee}, nil
+}
+
+func (r *fakeRepository) GetPrice(procedureCode string, date string, insurerId int) (domain.Price, int, error) {
+	amount, ok := r.prices[fakePrice{insurerId, procedureCode}]
+	if !ok {
+		return domain.Price{}, 0, nil
+	}
+	return domain.Price{ProcedureCode: procedureCode, Amount: amount}, 100 + insurerId, nil
+}
+
+func (r *fakeRepository) GetDiscounts() ([]domain.DiscountRule, error) {
+	return r.discounts, nil
+}
+
+// fakeFamilies devuelve familias con la cantidad de integrantes de cada paciente
+type fakeFamilies map[int]int
+
+func (f fakeFamilies) GetFamily(patientId int) (domain.Family, error) {
+	return domain.Family{PatientId: patientId, Members: make([]domain.FamilyMember, f[patientId])}, nil
+}
+
+func TestQuote(t *testing.T) {
+	percent := func(id int, value float64) domain.DiscountRule {
+		return domain.DiscountRule{Id: id, Name: "percent", Type: domain.DiscountPercentage, Value: value, Active: true}
+	}
+	fixed := func(id int, value float64) domain.DiscountRule {
+		return domain.DiscountRule{Id: id, Name: "fixed", Type: domain.DiscountFixed, Value: value, Active: true}
+	}
+	family := func(id int, value float64, members int) domain.DiscountRule {
+		return domain.DiscountRule{Id: id, Name: "family", Type: domain.DiscountFamily, Value: value, MinFamilyMembers: members, Active: true}
+	}
+	expired := percent(9, 50)
+	expired.ValidTo = "2026-01-31"
+	inactive := percent(8, 50)
+	inactive.Active = false
+	otherProcedure := percent(7, 50)
+	otherProcedure.ProcedureCode = "02.01"
+	future := percent(6, 50)
+	future.ValidFrom = "2026-12-01"
+
+	tests := []struct {
+		name         string
+		patientId    int
+		code         string
+		date         string
+		insurerId    int
+		discounts    []domain.DiscountRule
+		wantList     float64
+		wantListId   int
+		wantDiscount float64
+		wantId       int
+		wantPrice    float64
+		err          string
+	}{
+		{name: "private list", patientId: 1, code: "01.01", wantList: 100, wantListId: 100, wantPrice: 100},
+		{name: "catalog fee without list", patientId: 1, code: "01.02", wantList: 80, wantPrice: 80},
+		{name: "insurer list", patientId: 1, code: "01.01", insurerId: 5, wantList: 70, wantListId: 105, wantPrice: 70},
+		{name: "insurer without the procedure falls back to private list", patientId: 1, code: "01.03", insurerId: 5, wantList: 60, wantListId: 100, wantPrice: 60},
+		{name: "insurer prices get no discounts", patientId: 1, code: "01.01", insurerId: 5, discounts: []domain.DiscountRule{percent(1, 10)}, wantList: 70, wantListId: 105, wantPrice: 70},
+		{name: "percentage discount", patientId: 1, code: "01.01", discounts: []domain.DiscountRule{percent(1, 15)}, wantList: 100, wantListId: 100, wantDiscount: 15, wantId: 1, wantPrice: 85},
+		{name: "percentage discount rounds to cents", patientId: 1, code: "01.04", discounts: []domain.DiscountRule{percent(1, 10)}, wantList: 33.33, wantListId: 100, wantDiscount: 3.33, wantId: 1, wantPrice: 30},
+		{name: "fixed discount capped at the price", patientId: 1, code: "01.02", discounts: []domain.DiscountRule{fixed(2, 500)}, wantList: 80, wantDiscount: 80, wantId: 2, wantPrice: 0},
+		{name: "best discount wins, they don't add up", patientId: 1, code: "01.01", discounts: []domain.DiscountRule{percent(1, 10), fixed(2, 25), percent(3, 20)}, wantList: 100, wantListId: 100, wantDiscount: 25, wantId: 2, wantPrice: 75},
+		{name: "first of equal discounts wins", patientId: 1, code: "01.01", discounts: []domain.DiscountRule{percent(1, 10), fixed(2, 10)}, wantList: 100, wantListId: 100, wantDiscount: 10, wantId: 1, wantPrice: 90},
+		{name: "expired, future, inactive and other procedure discounts are ignored", patientId: 1, code: "01.01", discounts: []domain.DiscountRule{expired, future, inactive, otherProcedure}, wantList: 100, wantListId: 100, wantPrice: 100},
+		{name: "discount valid until the quote date", patientId: 1, code: "01.01", date: "2026-01-31", discounts: []domain.DiscountRule{expired}, wantList: 100, wantListId: 100, wantDiscount: 50, wantId: 9, wantPrice: 50},
+		{name: "family discount with enough members", patientId: 2, code: "01.01", discounts: []domain.DiscountRule{family(4, 30, 3)}, wantList: 100, wantListId: 100, wantDiscount: 30, wantId: 4, wantPrice: 70},
+		{name: "family discount with too few members", patientId: 1, code: "01.01", discounts: []domain.DiscountRule{family(4, 30, 3)}, wantList: 100, wantListId: 100, wantPrice: 100},
+		{name: "family discount without patient", code: "01.01", discounts: []domain.DiscountRule{family(4, 30, 1)}, wantList: 100, wantListId: 100, wantPrice: 100},
+		{name: "unknown procedure", patientId: 1, code: "99.99", err: "procedure 99.99 not found"},
+		{name: "invalid date", patientId: 1, code: "01.01", date: "19/10/2026", err: "invalid date, must be in format: yyyy-mm-dd"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{
+				fees: map[string]float64{"01.01": 90, "01.02": 80, "01.03": 50, "01.04": 30},
+				prices: map[fakePrice]float64{
+					{0, "01.01"}: 100, {0, "01.03"}: 60, {0, "01.04"}: 33.33,
+					{5, "01.01"}: 70,
+				},
+				discounts: tt.discounts,
+			}
+			s := NewPricingService(r, fakeFamilies{1: 2, 2: 3})
+			date := tt.date
+			if date == "" {
+				date = "2026-10-19"
+			}
+			quote, err := s.Quote(tt.patientId, tt.code, date, tt.insurerId)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if quote.ListPrice != tt.wantList || quote.PriceListId != tt.wantListId {
+				t.Errorf("expected list price %.2f from list %d, got %.2f from list %d", tt.wantList, tt.wantListId, quote.ListPrice, quote.PriceListId)
+			}
+			if quote.Discount != tt.wantDiscount || quote.DiscountId != tt.wantId {
+				t.Errorf("expected discount %.2f from rule %d, got %.2f from rule %d", tt.wantDiscount, tt.wantId, quote.Discount, quote.DiscountId)
+			}
+			if quote.Price != tt.wantPrice {
+				t.Errorf("expected price %.2f, got %.2f", tt.wantPrice, quote.Price)
+			}
+		})
+	}
+}
@@ -0,0 +1,92 @@
+package procedure
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type ProcedureRepository interface {
+	GetAll(category string) ([]domain.Procedure, error)
+	GetByCode(code string) (domain.Procedure, error)
+	Create(p domain.Procedure) (domain.Procedure, error)
+	Update(p domain.Procedure) (domain.Procedure, error)
+	Delete(code string) error
+	Import(procedures []domain.Procedure, keepSpecialty bool) (domain.ProcedureImport, error)
+}
+
+type procedureRepository struct {
+	storage store.ProcedureStore
+}
+
+// NewProcedureRepository crea un nuevo repositorio
+func NewProcedureRepository(storage store.ProcedureStore) ProcedureRepository {
+	return &procedureRepository{storage}
+}
+
+// GetAll busca los procedimientos del catalogo
+func (r *procedureRepository) GetAll(category string) ([]domain.Procedure, error) {
+	procedures, err := r.storage.GetAll(category)
+	if err != nil {
+		return []domain.Procedure{}, errors.New("error getting procedures")
+	}
+	return procedures, nil
+}
+
+// GetByCode busca un procedimiento por su codigo
+func (r *procedureRepository) GetByCode(code string) (domain.Procedure, error) {
+	procedure, err := r.storage.GetByCode(code)
+	if err != nil {
+		return domain.Procedure{}, errors.New(fmt.Sprintf("procedure %s not found", code))
+	}
+	return procedure, nil
+}
+
+// Create agrega un nuevo procedimiento
+func (r *procedureRepository) Create(p domain.Procedure) (domain.Procedure, error) {
+	_, err := r.storage.GetByCode(p.Code)
+	if err == nil {
+		return domain.Procedure{}, errors.New("code already exists")
+	}
+	procedure, err := r.storage.Create(p)
+	if err != nil {
+		return domain.Procedure{}, errors.New("error creating procedure")
+	}
+	return procedure, nil
+}
+
+// Update actualiza un procedimiento
+func (r *procedureRepository) Update(p domain.Procedure) (domain.Procedure, error) {
+	_, err := r.storage.GetByCode(p.Code)
+	if err != nil {
+		return domain.Procedure{}, errors.New(fmt.Sprintf("procedure %s not found", p.Code))
+	}
+	procedure, err := r.storage.Update(p)
+	if err != nil {
+		return domain.Procedure{}, errors.New("error updating procedure")
+	}
+	return procedure, nil
+}
+
+// Delete elimina un procedimiento que no este referenciado
+func (r *procedureRepository) Delete(code string) error {
+	_, err := r.storage.GetByCode(code)
+	if err != nil {
+		return errors.New(fmt.Sprintf("procedure %s not found", code))
+	}
+	err = r.storage.Delete(code)
+	if err != nil {
+		return errors.New(fmt.Sprintf("procedure %s is in use and can't be deleted", code))
+	}
+	return nil
+}
+
+// Import guarda los procedimientos de una importacion, si alguno falla no se guarda ninguno
+func (r *procedureRepository) Import(procedures []domain.Procedure, keepSpecialty bool) (domain.ProcedureImport, error) {
+	result, err := r.storage.Import(procedures, keepSpecialty)
+	if err != nil {
+		return domain.ProcedureImport{}, errors.New("error importing procedures, none were saved")
+	}
+	return result, nil
+}
@@ -0,0 +1,228 @@
+package procedure
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/fdi"
+	"encoding/csv"
+	"errors"
+	"fmt"
+	"io"
+	"strconv"
+	"strings"
+)
+
+// defaultDuration es la duracion en minutos de los procedimientos que no la indican
+const defaultDuration = 30
+
+// csvColumns son las columnas que espera Import
+var csvColumns = []string{"code", "name", "category", "default_fee", "default_duration", "teeth", "surfaces", "specialty"}
+
+type Service interface {
+	GetAll(category string) ([]domain.Procedure, error)
+	GetByCode(code string) (domain.Procedure, error)
+	Create(p domain.Procedure) (domain.Procedure, error)
+	Update(code string, p domain.Procedure) (domain.Procedure, error)
+	Delete(code string) error
+	Import(r io.Reader) (domain.ProcedureImport, error)
+	Validate(code string, tooth int, surfaces string) (domain.Procedure, error)
+}
+
+type service struct {
+	r ProcedureRepository
+}
+
+// NewProcedureService crea un nuevo servicio
+func NewProcedureService(r ProcedureRepository) Service {
+	return &service{r}
+}
+
+// GetAll busca los procedimientos del catalogo
+func (s *service) GetAll(category string) ([]domain.Procedure, error) {
+	return s.r.GetAll(category)
+}
+
+// GetByCode busca un procedimiento por su codigo
+func (s *service) GetByCode(code string) (domain.Procedure, error) {
+	return s.r.GetByCode(code)
+}
+
+// Create valida y agrega un nuevo procedimiento
+func (s *service) Create(p domain.Procedure) (domain.Procedure, error) {
+	p, err := normalize(p)
+	if err != nil {
+		return domain.Procedure{}, err
+	}
+	return s.r.Create(p)
+}
+
+// Update valida y reemplaza un procedimiento
+func (s *service) Update(code string, p domain.Procedure) (domain.Procedure, error) {
+	p.Code = code
+	p, err := normalize(p)
+	if err != nil {
+		return domain.Procedure{}, err
+	}
+	return s.r.Update(p)
+}
+
+// Delete elimina un procedimiento
+func (s *service) Delete(code string) error {
+	return s.r.Delete(code)
+}
+
+// Import carga el catalogo desde un CSV con encabezado, crea los codigos nuevos y actualiza los existentes.
+// Primero valida todas las filas y despues las guarda juntas, si alguna es invalida o falla no se guarda ninguna. Sin la columna specialty se
+// conserva la especialidad de los codigos existentes.
+func (s *service) Import(r io.Reader) (domain.ProcedureImport, error) {
+	reader := csv.NewReader(r)
+	reader.TrimLeadingSpace = true
+	header, err := reader.Read()
+	if err != nil {
+		return domain.ProcedureImport{}, errors.New("invalid csv, header not found")
+	}
+	columns := map[string]int{}
+	for i, name := range header {
+		columns[strings.ToLower(strings.TrimSpace(name))] = i
+	}
+	for _, name := range csvColumns[:3] {
+		if _, ok := columns[name]; !ok {
+			return domain.ProcedureImport{}, errors.New(fmt.Sprintf("invalid csv, column %s not found", name))
+		}
+	}
+	procedures := []domain.Procedure{}
+	for line := 2; ; line++ {
+		record, err := reader.Read()
+		if err == io.EOF {
+			break
+		}
+		if err != nil {
+			return domain.ProcedureImport{}, errors.New(fmt.Sprintf("invalid csv at line %d: %s", line, err.Error()))
+		}
+		value := func(name string) string {
+			i, ok := columns[name]
+			if !ok || i >= len(record) {
+				return ""
+			}
+			return strings.TrimSpace(record[i])
+		}
+		p := domain.Procedure{
+			Code:      value("code"),
+			Name:      value("name"),
+			Category:  value("category"),
+			Teeth:     value("teeth"),
+			Surfaces:  value("surfaces"),
+			Specialty: value("specialty"),
+		}
+		if fee := value("default_fee"); fee != "" {
+			p.Fee, err = strconv.ParseFloat(fee, 64)
+			if err != nil {
+				return domain.ProcedureImport{}, errors.New(fmt.Sprintf("invalid default_fee at line %d", line))
+			}
+		}
+		if duration := value("default_duration"); duration != "" {
+			p.Duration, err = strconv.Atoi(duration)
+			if err != nil {
+				return domain.ProcedureImport{}, errors.New(fmt.Sprintf("invalid default_duration at line %d", line))
+			}
+		}
+		p, err = normalize(p)
+		if err != nil {
+			return domain.ProcedureImport{}, errors.New(fmt.Sprintf("invalid procedure at line %d: %s", line, err.Error()))
+		}
+		procedures = append(procedures, p)
+	}
+	// un csv sin la columna specialty no borra las especialidades cargadas
+	_, hasSpecialty := columns["specialty"]
+	return s.r.Import(procedures, !hasSpecialty)
+}
+
+// Validate busca un procedimiento y verifica que se pueda aplicar a la pieza y las caras indicadas
+func (s *service) Validate(code string, tooth int, surfaces string) (domain.Procedure, error) {
+	p, err := s.r.GetByCode(code)
+	if err != nil {
+		return domain.Procedure{}, err
+	}
+	if p.Teeth != "" {
+		if tooth == 0 {
+			return domain.Procedure{}, errors.New(fmt.Sprintf("procedure %s needs a tooth", code))
+		}
+		if !appliesToTooth(p.Teeth, tooth) {
+			return domain.Procedure{}, errors.New(fmt.Sprintf("procedure %s can't be applied to tooth %d", code, tooth))
+		}
+	}
+	for _, surface := range strings.ToUpper(surfaces) {
+		if !strings.ContainsRune(p.Surfaces, surface) {
+			return domain.Procedure{}, errors.New(fmt.Sprintf("procedure %s can't be applied to surface %c", code, surface))
+		}
+	}
+	return p, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// normalize valida un procedimiento y completa sus valores por defecto
+func normalize(p domain.Procedure) (domain.Procedure, error) {
+	p.Code = strings.TrimSpace(p.Code)
+	switch {
+	case p.Code == "":
+		return domain.Procedure{}, errors.New("code can't be empty")
+	case len(p.Code) > 20:
+		return domain.Procedure{}, errors.New("code can't be longer than 20 characters")
+	case p.Name == "":
+		return domain.Procedure{}, errors.New("name can't be empty")
+	case p.Category == "":
+		return domain.Procedure{}, errors.New("category can't be empty")
+	case p.Fee < 0:
+		return domain.Procedure{}, errors.New("default_fee can't be negative")
+	case p.Duration < 0:
+		return domain.Procedure{}, errors.New("default_duration can't be negative")
+	}
+	if p.Duration == 0 {
+		p.Duration = defaultDuration
+	}
+	teeth, err := normalizeTeeth(p.Teeth)
+	if err != nil {
+		return domain.Procedure{}, err
+	}
+	p.Teeth = teeth
+	surfaces, err := fdi.NormalizeSurfaces(p.Surfaces)
+	if err != nil {
+		return domain.Procedure{}, err
+	}
+	p.Surfaces = surfaces
+	p.Specialty = strings.ToLower(strings.TrimSpace(p.Specialty))
+	if p.Specialty != "" && !domain.ValidSpecialty(p.Specialty) {
+		return domain.Procedure{}, errors.New(fmt.Sprintf("invalid specialty %q, must be one of %s", p.Specialty, strings.Join(domain.Specialties, ", ")))
+	}
+	return p, nil
+}
+
+// normalizeTeeth valida las piezas aplicables de un procedimiento
+func normalizeTeeth(teeth string) (string, error) {
+	teeth = strings.ToLower(strings.TrimSpace(teeth))
+	if teeth == "" || teeth == domain.DentitionPermanent || teeth == domain.DentitionDeciduous {
+		return teeth, nil
+	}
+	list := []string{}
+	for _, value := range strings.Split(teeth, ",") {
+		tooth, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil || !fdi.ValidTooth(tooth) {
+			return "", errors.New(fmt.Sprintf("invalid teeth %q, must be FDI tooth numbers separated by comma, permanent or deciduous", value))
+		}
+		list = append(list, strconv.Itoa(tooth))
+	}
+	return strings.Join(list, ","), nil
+}
+
+// appliesToTooth indica si una pieza esta dentro de las piezas aplicables
+func appliesToTooth(teeth string, tooth int) bool {
+	if teeth == domain.DentitionPermanent || teeth == domain.DentitionDeciduous {
+		return fdi.Dentition(tooth) == teeth
+	}
+	for _, value := range strings.Split(teeth, ",") {
+		if value == strconv.Itoa(tooth) {
+			return true
+		}
+	}
+	return false
+}
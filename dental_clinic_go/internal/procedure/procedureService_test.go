@@ -0,0 +1,60 @@
+package procedure
+
+import (
+	"dental_clinic_go/internal/domain"
+	"strings"
+	"testing"
+)
+
+// fakeRepository guarda lo que recibe Import, los metodos que no usa la importacion quedan sin implementar
+type fakeRepository struct {
+	ProcedureRepository
+	imported      []domain.Procedure
+	keepSpecialty bool
+	calls         int
+}
+
+func (r *fakeRepository) Import(procedures []domain.Procedure, keepSpecialty bool) (domain.ProcedureImport, error) {
+	r.calls++
+	r.imported = procedures
+	r.keepSpecialty = keepSpecialty
+	return domain.ProcedureImport{Created: len(procedures)}, nil
+}
+
+func TestImport(t *testing.T) {
+	tests := []struct {
+		name          string
+		csv           string
+		imported      int
+		keepSpecialty bool
+		err           string
+	}{
+		{name: "all rows", csv: "code,name,category,default_fee,specialty\n01.01,Consulta,consultas,100,\n03.01,Conducto,endodoncia,500,endodontics\n", imported: 2},
+		{name: "without specialty keeps the loaded ones", csv: "code,name,category\n01.01,Consulta,consultas\n", imported: 1, keepSpecialty: true},
+		{name: "invalid last row saves nothing", csv: "code,name,category,default_fee\n01.01,Consulta,consultas,100\n01.02,Control,consultas,-5\n", err: "invalid procedure at line 3: default_fee can't be negative"},
+		{name: "invalid fee", csv: "code,name,category,default_fee\n01.01,Consulta,consultas,cien\n", err: "invalid default_fee at line 2"},
+		{name: "invalid teeth", csv: "code,name,category,teeth\n01.01,Consulta,consultas,19\n", err: "invalid procedure at line 2: invalid teeth \"19\", must be FDI tooth numbers separated by comma, permanent or deciduous"},
+		{name: "missing column", csv: "code,name\n01.01,Consulta\n", err: "invalid csv, column category not found"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := &fakeRepository{}
+			result, err := NewProcedureService(r).Import(strings.NewReader(tt.csv))
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				if r.calls != 0 {
+					t.Fatalf("expected nothing to be saved, got %+v", r.imported)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if r.calls != 1 || len(r.imported) != tt.imported || result.Created != tt.imported || r.keepSpecialty != tt.keepSpecialty {
+				t.Fatalf("expected %d procedures saved at once keeping specialties %v, got %d calls with %+v", tt.imported, tt.keepSpecialty, r.calls, r.imported)
+			}
+		})
+	}
+}
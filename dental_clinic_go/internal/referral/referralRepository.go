@@ -0,0 +1,108 @@
+package referral
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type ReferralRepository interface {
+	GetByID(id int) (domain.Referral, error)
+	GetByPatient(patientId int) ([]domain.Referral, error)
+	GetInbox(dentistId int, status string) ([]domain.Referral, error)
+	GetByAppointment(appointmentId int) ([]domain.Referral, error)
+	GetDentist(id int) (domain.Dentist, error)
+	Create(referral domain.Referral) (domain.Referral, error)
+	Update(referral domain.Referral) (domain.Referral, error)
+}
+
+type referralRepository struct {
+	storage      store.ReferralStore
+	patientStore store.PatientStore
+	dentistStore store.DentistStore
+}
+
+// NewReferralRepository crea un nuevo repositorio
+func NewReferralRepository(storage store.ReferralStore, patientStore store.PatientStore, dentistStore store.DentistStore) ReferralRepository {
+	return &referralRepository{storage, patientStore, dentistStore}
+}
+
+// GetByID busca una derivacion por su id
+func (r *referralRepository) GetByID(id int) (domain.Referral, error) {
+	referral, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.Referral{}, errors.New(fmt.Sprintf("referral %d not found", id))
+	}
+	return referral, nil
+}
+
+// GetByPatient busca las derivaciones de un paciente
+func (r *referralRepository) GetByPatient(patientId int) ([]domain.Referral, error) {
+	_, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return []domain.Referral{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	referrals, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.Referral{}, errors.New(fmt.Sprintf("referrals of patient %d not found", patientId))
+	}
+	return referrals, nil
+}
+
+// GetInbox busca las derivaciones recibidas por un dentista en un estado
+func (r *referralRepository) GetInbox(dentistId int, status string) ([]domain.Referral, error) {
+	_, err := r.dentistStore.GetByID(dentistId)
+	if err != nil {
+		return []domain.Referral{}, errors.New(fmt.Sprintf("dentist %d not found", dentistId))
+	}
+	referrals, err := r.storage.GetInbox(dentistId, status)
+	if err != nil {
+		return []domain.Referral{}, errors.New(fmt.Sprintf("referrals of dentist %d not found", dentistId))
+	}
+	return referrals, nil
+}
+
+// GetByAppointment busca las derivaciones agendadas en un turno
+func (r *referralRepository) GetByAppointment(appointmentId int) ([]domain.Referral, error) {
+	referrals, err := r.storage.GetByAppointment(appointmentId)
+	if err != nil {
+		return []domain.Referral{}, errors.New(fmt.Sprintf("referrals of appointment %d not found", appointmentId))
+	}
+	return referrals, nil
+}
+
+// GetDentist busca un dentista con sus especialidades
+func (r *referralRepository) GetDentist(id int) (domain.Dentist, error) {
+	dentist, err := r.dentistStore.GetByID(id)
+	if err != nil {
+		return domain.Dentist{}, errors.New(fmt.Sprintf("dentist %d not found", id))
+	}
+	return dentist, nil
+}
+
+// Create agrega una derivacion verificando que existan el paciente y los dentistas
+func (r *referralRepository) Create(referral domain.Referral) (domain.Referral, error) {
+	_, err := r.patientStore.GetByID(referral.PatientId)
+	if err != nil {
+		return domain.Referral{}, errors.New(fmt.Sprintf("patient %d not found", referral.PatientId))
+	}
+	_, err = r.GetDentist(referral.FromDentist.Id)
+	if err != nil {
+		return domain.Referral{}, err
+	}
+	created, err := r.storage.Create(referral)
+	if err != nil {
+		return domain.Referral{}, errors.New("error creating referral")
+	}
+	return r.GetByID(created.Id)
+}
+
+// Update actualiza una derivacion
+func (r *referralRepository) Update(referral domain.Referral) (domain.Referral, error) {
+	err := r.storage.Update(referral)
+	if err != nil {
+		return domain.Referral{}, errors.New(fmt.Sprintf("error updating referral %d", referral.Id))
+	}
+	return r.GetByID(referral.Id)
+}
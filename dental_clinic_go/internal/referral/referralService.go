@@ -0,0 +1,241 @@
+package referral
+
+import (
+	"dental_clinic_go/internal/appointment"
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+)
+
+// transitions indica a que estados puede pasar una derivacion desde cada estado, a scheduled
+// solo se pasa agendando el turno con Schedule
+var transitions = map[string][]string{
+	domain.ReferralPending:  {domain.ReferralAccepted, domain.ReferralRejected, domain.ReferralCancelled},
+	domain.ReferralAccepted: {domain.ReferralCancelled},
+}
+
+// openStatuses son los estados de las derivaciones que esperan una accion del dentista que las recibe
+var openStatuses = []string{domain.ReferralPending, domain.ReferralAccepted}
+
+var statuses = []string{domain.ReferralPending, domain.ReferralAccepted, domain.ReferralRejected, domain.ReferralScheduled, domain.ReferralCancelled}
+
+var urgencies = []string{domain.UrgencyRoutine, domain.UrgencyPriority, domain.UrgencyUrgent}
+
+type Service interface {
+	GetByID(id int) (domain.Referral, error)
+	GetByPatient(patientId int) ([]domain.Referral, error)
+	GetInbox(dentistId int, status string) ([]domain.Referral, error)
+	Create(referral domain.Referral, dentistId int) (domain.Referral, error)
+	UpdateStatus(id int, status domain.ReferralStatus, dentistId int) (domain.Referral, error)
+	Schedule(id int, request domain.ReferralAppointment) (domain.Appointment, error)
+	AppointmentStatusChanged(before domain.Appointment, after domain.Appointment) error
+}
+
+type service struct {
+	r ReferralRepository
+	a appointment.AppointmentService
+}
+
+// NewReferralService crea un nuevo servicio
+func NewReferralService(r ReferralRepository, a appointment.AppointmentService) Service {
+	return &service{r, a}
+}
+
+// GetByID busca una derivacion por su id
+func (s *service) GetByID(id int) (domain.Referral, error) {
+	return s.r.GetByID(id)
+}
+
+// GetByPatient busca las derivaciones de un paciente
+func (s *service) GetByPatient(patientId int) ([]domain.Referral, error) {
+	return s.r.GetByPatient(patientId)
+}
+
+// GetInbox busca las derivaciones recibidas por un dentista, sin estado devuelve las pendientes
+// y las aceptadas sin agendar, primero las mas urgentes
+func (s *service) GetInbox(dentistId int, status string) ([]domain.Referral, error) {
+	inbox := openStatuses
+	if status != "" {
+		if !contains(statuses, status) {
+			return []domain.Referral{}, errors.New("invalid status, must be one of: pending, accepted, rejected, scheduled, cancelled")
+		}
+		inbox = []string{status}
+	}
+	referrals := []domain.Referral{}
+	for _, st := range inbox {
+		found, err := s.r.GetInbox(dentistId, st)
+		if err != nil {
+			return []domain.Referral{}, err
+		}
+		referrals = append(referrals, found...)
+	}
+	return referrals, nil
+}
+
+// Create valida y agrega una derivacion del dentista del usuario actual a un dentista o a una
+// especialidad, las derivaciones nuevas siempre empiezan pendientes
+func (s *service) Create(referral domain.Referral, dentistId int) (domain.Referral, error) {
+	referral.FromDentist = domain.Dentist{Id: dentistId}
+	if referral.Urgency == "" {
+		referral.Urgency = domain.UrgencyRoutine
+	}
+	switch {
+	case referral.PatientId == 0:
+		return domain.Referral{}, errors.New("patient_id can't be empty")
+	case referral.FromDentist.Id == 0:
+		return domain.Referral{}, errors.New("only users linked to a dentist can refer patients")
+	case referral.ToDentist.Id == 0 && referral.Specialty == "":
+		return domain.Referral{}, errors.New("to_dentist.id or specialty can't be empty")
+	case referral.ToDentist.Id == referral.FromDentist.Id:
+		return domain.Referral{}, errors.New("from_dentist and to_dentist can't be the same")
+	case referral.Reason == "":
+		return domain.Referral{}, errors.New("reason can't be empty")
+	case !contains(urgencies, referral.Urgency):
+		return domain.Referral{}, errors.New("invalid urgency, must be one of: routine, priority, urgent")
+	case referral.Specialty != "" && !domain.ValidSpecialty(referral.Specialty):
+		return domain.Referral{}, errors.New(fmt.Sprintf("invalid specialty %s", referral.Specialty))
+	}
+	if referral.ToDentist.Id != 0 {
+		dentist, err := s.r.GetDentist(referral.ToDentist.Id)
+		if err != nil {
+			return domain.Referral{}, err
+		}
+		if referral.Specialty != "" && !dentist.HasSpecialty(referral.Specialty) {
+			return domain.Referral{}, errors.New(fmt.Sprintf("dentist %d doesn't have specialty %s", dentist.Id, referral.Specialty))
+		}
+	}
+	referral.Status = domain.ReferralPending
+	referral.Response = ""
+	return s.r.Create(referral)
+}
+
+// UpdateStatus acepta, rechaza o cancela una derivacion validando la transicion. Solo la acepta o la
+// rechaza el dentista del usuario actual si es quien la recibe, al aceptar una derivacion hecha a una
+// especialidad queda asignada a el.
+func (s *service) UpdateStatus(id int, status domain.ReferralStatus, dentistId int) (domain.Referral, error) {
+	referral, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.Referral{}, err
+	}
+	if referral.Status == status.Status {
+		return referral, nil
+	}
+	if !contains(transitions[referral.Status], status.Status) {
+		return domain.Referral{}, errors.New(fmt.Sprintf("referral %d can't change from %s to %s", id, referral.Status, status.Status))
+	}
+	if status.Status == domain.ReferralAccepted || status.Status == domain.ReferralRejected {
+		receiver, err := s.receivingDentist(referral, dentistId)
+		if err != nil {
+			return domain.Referral{}, err
+		}
+		if status.Status == domain.ReferralAccepted {
+			referral.ToDentist = receiver
+		}
+	}
+	referral.Status = status.Status
+	if status.Response != "" {
+		referral.Response = status.Response
+	}
+	return s.r.Update(referral)
+}
+
+// Schedule crea un turno con el paciente y el dentista que acepto la derivacion. Si no se puede marcar la
+// derivacion como agendada elimina el turno, para no dejar un turno suelto.
+func (s *service) Schedule(id int, request domain.ReferralAppointment) (domain.Appointment, error) {
+	referral, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	if referral.Status != domain.ReferralAccepted {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("referral %d is %s, only accepted referrals can be scheduled", id, referral.Status))
+	}
+	if _, err := time.Parse("2006-01-02", request.Date); err != nil {
+		return domain.Appointment{}, errors.New("invalid date, must be in format: yyyy-mm-dd")
+	}
+	if _, err := time.Parse("15:04:05", request.Hour); err != nil {
+		return domain.Appointment{}, errors.New("invalid hour, must be in format: hh:mm:ss")
+	}
+	description := ""
+	if request.ProcedureCode == "" {
+		description = referral.Reason
+	}
+	a, err := s.a.Create(domain.Appointment{
+		Date:          request.Date,
+		Hour:          request.Hour,
+		Description:   description,
+		ProcedureCode: request.ProcedureCode,
+		Patient:       domain.Patient{Id: referral.PatientId},
+		Dentist:       referral.ToDentist,
+	})
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	referral.Status = domain.ReferralScheduled
+	referral.AppointmentId = a.Id
+	_, err = s.r.Update(referral)
+	if err != nil {
+		// sin el turno la derivacion sigue aceptada y se puede volver a agendar
+		if err := s.a.Delete(a.Id); err != nil {
+			log.Printf("error deleting appointment %d of referral %d: %s", a.Id, id, err.Error())
+		}
+		return domain.Appointment{}, err
+	}
+	return s.a.GetByID(a.Id)
+}
+
+// AppointmentStatusChanged vuelve a dejar aceptadas las derivaciones de un turno cancelado o
+// ausente para que se puedan agendar de nuevo
+func (s *service) AppointmentStatusChanged(before domain.Appointment, after domain.Appointment) error {
+	if after.Status != domain.AppointmentCancelled && after.Status != domain.AppointmentNoShow {
+		return nil
+	}
+	referrals, err := s.r.GetByAppointment(after.Id)
+	if err != nil {
+		return err
+	}
+	for _, referral := range referrals {
+		referral.Status = domain.ReferralAccepted
+		referral.AppointmentId = 0
+		_, err := s.r.Update(referral)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// receivingDentist devuelve el dentista del usuario actual si puede responder la derivacion: si estaba
+// dirigida a un dentista tiene que ser el mismo y si estaba dirigida a una especialidad tiene que tenerla
+func (s *service) receivingDentist(referral domain.Referral, dentistId int) (domain.Dentist, error) {
+	if dentistId == 0 {
+		return domain.Dentist{}, errors.New("only users linked to a dentist can accept or reject referrals")
+	}
+	if referral.ToDentist.Id != 0 {
+		if dentistId != referral.ToDentist.Id {
+			return domain.Dentist{}, errors.New(fmt.Sprintf("referral %d is addressed to dentist %d", referral.Id, referral.ToDentist.Id))
+		}
+		return referral.ToDentist, nil
+	}
+	dentist, err := s.r.GetDentist(dentistId)
+	if err != nil {
+		return domain.Dentist{}, err
+	}
+	if !dentist.HasSpecialty(referral.Specialty) {
+		return domain.Dentist{}, errors.New(fmt.Sprintf("dentist %d doesn't have specialty %s", dentistId, referral.Specialty))
+	}
+	return dentist, nil
+}
+
+// contains indica si un valor esta en la lista
+func contains(values []string, value string) bool {
+	for _, v := range values {
+		if v == value {
+			return true
+		}
+	}
+	return false
+}
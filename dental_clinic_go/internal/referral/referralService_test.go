@@ -0,0 +1,209 @@
+package referral
+
+import (
+	"dental_clinic_go/internal/appointment"
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeRepository es un ReferralRepository en memoria, failUpdate simula un error al guardar
+type fakeRepository struct {
+	referrals  map[int]domain.Referral
+	dentists   map[int]domain.Dentist
+	failUpdate bool
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.Referral, error) {
+	referral, ok := r.referrals[id]
+	if !ok {
+		return domain.Referral{}, errors.New(fmt.Sprintf("referral %d not found", id))
+	}
+	return referral, nil
+}
+
+func (r *fakeRepository) GetByPatient(patientId int) ([]domain.Referral, error) {
+	return []domain.Referral{}, nil
+}
+
+func (r *fakeRepository) GetInbox(dentistId int, status string) ([]domain.Referral, error) {
+	return []domain.Referral{}, nil
+}
+
+func (r *fakeRepository) GetByAppointment(appointmentId int) ([]domain.Referral, error) {
+	return []domain.Referral{}, nil
+}
+
+func (r *fakeRepository) GetDentist(id int) (domain.Dentist, error) {
+	dentist, ok := r.dentists[id]
+	if !ok {
+		return domain.Dentist{}, errors.New(fmt.Sprintf("dentist %d not found", id))
+	}
+	return dentist, nil
+}
+
+func (r *fakeRepository) Create(referral domain.Referral) (domain.Referral, error) {
+	referral.Id = len(r.referrals) + 1
+	r.referrals[referral.Id] = referral
+	return referral, nil
+}
+
+func (r *fakeRepository) Update(referral domain.Referral) (domain.Referral, error) {
+	if r.failUpdate {
+		return domain.Referral{}, errors.New(fmt.Sprintf("error updating referral %d", referral.Id))
+	}
+	r.referrals[referral.Id] = referral
+	return referral, nil
+}
+
+// fakeAppointments guarda los turnos creados y eliminados
+type fakeAppointments struct {
+	appointment.AppointmentService
+	created []domain.Appointment
+	deleted []int
+}
+
+func (a *fakeAppointments) Create(p domain.Appointment) (domain.Appointment, error) {
+	p.Id = len(a.created) + 1
+	a.created = append(a.created, p)
+	return p, nil
+}
+
+func (a *fakeAppointments) Delete(id int) error {
+	a.deleted = append(a.deleted, id)
+	return nil
+}
+
+func (a *fakeAppointments) GetByID(id int) (domain.Appointment, error) {
+	return a.created[id-1], nil
+}
+
+func newFakeRepository() *fakeRepository {
+	return &fakeRepository{
+		referrals: map[int]domain.Referral{
+			1: {Id: 1, PatientId: 1, FromDentist: domain.Dentist{Id: 1}, ToDentist: domain.Dentist{Id: 2}, Reason: "conducto", Status: domain.ReferralPending},
+			2: {Id: 2, PatientId: 1, FromDentist: domain.Dentist{Id: 1}, Specialty: domain.SpecialtyEndodontics, Reason: "conducto", Status: domain.ReferralPending},
+			3: {Id: 3, PatientId: 1, FromDentist: domain.Dentist{Id: 1}, ToDentist: domain.Dentist{Id: 2}, Reason: "conducto", Status: domain.ReferralAccepted},
+		},
+		dentists: map[int]domain.Dentist{
+			1: {Id: 1},
+			2: {Id: 2, Specialties: []string{domain.SpecialtyEndodontics}},
+			3: {Id: 3, Specialties: []string{domain.SpecialtyOrthodontics}},
+		},
+	}
+}
+
+func TestCreate(t *testing.T) {
+	tests := []struct {
+		name      string
+		referral  domain.Referral
+		dentistId int
+		err       string
+	}{
+		{name: "sender is the current dentist", referral: domain.Referral{PatientId: 1, ToDentist: domain.Dentist{Id: 2}, Reason: "conducto"}, dentistId: 1},
+		{name: "sender in the body is ignored", referral: domain.Referral{PatientId: 1, FromDentist: domain.Dentist{Id: 3}, ToDentist: domain.Dentist{Id: 2}, Reason: "conducto"}, dentistId: 1},
+		{name: "user without dentist", referral: domain.Referral{PatientId: 1, ToDentist: domain.Dentist{Id: 2}, Reason: "conducto"}, err: "only users linked to a dentist can refer patients"},
+		{name: "to themselves", referral: domain.Referral{PatientId: 1, ToDentist: domain.Dentist{Id: 1}, Reason: "conducto"}, dentistId: 1, err: "from_dentist and to_dentist can't be the same"},
+		{name: "without receiver", referral: domain.Referral{PatientId: 1, Reason: "conducto"}, dentistId: 1, err: "to_dentist.id or specialty can't be empty"},
+		{name: "receiver without the specialty", referral: domain.Referral{PatientId: 1, ToDentist: domain.Dentist{Id: 3}, Specialty: domain.SpecialtyEndodontics, Reason: "conducto"}, dentistId: 1, err: "dentist 3 doesn't have specialty endodontics"},
+		{name: "invalid urgency", referral: domain.Referral{PatientId: 1, Specialty: domain.SpecialtyEndodontics, Reason: "conducto", Urgency: "now"}, dentistId: 1, err: "invalid urgency, must be one of: routine, priority, urgent"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, err := NewReferralService(newFakeRepository(), &fakeAppointments{}).Create(tt.referral, tt.dentistId)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if r.FromDentist.Id != tt.dentistId || r.Status != domain.ReferralPending || r.Urgency != domain.UrgencyRoutine {
+				t.Fatalf("unexpected referral %+v", r)
+			}
+		})
+	}
+}
+
+func TestUpdateStatus(t *testing.T) {
+	tests := []struct {
+		name      string
+		id        int
+		status    string
+		dentistId int
+		receiver  int
+		err       string
+	}{
+		{name: "addressed dentist accepts", id: 1, status: domain.ReferralAccepted, dentistId: 2, receiver: 2},
+		{name: "another dentist can't accept", id: 1, status: domain.ReferralAccepted, dentistId: 3, err: "referral 1 is addressed to dentist 2"},
+		{name: "another dentist can't reject", id: 1, status: domain.ReferralRejected, dentistId: 3, err: "referral 1 is addressed to dentist 2"},
+		{name: "user without dentist can't accept", id: 1, status: domain.ReferralAccepted, err: "only users linked to a dentist can accept or reject referrals"},
+		{name: "specialist accepts a specialty referral", id: 2, status: domain.ReferralAccepted, dentistId: 2, receiver: 2},
+		{name: "dentist without the specialty", id: 2, status: domain.ReferralAccepted, dentistId: 3, err: "dentist 3 doesn't have specialty endodontics"},
+		{name: "rejecting keeps the specialty open", id: 2, status: domain.ReferralRejected, dentistId: 2},
+		{name: "staff can cancel", id: 1, status: domain.ReferralCancelled},
+		{name: "invalid transition", id: 3, status: domain.ReferralRejected, dentistId: 2, err: "referral 3 can't change from accepted to rejected"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			s := NewReferralService(newFakeRepository(), &fakeAppointments{})
+			r, err := s.UpdateStatus(tt.id, domain.ReferralStatus{Status: tt.status}, tt.dentistId)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if r.Status != tt.status || r.ToDentist.Id != tt.receiver && tt.receiver != 0 {
+				t.Fatalf("unexpected referral %+v", r)
+			}
+		})
+	}
+}
+
+func TestSchedule(t *testing.T) {
+	tests := []struct {
+		name       string
+		failUpdate bool
+		deleted    int
+		err        string
+	}{
+		{name: "links the appointment"},
+		{name: "deletes the appointment when the referral can't be updated", failUpdate: true, deleted: 1, err: "error updating referral 3"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := newFakeRepository()
+			r.failUpdate = tt.failUpdate
+			a := &fakeAppointments{}
+			appointment, err := NewReferralService(r, a).Schedule(3, domain.ReferralAppointment{Date: "2026-10-20", Hour: "10:00:00"})
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				if len(a.deleted) != 1 || a.deleted[0] != tt.deleted {
+					t.Fatalf("expected appointment %d to be deleted, got %v", tt.deleted, a.deleted)
+				}
+				if r.referrals[3].Status != domain.ReferralAccepted {
+					t.Fatalf("expected the referral to stay accepted, got %s", r.referrals[3].Status)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if appointment.Dentist.Id != 2 || appointment.Description != "conducto" {
+				t.Fatalf("unexpected appointment %+v", appointment)
+			}
+			if r.referrals[3].Status != domain.ReferralScheduled || r.referrals[3].AppointmentId != appointment.Id {
+				t.Fatalf("unexpected referral %+v", r.referrals[3])
+			}
+		})
+	}
+}
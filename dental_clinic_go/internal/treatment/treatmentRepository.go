@@ -0,0 +1,116 @@
+package treatment
+
+import (
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/pkg/store"
+	"errors"
+	"fmt"
+)
+
+type TreatmentRepository interface {
+	GetByID(id int) (domain.TreatmentPlan, error)
+	GetByPatient(patientId int) ([]domain.TreatmentPlan, error)
+	Create(plan domain.TreatmentPlan) (domain.TreatmentPlan, error)
+	UpdateStatus(id int, status string) error
+	GetItemByID(id int) (domain.TreatmentItem, error)
+	GetItemsByAppointment(appointmentId int) ([]domain.TreatmentItem, error)
+	CreateItem(item domain.TreatmentItem) (domain.TreatmentItem, error)
+	UpdateItem(item domain.TreatmentItem) error
+}
+
+type treatmentRepository struct {
+	storage      store.TreatmentStore
+	patientStore store.PatientStore
+	dentistStore store.DentistStore
+}
+
+// NewTreatmentRepository crea un nuevo repositorio
+func NewTreatmentRepository(storage store.TreatmentStore, patientStore store.PatientStore,
+	dentistStore store.DentistStore) TreatmentRepository {
+	return &treatmentRepository{storage, patientStore, dentistStore}
+}
+
+// GetByID busca un plan por su id
+func (r *treatmentRepository) GetByID(id int) (domain.TreatmentPlan, error) {
+	plan, err := r.storage.GetByID(id)
+	if err != nil {
+		return domain.TreatmentPlan{}, errors.New(fmt.Sprintf("treatment plan %d not found", id))
+	}
+	return plan, nil
+}
+
+// GetByPatient busca los planes de un paciente
+func (r *treatmentRepository) GetByPatient(patientId int) ([]domain.TreatmentPlan, error) {
+	_, err := r.patientStore.GetByID(patientId)
+	if err != nil {
+		return []domain.TreatmentPlan{}, errors.New(fmt.Sprintf("patient %d not found", patientId))
+	}
+	plans, err := r.storage.GetByPatient(patientId)
+	if err != nil {
+		return []domain.TreatmentPlan{}, errors.New(fmt.Sprintf("treatment plans of patient %d not found", patientId))
+	}
+	return plans, nil
+}
+
+// Create agrega un nuevo plan
+func (r *treatmentRepository) Create(plan domain.TreatmentPlan) (domain.TreatmentPlan, error) {
+	_, err := r.patientStore.GetByID(plan.PatientId)
+	if err != nil {
+		return domain.TreatmentPlan{}, errors.New(fmt.Sprintf("patient %d not found", plan.PatientId))
+	}
+	dentist, err := r.dentistStore.GetByID(plan.Dentist.Id)
+	if err != nil {
+		return domain.TreatmentPlan{}, errors.New(fmt.Sprintf("dentist %d not found", plan.Dentist.Id))
+	}
+	plan.Dentist = dentist
+	p, err := r.storage.Create(plan)
+	if err != nil {
+		return domain.TreatmentPlan{}, errors.New("error creating treatment plan")
+	}
+	return p, nil
+}
+
+// UpdateStatus actualiza el estado de un plan
+func (r *treatmentRepository) UpdateStatus(id int, status string) error {
+	err := r.storage.UpdateStatus(id, status)
+	if err != nil {
+		return errors.New("error updating treatment plan")
+	}
+	return nil
+}
+
+// GetItemByID busca un procedimiento por su id
+func (r *treatmentRepository) GetItemByID(id int) (domain.TreatmentItem, error) {
+	item, err := r.storage.GetItemByID(id)
+	if err != nil {
+		return domain.TreatmentItem{}, errors.New(fmt.Sprintf("treatment item %d not found", id))
+	}
+	return item, nil
+}
+
+// GetItemsByAppointment busca los procedimientos agendados en un turno
+func (r *treatmentRepository) GetItemsByAppointment(appointmentId int) ([]domain.TreatmentItem, error) {
+	items, err := r.storage.GetItemsByAppointment(appointmentId)
+	if err != nil {
+		return []domain.TreatmentItem{}, errors.New(fmt.Sprintf("treatment items of appointment %d not found", appointmentId))
+	}
+	return items, nil
+}
+
+// CreateItem agrega un procedimiento a un plan
+func (r *treatmentRepository) CreateItem(item domain.TreatmentItem) (domain.TreatmentItem, error) {
+	i, err := r.storage.CreateItem(item)
+	if err != nil {
+		return domain.TreatmentItem{}, errors.New("error creating treatment item")
+	}
+	return i, nil
+}
+
+// UpdateItem actualiza un procedimiento
+func (r *treatmentRepository) UpdateItem(item domain.TreatmentItem) error {
+	err := r.storage.UpdateItem(item)
+	if err != nil {
+		return errors.New("error updating treatment item")
+	}
+	return nil
+}
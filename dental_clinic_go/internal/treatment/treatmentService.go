@@ -0,0 +1,361 @@
+package treatment
+
+import (
+	"dental_clinic_go/internal/appointment"
+	"dental_clinic_go/internal/domain"
+	"dental_clinic_go/internal/procedure"
+	"dental_clinic_go/pkg/fdi"
+	"errors"
+	"fmt"
+	"log"
+	"math"
+	"time"
+)
+
+// PriceResolver devuelve el precio de lista vigente de un procedimiento
+type PriceResolver interface {
+	ListPrice(procedureCode string, date string, insurerId int) (float64, error)
+}
+
+type Service interface {
+	GetByID(id int) (domain.TreatmentPlan, error)
+	GetByPatient(patientId int) ([]domain.TreatmentPlan, error)
+	Create(plan domain.TreatmentPlan) (domain.TreatmentPlan, error)
+	UpdateStatus(id int, status string) (domain.TreatmentPlan, error)
+	AddItem(planId int, item domain.TreatmentItem) (domain.TreatmentPlan, error)
+	CancelItem(planId int, itemId int) (domain.TreatmentPlan, error)
+	ScheduleItem(planId int, itemId int, request domain.TreatmentItemAppointment) (domain.Appointment, error)
+	AppointmentStatusChanged(before domain.Appointment, after domain.Appointment) error
+}
+
+type service struct {
+	r      TreatmentRepository
+	a      appointment.AppointmentService
+	p      procedure.Service
+	prices PriceResolver
+}
+
+// NewTreatmentService crea un nuevo servicio
+func NewTreatmentService(r TreatmentRepository, a appointment.AppointmentService, p procedure.Service, prices PriceResolver) Service {
+	return &service{r, a, p, prices}
+}
+
+// GetByID busca un plan por su id y calcula su avance
+func (s *service) GetByID(id int) (domain.TreatmentPlan, error) {
+	plan, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	plan.Progress = progress(plan.Items)
+	return plan, nil
+}
+
+// GetByPatient busca los planes de un paciente y calcula su avance
+func (s *service) GetByPatient(patientId int) ([]domain.TreatmentPlan, error) {
+	plans, err := s.r.GetByPatient(patientId)
+	if err != nil {
+		return []domain.TreatmentPlan{}, err
+	}
+	for i := range plans {
+		plans[i].Progress = progress(plans[i].Items)
+	}
+	return plans, nil
+}
+
+// Create agrega un nuevo plan propuesto, el orden de los procedimientos es el de la lista
+func (s *service) Create(plan domain.TreatmentPlan) (domain.TreatmentPlan, error) {
+	for i := range plan.Items {
+		item, err := s.validateItem(plan.Items[i])
+		if err != nil {
+			return domain.TreatmentPlan{}, err
+		}
+		item.Position = i + 1
+		item.Status = domain.ItemPending
+		item.AppointmentId = 0
+		plan.Items[i] = item
+	}
+	plan.Status = domain.PlanProposed
+	p, err := s.r.Create(plan)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	return s.GetByID(p.Id)
+}
+
+// UpdateStatus acepta o cancela un plan, el resto de los estados se calculan segun el avance
+func (s *service) UpdateStatus(id int, status string) (domain.TreatmentPlan, error) {
+	plan, err := s.r.GetByID(id)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	if plan.Status == status {
+		return s.GetByID(id)
+	}
+	switch {
+	case status == domain.PlanAccepted && plan.Status == domain.PlanProposed:
+	case status == domain.PlanCancelled && plan.Status != domain.PlanCompleted:
+	default:
+		return domain.TreatmentPlan{}, errors.New(fmt.Sprintf("treatment plan %d can't change from %s to %s", id, plan.Status, status))
+	}
+	err = s.r.UpdateStatus(id, status)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	return s.GetByID(id)
+}
+
+// AddItem agrega un procedimiento al final de un plan abierto
+func (s *service) AddItem(planId int, item domain.TreatmentItem) (domain.TreatmentPlan, error) {
+	plan, err := s.getOpenPlan(planId)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	item, err = s.validateItem(item)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	item.PlanId = planId
+	item.Position = len(plan.Items) + 1
+	item.Status = domain.ItemPending
+	item.AppointmentId = 0
+	_, err = s.r.CreateItem(item)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	err = s.refreshStatus(planId)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	return s.GetByID(planId)
+}
+
+// CancelItem cancela un procedimiento que todavia no se realizo. Si estaba agendado cancela tambien su turno,
+// si el turno ya no se puede cancelar el procedimiento queda como estaba.
+func (s *service) CancelItem(planId int, itemId int) (domain.TreatmentPlan, error) {
+	item, err := s.getItem(planId, itemId)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	if item.Status == domain.ItemCompleted {
+		return domain.TreatmentPlan{}, errors.New(fmt.Sprintf("treatment item %d is already completed", itemId))
+	}
+	if item.Status == domain.ItemScheduled && item.AppointmentId != 0 {
+		_, err = s.a.Cancel(item.AppointmentId)
+		if err != nil {
+			return domain.TreatmentPlan{}, err
+		}
+		// al cancelar el turno AppointmentStatusChanged libera el procedimiento
+		item, err = s.getItem(planId, itemId)
+		if err != nil {
+			return domain.TreatmentPlan{}, err
+		}
+	}
+	item.Status = domain.ItemCancelled
+	err = s.r.UpdateItem(item)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	err = s.refreshStatus(planId)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	return s.GetByID(planId)
+}
+
+// ScheduleItem crea un turno con el paciente, el dentista y el procedimiento del item para realizarlo, asi
+// el turno pasa por las mismas validaciones que uno agendado a mano. Agendar un procedimiento de un plan propuesto lo da por aceptado.
+func (s *service) ScheduleItem(planId int, itemId int, request domain.TreatmentItemAppointment) (domain.Appointment, error) {
+	plan, err := s.getOpenPlan(planId)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	item, err := s.getItem(planId, itemId)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	if item.Status != domain.ItemPending {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("treatment item %d is %s", itemId, item.Status))
+	}
+	if _, err := time.Parse("2006-01-02", request.Date); err != nil {
+		return domain.Appointment{}, errors.New("invalid date, must be in format: yyyy-mm-dd")
+	}
+	if _, err := time.Parse("15:04:05", request.Hour); err != nil {
+		return domain.Appointment{}, errors.New("invalid hour, must be in format: hh:mm:ss")
+	}
+	a, err := s.a.Create(domain.Appointment{
+		Date:          request.Date,
+		Hour:          request.Hour,
+		Description:   item.Description,
+		ProcedureCode: item.ProcedureCode,
+		Patient:       domain.Patient{Id: plan.PatientId},
+		Dentist:       plan.Dentist,
+	})
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	item.Status = domain.ItemScheduled
+	item.AppointmentId = a.Id
+	err = s.r.UpdateItem(item)
+	if err != nil {
+		// sin el turno el procedimiento sigue pendiente y se puede volver a agendar
+		if err := s.a.Delete(a.Id); err != nil {
+			log.Printf("error deleting appointment %d of treatment item %d: %s", a.Id, itemId, err.Error())
+		}
+		return domain.Appointment{}, err
+	}
+	err = s.refreshStatus(planId)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	return s.a.GetByID(a.Id)
+}
+
+// AppointmentStatusChanged completa los procedimientos de un turno realizado y libera los de
+// un turno cancelado o ausente para que se vuelvan a agendar
+func (s *service) AppointmentStatusChanged(before domain.Appointment, after domain.Appointment) error {
+	var status string
+	switch after.Status {
+	case domain.AppointmentCompleted:
+		status = domain.ItemCompleted
+	case domain.AppointmentCancelled, domain.AppointmentNoShow:
+		status = domain.ItemPending
+	default:
+		return nil
+	}
+	items, err := s.r.GetItemsByAppointment(after.Id)
+	if err != nil {
+		return err
+	}
+	for _, item := range items {
+		if item.Status != domain.ItemScheduled {
+			continue
+		}
+		item.Status = status
+		if status == domain.ItemPending {
+			item.AppointmentId = 0
+		}
+		err = s.r.UpdateItem(item)
+		if err != nil {
+			return err
+		}
+		err = s.refreshStatus(item.PlanId)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// getOpenPlan busca un plan que todavia admite cambios
+func (s *service) getOpenPlan(planId int) (domain.TreatmentPlan, error) {
+	plan, err := s.r.GetByID(planId)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	if plan.Status == domain.PlanCancelled || plan.Status == domain.PlanCompleted {
+		return domain.TreatmentPlan{}, errors.New(fmt.Sprintf("treatment plan %d is %s", planId, plan.Status))
+	}
+	return plan, nil
+}
+
+// getItem busca un procedimiento y verifica que pertenezca al plan
+func (s *service) getItem(planId int, itemId int) (domain.TreatmentItem, error) {
+	item, err := s.r.GetItemByID(itemId)
+	if err != nil || item.PlanId != planId {
+		return domain.TreatmentItem{}, errors.New(fmt.Sprintf("treatment item %d not found in plan %d", itemId, planId))
+	}
+	return item, nil
+}
+
+// refreshStatus recalcula el estado de un plan segun sus procedimientos
+func (s *service) refreshStatus(planId int) error {
+	plan, err := s.r.GetByID(planId)
+	if err != nil {
+		return err
+	}
+	if plan.Status == domain.PlanCancelled {
+		return nil
+	}
+	active, completed, scheduled := 0, 0, 0
+	for _, item := range plan.Items {
+		switch item.Status {
+		case domain.ItemCompleted:
+			completed++
+		case domain.ItemScheduled:
+			scheduled++
+		}
+		if item.Status != domain.ItemCancelled {
+			active++
+		}
+	}
+	status := plan.Status
+	switch {
+	case active > 0 && completed == active:
+		status = domain.PlanCompleted
+	case completed > 0:
+		status = domain.PlanInProgress
+	case scheduled > 0 && plan.Status == domain.PlanProposed:
+		status = domain.PlanAccepted
+	}
+	if status == plan.Status {
+		return nil
+	}
+	return s.r.UpdateStatus(planId, status)
+}
+
+// validateItem valida un procedimiento y normaliza sus caras. Si indica un codigo del catalogo
+// verifica que se pueda aplicar y completa la descripcion con la del catalogo y el costo con la lista vigente.
+func (s *service) validateItem(item domain.TreatmentItem) (domain.TreatmentItem, error) {
+	if item.Tooth != 0 && !fdi.ValidTooth(item.Tooth) {
+		return domain.TreatmentItem{}, errors.New(fmt.Sprintf("invalid tooth %d, must be a FDI tooth number", item.Tooth))
+	}
+	surfaces, err := fdi.NormalizeSurfaces(item.Surfaces)
+	if err != nil {
+		return domain.TreatmentItem{}, err
+	}
+	item.Surfaces = surfaces
+	if item.ProcedureCode != "" {
+		p, err := s.p.Validate(item.ProcedureCode, item.Tooth, item.Surfaces)
+		if err != nil {
+			return domain.TreatmentItem{}, err
+		}
+		if item.Description == "" {
+			item.Description = p.Name
+		}
+		if item.EstimatedCost == 0 {
+			item.EstimatedCost, err = s.prices.ListPrice(p.Code, time.Now().Format("2006-01-02"), 0)
+			if err != nil {
+				return domain.TreatmentItem{}, err
+			}
+		}
+	}
+	if item.Description == "" {
+		return domain.TreatmentItem{}, errors.New("item description can't be empty")
+	}
+	if item.EstimatedCost < 0 {
+		return domain.TreatmentItem{}, errors.New("item estimated_cost can't be negative")
+	}
+	return item, nil
+}
+
+// progress calcula el avance de un plan sin contar los procedimientos cancelados
+func progress(items []domain.TreatmentItem) domain.TreatmentProgress {
+	p := domain.TreatmentProgress{}
+	for _, item := range items {
+		if item.Status == domain.ItemCancelled {
+			continue
+		}
+		p.Total++
+		p.EstimatedCost += item.EstimatedCost
+		if item.Status == domain.ItemCompleted {
+			p.Completed++
+			p.CompletedCost += item.EstimatedCost
+		}
+	}
+	if p.Total > 0 {
+		p.Percentage = math.Round(float64(p.Completed)/float64(p.Total)*1000) / 10
+	}
+	return p
+}
@@ -0,0 +1,200 @@
+package treatment
+
+import (
+	"dental_clinic_go/internal/appointment"
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"testing"
+)
+
+// fakeRepository es un TreatmentRepository en memoria con un unico plan, failUpdate simula un error al guardar
+type fakeRepository struct {
+	plan       domain.TreatmentPlan
+	items      map[int]domain.TreatmentItem
+	failUpdate bool
+}
+
+func (r *fakeRepository) GetByID(id int) (domain.TreatmentPlan, error) {
+	if id != r.plan.Id {
+		return domain.TreatmentPlan{}, errors.New(fmt.Sprintf("treatment plan %d not found", id))
+	}
+	plan := r.plan
+	plan.Items = []domain.TreatmentItem{}
+	for i := 1; i <= len(r.items); i++ {
+		plan.Items = append(plan.Items, r.items[i])
+	}
+	return plan, nil
+}
+
+func (r *fakeRepository) GetByPatient(patientId int) ([]domain.TreatmentPlan, error) {
+	return []domain.TreatmentPlan{}, nil
+}
+
+func (r *fakeRepository) Create(plan domain.TreatmentPlan) (domain.TreatmentPlan, error) {
+	return plan, nil
+}
+
+func (r *fakeRepository) UpdateStatus(id int, status string) error {
+	r.plan.Status = status
+	return nil
+}
+
+func (r *fakeRepository) GetItemByID(id int) (domain.TreatmentItem, error) {
+	item, ok := r.items[id]
+	if !ok {
+		return domain.TreatmentItem{}, errors.New(fmt.Sprintf("treatment item %d not found", id))
+	}
+	return item, nil
+}
+
+func (r *fakeRepository) GetItemsByAppointment(appointmentId int) ([]domain.TreatmentItem, error) {
+	items := []domain.TreatmentItem{}
+	for _, item := range r.items {
+		if item.AppointmentId == appointmentId {
+			items = append(items, item)
+		}
+	}
+	return items, nil
+}
+
+func (r *fakeRepository) CreateItem(item domain.TreatmentItem) (domain.TreatmentItem, error) {
+	return item, nil
+}
+
+func (r *fakeRepository) UpdateItem(item domain.TreatmentItem) error {
+	if r.failUpdate {
+		return errors.New(fmt.Sprintf("error updating treatment item %d", item.Id))
+	}
+	r.items[item.Id] = item
+	return nil
+}
+
+// fakeAppointments guarda los turnos y avisa los cambios de estado como el servicio real
+type fakeAppointments struct {
+	appointment.AppointmentService
+	appointments map[int]domain.Appointment
+	deleted      []int
+	listener     appointment.StatusListener
+}
+
+func (a *fakeAppointments) Create(p domain.Appointment) (domain.Appointment, error) {
+	p.Id = len(a.appointments) + 1
+	p.Status = domain.AppointmentScheduled
+	a.appointments[p.Id] = p
+	return p, nil
+}
+
+func (a *fakeAppointments) GetByID(id int) (domain.Appointment, error) {
+	return a.appointments[id], nil
+}
+
+func (a *fakeAppointments) Delete(id int) error {
+	a.deleted = append(a.deleted, id)
+	delete(a.appointments, id)
+	return nil
+}
+
+func (a *fakeAppointments) Cancel(id int) (domain.Appointment, error) {
+	before := a.appointments[id]
+	if before.Status != domain.AppointmentScheduled && before.Status != domain.AppointmentConfirmed {
+		return domain.Appointment{}, errors.New(fmt.Sprintf("appointment %d can't change from %s to cancelled", id, before.Status))
+	}
+	after := before
+	after.Status = domain.AppointmentCancelled
+	a.appointments[id] = after
+	return after, a.listener.AppointmentStatusChanged(before, after)
+}
+
+func newFixture() (*fakeRepository, *fakeAppointments, Service) {
+	r := &fakeRepository{
+		plan: domain.TreatmentPlan{Id: 1, PatientId: 1, Dentist: domain.Dentist{Id: 2}, Status: domain.PlanAccepted},
+		items: map[int]domain.TreatmentItem{
+			1: {Id: 1, PlanId: 1, Position: 1, Description: "extraccion", Status: domain.ItemPending},
+			2: {Id: 2, PlanId: 1, Position: 2, Description: "conducto", Status: domain.ItemScheduled, AppointmentId: 1},
+			3: {Id: 3, PlanId: 1, Position: 3, Description: "corona", Status: domain.ItemScheduled, AppointmentId: 2},
+			4: {Id: 4, PlanId: 1, Position: 4, Description: "limpieza", Status: domain.ItemCompleted},
+		},
+	}
+	a := &fakeAppointments{appointments: map[int]domain.Appointment{
+		1: {Id: 1, Status: domain.AppointmentConfirmed},
+		2: {Id: 2, Status: domain.AppointmentInProgress},
+	}}
+	s := NewTreatmentService(r, a, nil, nil)
+	a.listener = s
+	return r, a, s
+}
+
+func TestScheduleItem(t *testing.T) {
+	tests := []struct {
+		name       string
+		failUpdate bool
+		err        string
+	}{
+		{name: "links the appointment"},
+		{name: "deletes the appointment when the item can't be updated", failUpdate: true, err: "error updating treatment item 1"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, a, s := newFixture()
+			r.failUpdate = tt.failUpdate
+			appointment, err := s.ScheduleItem(1, 1, domain.TreatmentItemAppointment{Date: "2026-10-20", Hour: "10:00:00"})
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				if len(a.deleted) != 1 || a.deleted[0] != 3 {
+					t.Fatalf("expected appointment 3 to be deleted, got %v", a.deleted)
+				}
+				if r.items[1].Status != domain.ItemPending || r.items[1].AppointmentId != 0 {
+					t.Fatalf("expected the item to stay pending, got %+v", r.items[1])
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if appointment.Dentist.Id != 2 || appointment.Description != "extraccion" {
+				t.Fatalf("unexpected appointment %+v", appointment)
+			}
+			if r.items[1].Status != domain.ItemScheduled || r.items[1].AppointmentId != appointment.Id {
+				t.Fatalf("unexpected item %+v", r.items[1])
+			}
+		})
+	}
+}
+
+func TestCancelItem(t *testing.T) {
+	tests := []struct {
+		name        string
+		itemId      int
+		err         string
+		itemStatus  string
+		appointment string
+	}{
+		{name: "pending item", itemId: 1, itemStatus: domain.ItemCancelled},
+		{name: "scheduled item cancels its appointment", itemId: 2, itemStatus: domain.ItemCancelled, appointment: domain.AppointmentCancelled},
+		{name: "item of an appointment in progress", itemId: 3, err: "appointment 2 can't change from in_progress to cancelled", itemStatus: domain.ItemScheduled, appointment: domain.AppointmentInProgress},
+		{name: "completed item", itemId: 4, err: "treatment item 4 is already completed", itemStatus: domain.ItemCompleted},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r, a, s := newFixture()
+			original := r.items[tt.itemId]
+			_, err := s.CancelItem(1, tt.itemId)
+			if tt.err == "" && err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if tt.err != "" && (err == nil || err.Error() != tt.err) {
+				t.Fatalf("expected error %q, got %v", tt.err, err)
+			}
+			item := r.items[tt.itemId]
+			if item.Status != tt.itemStatus {
+				t.Fatalf("expected item status %s, got %s", tt.itemStatus, item.Status)
+			}
+			if original.AppointmentId != 0 && a.appointments[original.AppointmentId].Status != tt.appointment {
+				t.Fatalf("expected appointment status %s, got %s", tt.appointment, a.appointments[original.AppointmentId].Status)
+			}
+		})
+	}
+}
@@ -0,0 +1,51 @@
+package fdi
+
+import (
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"fmt"
+	"strings"
+)
+
+// Surfaces son las caras dentales: mesial, oclusal, distal, bucal y lingual
+const Surfaces = "MODBL"
+
+// ValidTooth indica si un numero de pieza es valido en la numeracion FDI,
+// cuadrantes 1 a 4 para la denticion permanente y 5 a 8 para la temporaria
+func ValidTooth(tooth int) bool {
+	quadrant, position := tooth/10, tooth%10
+	switch {
+	case quadrant >= 1 && quadrant <= 4:
+		return position >= 1 && position <= 8
+	case quadrant >= 5 && quadrant <= 8:
+		return position >= 1 && position <= 5
+	}
+	return false
+}
+
+// Dentition devuelve la denticion de una pieza FDI
+func Dentition(tooth int) string {
+	if tooth/10 >= 5 {
+		return domain.DentitionDeciduous
+	}
+	return domain.DentitionPermanent
+}
+
+// NormalizeSurfaces valida las caras y las devuelve en mayusculas y en orden M, O, D, B, L
+func NormalizeSurfaces(surfaces string) (string, error) {
+	surfaces = strings.ToUpper(surfaces)
+	normalized := ""
+	for _, surface := range Surfaces {
+		count := strings.Count(surfaces, string(surface))
+		if count > 1 {
+			return "", errors.New(fmt.Sprintf("surface %c is repeated", surface))
+		}
+		if count == 1 {
+			normalized += string(surface)
+		}
+	}
+	if len(normalized) != len(surfaces) {
+		return "", errors.New("invalid surfaces, must be any of: M, O, D, B, L")
+	}
+	return normalized, nil
+}
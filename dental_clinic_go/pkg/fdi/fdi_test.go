@@ -0,0 +1,63 @@
+package fdi
+
+import (
+	"dental_clinic_go/internal/domain"
+	"testing"
+)
+
+func TestValidTooth(t *testing.T) {
+	tests := []struct {
+		tooth     int
+		valid     bool
+		dentition string
+	}{
+		{11, true, domain.DentitionPermanent},
+		{18, true, domain.DentitionPermanent},
+		{48, true, domain.DentitionPermanent},
+		{51, true, domain.DentitionDeciduous},
+		{85, true, domain.DentitionDeciduous},
+		{10, false, ""},
+		{19, false, ""},
+		{56, false, ""},
+		{86, false, ""},
+		{91, false, ""},
+		{8, false, ""},
+		{0, false, ""},
+		{-11, false, ""},
+	}
+	for _, tt := range tests {
+		if valid := ValidTooth(tt.tooth); valid != tt.valid {
+			t.Errorf("tooth %d: expected valid %v, got %v", tt.tooth, tt.valid, valid)
+		}
+		if tt.valid && Dentition(tt.tooth) != tt.dentition {
+			t.Errorf("tooth %d: expected %s dentition, got %s", tt.tooth, tt.dentition, Dentition(tt.tooth))
+		}
+	}
+}
+
+func TestNormalizeSurfaces(t *testing.T) {
+	tests := []struct {
+		surfaces string
+		want     string
+		err      string
+	}{
+		{"", "", ""},
+		{"lbdom", "MODBL", ""},
+		{"DO", "OD", ""},
+		{"mm", "", "surface M is repeated"},
+		{"MX", "", "invalid surfaces, must be any of: M, O, D, B, L"},
+		{"M O", "", "invalid surfaces, must be any of: M, O, D, B, L"},
+	}
+	for _, tt := range tests {
+		got, err := NormalizeSurfaces(tt.surfaces)
+		if tt.err != "" {
+			if err == nil || err.Error() != tt.err {
+				t.Errorf("surfaces %q: expected error %q, got %v", tt.surfaces, tt.err, err)
+			}
+			continue
+		}
+		if err != nil || got != tt.want {
+			t.Errorf("surfaces %q: expected %q, got %q (%v)", tt.surfaces, tt.want, got, err)
+		}
+	}
+}
@@ -1,27 +1,42 @@
 package middleware
 
 import (
+	"dental_clinic_go/pkg/token"
 	"dental_clinic_go/pkg/web"
 	"errors"
-	"os"
+	"strings"
 
 	"github.com/gin-gonic/gin"
 )
 
-// Authentication manages the security by validating the token
-func Authentication() gin.HandlerFunc {
+type TokenValidator interface {
+	ValidateToken(accessToken string) (token.Claims, error)
+}
+
+// Authentication valida el JWT de acceso del usuario y guarda su id, su sesion, su rol y su dentista en el contexto.
+// El token va en el header Authorization como Bearer, o en el header TOKEN.
+func Authentication(v TokenValidator) gin.HandlerFunc {
 	return func(c *gin.Context) {
-		token := c.GetHeader("TOKEN")
-		if token == "" {
+		accessToken := strings.TrimSpace(strings.TrimPrefix(c.GetHeader("Authorization"), "Bearer "))
+		if accessToken == "" {
+			accessToken = c.GetHeader("TOKEN")
+		}
+		if accessToken == "" {
 			web.Failure(c, 401, errors.New("token not found"))
 			c.Abort()
 			return
 		}
-		if token != os.Getenv("TOKEN") {
-			web.Failure(c, 401, errors.New("invalid token"))
+		claims, err := v.ValidateToken(accessToken)
+		if err != nil {
+			web.Failure(c, 401, err)
 			c.Abort()
 			return
 		}
+		c.Set("user_id", claims.UserId())
+		c.Set("session_id", claims.SessionId)
+		c.Set("username", claims.Username)
+		c.Set("role", claims.Role)
+		c.Set("dentist_id", claims.DentistId)
 		c.Next()
 	}
 }
@@ -0,0 +1,26 @@
+package middleware
+
+import (
+	"dental_clinic_go/pkg/web"
+	"errors"
+	"fmt"
+
+	"github.com/gin-gonic/gin"
+)
+
+type PermissionChecker interface {
+	HasPermission(role string, permission string) bool
+}
+
+// Authorization verifica que el rol del usuario tenga el permiso de la ruta. Va despues de
+// Authentication, que guarda el rol en el contexto.
+func Authorization(p PermissionChecker, permission string) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		if !p.HasPermission(c.GetString("role"), permission) {
+			web.Failure(c, 403, errors.New(fmt.Sprintf("missing permission %s", permission)))
+			c.Abort()
+			return
+		}
+		c.Next()
+	}
+}
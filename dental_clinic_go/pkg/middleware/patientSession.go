@@ -0,0 +1,32 @@
+package middleware
+
+import (
+	"dental_clinic_go/pkg/web"
+	"errors"
+
+	"github.com/gin-gonic/gin"
+)
+
+type SessionValidator interface {
+	ValidateSession(token string) (int, error)
+}
+
+// PatientSession valida la sesion de un paciente del portal y guarda su id en el contexto
+func PatientSession(v SessionValidator) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		token := c.GetHeader("SESSION")
+		if token == "" {
+			web.Failure(c, 401, errors.New("session not found"))
+			c.Abort()
+			return
+		}
+		patientId, err := v.ValidateSession(token)
+		if err != nil {
+			web.Failure(c, 401, err)
+			c.Abort()
+			return
+		}
+		c.Set("patient_id", patientId)
+		c.Next()
+	}
+}
@@ -0,0 +1,45 @@
+package notify
+
+import (
+	"fmt"
+	"log"
+	"net/smtp"
+)
+
+type Notifier interface {
+	Send(to string, subject string, body string) error
+}
+
+type logNotifier struct{}
+
+// NewLogNotifier crea un notificador que solo escribe los mensajes en el log
+func NewLogNotifier() Notifier {
+	return &logNotifier{}
+}
+
+// Send escribe el mensaje en el log
+func (n *logNotifier) Send(to string, subject string, body string) error {
+	log.Printf("[notify] to: %s subject: %s body: %s", to, subject, body)
+	return nil
+}
+
+type smtpNotifier struct {
+	addr string
+	auth smtp.Auth
+	from string
+}
+
+// NewSmtpNotifier crea un notificador que envia los mensajes por email
+func NewSmtpNotifier(host string, port string, user string, password string, from string) Notifier {
+	return &smtpNotifier{
+		addr: fmt.Sprintf("%s:%s", host, port),
+		auth: smtp.PlainAuth("", user, password, host),
+		from: from,
+	}
+}
+
+// Send envia el mensaje por email
+func (n *smtpNotifier) Send(to string, subject string, body string) error {
+	msg := fmt.Sprintf("From: %s\r\nTo: %s\r\nSubject: %s\r\n\r\n%s\r\n", n.from, to, subject, body)
+	return smtp.SendMail(n.addr, n.auth, n.from, []string{to}, []byte(msg))
+}
@@ -0,0 +1,162 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"strings"
+)
+
+// Medidas de una hoja A4 en puntos
+const (
+	PageWidth  = 595.28
+	PageHeight = 841.89
+)
+
+// Document es un PDF de hojas A4 con texto en Helvetica y lineas. Las coordenadas se miden en puntos
+// desde la esquina superior izquierda de la hoja.
+type Document struct {
+	pages []*bytes.Buffer
+}
+
+// New crea un documento con una hoja en blanco
+func New() *Document {
+	d := &Document{}
+	d.AddPage()
+	return d
+}
+
+// AddPage agrega una hoja en blanco, lo que se dibuja despues va en esa hoja
+func (d *Document) AddPage() {
+	d.pages = append(d.pages, &bytes.Buffer{})
+}
+
+// Text escribe un texto con su linea base en y
+func (d *Document) Text(x float64, y float64, size float64, bold bool, text string) {
+	font := "F1"
+	if bold {
+		font = "F2"
+	}
+	fmt.Fprintf(d.page(), "BT /%s %.2f Tf %.2f %.2f Td (%s) Tj ET\n", font, size, x, PageHeight-y, escape(encode(text)))
+}
+
+// TextRight escribe un texto alineado a la derecha de x
+func (d *Document) TextRight(x float64, y float64, size float64, bold bool, text string) {
+	d.Text(x-Width(text, size, bold), y, size, bold, text)
+}
+
+// Line dibuja una linea de medio punto de ancho
+func (d *Document) Line(x1 float64, y1 float64, x2 float64, y2 float64) {
+	fmt.Fprintf(d.page(), "0.5 w %.2f %.2f m %.2f %.2f l S\n", x1, PageHeight-y1, x2, PageHeight-y2)
+}
+
+// Bytes arma el archivo PDF
+func (d *Document) Bytes() ([]byte, error) {
+	var out bytes.Buffer
+	offsets := []int{}
+	object := func(body string) {
+		offsets = append(offsets, out.Len())
+		fmt.Fprintf(&out, "%d 0 obj\n%s\nendobj\n", len(offsets), body)
+	}
+
+	out.WriteString("%PDF-1.4\n%\xe2\xe3\xcf\xd3\n")
+	// 1 catalogo, 2 arbol de hojas, 3 y 4 fuentes, despues cada hoja y su contenido
+	kids := []string{}
+	for i := range d.pages {
+		kids = append(kids, fmt.Sprintf("%d 0 R", 5+i*2))
+	}
+	object("<< /Type /Catalog /Pages 2 0 R >>")
+	object(fmt.Sprintf("<< /Type /Pages /Kids [%s] /Count %d >>", strings.Join(kids, " "), len(d.pages)))
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica /Encoding /WinAnsiEncoding >>")
+	object("<< /Type /Font /Subtype /Type1 /BaseFont /Helvetica-Bold /Encoding /WinAnsiEncoding >>")
+	for i, page := range d.pages {
+		object(fmt.Sprintf("<< /Type /Page /Parent 2 0 R /MediaBox [0 0 %.2f %.2f] /Resources << /Font << /F1 3 0 R /F2 4 0 R >> >> /Contents %d 0 R >>",
+			PageWidth, PageHeight, 6+i*2))
+		var content bytes.Buffer
+		w := zlib.NewWriter(&content)
+		_, err := w.Write(page.Bytes())
+		if err != nil {
+			return nil, err
+		}
+		err = w.Close()
+		if err != nil {
+			return nil, err
+		}
+		object(fmt.Sprintf("<< /Length %d /Filter /FlateDecode >>\nstream\n%s\nendstream", content.Len(), content.String()))
+	}
+
+	xref := out.Len()
+	fmt.Fprintf(&out, "xref\n0 %d\n0000000000 65535 f \n", len(offsets)+1)
+	for _, offset := range offsets {
+		fmt.Fprintf(&out, "%010d 00000 n \n", offset)
+	}
+	fmt.Fprintf(&out, "trailer\n<< /Size %d /Root 1 0 R >>\nstartxref\n%d\n%%%%EOF\n", len(offsets)+1, xref)
+	return out.Bytes(), nil
+}
+
+// Width calcula el ancho en puntos de un texto
+func Width(text string, size float64, bold bool) float64 {
+	widths := helvetica
+	if bold {
+		widths = helveticaBold
+	}
+	total := 0
+	for _, c := range encode(text) {
+		total += charWidth(widths, c)
+	}
+	return float64(total) * size / 1000
+}
+
+// Fit recorta un texto para que no supere el ancho, marcando el corte con puntos suspensivos
+func Fit(text string, size float64, bold bool, width float64) string {
+	if Width(text, size, bold) <= width {
+		return text
+	}
+	runes := []rune(text)
+	for len(runes) > 0 && Width(string(runes)+"...", size, bold) > width {
+		runes = runes[:len(runes)-1]
+	}
+	return strings.TrimSpace(string(runes)) + "..."
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// page devuelve el contenido de la hoja actual
+func (d *Document) page() *bytes.Buffer {
+	return d.pages[len(d.pages)-1]
+}
+
+// encode pasa un texto a WinAnsiEncoding, los caracteres que no existen en esa codificacion quedan como ?
+func encode(text string) []byte {
+	encoded := []byte{}
+	for _, r := range text {
+		switch {
+		case r < 0x80 || (r >= 0xa0 && r <= 0xff):
+			encoded = append(encoded, byte(r))
+		case r == '€':
+			encoded = append(encoded, 0x80)
+		case r == '–' || r == '—':
+			encoded = append(encoded, '-')
+		default:
+			encoded = append(encoded, '?')
+		}
+	}
+	return encoded
+}
+
+// escape escapa los caracteres especiales de un string de PDF
+func escape(text []byte) string {
+	var b strings.Builder
+	for _, c := range text {
+		switch c {
+		case '(', ')', '\\':
+			b.WriteByte('\\')
+			b.WriteByte(c)
+		case '\n', '\r', '\t':
+			b.WriteByte(' ')
+		default:
+			b.WriteByte(c)
+		}
+	}
+	return b.String()
+}
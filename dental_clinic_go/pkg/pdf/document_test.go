@@ -0,0 +1,112 @@
+package pdf
+
+import (
+	"bytes"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"regexp"
+	"strings"
+	"testing"
+)
+
+// contents descomprime el contenido de cada hoja de un PDF
+func contents(t *testing.T, file []byte) []string {
+	t.Helper()
+	pages := []string{}
+	for _, m := range regexp.MustCompile(`(?s)stream\n(.*?)\nendstream`).FindAllSubmatch(file, -1) {
+		r, err := zlib.NewReader(bytes.NewReader(m[1]))
+		if err != nil {
+			t.Fatalf("invalid page stream: %s", err)
+		}
+		content, err := io.ReadAll(r)
+		if err != nil {
+			t.Fatalf("invalid page stream: %s", err)
+		}
+		pages = append(pages, string(content))
+	}
+	return pages
+}
+
+func TestBytes(t *testing.T) {
+	d := New()
+	d.Text(50, 100, 10, true, "Total (IVA) año 2026 \\ ok")
+	d.Line(50, 110, 545, 110)
+	d.AddPage()
+	d.Text(50, 100, 10, false, "Hoja 2 ✓")
+	file, err := d.Bytes()
+	if err != nil {
+		t.Fatalf("unexpected error: %s", err)
+	}
+	if !bytes.HasPrefix(file, []byte("%PDF-1.4\n")) || !bytes.HasSuffix(file, []byte("%%EOF\n")) {
+		t.Fatalf("expected a PDF header and trailer")
+	}
+	if !bytes.Contains(file, []byte("/Kids [5 0 R 7 0 R] /Count 2")) {
+		t.Fatalf("expected two pages in the page tree")
+	}
+	// cada entrada del xref apunta al comienzo de su objeto
+	xref := bytes.LastIndex(file, []byte("\nxref\n")) + 1
+	offsets := regexp.MustCompile(`(\d{10}) 00000 n`).FindAllSubmatch(file[xref:], -1)
+	if len(offsets) != 8 {
+		t.Fatalf("expected 8 objects in the xref, got %d", len(offsets))
+	}
+	for n, o := range offsets {
+		var offset int
+		fmt.Sscanf(string(o[1]), "%d", &offset)
+		if !bytes.HasPrefix(file[offset:], []byte(fmt.Sprintf("%d 0 obj", n+1))) {
+			t.Fatalf("xref entry %d doesn't point to its object", n+1)
+		}
+	}
+	if !bytes.Contains(file, []byte(fmt.Sprintf("startxref\n%d\n", xref))) {
+		t.Fatalf("expected startxref to point to the xref")
+	}
+	pages := contents(t, file)
+	if len(pages) != 2 {
+		t.Fatalf("expected 2 page streams, got %d", len(pages))
+	}
+	// y se mide desde arriba, los parentesis y la barra se escapan y la ñ va en WinAnsiEncoding
+	if !strings.Contains(pages[0], "BT /F2 10.00 Tf 50.00 741.89 Td (Total \\(IVA\\) a\xf1o 2026 \\\\ ok) Tj ET") {
+		t.Fatalf("unexpected first page %q", pages[0])
+	}
+	if !strings.Contains(pages[0], "0.5 w 50.00 731.89 m 545.00 731.89 l S") {
+		t.Fatalf("expected a line on the first page, got %q", pages[0])
+	}
+	// los caracteres que no existen en WinAnsiEncoding quedan como ?
+	if !strings.Contains(pages[1], "/F1 10.00 Tf 50.00 741.89 Td (Hoja 2 ?) Tj ET") {
+		t.Fatalf("unexpected second page %q", pages[1])
+	}
+}
+
+func TestWidth(t *testing.T) {
+	tests := []struct {
+		name  string
+		text  string
+		bold  bool
+		width float64
+	}{
+		{name: "regular", text: "Hola", width: 20.56},
+		{name: "bold", text: "Hola", bold: true, width: 21.67},
+		{name: "accents measure as their letter", text: "Hóla", width: 20.56},
+		{name: "empty", text: "", width: 0},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if w := Width(tt.text, 10, tt.bold); fmt.Sprintf("%.2f", w) != fmt.Sprintf("%.2f", tt.width) {
+				t.Fatalf("expected width %.2f, got %.2f", tt.width, w)
+			}
+		})
+	}
+}
+
+func TestFit(t *testing.T) {
+	if text := Fit("Consulta", 10, false, 100); text != "Consulta" {
+		t.Fatalf("expected the text unchanged, got %q", text)
+	}
+	text := Fit("Tratamiento de conducto en molar inferior", 10, false, 100)
+	if !strings.HasSuffix(text, "...") || Width(text, 10, false) > 100 {
+		t.Fatalf("expected the text cut to 100 points, got %q", text)
+	}
+	if !strings.HasPrefix("Tratamiento de conducto en molar inferior", strings.TrimSuffix(text, "...")) {
+		t.Fatalf("expected a prefix of the text, got %q", text)
+	}
+}
@@ -3,10 +3,19 @@ package store
 import (
 	"database/sql"
 	"dental_clinic_go/internal/domain"
+	"errors"
 
 	"time"
+
+	"github.com/go-sql-driver/mysql"
 )
 
+// ErrSlotTaken indica que el dentista ya tiene un turno no cancelado en esa fecha y hora
+var ErrSlotTaken = errors.New("slot already taken")
+
+// appointmentColumns son las columnas de un turno con su paciente y dentista en el orden que espera appointmentFields
+const appointmentColumns = "appointment.id, appointment.date, appointment.hour, appointment.description, COALESCE(appointment.procedure_code, ''), appointment.status, " + patientColumns + ", dentist.*"
+
 type appointmentSqlStore struct {
 	DB *sql.DB
 }
@@ -19,9 +28,14 @@ func NewAppointmentSqlStore(db *sql.DB) AppointmentStore {
 // GetByID devuelve un turno por su id
 func (s *appointmentSqlStore) GetByID(id int) (domain.Appointment, error) {
 	var appointmentReturn domain.Appointment
-	query := "SELECT appointment.id, appointment.date, appointment.hour, appointment.description, patient.*, dentist.* FROM appointment INNER JOIN patient ON appointment.patient_id = patient.id INNER JOIN dentist ON appointment.dentist_id = dentist.id WHERE appointment.id = ?;"
+	query := "SELECT " + appointmentColumns + " FROM appointment INNER JOIN patient ON appointment.patient_id = patient.id INNER JOIN dentist ON appointment.dentist_id = dentist.id WHERE appointment.id = ?;"
 	row := s.DB.QueryRow(query, id)
-	err := row.Scan(&appointmentReturn.Id, &appointmentReturn.Date, &appointmentReturn.Hour, &appointmentReturn.Description, &appointmentReturn.Patient.Id, &appointmentReturn.Patient.Name, &appointmentReturn.Patient.LastName, &appointmentReturn.Patient.Domicilio, &appointmentReturn.Patient.Dni, &appointmentReturn.Patient.Email, &appointmentReturn.Patient.AdmissionDate, &appointmentReturn.Dentist.Id, &appointmentReturn.Dentist.Name, &appointmentReturn.Dentist.LastName, &appointmentReturn.Dentist.License)
+	err := row.Scan(appointmentFields(&appointmentReturn)...)
+	if err != nil {
+		return domain.Appointment{}, err
+	}
+	appointmentReturn.Patient.Age = appointmentReturn.Patient.AgeAt(time.Now())
+	appointmentReturn.Alerts, err = s.getAlerts(appointmentReturn.Patient.Id)
 	if err != nil {
 		return domain.Appointment{}, err
 	}
@@ -32,7 +46,7 @@ func (s *appointmentSqlStore) GetByID(id int) (domain.Appointment, error) {
 func (s *appointmentSqlStore) GetByDni(dni int) ([]domain.Appointment, error) {
 	var appointments []domain.Appointment
 
-	query := "SELECT appointment.id, appointment.date, appointment.hour, appointment.description, patient.*, dentist.* FROM appointment INNER JOIN patient ON appointment.patient_id = patient.id INNER JOIN dentist ON appointment.dentist_id = dentist.id WHERE patient.dni = ?"
+	query := "SELECT " + appointmentColumns + " FROM appointment INNER JOIN patient ON appointment.patient_id = patient.id INNER JOIN dentist ON appointment.dentist_id = dentist.id WHERE patient.dni = ?"
 	rows, err := s.DB.Query(query, dni)
 	if err != nil {
 		return []domain.Appointment{}, err
@@ -41,10 +55,37 @@ func (s *appointmentSqlStore) GetByDni(dni int) ([]domain.Appointment, error) {
 
 	for rows.Next() {
 		var appointmentReturn domain.Appointment
-		err := rows.Scan(&appointmentReturn.Id, &appointmentReturn.Date, &appointmentReturn.Hour, &appointmentReturn.Description, &appointmentReturn.Patient.Id, &appointmentReturn.Patient.Name, &appointmentReturn.Patient.LastName, &appointmentReturn.Patient.Domicilio, &appointmentReturn.Patient.Dni, &appointmentReturn.Patient.Email, &appointmentReturn.Patient.AdmissionDate, &appointmentReturn.Dentist.Id, &appointmentReturn.Dentist.Name, &appointmentReturn.Dentist.LastName, &appointmentReturn.Dentist.License)
+		err := rows.Scan(appointmentFields(&appointmentReturn)...)
+		if err != nil {
+			return []domain.Appointment{}, err
+		}
+		appointmentReturn.Patient.Age = appointmentReturn.Patient.AgeAt(time.Now())
+		appointments = append(appointments, appointmentReturn)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Appointment{}, err
+	}
+	return appointments, nil
+}
+
+// GetByDentistAndDate devuelve los turnos de un dentista en una fecha
+func (s *appointmentSqlStore) GetByDentistAndDate(dentistId int, date string) ([]domain.Appointment, error) {
+	var appointments []domain.Appointment
+
+	query := "SELECT " + appointmentColumns + " FROM appointment INNER JOIN patient ON appointment.patient_id = patient.id INNER JOIN dentist ON appointment.dentist_id = dentist.id WHERE dentist.id = ? AND appointment.date = ?"
+	rows, err := s.DB.Query(query, dentistId, date)
+	if err != nil {
+		return []domain.Appointment{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var appointmentReturn domain.Appointment
+		err := rows.Scan(appointmentFields(&appointmentReturn)...)
 		if err != nil {
 			return []domain.Appointment{}, err
 		}
+		appointmentReturn.Patient.Age = appointmentReturn.Patient.AgeAt(time.Now())
 		appointments = append(appointments, appointmentReturn)
 	}
 	if err = rows.Err(); err != nil {
@@ -55,7 +96,7 @@ func (s *appointmentSqlStore) GetByDni(dni int) ([]domain.Appointment, error) {
 
 // Create agrega un nuevo turno
 func (s *appointmentSqlStore) Create(appointment domain.Appointment) (domain.Appointment, error) {
-	stmt, err := s.DB.Prepare("INSERT INTO appointment (date, hour, description, patient_id, dentist_id) VALUES (?, ?, ?, ?, ?);")
+	stmt, err := s.DB.Prepare("INSERT INTO appointment (date, hour, description, procedure_code, status, patient_id, dentist_id) VALUES (?, ?, ?, ?, ?, ?, ?);")
 	if err != nil {
 		return domain.Appointment{}, err
 	}
@@ -69,9 +110,12 @@ func (s *appointmentSqlStore) Create(appointment domain.Appointment) (domain.App
 		return domain.Appointment{}, err
 	}
 	hour := time.Date(1970, 1, 1, t.Hour(), t.Minute(), t.Second(), 0, time.UTC).Format("15:04:05")
-	result, err := stmt.Exec(date, hour, appointment.Description, appointment.Patient.Id, appointment.Dentist.Id)
+	if appointment.Status == "" {
+		appointment.Status = domain.AppointmentScheduled
+	}
+	result, err := stmt.Exec(date, hour, appointment.Description, nullString(appointment.ProcedureCode), appointment.Status, appointment.Patient.Id, appointment.Dentist.Id)
 	if err != nil {
-		return domain.Appointment{}, err
+		return domain.Appointment{}, slotError(err)
 	}
 	insertedId, _ := result.LastInsertId()
 	appointment.Id = int(insertedId)
@@ -84,7 +128,7 @@ func (s *appointmentSqlStore) Update(appointment domain.Appointment) (bool, bool
 	if err != nil {
 		return false, false, domain.Appointment{}, err
 	}
-	stmt, err := s.DB.Prepare("UPDATE appointment SET date = ?, hour = ?, description = ?, patient_id = ?, dentist_id = ? WHERE id = ?;")
+	stmt, err := s.DB.Prepare("UPDATE appointment SET date = ?, hour = ?, description = ?, procedure_code = ?, status = ?, patient_id = ?, dentist_id = ? WHERE id = ?;")
 	if err != nil {
 		return false, false, domain.Appointment{}, err
 	}
@@ -98,13 +142,23 @@ func (s *appointmentSqlStore) Update(appointment domain.Appointment) (bool, bool
 		return false, false, domain.Appointment{}, err
 	}
 	hour := time.Date(1970, 1, 1, t.Hour(), t.Minute(), t.Second(), 0, time.UTC).Format("15:04:05")
-	_, err = stmt.Exec(date, hour, appointmentUpdated.Description, appointmentUpdated.Patient.Id, appointmentUpdated.Dentist.Id, appointmentUpdated.Id)
+	_, err = stmt.Exec(date, hour, appointmentUpdated.Description, nullString(appointmentUpdated.ProcedureCode), appointmentUpdated.Status, appointmentUpdated.Patient.Id, appointmentUpdated.Dentist.Id, appointmentUpdated.Id)
 	if err != nil {
-		return false, false, domain.Appointment{}, err
+		return false, false, domain.Appointment{}, slotError(err)
 	}
 	return patientFlag, dentistFlag, appointmentUpdated, nil
 }
 
+// UpdateStatus actualiza el estado de un turno
+func (s *appointmentSqlStore) UpdateStatus(id int, status string) error {
+	stmt := "UPDATE appointment SET status = ? WHERE id = ?"
+	_, err := s.DB.Exec(stmt, status, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
 // Delete elimina un turno
 func (s *appointmentSqlStore) Delete(id int) error {
 	stmt := "DELETE FROM appointment WHERE id = ?"
@@ -115,6 +169,40 @@ func (s *appointmentSqlStore) Delete(id int) error {
 	return nil
 }
 
+// getAlerts resume la historia medica de un paciente, primero las alergias mas severas
+func (s *appointmentSqlStore) getAlerts(patientId int) ([]domain.MedicalAlert, error) {
+	alerts := []domain.MedicalAlert{}
+
+	query := `SELECT type, description, severity FROM (
+		SELECT ? AS type, CONCAT(substance, IF(reaction = '', '', CONCAT(' (', reaction, ')'))) AS description, severity, FIELD(severity, ?, ?, ?) AS sort FROM allergy WHERE patient_id = ?
+		UNION ALL
+		SELECT ?, TRIM(CONCAT(name, ' ', dose, ' ', frequency)), '', 4 FROM medication WHERE patient_id = ?
+		UNION ALL
+		SELECT ?, name, '', 5 FROM medical_condition WHERE patient_id = ?
+	) alerts ORDER BY sort, description`
+	rows, err := s.DB.Query(query,
+		domain.AlertAllergy, domain.SeveritySevere, domain.SeverityModerate, domain.SeverityMild, patientId,
+		domain.AlertMedication, patientId,
+		domain.AlertCondition, patientId)
+	if err != nil {
+		return []domain.MedicalAlert{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var alert domain.MedicalAlert
+		err := rows.Scan(&alert.Type, &alert.Description, &alert.Severity)
+		if err != nil {
+			return []domain.MedicalAlert{}, err
+		}
+		alerts = append(alerts, alert)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.MedicalAlert{}, err
+	}
+	return alerts, nil
+}
+
 // completeEmptyAttributes compara el turno viejo con el nuevo y se queda con los campos diferentes
 func (s *appointmentSqlStore) CompleteEmptyAttributes(updatedAppointment domain.Appointment) (bool, bool, domain.Appointment, error) {
 	a, err := s.GetByID(updatedAppointment.Id)
@@ -132,13 +220,19 @@ func (s *appointmentSqlStore) CompleteEmptyAttributes(updatedAppointment domain.
 	if updatedAppointment.Description != "" {
 		a.Description = updatedAppointment.Description
 	}
-	if (updatedAppointment.Patient != domain.Patient{} && updatedAppointment.Patient.Id != 0) {
+	if updatedAppointment.ProcedureCode != "" {
+		a.ProcedureCode = updatedAppointment.ProcedureCode
+	}
+	if updatedAppointment.Status != "" {
+		a.Status = updatedAppointment.Status
+	}
+	if !updatedAppointment.Patient.Empty() && updatedAppointment.Patient.Id != 0 {
 		if a.Patient.Id != updatedAppointment.Patient.Id {
 			patientFlag = true
 		}
 		a.Patient = updatedAppointment.Patient
 	}
-	if (updatedAppointment.Dentist != domain.Dentist{} && updatedAppointment.Dentist.Id != 0) {
+	if !updatedAppointment.Dentist.Empty() && updatedAppointment.Dentist.Id != 0 {
 		if a.Dentist.Id != updatedAppointment.Dentist.Id {
 			dentistFlag = true
 		}
@@ -146,3 +240,20 @@ func (s *appointmentSqlStore) CompleteEmptyAttributes(updatedAppointment domain.
 	}
 	return patientFlag, dentistFlag, a, nil
 }
+
+// appointmentFields devuelve los destinos de Scan para las columnas de appointmentColumns
+func appointmentFields(a *domain.Appointment) []interface{} {
+	fields := []interface{}{&a.Id, &a.Date, &a.Hour, &a.Description, &a.ProcedureCode, &a.Status}
+	fields = append(fields, patientFields(&a.Patient)...)
+	return append(fields, &a.Dentist.Id, &a.Dentist.Name, &a.Dentist.LastName, &a.Dentist.License)
+}
+
+// slotError traduce la violacion del indice unico de horarios de un dentista a ErrSlotTaken. El indice
+// evita que dos pedidos simultaneos reserven el mismo horario aunque ambos lo hayan visto libre.
+func slotError(err error) error {
+	var mysqlErr *mysql.MySQLError
+	if errors.As(err, &mysqlErr) && mysqlErr.Number == 1062 {
+		return ErrSlotTaken
+	}
+	return err
+}
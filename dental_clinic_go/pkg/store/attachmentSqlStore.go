@@ -0,0 +1,94 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+)
+
+type attachmentSqlStore struct {
+	DB *sql.DB
+}
+
+// NewAttachmentSqlStore crea un nuevo store de archivos adjuntos
+func NewAttachmentSqlStore(db *sql.DB) AttachmentStore {
+	return &attachmentSqlStore{db}
+}
+
+// GetByID devuelve la metadata de un archivo por su id
+func (s *attachmentSqlStore) GetByID(id int) (domain.Attachment, error) {
+	attachments, err := s.getAttachments("id = ?", id)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	if len(attachments) == 0 {
+		return domain.Attachment{}, sql.ErrNoRows
+	}
+	return attachments[0], nil
+}
+
+// GetByPatient devuelve los archivos de un paciente, incluidos los de sus turnos
+func (s *attachmentSqlStore) GetByPatient(patientId int) ([]domain.Attachment, error) {
+	return s.getAttachments("patient_id = ?", patientId)
+}
+
+// GetByAppointment devuelve los archivos de un turno
+func (s *attachmentSqlStore) GetByAppointment(appointmentId int) ([]domain.Attachment, error) {
+	return s.getAttachments("appointment_id = ?", appointmentId)
+}
+
+// Create agrega la metadata de un archivo
+func (s *attachmentSqlStore) Create(a domain.Attachment) (domain.Attachment, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO attachment (patient_id, appointment_id, file_name, description, content_type, size, checksum, storage_key, has_thumbnail) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);")
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	defer stmt.Close()
+	var appointmentId sql.NullInt64
+	if a.AppointmentId != 0 {
+		appointmentId = sql.NullInt64{Int64: int64(a.AppointmentId), Valid: true}
+	}
+	result, err := stmt.Exec(a.PatientId, appointmentId, a.FileName, a.Description, a.ContentType, a.Size, a.Checksum, a.StorageKey, a.HasThumbnail)
+	if err != nil {
+		return domain.Attachment{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	a.Id = int(insertedId)
+	return a, nil
+}
+
+// Delete elimina la metadata de un archivo
+func (s *attachmentSqlStore) Delete(id int) error {
+	stmt := "DELETE FROM attachment WHERE id = ?"
+	_, err := s.DB.Exec(stmt, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getAttachments busca los archivos que cumplen la condicion, primero los mas recientes
+func (s *attachmentSqlStore) getAttachments(condition string, args ...interface{}) ([]domain.Attachment, error) {
+	attachments := []domain.Attachment{}
+
+	query := "SELECT id, patient_id, appointment_id, file_name, description, content_type, size, checksum, storage_key, has_thumbnail, created_at FROM attachment WHERE " + condition + " ORDER BY created_at DESC, id DESC"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.Attachment{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a domain.Attachment
+		var appointmentId sql.NullInt64
+		err := rows.Scan(&a.Id, &a.PatientId, &appointmentId, &a.FileName, &a.Description, &a.ContentType, &a.Size, &a.Checksum, &a.StorageKey, &a.HasThumbnail, &a.CreatedAt)
+		if err != nil {
+			return []domain.Attachment{}, err
+		}
+		a.AppointmentId = int(appointmentId.Int64)
+		attachments = append(attachments, a)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Attachment{}, err
+	}
+	return attachments, nil
+}
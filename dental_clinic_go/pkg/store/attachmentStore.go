@@ -0,0 +1,11 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type AttachmentStore interface {
+	GetByID(id int) (domain.Attachment, error)
+	GetByPatient(patientId int) ([]domain.Attachment, error)
+	GetByAppointment(appointmentId int) ([]domain.Attachment, error)
+	Create(a domain.Attachment) (domain.Attachment, error)
+	Delete(id int) error
+}
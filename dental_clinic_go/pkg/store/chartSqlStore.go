@@ -0,0 +1,62 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+
+	"time"
+)
+
+type chartSqlStore struct {
+	DB *sql.DB
+}
+
+// NewChartSqlStore crea un nuevo store del odontograma
+func NewChartSqlStore(db *sql.DB) ChartStore {
+	return &chartSqlStore{db}
+}
+
+// GetByPatient devuelve el historial del odontograma de un paciente en orden cronologico
+func (s *chartSqlStore) GetByPatient(patientId int) ([]domain.ChartEntry, error) {
+	var entries []domain.ChartEntry
+
+	query := "SELECT chart_entry.id, chart_entry.patient_id, chart_entry.tooth, chart_entry.surfaces, chart_entry.tooth_condition, COALESCE(chart_entry.procedure_code, ''), chart_entry.date, chart_entry.notes, chart_entry.created_at, dentist.* FROM chart_entry INNER JOIN dentist ON chart_entry.dentist_id = dentist.id WHERE chart_entry.patient_id = ? ORDER BY chart_entry.date, chart_entry.id"
+	rows, err := s.DB.Query(query, patientId)
+	if err != nil {
+		return []domain.ChartEntry{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var entry domain.ChartEntry
+		err := rows.Scan(&entry.Id, &entry.PatientId, &entry.Tooth, &entry.Surfaces, &entry.Condition, &entry.ProcedureCode, &entry.Date, &entry.Notes, &entry.CreatedAt, &entry.Dentist.Id, &entry.Dentist.Name, &entry.Dentist.LastName, &entry.Dentist.License)
+		if err != nil {
+			return []domain.ChartEntry{}, err
+		}
+		entries = append(entries, entry)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.ChartEntry{}, err
+	}
+	return entries, nil
+}
+
+// Create agrega una nueva entrada al odontograma
+func (s *chartSqlStore) Create(entry domain.ChartEntry) (domain.ChartEntry, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO chart_entry (patient_id, tooth, surfaces, tooth_condition, procedure_code, date, notes, dentist_id) VALUES (?, ?, ?, ?, ?, ?, ?, ?);")
+	if err != nil {
+		return domain.ChartEntry{}, err
+	}
+	defer stmt.Close()
+	date, err := time.Parse("2006-01-02", entry.Date)
+	if err != nil {
+		return domain.ChartEntry{}, err
+	}
+	result, err := stmt.Exec(entry.PatientId, entry.Tooth, entry.Surfaces, entry.Condition, nullString(entry.ProcedureCode), date, entry.Notes, entry.Dentist.Id)
+	if err != nil {
+		return domain.ChartEntry{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	entry.Id = int(insertedId)
+	return entry, nil
+}
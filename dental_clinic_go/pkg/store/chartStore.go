@@ -0,0 +1,8 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type ChartStore interface {
+	GetByPatient(patientId int) ([]domain.ChartEntry, error)
+	Create(entry domain.ChartEntry) (domain.ChartEntry, error)
+}
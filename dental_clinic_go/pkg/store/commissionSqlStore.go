@@ -0,0 +1,130 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+)
+
+type commissionSqlStore struct {
+	DB *sql.DB
+}
+
+// NewCommissionSqlStore crea un nuevo store de comisiones y produccion de los dentistas
+func NewCommissionSqlStore(db *sql.DB) CommissionStore {
+	return &commissionSqlStore{db}
+}
+
+// GetPlan devuelve las reglas de comision de un dentista, la regla sin procedimiento es el porcentaje por defecto
+func (s *commissionSqlStore) GetPlan(dentistId int) (domain.CommissionPlan, error) {
+	plan := domain.CommissionPlan{DentistId: dentistId, Rules: []domain.CommissionRule{}}
+
+	query := "SELECT COALESCE(procedure_code, ''), percent FROM commission_rule WHERE dentist_id = ? ORDER BY procedure_code"
+	rows, err := s.DB.Query(query, dentistId)
+	if err != nil {
+		return domain.CommissionPlan{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r domain.CommissionRule
+		err := rows.Scan(&r.ProcedureCode, &r.Percent)
+		if err != nil {
+			return domain.CommissionPlan{}, err
+		}
+		if r.ProcedureCode == "" {
+			plan.DefaultPercent = r.Percent
+			continue
+		}
+		plan.Rules = append(plan.Rules, r)
+	}
+	if err = rows.Err(); err != nil {
+		return domain.CommissionPlan{}, err
+	}
+	return plan, nil
+}
+
+// SavePlan reemplaza las reglas de comision de un dentista en una transaccion
+func (s *commissionSqlStore) SavePlan(plan domain.CommissionPlan) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec("DELETE FROM commission_rule WHERE dentist_id = ?", plan.DentistId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO commission_rule (dentist_id, procedure_code, percent) VALUES (?, NULL, ?);", plan.DentistId, plan.DefaultPercent)
+	if err != nil {
+		return err
+	}
+	for _, r := range plan.Rules {
+		_, err = tx.Exec("INSERT INTO commission_rule (dentist_id, procedure_code, percent) VALUES (?, ?, ?);", plan.DentistId, r.ProcedureCode, r.Percent)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// GetCompleted devuelve los dentistas con la cantidad de turnos completados entre dos fechas
+func (s *commissionSqlStore) GetCompleted(from string, to string) ([]domain.DentistProduction, error) {
+	dentists := []domain.DentistProduction{}
+
+	query := `SELECT dentist.id, dentist.name, dentist.last_name, dentist.license, COUNT(appointment.id) FROM appointment
+		INNER JOIN dentist ON appointment.dentist_id = dentist.id
+		WHERE appointment.status = ? AND appointment.date BETWEEN ? AND ?
+		GROUP BY dentist.id, dentist.name, dentist.last_name, dentist.license ORDER BY dentist.last_name, dentist.name`
+	rows, err := s.DB.Query(query, domain.AppointmentCompleted, from, to)
+	if err != nil {
+		return []domain.DentistProduction{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d domain.DentistProduction
+		err := rows.Scan(&d.DentistId, &d.Name, &d.LastName, &d.License, &d.CompletedAppointments)
+		if err != nil {
+			return []domain.DentistProduction{}, err
+		}
+		dentists = append(dentists, d)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.DentistProduction{}, err
+	}
+	return dentists, nil
+}
+
+// GetBilled devuelve los items de las facturas emitidas por turnos completados entre dos fechas, con el
+// dentista de cada turno
+func (s *commissionSqlStore) GetBilled(from string, to string) ([]domain.BilledItem, error) {
+	items := []domain.BilledItem{}
+
+	query := `SELECT dentist.id, dentist.name, dentist.last_name, dentist.license, appointment.id, appointment.date,
+		COALESCE(invoice_item.procedure_code, ''), invoice_item.description, invoice_item.quantity, invoice_item.subtotal - invoice_item.discount
+		FROM invoice_item
+		INNER JOIN invoice ON invoice_item.invoice_id = invoice.id
+		INNER JOIN appointment ON invoice.appointment_id = appointment.id
+		INNER JOIN dentist ON appointment.dentist_id = dentist.id
+		WHERE invoice.status = ? AND appointment.status = ? AND appointment.date BETWEEN ? AND ?
+		ORDER BY dentist.id, appointment.date, invoice_item.id`
+	rows, err := s.DB.Query(query, domain.InvoiceIssued, domain.AppointmentCompleted, from, to)
+	if err != nil {
+		return []domain.BilledItem{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.BilledItem
+		err := rows.Scan(&i.Dentist.Id, &i.Dentist.Name, &i.Dentist.LastName, &i.Dentist.License, &i.AppointmentId, &i.Date,
+			&i.ProcedureCode, &i.Description, &i.Quantity, &i.Amount)
+		if err != nil {
+			return []domain.BilledItem{}, err
+		}
+		items = append(items, i)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.BilledItem{}, err
+	}
+	return items, nil
+}
@@ -0,0 +1,10 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type CommissionStore interface {
+	GetPlan(dentistId int) (domain.CommissionPlan, error)
+	SavePlan(plan domain.CommissionPlan) error
+	GetCompleted(from string, to string) ([]domain.DentistProduction, error)
+	GetBilled(from string, to string) ([]domain.BilledItem, error)
+}
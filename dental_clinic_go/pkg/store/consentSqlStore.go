@@ -0,0 +1,235 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"strings"
+)
+
+type consentSqlStore struct {
+	DB *sql.DB
+}
+
+// NewConsentSqlStore crea un nuevo store de consentimientos informados
+func NewConsentSqlStore(db *sql.DB) ConsentStore {
+	return &consentSqlStore{db}
+}
+
+// GetTemplates devuelve todas las plantillas
+func (s *consentSqlStore) GetTemplates() ([]domain.ConsentTemplate, error) {
+	return s.getTemplates("SELECT id, name, body, active, created_at FROM consent_template ORDER BY name")
+}
+
+// GetTemplateByID devuelve una plantilla por su id
+func (s *consentSqlStore) GetTemplateByID(id int) (domain.ConsentTemplate, error) {
+	templates, err := s.getTemplates("SELECT id, name, body, active, created_at FROM consent_template WHERE id = ?", id)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	if len(templates) == 0 {
+		return domain.ConsentTemplate{}, sql.ErrNoRows
+	}
+	return templates[0], nil
+}
+
+// GetRequiredTemplates devuelve las plantillas activas que requiere un procedimiento
+func (s *consentSqlStore) GetRequiredTemplates(procedureCode string) ([]domain.ConsentTemplate, error) {
+	query := "SELECT consent_template.id, consent_template.name, consent_template.body, consent_template.active, consent_template.created_at FROM consent_template INNER JOIN consent_template_procedure ON consent_template.id = consent_template_procedure.template_id WHERE consent_template_procedure.procedure_code = ? AND consent_template.active ORDER BY consent_template.id"
+	return s.getTemplates(query, procedureCode)
+}
+
+// CreateTemplate agrega una plantilla y sus procedimientos en una transaccion
+func (s *consentSqlStore) CreateTemplate(t domain.ConsentTemplate) (domain.ConsentTemplate, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT INTO consent_template (name, body, active) VALUES (?, ?, ?);", t.Name, t.Body, t.Active)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	t.Id = int(insertedId)
+	err = insertTemplateProcedures(tx, t)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	return t, nil
+}
+
+// UpdateTemplate reemplaza una plantilla y sus procedimientos en una transaccion
+func (s *consentSqlStore) UpdateTemplate(t domain.ConsentTemplate) (domain.ConsentTemplate, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec("UPDATE consent_template SET name = ?, body = ?, active = ? WHERE id = ?", t.Name, t.Body, t.Active, t.Id)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	_, err = tx.Exec("DELETE FROM consent_template_procedure WHERE template_id = ?", t.Id)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	err = insertTemplateProcedures(tx, t)
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.ConsentTemplate{}, err
+	}
+	return t, nil
+}
+
+// GetByID devuelve un consentimiento por su id
+func (s *consentSqlStore) GetByID(id int) (domain.Consent, error) {
+	consents, err := s.getConsents("consent.id = ?", id)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	if len(consents) == 0 {
+		return domain.Consent{}, sql.ErrNoRows
+	}
+	return consents[0], nil
+}
+
+// GetByPatient devuelve los consentimientos de un paciente, primero los mas recientes
+func (s *consentSqlStore) GetByPatient(patientId int) ([]domain.Consent, error) {
+	return s.getConsents("consent.patient_id = ?", patientId)
+}
+
+// Create agrega un consentimiento pendiente de firma
+func (s *consentSqlStore) Create(c domain.Consent) (domain.Consent, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO consent (template_id, patient_id, appointment_id, treatment_plan_id, body, status) VALUES (?, ?, ?, ?, ?, ?);")
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	defer stmt.Close()
+	result, err := stmt.Exec(c.TemplateId, c.PatientId, nullInt(c.AppointmentId), nullInt(c.TreatmentPlanId), c.Body, domain.ConsentPending)
+	if err != nil {
+		return domain.Consent{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	c.Id = int(insertedId)
+	return c, nil
+}
+
+// Sign guarda la firma de un consentimiento pendiente
+func (s *consentSqlStore) Sign(c domain.Consent) error {
+	stmt := "UPDATE consent SET status = ?, signer_name = ?, signature_key = ?, signed_ip = ?, signed_at = CURRENT_TIMESTAMP WHERE id = ? AND status = ?"
+	result, err := s.DB.Exec(stmt, domain.ConsentSigned, c.SignerName, c.SignatureKey, c.SignedIp, c.Id, domain.ConsentPending)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// HasSigned indica si hay un consentimiento firmado de la plantilla para el turno, ya sea
+// del propio turno o del plan de tratamiento que lo agendo
+func (s *consentSqlStore) HasSigned(templateId int, appointmentId int) (bool, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM consent WHERE template_id = ? AND status = ? AND (appointment_id = ? OR treatment_plan_id IN (SELECT plan_id FROM treatment_item WHERE appointment_id = ?));"
+	row := s.DB.QueryRow(query, templateId, domain.ConsentSigned, appointmentId, appointmentId)
+	err := row.Scan(&count)
+	if err != nil {
+		return false, err
+	}
+	return count > 0, nil
+}
+
+// getTemplates busca plantillas y completa sus procedimientos
+func (s *consentSqlStore) getTemplates(query string, args ...interface{}) ([]domain.ConsentTemplate, error) {
+	templates := []domain.ConsentTemplate{}
+
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.ConsentTemplate{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var t domain.ConsentTemplate
+		err := rows.Scan(&t.Id, &t.Name, &t.Body, &t.Active, &t.CreatedAt)
+		if err != nil {
+			return []domain.ConsentTemplate{}, err
+		}
+		templates = append(templates, t)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.ConsentTemplate{}, err
+	}
+	for i := range templates {
+		templates[i].Procedures, err = s.getTemplateProcedures(templates[i].Id)
+		if err != nil {
+			return []domain.ConsentTemplate{}, err
+		}
+	}
+	return templates, nil
+}
+
+// getTemplateProcedures devuelve los codigos de procedimiento que requieren la plantilla
+func (s *consentSqlStore) getTemplateProcedures(templateId int) ([]string, error) {
+	var codes string
+	row := s.DB.QueryRow("SELECT COALESCE(GROUP_CONCAT(procedure_code ORDER BY procedure_code), '') FROM consent_template_procedure WHERE template_id = ?;", templateId)
+	err := row.Scan(&codes)
+	if err != nil {
+		return []string{}, err
+	}
+	if codes == "" {
+		return []string{}, nil
+	}
+	return strings.Split(codes, ","), nil
+}
+
+// getConsents busca los consentimientos que cumplen la condicion
+func (s *consentSqlStore) getConsents(condition string, args ...interface{}) ([]domain.Consent, error) {
+	consents := []domain.Consent{}
+
+	query := "SELECT consent.id, consent.template_id, consent.patient_id, consent.appointment_id, consent.treatment_plan_id, consent_template.name, consent.body, consent.status, consent.signer_name, consent.signature_key, COALESCE(consent.signed_at, ''), consent.signed_ip, consent.created_at FROM consent INNER JOIN consent_template ON consent.template_id = consent_template.id WHERE " + condition + " ORDER BY consent.created_at DESC, consent.id DESC"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.Consent{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c domain.Consent
+		var appointmentId, planId sql.NullInt64
+		err := rows.Scan(&c.Id, &c.TemplateId, &c.PatientId, &appointmentId, &planId, &c.Name, &c.Body, &c.Status, &c.SignerName, &c.SignatureKey, &c.SignedAt, &c.SignedIp, &c.CreatedAt)
+		if err != nil {
+			return []domain.Consent{}, err
+		}
+		c.AppointmentId = int(appointmentId.Int64)
+		c.TreatmentPlanId = int(planId.Int64)
+		consents = append(consents, c)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Consent{}, err
+	}
+	return consents, nil
+}
+
+// insertTemplateProcedures guarda los procedimientos que requieren la plantilla
+func insertTemplateProcedures(tx *sql.Tx, t domain.ConsentTemplate) error {
+	for _, code := range t.Procedures {
+		_, err := tx.Exec("INSERT INTO consent_template_procedure (template_id, procedure_code) VALUES (?, ?);", t.Id, code)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,16 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type ConsentStore interface {
+	GetTemplates() ([]domain.ConsentTemplate, error)
+	GetTemplateByID(id int) (domain.ConsentTemplate, error)
+	GetRequiredTemplates(procedureCode string) ([]domain.ConsentTemplate, error)
+	CreateTemplate(t domain.ConsentTemplate) (domain.ConsentTemplate, error)
+	UpdateTemplate(t domain.ConsentTemplate) (domain.ConsentTemplate, error)
+	GetByID(id int) (domain.Consent, error)
+	GetByPatient(patientId int) ([]domain.Consent, error)
+	Create(c domain.Consent) (domain.Consent, error)
+	Sign(c domain.Consent) error
+	HasSigned(templateId int, appointmentId int) (bool, error)
+}
@@ -14,63 +14,74 @@ func NewDentistSqlStore(db *sql.DB) DentistStore {
 	return &dentistSqlStore{db}
 }
 
+// GetAll devuelve los dentistas, si specialty no esta vacia solo los que tienen esa especialidad
+func (s *dentistSqlStore) GetAll(specialty string) ([]domain.Dentist, error) {
+	return s.getDentists("? = '' OR id IN (SELECT dentist_id FROM dentist_specialty WHERE specialty = ?)", specialty, specialty)
+}
+
 // GetByID devuelve un dentista por su id
 func (s *dentistSqlStore) GetByID(id int) (domain.Dentist, error) {
-	var dentistReturn domain.Dentist
-
-	query := "SELECT * FROM dentist WHERE id = ?;"
-	row := s.DB.QueryRow(query, id)
-	err := row.Scan(&dentistReturn.Id, &dentistReturn.Name, &dentistReturn.LastName, &dentistReturn.License)
-
-	if err != nil {
-		return domain.Dentist{}, err
-	}
-	return dentistReturn, nil
+	return s.getDentist("id = ?", id)
 }
 
 // GetByLicense devuelve un dentista por su matricula
 func (s *dentistSqlStore) GetByLicense(license string) (domain.Dentist, error) {
-	var dentistReturn domain.Dentist
-
-	query := "SELECT * FROM dentist WHERE license = ?;"
-	row := s.DB.QueryRow(query, license)
-	err := row.Scan(&dentistReturn.Id, &dentistReturn.Name, &dentistReturn.LastName, &dentistReturn.License)
-
-	if err != nil {
-		return domain.Dentist{}, err
-	}
-	return dentistReturn, nil
+	return s.getDentist("license = ?", license)
 }
 
-// Create agrega un nuevo dentista
+// Create agrega un nuevo dentista con sus especialidades
 func (s *dentistSqlStore) Create(dentist domain.Dentist) (domain.Dentist, error) {
-	stmt, err := s.DB.Prepare("INSERT INTO dentist(name, last_name, license) VALUES( ?, ?, ?)")
+	tx, err := s.DB.Begin()
 	if err != nil {
 		return domain.Dentist{}, err
 	}
-	defer stmt.Close()
-	var result sql.Result
-	result, err = stmt.Exec(dentist.Name, dentist.LastName, dentist.License)
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT INTO dentist(name, last_name, license) VALUES( ?, ?, ?)", dentist.Name, dentist.LastName, dentist.License)
 	if err != nil {
 		return domain.Dentist{}, err
 	}
 	insertedId, _ := result.LastInsertId()
 	dentist.Id = int(insertedId)
+	err = insertSpecialties(tx, dentist)
+	if err != nil {
+		return domain.Dentist{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.Dentist{}, err
+	}
+	if dentist.Specialties == nil {
+		dentist.Specialties = []string{}
+	}
 	return dentist, nil
 }
 
-// Update actualiza un dentista
+// Update actualiza un dentista, si trae especialidades reemplazan a las anteriores
 func (s *dentistSqlStore) Update(dentist domain.Dentist) (domain.Dentist, error) {
 	dentistUpdated, err := s.CompleteEmptyAttributes(dentist)
 	if err != nil {
 		return domain.Dentist{}, err
 	}
-	stmt, err := s.DB.Prepare("UPDATE dentist SET name = ?, last_name = ?, license = ? WHERE id = ?;")
+	tx, err := s.DB.Begin()
 	if err != nil {
 		return domain.Dentist{}, err
 	}
-	defer stmt.Close()
-	_, err = stmt.Exec(dentistUpdated.Name, dentistUpdated.LastName, dentistUpdated.License, dentist.Id)
+	defer tx.Rollback()
+	_, err = tx.Exec("UPDATE dentist SET name = ?, last_name = ?, license = ? WHERE id = ?;", dentistUpdated.Name, dentistUpdated.LastName, dentistUpdated.License, dentist.Id)
+	if err != nil {
+		return domain.Dentist{}, err
+	}
+	if dentist.Specialties != nil {
+		_, err = tx.Exec("DELETE FROM dentist_specialty WHERE dentist_id = ?", dentist.Id)
+		if err != nil {
+			return domain.Dentist{}, err
+		}
+		err = insertSpecialties(tx, dentistUpdated)
+		if err != nil {
+			return domain.Dentist{}, err
+		}
+	}
+	err = tx.Commit()
 	if err != nil {
 		return domain.Dentist{}, err
 	}
@@ -102,5 +113,89 @@ func (s *dentistSqlStore) CompleteEmptyAttributes(updatedDentist domain.Dentist)
 	if updatedDentist.License != "" {
 		d.License = updatedDentist.License
 	}
+	if updatedDentist.Specialties != nil {
+		d.Specialties = updatedDentist.Specialties
+	}
 	return d, nil
 }
+
+// getDentist busca el primer dentista que cumple la condicion
+func (s *dentistSqlStore) getDentist(condition string, args ...interface{}) (domain.Dentist, error) {
+	dentists, err := s.getDentists(condition, args...)
+	if err != nil {
+		return domain.Dentist{}, err
+	}
+	if len(dentists) == 0 {
+		return domain.Dentist{}, sql.ErrNoRows
+	}
+	return dentists[0], nil
+}
+
+// getDentists busca los dentistas que cumplen la condicion y completa sus especialidades
+func (s *dentistSqlStore) getDentists(condition string, args ...interface{}) ([]domain.Dentist, error) {
+	dentists := []domain.Dentist{}
+
+	query := "SELECT * FROM dentist WHERE " + condition + " ORDER BY last_name, name"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.Dentist{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d domain.Dentist
+		err := rows.Scan(&d.Id, &d.Name, &d.LastName, &d.License)
+		if err != nil {
+			return []domain.Dentist{}, err
+		}
+		dentists = append(dentists, d)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Dentist{}, err
+	}
+	for i := range dentists {
+		dentists[i].Specialties, err = s.getSpecialties(dentists[i].Id)
+		if err != nil {
+			return []domain.Dentist{}, err
+		}
+	}
+	return dentists, nil
+}
+
+// getSpecialties devuelve los codigos de las especialidades de un dentista
+func (s *dentistSqlStore) getSpecialties(dentistId int) ([]string, error) {
+	specialties := []string{}
+
+	query := "SELECT specialty FROM dentist_specialty WHERE dentist_id = ? ORDER BY specialty"
+	rows, err := s.DB.Query(query, dentistId)
+	if err != nil {
+		return []string{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var specialty string
+		err := rows.Scan(&specialty)
+		if err != nil {
+			return []string{}, err
+		}
+		specialties = append(specialties, specialty)
+	}
+	if err = rows.Err(); err != nil {
+		return []string{}, err
+	}
+	return specialties, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// insertSpecialties agrega las especialidades de un dentista dentro de una transaccion
+func insertSpecialties(tx *sql.Tx, dentist domain.Dentist) error {
+	for _, specialty := range dentist.Specialties {
+		_, err := tx.Exec("INSERT INTO dentist_specialty (dentist_id, specialty) VALUES (?, ?);", dentist.Id, specialty)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
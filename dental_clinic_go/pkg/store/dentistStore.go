@@ -3,6 +3,7 @@ package store
 import "dental_clinic_go/internal/domain"
 
 type DentistStore interface {
+	GetAll(specialty string) ([]domain.Dentist, error)
 	GetByID(id int) (domain.Dentist, error)
 	GetByLicense(license string) (domain.Dentist, error)
 	Create(dentist domain.Dentist) (domain.Dentist, error)
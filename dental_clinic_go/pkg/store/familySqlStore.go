@@ -0,0 +1,113 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"time"
+)
+
+type familySqlStore struct {
+	DB *sql.DB
+}
+
+// NewFamilySqlStore crea un nuevo store de relaciones familiares
+func NewFamilySqlStore(db *sql.DB) FamilyStore {
+	return &familySqlStore{db}
+}
+
+// GetByID devuelve una relacion con los datos del familiar
+func (s *familySqlStore) GetByID(id int) (domain.PatientRelation, error) {
+	relations, err := s.getRelations("patient_relation.id = ?", id)
+	if err != nil {
+		return domain.PatientRelation{}, err
+	}
+	if len(relations) == 0 {
+		return domain.PatientRelation{}, sql.ErrNoRows
+	}
+	return relations[0], nil
+}
+
+// GetByPatient devuelve los guardianes, padres y conyuges registrados de un paciente
+func (s *familySqlStore) GetByPatient(patientId int) ([]domain.PatientRelation, error) {
+	return s.getRelations("patient_relation.patient_id = ?", patientId)
+}
+
+// GetByMember devuelve las relaciones en las que participa un paciente de cualquiera de los dos lados
+func (s *familySqlStore) GetByMember(patientId int) ([]domain.PatientRelation, error) {
+	return s.getRelations("patient_relation.patient_id = ? OR patient_relation.related_id = ?", patientId, patientId)
+}
+
+// Create agrega una relacion, si es responsable quita el responsable anterior en una transaccion
+func (s *familySqlStore) Create(relation domain.PatientRelation) (domain.PatientRelation, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.PatientRelation{}, err
+	}
+	defer tx.Rollback()
+	if relation.Responsible {
+		_, err = tx.Exec("UPDATE patient_relation SET responsible = FALSE WHERE patient_id = ?", relation.PatientId)
+		if err != nil {
+			return domain.PatientRelation{}, err
+		}
+	}
+	result, err := tx.Exec("INSERT INTO patient_relation (patient_id, related_id, relationship, responsible) VALUES (?, ?, ?, ?);",
+		relation.PatientId, relation.RelatedId, relation.Relationship, relation.Responsible)
+	if err != nil {
+		return domain.PatientRelation{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	relation.Id = int(insertedId)
+	err = tx.Commit()
+	if err != nil {
+		return domain.PatientRelation{}, err
+	}
+	return relation, nil
+}
+
+// SetResponsible deja como unico responsable del paciente a la relacion, con 0 no queda ninguno
+func (s *familySqlStore) SetResponsible(patientId int, relationId int) error {
+	_, err := s.DB.Exec("UPDATE patient_relation SET responsible = (id = ?) WHERE patient_id = ?", relationId, patientId)
+	return err
+}
+
+// Delete elimina una relacion
+func (s *familySqlStore) Delete(id int) error {
+	result, err := s.DB.Exec("DELETE FROM patient_relation WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// getRelations busca las relaciones que cumplen la condicion junto con los datos del familiar
+func (s *familySqlStore) getRelations(condition string, args ...interface{}) ([]domain.PatientRelation, error) {
+	relations := []domain.PatientRelation{}
+
+	query := "SELECT patient_relation.id, patient_relation.patient_id, patient_relation.related_id, patient_relation.relationship, patient_relation.responsible, patient_relation.created_at, " + patientColumns + " FROM patient_relation INNER JOIN patient ON patient_relation.related_id = patient.id WHERE " + condition + " ORDER BY patient_relation.id"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.PatientRelation{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r domain.PatientRelation
+		err := rows.Scan(append([]interface{}{&r.Id, &r.PatientId, &r.RelatedId, &r.Relationship, &r.Responsible, &r.CreatedAt}, patientFields(&r.Related)...)...)
+		if err != nil {
+			return []domain.PatientRelation{}, err
+		}
+		r.Related.Age = r.Related.AgeAt(time.Now())
+		relations = append(relations, r)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.PatientRelation{}, err
+	}
+	return relations, nil
+}
@@ -0,0 +1,12 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type FamilyStore interface {
+	GetByID(id int) (domain.PatientRelation, error)
+	GetByPatient(patientId int) ([]domain.PatientRelation, error)
+	GetByMember(patientId int) ([]domain.PatientRelation, error)
+	Create(relation domain.PatientRelation) (domain.PatientRelation, error)
+	SetResponsible(patientId int, relationId int) error
+	Delete(id int) error
+}
@@ -0,0 +1,417 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+)
+
+// coverageColumns son las columnas de una cobertura con su plan y financiador en el orden que espera coverageFields
+const coverageColumns = "patient_coverage.id, patient_coverage.patient_id, patient_coverage.plan_id, insurance_plan.code, insurance_plan.name, insurer.id, insurer.name, patient_coverage.member_number, patient_coverage.valid_from, COALESCE(patient_coverage.valid_to, '')"
+
+type insuranceSqlStore struct {
+	DB *sql.DB
+}
+
+// NewInsuranceSqlStore crea un nuevo store de obras sociales y prepagas
+func NewInsuranceSqlStore(db *sql.DB) InsuranceStore {
+	return &insuranceSqlStore{db}
+}
+
+// GetInsurers devuelve los financiadores ordenados por nombre
+func (s *insuranceSqlStore) GetInsurers() ([]domain.Insurer, error) {
+	insurers := []domain.Insurer{}
+
+	query := "SELECT id, code, name, insurer_type, claim_format FROM insurer ORDER BY name"
+	rows, err := s.DB.Query(query)
+	if err != nil {
+		return []domain.Insurer{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.Insurer
+		err := rows.Scan(&i.Id, &i.Code, &i.Name, &i.Type, &i.ClaimFormat)
+		if err != nil {
+			return []domain.Insurer{}, err
+		}
+		insurers = append(insurers, i)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Insurer{}, err
+	}
+	return insurers, nil
+}
+
+// GetInsurer devuelve un financiador con sus planes
+func (s *insuranceSqlStore) GetInsurer(id int) (domain.Insurer, error) {
+	var i domain.Insurer
+	query := "SELECT id, code, name, insurer_type, claim_format FROM insurer WHERE id = ?"
+	err := s.DB.QueryRow(query, id).Scan(&i.Id, &i.Code, &i.Name, &i.Type, &i.ClaimFormat)
+	if err != nil {
+		return domain.Insurer{}, err
+	}
+	i.Plans, err = s.getPlans("insurer_id = ?", id)
+	if err != nil {
+		return domain.Insurer{}, err
+	}
+	return i, nil
+}
+
+// CreateInsurer agrega un financiador
+func (s *insuranceSqlStore) CreateInsurer(insurer domain.Insurer) (domain.Insurer, error) {
+	result, err := s.DB.Exec("INSERT INTO insurer (code, name, insurer_type, claim_format) VALUES (?, ?, ?, ?);",
+		insurer.Code, insurer.Name, insurer.Type, insurer.ClaimFormat)
+	if err != nil {
+		return domain.Insurer{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	insurer.Id = int(insertedId)
+	return insurer, nil
+}
+
+// UpdateInsurer actualiza los datos de un financiador
+func (s *insuranceSqlStore) UpdateInsurer(insurer domain.Insurer) error {
+	_, err := s.DB.Exec("UPDATE insurer SET code = ?, name = ?, insurer_type = ?, claim_format = ? WHERE id = ?",
+		insurer.Code, insurer.Name, insurer.Type, insurer.ClaimFormat, insurer.Id)
+	return err
+}
+
+// GetPlan devuelve un plan con sus reglas de cobertura
+func (s *insuranceSqlStore) GetPlan(id int) (domain.InsurancePlan, error) {
+	plans, err := s.getPlans("id = ?", id)
+	if err != nil {
+		return domain.InsurancePlan{}, err
+	}
+	if len(plans) == 0 {
+		return domain.InsurancePlan{}, sql.ErrNoRows
+	}
+	plan := plans[0]
+	plan.Rules, err = s.getRules(id)
+	if err != nil {
+		return domain.InsurancePlan{}, err
+	}
+	return plan, nil
+}
+
+// CreatePlan agrega un plan a un financiador
+func (s *insuranceSqlStore) CreatePlan(plan domain.InsurancePlan) (domain.InsurancePlan, error) {
+	result, err := s.DB.Exec("INSERT INTO insurance_plan (insurer_id, code, name) VALUES (?, ?, ?);", plan.InsurerId, plan.Code, plan.Name)
+	if err != nil {
+		return domain.InsurancePlan{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	plan.Id = int(insertedId)
+	return plan, nil
+}
+
+// SaveRule agrega la regla de un procedimiento a un plan o reemplaza la que tenia
+func (s *insuranceSqlStore) SaveRule(rule domain.CoverageRule) error {
+	_, err := s.DB.Exec(`INSERT INTO coverage_rule (plan_id, procedure_code, percent, copay, annual_limit) VALUES (?, ?, ?, ?, ?)
+		ON DUPLICATE KEY UPDATE percent = VALUES(percent), copay = VALUES(copay), annual_limit = VALUES(annual_limit);`,
+		rule.PlanId, rule.ProcedureCode, rule.Percent, rule.Copay, rule.AnnualLimit)
+	return err
+}
+
+// DeleteRule elimina la regla de un procedimiento de un plan
+func (s *insuranceSqlStore) DeleteRule(planId int, procedureCode string) error {
+	return s.delete("DELETE FROM coverage_rule WHERE plan_id = ? AND procedure_code = ?", planId, procedureCode)
+}
+
+// GetCoverage devuelve una cobertura por su id
+func (s *insuranceSqlStore) GetCoverage(id int) (domain.PatientCoverage, error) {
+	coverages, err := s.getCoverages("patient_coverage.id = ?", id)
+	if err != nil {
+		return domain.PatientCoverage{}, err
+	}
+	if len(coverages) == 0 {
+		return domain.PatientCoverage{}, sql.ErrNoRows
+	}
+	return coverages[0], nil
+}
+
+// GetCoverages devuelve las coberturas de un paciente, las mas recientes primero
+func (s *insuranceSqlStore) GetCoverages(patientId int) ([]domain.PatientCoverage, error) {
+	return s.getCoverages("patient_coverage.patient_id = ?", patientId)
+}
+
+// CreateCoverage agrega una cobertura a un paciente
+func (s *insuranceSqlStore) CreateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error) {
+	result, err := s.DB.Exec("INSERT INTO patient_coverage (patient_id, plan_id, member_number, valid_from, valid_to) VALUES (?, ?, ?, ?, ?);",
+		coverage.PatientId, coverage.PlanId, coverage.MemberNumber, coverage.ValidFrom, nullString(coverage.ValidTo))
+	if err != nil {
+		return domain.PatientCoverage{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	coverage.Id = int(insertedId)
+	return coverage, nil
+}
+
+// UpdateCoverage actualiza el plan, el numero de afiliado y la vigencia de una cobertura
+func (s *insuranceSqlStore) UpdateCoverage(coverage domain.PatientCoverage) error {
+	_, err := s.DB.Exec("UPDATE patient_coverage SET plan_id = ?, member_number = ?, valid_from = ?, valid_to = ? WHERE id = ? AND patient_id = ?",
+		coverage.PlanId, coverage.MemberNumber, coverage.ValidFrom, nullString(coverage.ValidTo), coverage.Id, coverage.PatientId)
+	return err
+}
+
+// DeleteCoverage elimina una cobertura de un paciente
+func (s *insuranceSqlStore) DeleteCoverage(patientId int, id int) error {
+	return s.delete("DELETE FROM patient_coverage WHERE id = ? AND patient_id = ?", id, patientId)
+}
+
+// CountProcedures cuenta los turnos no cancelados de un paciente con un procedimiento en el año
+// de la fecha, anteriores al turno appointmentId
+func (s *insuranceSqlStore) CountProcedures(patientId int, procedureCode string, date string, appointmentId int) (int, error) {
+	var count int
+	query := `SELECT COUNT(*) FROM appointment WHERE patient_id = ? AND procedure_code = ? AND status NOT IN (?, ?)
+		AND YEAR(date) = YEAR(?) AND (date < ? OR (date = ? AND (? = 0 OR id < ?)))`
+	err := s.DB.QueryRow(query, patientId, procedureCode, domain.AppointmentCancelled, domain.AppointmentNoShow,
+		date, date, date, appointmentId, appointmentId).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetClaimable devuelve los turnos completados en el periodo de pacientes con una cobertura vigente
+// del financiador que todavia no se presentaron, sin arancel ni monto
+func (s *insuranceSqlStore) GetClaimable(insurerId int, from string, to string) ([]domain.Claim, error) {
+	claims := []domain.Claim{}
+
+	query := `SELECT appointment.id, patient_coverage.id, patient.id, patient.name, patient.last_name, patient.dni, patient_coverage.member_number,
+		insurance_plan.code, appointment.procedure_code, appointment.date
+		FROM appointment
+		INNER JOIN patient ON appointment.patient_id = patient.id
+		INNER JOIN patient_coverage ON patient_coverage.patient_id = appointment.patient_id AND patient_coverage.valid_from <= appointment.date
+			AND (patient_coverage.valid_to IS NULL OR patient_coverage.valid_to >= appointment.date)
+		INNER JOIN insurance_plan ON patient_coverage.plan_id = insurance_plan.id
+		LEFT JOIN claim ON claim.appointment_id = appointment.id
+		WHERE insurance_plan.insurer_id = ? AND appointment.status = ? AND appointment.procedure_code IS NOT NULL
+			AND appointment.date BETWEEN ? AND ? AND claim.id IS NULL
+		ORDER BY appointment.date, appointment.id, patient_coverage.valid_from DESC`
+	rows, err := s.DB.Query(query, insurerId, domain.AppointmentCompleted, from, to)
+	if err != nil {
+		return []domain.Claim{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c domain.Claim
+		err := rows.Scan(&c.AppointmentId, &c.CoverageId, &c.PatientId, &c.PatientName, &c.PatientLastName, &c.PatientDni, &c.MemberNumber,
+			&c.PlanCode, &c.ProcedureCode, &c.Date)
+		if err != nil {
+			return []domain.Claim{}, err
+		}
+		claims = append(claims, c)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Claim{}, err
+	}
+	return claims, nil
+}
+
+// GetBatch devuelve un lote con sus prestaciones
+func (s *insuranceSqlStore) GetBatch(id int) (domain.ClaimBatch, error) {
+	batches, err := s.getBatches("claim_batch.id = ?", id)
+	if err != nil {
+		return domain.ClaimBatch{}, err
+	}
+	if len(batches) == 0 {
+		return domain.ClaimBatch{}, sql.ErrNoRows
+	}
+	batch := batches[0]
+	batch.Claims, err = s.getClaims(id)
+	if err != nil {
+		return domain.ClaimBatch{}, err
+	}
+	return batch, nil
+}
+
+// GetBatches devuelve los lotes sin sus prestaciones, filtrando por financiador y estado si no estan vacios
+func (s *insuranceSqlStore) GetBatches(insurerId int, status string) ([]domain.ClaimBatch, error) {
+	return s.getBatches("(? = 0 OR claim_batch.insurer_id = ?) AND (? = '' OR claim_batch.status = ?)", insurerId, insurerId, status, status)
+}
+
+// CreateBatch agrega un lote y sus prestaciones en una transaccion
+func (s *insuranceSqlStore) CreateBatch(batch domain.ClaimBatch) (domain.ClaimBatch, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.ClaimBatch{}, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT INTO claim_batch (insurer_id, date_from, date_to, status, total) VALUES (?, ?, ?, ?, ?);",
+		batch.InsurerId, batch.From, batch.To, batch.Status, batch.Total)
+	if err != nil {
+		return domain.ClaimBatch{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	batch.Id = int(insertedId)
+	for i, c := range batch.Claims {
+		result, err := tx.Exec("INSERT INTO claim (batch_id, appointment_id, coverage_id, procedure_code, service_date, fee, copay, amount) VALUES (?, ?, ?, ?, ?, ?, ?, ?);",
+			batch.Id, c.AppointmentId, c.CoverageId, c.ProcedureCode, c.Date, c.Fee, c.Copay, c.Amount)
+		if err != nil {
+			return domain.ClaimBatch{}, err
+		}
+		insertedId, _ := result.LastInsertId()
+		batch.Claims[i].Id = int(insertedId)
+		batch.Claims[i].BatchId = batch.Id
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.ClaimBatch{}, err
+	}
+	return batch, nil
+}
+
+// UpdateBatchStatus cambia el estado de un lote
+func (s *insuranceSqlStore) UpdateBatchStatus(id int, status string) error {
+	_, err := s.DB.Exec("UPDATE claim_batch SET status = ? WHERE id = ?", status, id)
+	return err
+}
+
+// getPlans busca los planes que cumplen la condicion sin sus reglas
+func (s *insuranceSqlStore) getPlans(condition string, args ...interface{}) ([]domain.InsurancePlan, error) {
+	plans := []domain.InsurancePlan{}
+
+	query := "SELECT id, insurer_id, code, name FROM insurance_plan WHERE " + condition + " ORDER BY name"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.InsurancePlan{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p domain.InsurancePlan
+		err := rows.Scan(&p.Id, &p.InsurerId, &p.Code, &p.Name)
+		if err != nil {
+			return []domain.InsurancePlan{}, err
+		}
+		plans = append(plans, p)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.InsurancePlan{}, err
+	}
+	return plans, nil
+}
+
+// getRules devuelve las reglas de cobertura de un plan
+func (s *insuranceSqlStore) getRules(planId int) ([]domain.CoverageRule, error) {
+	rules := []domain.CoverageRule{}
+
+	query := "SELECT id, plan_id, procedure_code, percent, copay, annual_limit FROM coverage_rule WHERE plan_id = ? ORDER BY procedure_code"
+	rows, err := s.DB.Query(query, planId)
+	if err != nil {
+		return []domain.CoverageRule{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r domain.CoverageRule
+		err := rows.Scan(&r.Id, &r.PlanId, &r.ProcedureCode, &r.Percent, &r.Copay, &r.AnnualLimit)
+		if err != nil {
+			return []domain.CoverageRule{}, err
+		}
+		rules = append(rules, r)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.CoverageRule{}, err
+	}
+	return rules, nil
+}
+
+// getCoverages busca las coberturas que cumplen la condicion
+func (s *insuranceSqlStore) getCoverages(condition string, args ...interface{}) ([]domain.PatientCoverage, error) {
+	coverages := []domain.PatientCoverage{}
+
+	query := "SELECT " + coverageColumns + " FROM patient_coverage INNER JOIN insurance_plan ON patient_coverage.plan_id = insurance_plan.id INNER JOIN insurer ON insurance_plan.insurer_id = insurer.id WHERE " + condition + " ORDER BY patient_coverage.valid_from DESC, patient_coverage.id DESC"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.PatientCoverage{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c domain.PatientCoverage
+		err := rows.Scan(&c.Id, &c.PatientId, &c.PlanId, &c.PlanCode, &c.PlanName, &c.InsurerId, &c.InsurerName, &c.MemberNumber, &c.ValidFrom, &c.ValidTo)
+		if err != nil {
+			return []domain.PatientCoverage{}, err
+		}
+		coverages = append(coverages, c)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.PatientCoverage{}, err
+	}
+	return coverages, nil
+}
+
+// getBatches busca los lotes que cumplen la condicion sin sus prestaciones, los mas nuevos primero
+func (s *insuranceSqlStore) getBatches(condition string, args ...interface{}) ([]domain.ClaimBatch, error) {
+	batches := []domain.ClaimBatch{}
+
+	query := "SELECT claim_batch.id, insurer.id, insurer.code, insurer.name, claim_batch.date_from, claim_batch.date_to, claim_batch.status, claim_batch.total, claim_batch.created_at FROM claim_batch INNER JOIN insurer ON claim_batch.insurer_id = insurer.id WHERE " + condition + " ORDER BY claim_batch.id DESC"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.ClaimBatch{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var b domain.ClaimBatch
+		err := rows.Scan(&b.Id, &b.InsurerId, &b.InsurerCode, &b.InsurerName, &b.From, &b.To, &b.Status, &b.Total, &b.CreatedAt)
+		if err != nil {
+			return []domain.ClaimBatch{}, err
+		}
+		batches = append(batches, b)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.ClaimBatch{}, err
+	}
+	return batches, nil
+}
+
+// getClaims devuelve las prestaciones de un lote con los datos del paciente
+func (s *insuranceSqlStore) getClaims(batchId int) ([]domain.Claim, error) {
+	claims := []domain.Claim{}
+
+	query := `SELECT claim.id, claim.batch_id, claim.appointment_id, claim.coverage_id, patient.id, patient.name, patient.last_name, patient.dni,
+		patient_coverage.member_number, insurance_plan.code, claim.procedure_code, claim.service_date, claim.fee, claim.copay, claim.amount
+		FROM claim
+		INNER JOIN patient_coverage ON claim.coverage_id = patient_coverage.id
+		INNER JOIN insurance_plan ON patient_coverage.plan_id = insurance_plan.id
+		INNER JOIN patient ON patient_coverage.patient_id = patient.id
+		WHERE claim.batch_id = ? ORDER BY claim.service_date, claim.id`
+	rows, err := s.DB.Query(query, batchId)
+	if err != nil {
+		return []domain.Claim{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var c domain.Claim
+		err := rows.Scan(&c.Id, &c.BatchId, &c.AppointmentId, &c.CoverageId, &c.PatientId, &c.PatientName, &c.PatientLastName, &c.PatientDni,
+			&c.MemberNumber, &c.PlanCode, &c.ProcedureCode, &c.Date, &c.Fee, &c.Copay, &c.Amount)
+		if err != nil {
+			return []domain.Claim{}, err
+		}
+		claims = append(claims, c)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Claim{}, err
+	}
+	return claims, nil
+}
+
+// delete ejecuta un delete y devuelve sql.ErrNoRows si no borro nada
+func (s *insuranceSqlStore) delete(query string, args ...interface{}) error {
+	result, err := s.DB.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
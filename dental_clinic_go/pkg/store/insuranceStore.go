@@ -0,0 +1,25 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type InsuranceStore interface {
+	GetInsurers() ([]domain.Insurer, error)
+	GetInsurer(id int) (domain.Insurer, error)
+	CreateInsurer(insurer domain.Insurer) (domain.Insurer, error)
+	UpdateInsurer(insurer domain.Insurer) error
+	GetPlan(id int) (domain.InsurancePlan, error)
+	CreatePlan(plan domain.InsurancePlan) (domain.InsurancePlan, error)
+	SaveRule(rule domain.CoverageRule) error
+	DeleteRule(planId int, procedureCode string) error
+	GetCoverage(id int) (domain.PatientCoverage, error)
+	GetCoverages(patientId int) ([]domain.PatientCoverage, error)
+	CreateCoverage(coverage domain.PatientCoverage) (domain.PatientCoverage, error)
+	UpdateCoverage(coverage domain.PatientCoverage) error
+	DeleteCoverage(patientId int, id int) error
+	CountProcedures(patientId int, procedureCode string, date string, appointmentId int) (int, error)
+	GetClaimable(insurerId int, from string, to string) ([]domain.Claim, error)
+	GetBatch(id int) (domain.ClaimBatch, error)
+	GetBatches(insurerId int, status string) ([]domain.ClaimBatch, error)
+	CreateBatch(batch domain.ClaimBatch) (domain.ClaimBatch, error)
+	UpdateBatchStatus(id int, status string) error
+}
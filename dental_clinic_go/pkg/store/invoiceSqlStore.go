@@ -0,0 +1,258 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"fmt"
+)
+
+type invoiceSqlStore struct {
+	DB *sql.DB
+}
+
+// NewInvoiceSqlStore crea un nuevo store de facturas
+func NewInvoiceSqlStore(db *sql.DB) InvoiceStore {
+	return &invoiceSqlStore{db}
+}
+
+// GetByID devuelve una factura con sus items
+func (s *invoiceSqlStore) GetByID(id int) (domain.Invoice, error) {
+	invoices, err := s.getInvoices("invoice.id = ?", id)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	if len(invoices) == 0 {
+		return domain.Invoice{}, sql.ErrNoRows
+	}
+	return invoices[0], nil
+}
+
+// GetByPatient devuelve las facturas de un paciente
+func (s *invoiceSqlStore) GetByPatient(patientId int) ([]domain.Invoice, error) {
+	return s.getInvoices("invoice.patient_id = ?", patientId)
+}
+
+// GetByStatus devuelve las facturas en un estado, si status esta vacio devuelve todas
+func (s *invoiceSqlStore) GetByStatus(status string) ([]domain.Invoice, error) {
+	return s.getInvoices("? = '' OR invoice.status = ?", status, status)
+}
+
+// GetByAppointment devuelve las facturas de un turno
+func (s *invoiceSqlStore) GetByAppointment(appointmentId int) ([]domain.Invoice, error) {
+	return s.getInvoices("invoice.appointment_id = ?", appointmentId)
+}
+
+// Create agrega una factura y sus items en una transaccion
+func (s *invoiceSqlStore) Create(invoice domain.Invoice) (domain.Invoice, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT INTO invoice (patient_id, appointment_id, status, bill_to_id, bill_to_name, bill_to_last_name, bill_to_email, subtotal, discount, tax, total, notes, void_reason) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);",
+		invoice.PatientId, nullInt(invoice.AppointmentId), invoice.Status, invoice.BillTo.ContactId, invoice.BillTo.Name, invoice.BillTo.LastName, invoice.BillTo.Email,
+		invoice.Subtotal, invoice.Discount, invoice.Tax, invoice.Total, invoice.Notes, invoice.VoidReason)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	invoice.Id = int(insertedId)
+	err = insertInvoiceItems(tx, &invoice)
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.Invoice{}, err
+	}
+	return invoice, nil
+}
+
+// Update reemplaza el destinatario, los items, los totales y las notas de un borrador
+func (s *invoiceSqlStore) Update(invoice domain.Invoice) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("UPDATE invoice SET bill_to_id = ?, bill_to_name = ?, bill_to_last_name = ?, bill_to_email = ?, subtotal = ?, discount = ?, tax = ?, total = ?, notes = ? WHERE id = ? AND status = ?",
+		invoice.BillTo.ContactId, invoice.BillTo.Name, invoice.BillTo.LastName, invoice.BillTo.Email, invoice.Subtotal, invoice.Discount, invoice.Tax, invoice.Total, invoice.Notes,
+		invoice.Id, domain.InvoiceDraft)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	_, err = tx.Exec("DELETE FROM invoice_item WHERE invoice_id = ?", invoice.Id)
+	if err != nil {
+		return err
+	}
+	err = insertInvoiceItems(tx, &invoice)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// Issue emite un borrador con el siguiente numero de la serie. El numero se toma bloqueando
+// la fila de la serie en la misma transaccion, si algo falla no se consume y no quedan huecos.
+func (s *invoiceSqlStore) Issue(id int, series string, date string) (int, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	// ON DUPLICATE KEY toma el lock exclusivo de la serie, con INSERT IGNORE dos emisiones
+	// simultaneas toman un lock compartido y se bloquean al pedir el FOR UPDATE
+	_, err = tx.Exec("INSERT INTO invoice_sequence (series, last_number) VALUES (?, 0) ON DUPLICATE KEY UPDATE last_number = last_number", series)
+	if err != nil {
+		return 0, err
+	}
+	var number int
+	err = tx.QueryRow("SELECT last_number FROM invoice_sequence WHERE series = ? FOR UPDATE", series).Scan(&number)
+	if err != nil {
+		return 0, err
+	}
+	number++
+	result, err := tx.Exec("UPDATE invoice SET series = ?, number = ?, status = ?, issue_date = ? WHERE id = ? AND status = ?",
+		series, number, domain.InvoiceIssued, date, id, domain.InvoiceDraft)
+	if err != nil {
+		return 0, err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return 0, err
+	}
+	if rowsAffected == 0 {
+		return 0, sql.ErrNoRows
+	}
+	_, err = tx.Exec("UPDATE invoice_sequence SET last_number = ? WHERE series = ?", number, series)
+	if err != nil {
+		return 0, err
+	}
+	// El cargo en la cuenta del paciente se registra en la misma transaccion que la emision
+	description := "Factura " + domain.Invoice{Series: series, Number: number}.FullNumber()
+	_, err = tx.Exec("INSERT INTO ledger_entry (patient_id, entry_type, entry_date, debit, credit, invoice_id, reference, description) SELECT patient_id, ?, ?, total, 0, id, '', ? FROM invoice WHERE id = ? AND total <> 0",
+		domain.EntryCharge, date, description, id)
+	if err != nil {
+		return 0, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return number, nil
+}
+
+// Void anula una factura, las emitidas conservan su numero. Si estaba emitida revierte su cargo
+// con un ajuste en la misma transaccion.
+func (s *invoiceSqlStore) Void(id int, reason string, date string) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var i domain.Invoice
+	err = tx.QueryRow("SELECT patient_id, status, COALESCE(series, ''), COALESCE(number, 0), total FROM invoice WHERE id = ? FOR UPDATE", id).
+		Scan(&i.PatientId, &i.Status, &i.Series, &i.Number, &i.Total)
+	if err != nil {
+		return err
+	}
+	if i.Status == domain.InvoiceVoid {
+		return sql.ErrNoRows
+	}
+	_, err = tx.Exec("UPDATE invoice SET status = ?, void_reason = ? WHERE id = ?", domain.InvoiceVoid, reason, id)
+	if err != nil {
+		return err
+	}
+	if i.Status == domain.InvoiceIssued && i.Total != 0 {
+		description := fmt.Sprintf("Anulacion de la factura %s: %s", i.FullNumber(), reason)
+		_, err = tx.Exec("INSERT INTO ledger_entry (patient_id, entry_type, entry_date, debit, credit, invoice_id, reference, description) VALUES (?, ?, ?, 0, ?, ?, '', ?)",
+			i.PatientId, domain.EntryAdjustment, date, i.Total, id, description)
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// getInvoices busca las facturas que cumplen la condicion y completa sus items
+func (s *invoiceSqlStore) getInvoices(condition string, args ...interface{}) ([]domain.Invoice, error) {
+	invoices := []domain.Invoice{}
+
+	query := "SELECT invoice.id, COALESCE(invoice.series, ''), COALESCE(invoice.number, 0), invoice.patient_id, COALESCE(invoice.appointment_id, 0), invoice.status, COALESCE(invoice.issue_date, ''), invoice.bill_to_id, invoice.bill_to_name, invoice.bill_to_last_name, invoice.bill_to_email, invoice.subtotal, invoice.discount, invoice.tax, invoice.total, invoice.notes, invoice.void_reason, invoice.created_at FROM invoice WHERE " + condition + " ORDER BY invoice.created_at DESC, invoice.id DESC"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.Invoice{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var i domain.Invoice
+		err := rows.Scan(&i.Id, &i.Series, &i.Number, &i.PatientId, &i.AppointmentId, &i.Status, &i.IssueDate, &i.BillTo.ContactId, &i.BillTo.Name, &i.BillTo.LastName, &i.BillTo.Email,
+			&i.Subtotal, &i.Discount, &i.Tax, &i.Total, &i.Notes, &i.VoidReason, &i.CreatedAt)
+		if err != nil {
+			return []domain.Invoice{}, err
+		}
+		i.BillTo.PatientId = i.PatientId
+		invoices = append(invoices, i)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Invoice{}, err
+	}
+	for i := range invoices {
+		invoices[i].Items, err = s.getItems(invoices[i].Id)
+		if err != nil {
+			return []domain.Invoice{}, err
+		}
+	}
+	return invoices, nil
+}
+
+// getItems devuelve los items de una factura en orden
+func (s *invoiceSqlStore) getItems(invoiceId int) ([]domain.InvoiceItem, error) {
+	items := []domain.InvoiceItem{}
+
+	query := "SELECT id, COALESCE(procedure_code, ''), description, tooth, quantity, unit_price, discount_percent, discount_fixed, tax_rate, subtotal, discount, tax, total FROM invoice_item WHERE invoice_id = ? ORDER BY position"
+	rows, err := s.DB.Query(query, invoiceId)
+	if err != nil {
+		return []domain.InvoiceItem{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.InvoiceItem
+		err := rows.Scan(&item.Id, &item.ProcedureCode, &item.Description, &item.Tooth, &item.Quantity, &item.UnitPrice, &item.DiscountPercent, &item.DiscountFixed, &item.TaxRate,
+			&item.Subtotal, &item.Discount, &item.Tax, &item.Total)
+		if err != nil {
+			return []domain.InvoiceItem{}, err
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.InvoiceItem{}, err
+	}
+	return items, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// insertInvoiceItems agrega los items de una factura dentro de una transaccion y completa sus ids
+func insertInvoiceItems(tx *sql.Tx, invoice *domain.Invoice) error {
+	for i, item := range invoice.Items {
+		result, err := tx.Exec("INSERT INTO invoice_item (invoice_id, position, procedure_code, description, tooth, quantity, unit_price, discount_percent, discount_fixed, tax_rate, subtotal, discount, tax, total) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);",
+			invoice.Id, i+1, nullString(item.ProcedureCode), item.Description, item.Tooth, item.Quantity, item.UnitPrice, item.DiscountPercent, item.DiscountFixed, item.TaxRate,
+			item.Subtotal, item.Discount, item.Tax, item.Total)
+		if err != nil {
+			return err
+		}
+		itemId, _ := result.LastInsertId()
+		invoice.Items[i].Id = int(itemId)
+	}
+	return nil
+}
@@ -0,0 +1,149 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	_ "github.com/go-sql-driver/mysql"
+)
+
+// testDB abre la base de TEST_DB_URL, creada con utils/db/build_database.sql. Sin ella el test se saltea.
+func testDB(t *testing.T) *sql.DB {
+	url := os.Getenv("TEST_DB_URL")
+	if url == "" {
+		t.Skip("TEST_DB_URL not set")
+	}
+	db, err := sql.Open("mysql", url)
+	if err != nil {
+		t.Fatalf("opening database: %s", err)
+	}
+	if err = db.Ping(); err != nil {
+		t.Fatalf("connecting to database: %s", err)
+	}
+	t.Cleanup(func() { db.Close() })
+	return db
+}
+
+func TestInvoiceIssueIsGapless(t *testing.T) {
+	db := testDB(t)
+	s := NewInvoiceSqlStore(db)
+	var patientId int
+	err := db.QueryRow("SELECT id FROM patient ORDER BY id LIMIT 1").Scan(&patientId)
+	if err != nil {
+		t.Fatalf("finding a patient: %s", err)
+	}
+	series := fmt.Sprintf("T%d", time.Now().UnixNano()%1e9)
+	date := "2026-10-19"
+
+	ids := []int{}
+	t.Cleanup(func() {
+		for _, id := range ids {
+			db.Exec("DELETE FROM ledger_entry WHERE invoice_id = ?", id)
+			db.Exec("DELETE FROM invoice WHERE id = ?", id)
+		}
+		db.Exec("DELETE FROM invoice_sequence WHERE series = ?", series)
+	})
+	draft := func(total float64) int {
+		invoice, err := s.Create(domain.Invoice{PatientId: patientId, Status: domain.InvoiceDraft, BillTo: domain.Contact{ContactId: patientId, Name: "Test"}, Total: total})
+		if err != nil {
+			t.Fatalf("creating draft: %s", err)
+		}
+		ids = append(ids, invoice.Id)
+		return invoice.Id
+	}
+
+	// Las emisiones simultaneas toman numeros consecutivos sin repetir ni saltear
+	const concurrent = 10
+	drafts := []int{}
+	for i := 0; i < concurrent; i++ {
+		drafts = append(drafts, draft(100))
+	}
+	numbers := make([]int, concurrent)
+	errs := make([]error, concurrent)
+	var wg sync.WaitGroup
+	for i, id := range drafts {
+		wg.Add(1)
+		go func(i int, id int) {
+			defer wg.Done()
+			numbers[i], errs[i] = s.Issue(id, series, date)
+		}(i, id)
+	}
+	wg.Wait()
+	for i, err := range errs {
+		if err != nil {
+			t.Fatalf("issuing invoice %d: %s", drafts[i], err)
+		}
+	}
+	sort.Ints(numbers)
+	for i, number := range numbers {
+		if number != i+1 {
+			t.Fatalf("expected numbers 1 to %d, got %v", concurrent, numbers)
+		}
+	}
+
+	// Las emisiones que fallan no consumen numero
+	tests := []struct {
+		name   string
+		id     int
+		number int
+		err    error
+	}{
+		{"already issued", drafts[0], 0, sql.ErrNoRows},
+		{"missing invoice", 0, 0, sql.ErrNoRows},
+		{"next draft", draft(50), concurrent + 1, nil},
+		{"free draft", draft(0), concurrent + 2, nil},
+	}
+	for _, tt := range tests {
+		number, err := s.Issue(tt.id, series, date)
+		if err != tt.err || number != tt.number {
+			t.Fatalf("%s: expected number %d and error %v, got %d and %v", tt.name, tt.number, tt.err, number, err)
+		}
+	}
+
+	// Cada factura emitida con importe tiene un unico cargo, las de importe 0 no tienen
+	for i, id := range ids {
+		var charges int
+		var debit float64
+		err := db.QueryRow("SELECT COUNT(*), COALESCE(SUM(debit), 0) FROM ledger_entry WHERE invoice_id = ? AND entry_type = ?", id, domain.EntryCharge).Scan(&charges, &debit)
+		if err != nil {
+			t.Fatalf("counting charges of invoice %d: %s", id, err)
+		}
+		want, wantDebit := 1, 100.0
+		if i == concurrent {
+			wantDebit = 50
+		}
+		if i == concurrent+1 {
+			want, wantDebit = 0, 0
+		}
+		if charges != want || debit != wantDebit {
+			t.Fatalf("invoice %d: expected %d charges of %.2f, got %d of %.2f", id, want, wantDebit, charges, debit)
+		}
+	}
+
+	// Anular conserva el numero y revierte el cargo, una segunda anulacion falla
+	err = s.Void(drafts[0], "error de carga", date)
+	if err != nil {
+		t.Fatalf("voiding: %s", err)
+	}
+	voided, err := s.GetByID(drafts[0])
+	if err != nil {
+		t.Fatalf("getting voided invoice: %s", err)
+	}
+	if voided.Status != domain.InvoiceVoid || voided.Number == 0 {
+		t.Fatalf("expected a void invoice that keeps its number, got %s %d", voided.Status, voided.Number)
+	}
+	var credit float64
+	err = db.QueryRow("SELECT COALESCE(SUM(credit), 0) FROM ledger_entry WHERE invoice_id = ? AND entry_type = ?", drafts[0], domain.EntryAdjustment).Scan(&credit)
+	if err != nil || credit != 100 {
+		t.Fatalf("expected an adjustment of 100.00, got %.2f (%v)", credit, err)
+	}
+	if err = s.Void(drafts[0], "otra vez", date); err != sql.ErrNoRows {
+		t.Fatalf("expected voiding twice to fail, got %v", err)
+	}
+}
@@ -0,0 +1,14 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type InvoiceStore interface {
+	GetByID(id int) (domain.Invoice, error)
+	GetByPatient(patientId int) ([]domain.Invoice, error)
+	GetByStatus(status string) ([]domain.Invoice, error)
+	GetByAppointment(appointmentId int) ([]domain.Invoice, error)
+	Create(invoice domain.Invoice) (domain.Invoice, error)
+	Update(invoice domain.Invoice) error
+	Issue(id int, series string, date string) (int, error)
+	Void(id int, reason string, date string) error
+}
@@ -0,0 +1,109 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"time"
+)
+
+type labSqlStore struct {
+	DB *sql.DB
+}
+
+// NewLabSqlStore crea un nuevo store de ordenes de laboratorio
+func NewLabSqlStore(db *sql.DB) LabStore {
+	return &labSqlStore{db}
+}
+
+// GetByID devuelve una orden de laboratorio
+func (s *labSqlStore) GetByID(id int) (domain.LabOrder, error) {
+	orders, err := s.getOrders("lab_order.id = ?", id)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	if len(orders) == 0 {
+		return domain.LabOrder{}, sql.ErrNoRows
+	}
+	return orders[0], nil
+}
+
+// GetByPatient devuelve las ordenes de laboratorio de un paciente
+func (s *labSqlStore) GetByPatient(patientId int) ([]domain.LabOrder, error) {
+	return s.getOrders("lab_order.patient_id = ?", patientId)
+}
+
+// GetByStatus devuelve las ordenes de laboratorio en un estado
+func (s *labSqlStore) GetByStatus(status string) ([]domain.LabOrder, error) {
+	return s.getOrders("lab_order.status = ?", status)
+}
+
+// GetByAppointment devuelve las ordenes de los procedimientos agendados en un turno
+func (s *labSqlStore) GetByAppointment(appointmentId int) ([]domain.LabOrder, error) {
+	return s.getOrders("treatment_item.appointment_id = ?", appointmentId)
+}
+
+// Create agrega una orden de laboratorio
+func (s *labSqlStore) Create(order domain.LabOrder) (domain.LabOrder, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO lab_order (patient_id, dentist_id, treatment_item_id, lab_name, description, status, sent_date, expected_date, notes) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);")
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	defer stmt.Close()
+	sentDate, err := time.Parse("2006-01-02", order.SentDate)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	expectedDate, err := time.Parse("2006-01-02", order.ExpectedDate)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	result, err := stmt.Exec(order.PatientId, order.Dentist.Id, order.TreatmentItemId, order.Lab, order.Description, order.Status, sentDate, expectedDate, order.Notes)
+	if err != nil {
+		return domain.LabOrder{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	order.Id = int(insertedId)
+	return order, nil
+}
+
+// Update actualiza el laboratorio, las fechas, el estado y las notas de una orden
+func (s *labSqlStore) Update(order domain.LabOrder) error {
+	stmt := "UPDATE lab_order SET lab_name = ?, description = ?, status = ?, expected_date = ?, received_date = ?, fitted_date = ?, notes = ? WHERE id = ?"
+	result, err := s.DB.Exec(stmt, order.Lab, order.Description, order.Status, order.ExpectedDate, nullString(order.ReceivedDate), nullString(order.FittedDate), order.Notes, order.Id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// getOrders busca las ordenes que cumplen la condicion junto con el turno de colocacion
+func (s *labSqlStore) getOrders(condition string, args ...interface{}) ([]domain.LabOrder, error) {
+	orders := []domain.LabOrder{}
+
+	query := "SELECT lab_order.id, lab_order.patient_id, lab_order.treatment_item_id, lab_order.lab_name, lab_order.description, lab_order.status, lab_order.sent_date, lab_order.expected_date, COALESCE(lab_order.received_date, ''), COALESCE(lab_order.fitted_date, ''), lab_order.notes, lab_order.created_at, COALESCE(appointment.id, 0), COALESCE(appointment.date, ''), dentist.* FROM lab_order INNER JOIN dentist ON lab_order.dentist_id = dentist.id INNER JOIN treatment_item ON lab_order.treatment_item_id = treatment_item.id LEFT JOIN appointment ON treatment_item.appointment_id = appointment.id WHERE " + condition + " ORDER BY lab_order.expected_date, lab_order.id"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.LabOrder{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var o domain.LabOrder
+		err := rows.Scan(&o.Id, &o.PatientId, &o.TreatmentItemId, &o.Lab, &o.Description, &o.Status, &o.SentDate, &o.ExpectedDate, &o.ReceivedDate, &o.FittedDate, &o.Notes, &o.CreatedAt, &o.FittingAppointmentId, &o.FittingDate, &o.Dentist.Id, &o.Dentist.Name, &o.Dentist.LastName, &o.Dentist.License)
+		if err != nil {
+			return []domain.LabOrder{}, err
+		}
+		orders = append(orders, o)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.LabOrder{}, err
+	}
+	return orders, nil
+}
@@ -0,0 +1,12 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type LabStore interface {
+	GetByID(id int) (domain.LabOrder, error)
+	GetByPatient(patientId int) ([]domain.LabOrder, error)
+	GetByStatus(status string) ([]domain.LabOrder, error)
+	GetByAppointment(appointmentId int) ([]domain.LabOrder, error)
+	Create(order domain.LabOrder) (domain.LabOrder, error)
+	Update(order domain.LabOrder) error
+}
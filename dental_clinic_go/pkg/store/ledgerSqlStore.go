@@ -0,0 +1,170 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"time"
+)
+
+type ledgerSqlStore struct {
+	DB *sql.DB
+}
+
+// NewLedgerSqlStore crea un nuevo store de cuentas de pacientes
+func NewLedgerSqlStore(db *sql.DB) LedgerStore {
+	return &ledgerSqlStore{db}
+}
+
+// GetByID devuelve un movimiento con sus imputaciones
+func (s *ledgerSqlStore) GetByID(id int) (domain.LedgerEntry, error) {
+	entries, err := s.getEntries("ledger_entry.id = ?", id)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	if len(entries) == 0 {
+		return domain.LedgerEntry{}, sql.ErrNoRows
+	}
+	return entries[0], nil
+}
+
+// GetByPatient devuelve los movimientos de un paciente en orden cronologico
+func (s *ledgerSqlStore) GetByPatient(patientId int) ([]domain.LedgerEntry, error) {
+	return s.getEntries("ledger_entry.patient_id = ?", patientId)
+}
+
+// GetPatients devuelve los pacientes con movimientos hasta una fecha
+func (s *ledgerSqlStore) GetPatients(date string) ([]int, error) {
+	patients := []int{}
+
+	query := "SELECT DISTINCT patient_id FROM ledger_entry WHERE entry_date <= ? ORDER BY patient_id"
+	rows, err := s.DB.Query(query, date)
+	if err != nil {
+		return []int{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var patientId int
+		err := rows.Scan(&patientId)
+		if err != nil {
+			return []int{}, err
+		}
+		patients = append(patients, patientId)
+	}
+	if err = rows.Err(); err != nil {
+		return []int{}, err
+	}
+	return patients, nil
+}
+
+// Create agrega un movimiento y sus imputaciones en una transaccion
+func (s *ledgerSqlStore) Create(entry domain.LedgerEntry) (domain.LedgerEntry, error) {
+	date, err := time.Parse("2006-01-02", entry.Date)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT INTO ledger_entry (patient_id, entry_type, entry_date, debit, credit, method, invoice_id, reference, description) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?);",
+		entry.PatientId, entry.Type, date, entry.Debit, entry.Credit, nullString(entry.Method), nullInt(entry.InvoiceId), entry.Reference, entry.Description)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	entry.Id = int(insertedId)
+	err = insertAllocations(tx, entry.Id, entry.Allocations)
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.LedgerEntry{}, err
+	}
+	return entry, nil
+}
+
+// Allocate agrega imputaciones a un pago existente
+func (s *ledgerSqlStore) Allocate(entryId int, allocations []domain.PaymentAllocation) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	err = insertAllocations(tx, entryId, allocations)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// getEntries busca los movimientos que cumplen la condicion y completa sus imputaciones
+func (s *ledgerSqlStore) getEntries(condition string, args ...interface{}) ([]domain.LedgerEntry, error) {
+	entries := []domain.LedgerEntry{}
+
+	query := "SELECT id, patient_id, entry_type, entry_date, debit, credit, COALESCE(method, ''), COALESCE(invoice_id, 0), reference, description, created_at FROM ledger_entry WHERE " + condition + " ORDER BY entry_date, id"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.LedgerEntry{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var e domain.LedgerEntry
+		err := rows.Scan(&e.Id, &e.PatientId, &e.Type, &e.Date, &e.Debit, &e.Credit, &e.Method, &e.InvoiceId, &e.Reference, &e.Description, &e.CreatedAt)
+		if err != nil {
+			return []domain.LedgerEntry{}, err
+		}
+		entries = append(entries, e)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.LedgerEntry{}, err
+	}
+	for i := range entries {
+		entries[i].Allocations, err = s.getAllocations(entries[i].Id)
+		if err != nil {
+			return []domain.LedgerEntry{}, err
+		}
+	}
+	return entries, nil
+}
+
+// getAllocations devuelve las imputaciones de un pago
+func (s *ledgerSqlStore) getAllocations(entryId int) ([]domain.PaymentAllocation, error) {
+	allocations := []domain.PaymentAllocation{}
+
+	query := "SELECT id, entry_id, invoice_id, amount FROM payment_allocation WHERE entry_id = ? ORDER BY id"
+	rows, err := s.DB.Query(query, entryId)
+	if err != nil {
+		return []domain.PaymentAllocation{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var a domain.PaymentAllocation
+		err := rows.Scan(&a.Id, &a.EntryId, &a.InvoiceId, &a.Amount)
+		if err != nil {
+			return []domain.PaymentAllocation{}, err
+		}
+		allocations = append(allocations, a)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.PaymentAllocation{}, err
+	}
+	return allocations, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// insertAllocations agrega las imputaciones de un pago dentro de una transaccion
+func insertAllocations(tx *sql.Tx, entryId int, allocations []domain.PaymentAllocation) error {
+	for _, a := range allocations {
+		_, err := tx.Exec("INSERT INTO payment_allocation (entry_id, invoice_id, amount) VALUES (?, ?, ?);", entryId, a.InvoiceId, a.Amount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,11 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type LedgerStore interface {
+	GetByID(id int) (domain.LedgerEntry, error)
+	GetByPatient(patientId int) ([]domain.LedgerEntry, error)
+	GetPatients(date string) ([]int, error)
+	Create(entry domain.LedgerEntry) (domain.LedgerEntry, error)
+	Allocate(entryId int, allocations []domain.PaymentAllocation) error
+}
@@ -0,0 +1,198 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"time"
+)
+
+type medicalSqlStore struct {
+	DB *sql.DB
+}
+
+// NewMedicalSqlStore crea un nuevo store de historias medicas
+func NewMedicalSqlStore(db *sql.DB) MedicalStore {
+	return &medicalSqlStore{db}
+}
+
+// GetAllergies devuelve las alergias de un paciente
+func (s *medicalSqlStore) GetAllergies(patientId int) ([]domain.Allergy, error) {
+	allergies := []domain.Allergy{}
+
+	query := "SELECT id, patient_id, substance, severity, reaction, created_at FROM allergy WHERE patient_id = ? ORDER BY id"
+	rows, err := s.DB.Query(query, patientId)
+	if err != nil {
+		return []domain.Allergy{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var allergy domain.Allergy
+		err := rows.Scan(&allergy.Id, &allergy.PatientId, &allergy.Substance, &allergy.Severity, &allergy.Reaction, &allergy.CreatedAt)
+		if err != nil {
+			return []domain.Allergy{}, err
+		}
+		allergies = append(allergies, allergy)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Allergy{}, err
+	}
+	return allergies, nil
+}
+
+// CreateAllergy agrega una alergia
+func (s *medicalSqlStore) CreateAllergy(allergy domain.Allergy) (domain.Allergy, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO allergy (patient_id, substance, severity, reaction) VALUES (?, ?, ?, ?);")
+	if err != nil {
+		return domain.Allergy{}, err
+	}
+	defer stmt.Close()
+	result, err := stmt.Exec(allergy.PatientId, allergy.Substance, allergy.Severity, allergy.Reaction)
+	if err != nil {
+		return domain.Allergy{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	allergy.Id = int(insertedId)
+	return allergy, nil
+}
+
+// DeleteAllergy elimina una alergia de un paciente
+func (s *medicalSqlStore) DeleteAllergy(patientId int, id int) error {
+	return s.delete("DELETE FROM allergy WHERE id = ? AND patient_id = ?", id, patientId)
+}
+
+// GetMedications devuelve la medicacion actual de un paciente
+func (s *medicalSqlStore) GetMedications(patientId int) ([]domain.Medication, error) {
+	medications := []domain.Medication{}
+
+	query := "SELECT id, patient_id, name, dose, frequency, created_at FROM medication WHERE patient_id = ? ORDER BY id"
+	rows, err := s.DB.Query(query, patientId)
+	if err != nil {
+		return []domain.Medication{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var medication domain.Medication
+		err := rows.Scan(&medication.Id, &medication.PatientId, &medication.Name, &medication.Dose, &medication.Frequency, &medication.CreatedAt)
+		if err != nil {
+			return []domain.Medication{}, err
+		}
+		medications = append(medications, medication)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Medication{}, err
+	}
+	return medications, nil
+}
+
+// CreateMedication agrega una medicacion
+func (s *medicalSqlStore) CreateMedication(medication domain.Medication) (domain.Medication, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO medication (patient_id, name, dose, frequency) VALUES (?, ?, ?, ?);")
+	if err != nil {
+		return domain.Medication{}, err
+	}
+	defer stmt.Close()
+	result, err := stmt.Exec(medication.PatientId, medication.Name, medication.Dose, medication.Frequency)
+	if err != nil {
+		return domain.Medication{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	medication.Id = int(insertedId)
+	return medication, nil
+}
+
+// DeleteMedication elimina una medicacion de un paciente
+func (s *medicalSqlStore) DeleteMedication(patientId int, id int) error {
+	return s.delete("DELETE FROM medication WHERE id = ? AND patient_id = ?", id, patientId)
+}
+
+// GetConditions devuelve las condiciones medicas de un paciente
+func (s *medicalSqlStore) GetConditions(patientId int) ([]domain.MedicalCondition, error) {
+	conditions := []domain.MedicalCondition{}
+
+	query := "SELECT id, patient_id, name, notes, created_at FROM medical_condition WHERE patient_id = ? ORDER BY id"
+	rows, err := s.DB.Query(query, patientId)
+	if err != nil {
+		return []domain.MedicalCondition{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var condition domain.MedicalCondition
+		err := rows.Scan(&condition.Id, &condition.PatientId, &condition.Name, &condition.Notes, &condition.CreatedAt)
+		if err != nil {
+			return []domain.MedicalCondition{}, err
+		}
+		conditions = append(conditions, condition)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.MedicalCondition{}, err
+	}
+	return conditions, nil
+}
+
+// CreateCondition agrega una condicion medica
+func (s *medicalSqlStore) CreateCondition(condition domain.MedicalCondition) (domain.MedicalCondition, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO medical_condition (patient_id, name, notes) VALUES (?, ?, ?);")
+	if err != nil {
+		return domain.MedicalCondition{}, err
+	}
+	defer stmt.Close()
+	result, err := stmt.Exec(condition.PatientId, condition.Name, condition.Notes)
+	if err != nil {
+		return domain.MedicalCondition{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	condition.Id = int(insertedId)
+	return condition, nil
+}
+
+// DeleteCondition elimina una condicion medica de un paciente
+func (s *medicalSqlStore) DeleteCondition(patientId int, id int) error {
+	return s.delete("DELETE FROM medical_condition WHERE id = ? AND patient_id = ?", id, patientId)
+}
+
+// GetLastReviewed devuelve la fecha de la ultima revision de la historia medica, vacia si nunca se reviso
+func (s *medicalSqlStore) GetLastReviewed(patientId int) (string, error) {
+	var date string
+	row := s.DB.QueryRow("SELECT reviewed_at FROM medical_review WHERE patient_id = ?;", patientId)
+	err := row.Scan(&date)
+	if err == sql.ErrNoRows {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return date, nil
+}
+
+// Review guarda la fecha de revision de la historia medica
+func (s *medicalSqlStore) Review(patientId int, date string) error {
+	reviewedAt, err := time.Parse("2006-01-02", date)
+	if err != nil {
+		return err
+	}
+	stmt := "INSERT INTO medical_review (patient_id, reviewed_at) VALUES (?, ?) ON DUPLICATE KEY UPDATE reviewed_at = VALUES(reviewed_at)"
+	_, err = s.DB.Exec(stmt, patientId, reviewedAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// delete ejecuta un borrado y verifica que haya eliminado una fila
+func (s *medicalSqlStore) delete(query string, args ...interface{}) error {
+	result, err := s.DB.Exec(query, args...)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
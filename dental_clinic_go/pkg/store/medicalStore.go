@@ -0,0 +1,17 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type MedicalStore interface {
+	GetAllergies(patientId int) ([]domain.Allergy, error)
+	CreateAllergy(allergy domain.Allergy) (domain.Allergy, error)
+	DeleteAllergy(patientId int, id int) error
+	GetMedications(patientId int) ([]domain.Medication, error)
+	CreateMedication(medication domain.Medication) (domain.Medication, error)
+	DeleteMedication(patientId int, id int) error
+	GetConditions(patientId int) ([]domain.MedicalCondition, error)
+	CreateCondition(condition domain.MedicalCondition) (domain.MedicalCondition, error)
+	DeleteCondition(patientId int, id int) error
+	GetLastReviewed(patientId int) (string, error)
+	Review(patientId int, date string) error
+}
@@ -0,0 +1,172 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+)
+
+type noteSqlStore struct {
+	DB *sql.DB
+}
+
+// NewNoteSqlStore crea un nuevo store de notas clinicas
+func NewNoteSqlStore(db *sql.DB) NoteStore {
+	return &noteSqlStore{db}
+}
+
+// GetByID devuelve una nota con sus agregados
+func (s *noteSqlStore) GetByID(id int) (domain.ClinicalNote, error) {
+	notes, err := s.getNotes("clinical_note.id = ?", id)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	if len(notes) == 0 {
+		return domain.ClinicalNote{}, sql.ErrNoRows
+	}
+	return notes[0], nil
+}
+
+// GetByAppointment devuelve las notas de un turno con sus agregados
+func (s *noteSqlStore) GetByAppointment(appointmentId int) ([]domain.ClinicalNote, error) {
+	return s.getNotes("clinical_note.appointment_id = ?", appointmentId)
+}
+
+// CountByAppointment devuelve la cantidad de notas de un turno
+func (s *noteSqlStore) CountByAppointment(appointmentId int) (int, error) {
+	var count int
+	row := s.DB.QueryRow("SELECT COUNT(*) FROM clinical_note WHERE appointment_id = ?;", appointmentId)
+	err := row.Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// Create agrega una nota en borrador
+func (s *noteSqlStore) Create(note domain.ClinicalNote) (domain.ClinicalNote, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO clinical_note (appointment_id, dentist_id, subjective, objective, assessment, plan, status) VALUES (?, ?, ?, ?, ?, ?, ?);")
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	defer stmt.Close()
+	result, err := stmt.Exec(note.AppointmentId, note.Dentist.Id, note.Subjective, note.Objective, note.Assessment, note.Plan, domain.NoteDraft)
+	if err != nil {
+		return domain.ClinicalNote{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	note.Id = int(insertedId)
+	return note, nil
+}
+
+// Update reemplaza las secciones de una nota, las notas firmadas no se modifican
+func (s *noteSqlStore) Update(note domain.ClinicalNote) error {
+	stmt := "UPDATE clinical_note SET subjective = ?, objective = ?, assessment = ?, plan = ? WHERE id = ? AND status = ?"
+	_, err := s.DB.Exec(stmt, note.Subjective, note.Objective, note.Assessment, note.Plan, note.Id, domain.NoteDraft)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Sign firma una nota en borrador
+func (s *noteSqlStore) Sign(id int) error {
+	stmt := "UPDATE clinical_note SET status = ?, signed_at = CURRENT_TIMESTAMP WHERE id = ? AND status = ?"
+	result, err := s.DB.Exec(stmt, domain.NoteSigned, id, domain.NoteDraft)
+	if err != nil {
+		return err
+	}
+	return draftAffected(result)
+}
+
+// Delete elimina una nota, solo si todavia es un borrador
+func (s *noteSqlStore) Delete(id int) error {
+	result, err := s.DB.Exec("DELETE FROM clinical_note WHERE id = ? AND status = ?", id, domain.NoteDraft)
+	if err != nil {
+		return err
+	}
+	return draftAffected(result)
+}
+
+// CreateAddendum agrega una correccion a una nota
+func (s *noteSqlStore) CreateAddendum(addendum domain.ClinicalAddendum) (domain.ClinicalAddendum, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO clinical_addendum (note_id, dentist_id, text) VALUES (?, ?, ?);")
+	if err != nil {
+		return domain.ClinicalAddendum{}, err
+	}
+	defer stmt.Close()
+	result, err := stmt.Exec(addendum.NoteId, addendum.Dentist.Id, addendum.Text)
+	if err != nil {
+		return domain.ClinicalAddendum{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	addendum.Id = int(insertedId)
+	return addendum, nil
+}
+
+// getNotes busca las notas que cumplen la condicion y completa sus agregados
+func (s *noteSqlStore) getNotes(condition string, args ...interface{}) ([]domain.ClinicalNote, error) {
+	notes := []domain.ClinicalNote{}
+
+	query := "SELECT clinical_note.id, clinical_note.appointment_id, clinical_note.subjective, clinical_note.objective, clinical_note.assessment, clinical_note.plan, clinical_note.status, clinical_note.created_at, COALESCE(clinical_note.signed_at, ''), dentist.* FROM clinical_note INNER JOIN dentist ON clinical_note.dentist_id = dentist.id WHERE " + condition + " ORDER BY clinical_note.created_at, clinical_note.id"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.ClinicalNote{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var note domain.ClinicalNote
+		err := rows.Scan(&note.Id, &note.AppointmentId, &note.Subjective, &note.Objective, &note.Assessment, &note.Plan, &note.Status, &note.CreatedAt, &note.SignedAt, &note.Dentist.Id, &note.Dentist.Name, &note.Dentist.LastName, &note.Dentist.License)
+		if err != nil {
+			return []domain.ClinicalNote{}, err
+		}
+		notes = append(notes, note)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.ClinicalNote{}, err
+	}
+	for i := range notes {
+		notes[i].Addenda, err = s.getAddenda(notes[i].Id)
+		if err != nil {
+			return []domain.ClinicalNote{}, err
+		}
+	}
+	return notes, nil
+}
+
+// getAddenda devuelve los agregados de una nota en orden cronologico
+func (s *noteSqlStore) getAddenda(noteId int) ([]domain.ClinicalAddendum, error) {
+	addenda := []domain.ClinicalAddendum{}
+
+	query := "SELECT clinical_addendum.id, clinical_addendum.note_id, clinical_addendum.text, clinical_addendum.created_at, dentist.* FROM clinical_addendum INNER JOIN dentist ON clinical_addendum.dentist_id = dentist.id WHERE clinical_addendum.note_id = ? ORDER BY clinical_addendum.created_at, clinical_addendum.id"
+	rows, err := s.DB.Query(query, noteId)
+	if err != nil {
+		return []domain.ClinicalAddendum{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var addendum domain.ClinicalAddendum
+		err := rows.Scan(&addendum.Id, &addendum.NoteId, &addendum.Text, &addendum.CreatedAt, &addendum.Dentist.Id, &addendum.Dentist.Name, &addendum.Dentist.LastName, &addendum.Dentist.License)
+		if err != nil {
+			return []domain.ClinicalAddendum{}, err
+		}
+		addenda = append(addenda, addendum)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.ClinicalAddendum{}, err
+	}
+	return addenda, nil
+}
+
+// draftAffected verifica que la sentencia haya modificado una nota en borrador
+func draftAffected(result sql.Result) error {
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
@@ -0,0 +1,14 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type NoteStore interface {
+	GetByID(id int) (domain.ClinicalNote, error)
+	GetByAppointment(appointmentId int) ([]domain.ClinicalNote, error)
+	CountByAppointment(appointmentId int) (int, error)
+	Create(note domain.ClinicalNote) (domain.ClinicalNote, error)
+	Update(note domain.ClinicalNote) error
+	Sign(id int) error
+	Delete(id int) error
+	CreateAddendum(addendum domain.ClinicalAddendum) (domain.ClinicalAddendum, error)
+}
@@ -7,6 +7,9 @@ import (
 	"time"
 )
 
+// patientColumns son las columnas de un paciente en el orden que espera patientFields
+const patientColumns = "patient.id, patient.name, patient.last_name, patient.address_street, patient.address_number, patient.address_city, patient.address_province, patient.address_postal_code, patient.dni, COALESCE(patient.birth_date, ''), patient.email, patient.preferred_language, patient.contact_channel, patient.reminders, patient.admission_date"
+
 type patientSqlStore struct {
 	DB *sql.DB
 }
@@ -18,64 +21,80 @@ func NewPatientSqlStore(db *sql.DB) PatientStore {
 
 // GetByID devuelve un paciente por su id
 func (s *patientSqlStore) GetByID(id int) (domain.Patient, error) {
-	var patientReturn domain.Patient
-	query := "SELECT * FROM patient WHERE id = ?;"
-	row := s.DB.QueryRow(query, id)
-	err := row.Scan(&patientReturn.Id, &patientReturn.Name, &patientReturn.LastName, &patientReturn.Domicilio, &patientReturn.Dni, &patientReturn.Email, &patientReturn.AdmissionDate)
-	if err != nil {
-		return domain.Patient{}, err
-	}
-	return patientReturn, nil
+	return s.getPatient("patient.id = ?", id)
 }
 
 // GetByDni devuelve un paciente por su dni
 func (s *patientSqlStore) GetByDni(dni int) (domain.Patient, error) {
-	var patientReturn domain.Patient
-	query := "SELECT * FROM patient WHERE dni = ?;"
-	row := s.DB.QueryRow(query, dni)
-	err := row.Scan(&patientReturn.Id, &patientReturn.Name, &patientReturn.LastName, &patientReturn.Domicilio, &patientReturn.Dni, &patientReturn.Email, &patientReturn.AdmissionDate)
-	if err != nil {
-		return domain.Patient{}, err
-	}
-	return patientReturn, nil
+	return s.getPatient("patient.dni = ?", dni)
 }
 
-// Create agrega un nuevo paciente
+// Create agrega un nuevo paciente con sus telefonos
 func (s *patientSqlStore) Create(patient domain.Patient) (domain.Patient, error) {
-	stmt, err := s.DB.Prepare("INSERT INTO patient (name, last_name, domicilio, dni, email, admission_date) VALUES (?, ?, ?, ?, ?, ?);")
+	date, err := time.Parse("2006-01-02", patient.AdmissionDate)
 	if err != nil {
 		return domain.Patient{}, err
 	}
-	defer stmt.Close()
-	date, err := time.Parse("2006-01-02", patient.AdmissionDate)
+	tx, err := s.DB.Begin()
 	if err != nil {
 		return domain.Patient{}, err
 	}
-	result, err := stmt.Exec(patient.Name, patient.LastName, patient.Domicilio, patient.Dni, patient.Email, date)
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT INTO patient (name, last_name, address_street, address_number, address_city, address_province, address_postal_code, dni, birth_date, email, preferred_language, contact_channel, reminders, admission_date) VALUES (?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?, ?);",
+		patient.Name, patient.LastName, patient.Address.Street, patient.Address.Number, patient.Address.City, patient.Address.Province, patient.Address.PostalCode,
+		patient.Dni, nullString(patient.BirthDate), patient.Email, patient.PreferredLanguage, patient.ContactPreferences.Channel, reminders(patient), date)
 	if err != nil {
 		return domain.Patient{}, err
 	}
 	insertedId, _ := result.LastInsertId()
 	patient.Id = int(insertedId)
+	err = insertPhones(tx, patient)
+	if err != nil {
+		return domain.Patient{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.Patient{}, err
+	}
 	return patient, nil
 }
 
-// Update actualiza un paciente
+// Update actualiza un paciente, si trae telefonos reemplazan a los anteriores
 func (s *patientSqlStore) Update(patient domain.Patient) (domain.Patient, error) {
 	patientUpdated, err := s.CompleteEmptyAttributes(patient)
-	stmt, err := s.DB.Prepare("UPDATE patient SET name = ?, last_name = ?, domicilio = ?, dni = ?, email = ?, admission_date = ? WHERE id = ?;")
 	if err != nil {
 		return domain.Patient{}, err
 	}
-	defer stmt.Close()
-	date, err := time.Parse("2006-01-02", patient.AdmissionDate)
+	date, err := time.Parse("2006-01-02", patientUpdated.AdmissionDate)
+	if err != nil {
+		return domain.Patient{}, err
+	}
+	tx, err := s.DB.Begin()
 	if err != nil {
 		return domain.Patient{}, err
 	}
-	_, err = stmt.Exec(patientUpdated.Name, patientUpdated.LastName, patientUpdated.Domicilio, patientUpdated.Dni, patientUpdated.Email, date, patientUpdated.Id)
+	defer tx.Rollback()
+	_, err = tx.Exec("UPDATE patient SET name = ?, last_name = ?, address_street = ?, address_number = ?, address_city = ?, address_province = ?, address_postal_code = ?, dni = ?, birth_date = ?, email = ?, preferred_language = ?, contact_channel = ?, reminders = ?, admission_date = ? WHERE id = ?;",
+		patientUpdated.Name, patientUpdated.LastName, patientUpdated.Address.Street, patientUpdated.Address.Number, patientUpdated.Address.City, patientUpdated.Address.Province, patientUpdated.Address.PostalCode,
+		patientUpdated.Dni, nullString(patientUpdated.BirthDate), patientUpdated.Email, patientUpdated.PreferredLanguage, patientUpdated.ContactPreferences.Channel, reminders(patientUpdated), date, patientUpdated.Id)
 	if err != nil {
 		return domain.Patient{}, err
 	}
+	if patient.Phones != nil {
+		_, err = tx.Exec("DELETE FROM patient_phone WHERE patient_id = ?", patientUpdated.Id)
+		if err != nil {
+			return domain.Patient{}, err
+		}
+		err = insertPhones(tx, patientUpdated)
+		if err != nil {
+			return domain.Patient{}, err
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.Patient{}, err
+	}
+	patientUpdated.Age = patientUpdated.AgeAt(time.Now())
 	return patientUpdated, nil
 }
 
@@ -101,17 +120,110 @@ func (s *patientSqlStore) CompleteEmptyAttributes(updatedPatient domain.Patient)
 	if updatedPatient.LastName != "" {
 		p.LastName = updatedPatient.LastName
 	}
-	if updatedPatient.Domicilio != "" {
-		p.Domicilio = updatedPatient.Domicilio
+	if updatedPatient.Address.Street != "" {
+		p.Address.Street = updatedPatient.Address.Street
+	}
+	if updatedPatient.Address.Number != "" {
+		p.Address.Number = updatedPatient.Address.Number
+	}
+	if updatedPatient.Address.City != "" {
+		p.Address.City = updatedPatient.Address.City
+	}
+	if updatedPatient.Address.Province != "" {
+		p.Address.Province = updatedPatient.Address.Province
+	}
+	if updatedPatient.Address.PostalCode != "" {
+		p.Address.PostalCode = updatedPatient.Address.PostalCode
 	}
 	if updatedPatient.Dni != 0 {
 		p.Dni = updatedPatient.Dni
 	}
+	if updatedPatient.BirthDate != "" {
+		p.BirthDate = updatedPatient.BirthDate
+	}
 	if updatedPatient.Email != "" {
 		p.Email = updatedPatient.Email
 	}
+	if updatedPatient.Phones != nil {
+		p.Phones = updatedPatient.Phones
+	}
+	if updatedPatient.PreferredLanguage != "" {
+		p.PreferredLanguage = updatedPatient.PreferredLanguage
+	}
+	if updatedPatient.ContactPreferences.Channel != "" {
+		p.ContactPreferences.Channel = updatedPatient.ContactPreferences.Channel
+	}
+	if updatedPatient.ContactPreferences.Reminders != nil {
+		p.ContactPreferences.Reminders = updatedPatient.ContactPreferences.Reminders
+	}
 	if updatedPatient.AdmissionDate != "" {
 		p.AdmissionDate = updatedPatient.AdmissionDate
 	}
 	return p, nil
 }
+
+// getPatient busca un paciente con sus telefonos y calcula su edad
+func (s *patientSqlStore) getPatient(condition string, args ...interface{}) (domain.Patient, error) {
+	var patientReturn domain.Patient
+	query := "SELECT " + patientColumns + " FROM patient WHERE " + condition + ";"
+	row := s.DB.QueryRow(query, args...)
+	err := row.Scan(patientFields(&patientReturn)...)
+	if err != nil {
+		return domain.Patient{}, err
+	}
+	patientReturn.Age = patientReturn.AgeAt(time.Now())
+	patientReturn.Phones, err = s.getPhones(patientReturn.Id)
+	if err != nil {
+		return domain.Patient{}, err
+	}
+	return patientReturn, nil
+}
+
+// getPhones devuelve los telefonos de un paciente, primero el principal
+func (s *patientSqlStore) getPhones(patientId int) ([]domain.Phone, error) {
+	phones := []domain.Phone{}
+
+	query := "SELECT phone_type, number, is_primary FROM patient_phone WHERE patient_id = ? ORDER BY is_primary DESC, id"
+	rows, err := s.DB.Query(query, patientId)
+	if err != nil {
+		return []domain.Phone{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var phone domain.Phone
+		err := rows.Scan(&phone.Type, &phone.Number, &phone.Primary)
+		if err != nil {
+			return []domain.Phone{}, err
+		}
+		phones = append(phones, phone)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Phone{}, err
+	}
+	return phones, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// patientFields devuelve los destinos de Scan para las columnas de patientColumns
+func patientFields(p *domain.Patient) []interface{} {
+	return []interface{}{&p.Id, &p.Name, &p.LastName, &p.Address.Street, &p.Address.Number, &p.Address.City, &p.Address.Province, &p.Address.PostalCode,
+		&p.Dni, &p.BirthDate, &p.Email, &p.PreferredLanguage, &p.ContactPreferences.Channel, &p.ContactPreferences.Reminders, &p.AdmissionDate}
+}
+
+// insertPhones agrega los telefonos de un paciente dentro de una transaccion
+func insertPhones(tx *sql.Tx, patient domain.Patient) error {
+	for _, phone := range patient.Phones {
+		_, err := tx.Exec("INSERT INTO patient_phone (patient_id, phone_type, number, is_primary) VALUES (?, ?, ?, ?);", patient.Id, phone.Type, phone.Number, phone.Primary)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// reminders devuelve si el paciente acepta recordatorios, por defecto si
+func reminders(patient domain.Patient) bool {
+	return patient.ContactPreferences.Reminders == nil || *patient.ContactPreferences.Reminders
+}
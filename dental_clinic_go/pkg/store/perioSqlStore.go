@@ -0,0 +1,132 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"time"
+)
+
+type perioSqlStore struct {
+	DB *sql.DB
+}
+
+// NewPerioSqlStore crea un nuevo store de periodontogramas
+func NewPerioSqlStore(db *sql.DB) PerioStore {
+	return &perioSqlStore{db}
+}
+
+// GetByID devuelve un examen periodontal con sus medidas
+func (s *perioSqlStore) GetByID(id int) (domain.PerioExam, error) {
+	exams, err := s.getExams("perio_exam.id = ?", id)
+	if err != nil {
+		return domain.PerioExam{}, err
+	}
+	if len(exams) == 0 {
+		return domain.PerioExam{}, sql.ErrNoRows
+	}
+	return exams[0], nil
+}
+
+// GetByPatient devuelve los examenes periodontales de un paciente en orden cronologico
+func (s *perioSqlStore) GetByPatient(patientId int) ([]domain.PerioExam, error) {
+	return s.getExams("perio_exam.patient_id = ?", patientId)
+}
+
+// Create agrega un examen periodontal y sus medidas en una transaccion
+func (s *perioSqlStore) Create(exam domain.PerioExam) (domain.PerioExam, error) {
+	date, err := time.Parse("2006-01-02", exam.Date)
+	if err != nil {
+		return domain.PerioExam{}, err
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.PerioExam{}, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT INTO perio_exam (patient_id, dentist_id, date, notes) VALUES (?, ?, ?, ?);",
+		exam.PatientId, exam.Dentist.Id, date, exam.Notes)
+	if err != nil {
+		return domain.PerioExam{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	exam.Id = int(insertedId)
+	for _, tooth := range exam.Teeth {
+		_, err := tx.Exec("INSERT INTO perio_tooth (exam_id, tooth, mobility) VALUES (?, ?, ?);", exam.Id, tooth.Tooth, tooth.Mobility)
+		if err != nil {
+			return domain.PerioExam{}, err
+		}
+		for _, site := range tooth.Sites {
+			_, err := tx.Exec("INSERT INTO perio_site (exam_id, tooth, site, depth, recession, bleeding) VALUES (?, ?, ?, ?, ?, ?);",
+				exam.Id, tooth.Tooth, site.Site, site.Depth, site.Recession, site.Bleeding)
+			if err != nil {
+				return domain.PerioExam{}, err
+			}
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.PerioExam{}, err
+	}
+	return exam, nil
+}
+
+// getExams busca los examenes que cumplen la condicion y completa sus medidas
+func (s *perioSqlStore) getExams(condition string, args ...interface{}) ([]domain.PerioExam, error) {
+	exams := []domain.PerioExam{}
+
+	query := "SELECT perio_exam.id, perio_exam.patient_id, perio_exam.date, perio_exam.notes, perio_exam.created_at, dentist.* FROM perio_exam INNER JOIN dentist ON perio_exam.dentist_id = dentist.id WHERE " + condition + " ORDER BY perio_exam.date, perio_exam.id"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.PerioExam{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var exam domain.PerioExam
+		err := rows.Scan(&exam.Id, &exam.PatientId, &exam.Date, &exam.Notes, &exam.CreatedAt, &exam.Dentist.Id, &exam.Dentist.Name, &exam.Dentist.LastName, &exam.Dentist.License)
+		if err != nil {
+			return []domain.PerioExam{}, err
+		}
+		exams = append(exams, exam)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.PerioExam{}, err
+	}
+	for i := range exams {
+		exams[i].Teeth, err = s.getTeeth(exams[i].Id)
+		if err != nil {
+			return []domain.PerioExam{}, err
+		}
+	}
+	return exams, nil
+}
+
+// getTeeth devuelve las piezas de un examen con sus sitios, ordenadas por pieza
+func (s *perioSqlStore) getTeeth(examId int) ([]domain.PerioTooth, error) {
+	teeth := []domain.PerioTooth{}
+
+	query := "SELECT perio_tooth.tooth, perio_tooth.mobility, perio_site.site, perio_site.depth, perio_site.recession, perio_site.bleeding FROM perio_tooth INNER JOIN perio_site ON perio_tooth.exam_id = perio_site.exam_id AND perio_tooth.tooth = perio_site.tooth WHERE perio_tooth.exam_id = ? ORDER BY perio_tooth.tooth, FIELD(perio_site.site, 'MB', 'B', 'DB', 'ML', 'L', 'DL')"
+	rows, err := s.DB.Query(query, examId)
+	if err != nil {
+		return []domain.PerioTooth{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var tooth, mobility int
+		var site domain.PerioSite
+		err := rows.Scan(&tooth, &mobility, &site.Site, &site.Depth, &site.Recession, &site.Bleeding)
+		if err != nil {
+			return []domain.PerioTooth{}, err
+		}
+		site.AttachmentLoss = site.Depth + site.Recession
+		if len(teeth) == 0 || teeth[len(teeth)-1].Tooth != tooth {
+			teeth = append(teeth, domain.PerioTooth{Tooth: tooth, Mobility: mobility})
+		}
+		teeth[len(teeth)-1].Sites = append(teeth[len(teeth)-1].Sites, site)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.PerioTooth{}, err
+	}
+	return teeth, nil
+}
@@ -0,0 +1,9 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type PerioStore interface {
+	GetByID(id int) (domain.PerioExam, error)
+	GetByPatient(patientId int) ([]domain.PerioExam, error)
+	Create(exam domain.PerioExam) (domain.PerioExam, error)
+}
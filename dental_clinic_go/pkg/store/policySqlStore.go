@@ -0,0 +1,124 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"fmt"
+)
+
+type policySqlStore struct {
+	DB *sql.DB
+}
+
+// NewPolicySqlStore crea un nuevo store de eventos de la politica de ausencias
+func NewPolicySqlStore(db *sql.DB) PolicyStore {
+	return &policySqlStore{db}
+}
+
+// GetByPatient devuelve los eventos de un paciente
+func (s *policySqlStore) GetByPatient(patientId int) ([]domain.PolicyEvent, error) {
+	var events []domain.PolicyEvent
+
+	query := "SELECT id, patient_id, appointment_id, type, fee, created_at FROM policy_event WHERE patient_id = ? ORDER BY created_at"
+	rows, err := s.DB.Query(query, patientId)
+	if err != nil {
+		return []domain.PolicyEvent{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var event domain.PolicyEvent
+		err := rows.Scan(&event.Id, &event.PatientId, &event.AppointmentId, &event.Type, &event.Fee, &event.CreatedAt)
+		if err != nil {
+			return []domain.PolicyEvent{}, err
+		}
+		events = append(events, event)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.PolicyEvent{}, err
+	}
+	return events, nil
+}
+
+// CountByPatient devuelve la cantidad de ausencias y cancelaciones tardias de un paciente
+func (s *policySqlStore) CountByPatient(patientId int) (int, int, error) {
+	var noShows, lateCancellations int
+	query := "SELECT COALESCE(SUM(type = ?), 0), COALESCE(SUM(type = ?), 0) FROM policy_event WHERE patient_id = ?;"
+	row := s.DB.QueryRow(query, domain.PolicyNoShow, domain.PolicyLateCancellation, patientId)
+	err := row.Scan(&noShows, &lateCancellations)
+	if err != nil {
+		return 0, 0, err
+	}
+	return noShows, lateCancellations, nil
+}
+
+// Create agrega un nuevo evento, si el turno ya tenia un evento del mismo tipo no hace nada. El cargo del
+// evento se registra en la cuenta del paciente en la misma transaccion.
+func (s *policySqlStore) Create(event domain.PolicyEvent) (domain.PolicyEvent, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.PolicyEvent{}, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT IGNORE INTO policy_event (patient_id, appointment_id, type, fee) VALUES (?, ?, ?, ?);",
+		event.PatientId, event.AppointmentId, event.Type, event.Fee)
+	if err != nil {
+		return domain.PolicyEvent{}, err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return domain.PolicyEvent{}, err
+	}
+	if affected == 0 {
+		return event, nil
+	}
+	insertedId, _ := result.LastInsertId()
+	event.Id = int(insertedId)
+	if event.Fee > 0 {
+		_, err = tx.Exec("INSERT INTO ledger_entry (patient_id, entry_type, entry_date, debit, credit, reference, description) VALUES (?, ?, CURDATE(), ?, 0, '', ?)",
+			event.PatientId, domain.EntryCharge, event.Fee, policyDescription(event))
+		if err != nil {
+			return domain.PolicyEvent{}, err
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.PolicyEvent{}, err
+	}
+	return event, nil
+}
+
+// Delete elimina un evento y, si tenia cargo, lo revierte con un ajuste en la cuenta del paciente
+func (s *policySqlStore) Delete(id int) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var event domain.PolicyEvent
+	row := tx.QueryRow("SELECT id, patient_id, appointment_id, type, fee FROM policy_event WHERE id = ? FOR UPDATE", id)
+	err = row.Scan(&event.Id, &event.PatientId, &event.AppointmentId, &event.Type, &event.Fee)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM policy_event WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	if event.Fee > 0 {
+		_, err = tx.Exec("INSERT INTO ledger_entry (patient_id, entry_type, entry_date, debit, credit, reference, description) VALUES (?, ?, CURDATE(), 0, ?, '', ?)",
+			event.PatientId, domain.EntryAdjustment, event.Fee, "Anulacion: "+policyDescription(event))
+		if err != nil {
+			return err
+		}
+	}
+	return tx.Commit()
+}
+
+// policyDescription es el detalle del movimiento de cuenta de un evento
+func policyDescription(event domain.PolicyEvent) string {
+	if event.Type == domain.PolicyNoShow {
+		return fmt.Sprintf("Ausencia al turno %d", event.AppointmentId)
+	}
+	return fmt.Sprintf("Cancelacion tardia del turno %d", event.AppointmentId)
+}
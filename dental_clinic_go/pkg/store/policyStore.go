@@ -0,0 +1,10 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type PolicyStore interface {
+	GetByPatient(patientId int) ([]domain.PolicyEvent, error)
+	CountByPatient(patientId int) (int, int, error)
+	Create(event domain.PolicyEvent) (domain.PolicyEvent, error)
+	Delete(id int) error
+}
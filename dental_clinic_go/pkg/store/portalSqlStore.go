@@ -0,0 +1,116 @@
+package store
+
+import (
+	"database/sql"
+	"errors"
+	"time"
+)
+
+type portalSqlStore struct {
+	DB *sql.DB
+}
+
+// NewPortalSqlStore crea un nuevo store de accesos al portal de pacientes
+func NewPortalSqlStore(db *sql.DB) PortalStore {
+	return &portalSqlStore{db}
+}
+
+// CreateCode guarda un codigo de un solo uso para un paciente e invalida los que tenia sin usar
+func (s *portalSqlStore) CreateCode(patientId int, codeHash string, expiresAt time.Time) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec("UPDATE portal_code SET used = 1 WHERE patient_id = ? AND used = 0", patientId)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("INSERT INTO portal_code (patient_id, code_hash, expires_at) VALUES (?, ?, ?);", patientId, codeHash, expiresAt)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CountCodes devuelve la cantidad de codigos que se generaron para un paciente en el ultimo window. La ventana
+// se calcula en la base porque created_at lo completa la base con su propia zona horaria.
+func (s *portalSqlStore) CountCodes(patientId int, window time.Duration) (int, error) {
+	var count int
+	query := "SELECT COUNT(*) FROM portal_code WHERE patient_id = ? AND created_at > NOW() - INTERVAL ? SECOND"
+	err := s.DB.QueryRow(query, patientId, int(window.Seconds())).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// UseCode marca como usado el codigo vigente de un paciente si coincide con codeHash. Si no coincide suma
+// un intento, y al llegar a maxAttempts el codigo deja de valer. Falla si no hay codigo vigente.
+func (s *portalSqlStore) UseCode(patientId int, codeHash string, now time.Time, maxAttempts int) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	var id int
+	var currentHash string
+	query := `SELECT id, code_hash FROM portal_code WHERE patient_id = ? AND used = 0 AND expires_at > ? AND attempts < ?
+		ORDER BY id DESC LIMIT 1 FOR UPDATE`
+	err = tx.QueryRow(query, patientId, now, maxAttempts).Scan(&id, &currentHash)
+	if err != nil {
+		return err
+	}
+	if currentHash != codeHash {
+		_, err = tx.Exec("UPDATE portal_code SET attempts = attempts + 1 WHERE id = ?", id)
+		if err != nil {
+			return err
+		}
+		err = tx.Commit()
+		if err != nil {
+			return err
+		}
+		return errors.New("wrong code")
+	}
+	_, err = tx.Exec("UPDATE portal_code SET used = 1 WHERE id = ?", id)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// CreateSession guarda una nueva sesion del portal
+func (s *portalSqlStore) CreateSession(patientId int, tokenHash string, expiresAt time.Time) error {
+	stmt, err := s.DB.Prepare("INSERT INTO portal_session (patient_id, token_hash, expires_at) VALUES (?, ?, ?);")
+	if err != nil {
+		return err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(patientId, tokenHash, expiresAt)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetSession devuelve el id del paciente de una sesion vigente
+func (s *portalSqlStore) GetSession(tokenHash string, now time.Time) (int, error) {
+	var patientId int
+	query := "SELECT patient_id FROM portal_session WHERE token_hash = ? AND expires_at > ?;"
+	row := s.DB.QueryRow(query, tokenHash, now)
+	err := row.Scan(&patientId)
+	if err != nil {
+		return 0, err
+	}
+	return patientId, nil
+}
+
+// DeleteSession elimina una sesion del portal
+func (s *portalSqlStore) DeleteSession(tokenHash string) error {
+	stmt := "DELETE FROM portal_session WHERE token_hash = ?"
+	_, err := s.DB.Exec(stmt, tokenHash)
+	if err != nil {
+		return err
+	}
+	return nil
+}
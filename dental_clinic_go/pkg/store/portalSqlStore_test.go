@@ -0,0 +1,42 @@
+package store
+
+import (
+	"testing"
+	"time"
+)
+
+func TestPortalCountCodesWindow(t *testing.T) {
+	db := testDB(t)
+	s := NewPortalSqlStore(db)
+	var patientId int
+	err := db.QueryRow("SELECT id FROM patient ORDER BY id LIMIT 1").Scan(&patientId)
+	if err != nil {
+		t.Fatalf("finding a patient: %s", err)
+	}
+	t.Cleanup(func() {
+		db.Exec("DELETE FROM portal_code WHERE patient_id = ? AND code_hash LIKE 'test%'", patientId)
+	})
+	before, err := s.CountCodes(patientId, time.Hour)
+	if err != nil {
+		t.Fatalf("counting codes: %s", err)
+	}
+
+	// Un codigo de hace dos horas queda fuera de la ventana, uno recien creado cuenta
+	for _, codeHash := range []string{"test-old", "test-new"} {
+		err = s.CreateCode(patientId, codeHash, time.Now().UTC().Add(10*time.Minute))
+		if err != nil {
+			t.Fatalf("creating code: %s", err)
+		}
+	}
+	_, err = db.Exec("UPDATE portal_code SET created_at = NOW() - INTERVAL 2 HOUR WHERE patient_id = ? AND code_hash = 'test-old'", patientId)
+	if err != nil {
+		t.Fatalf("backdating code: %s", err)
+	}
+	count, err := s.CountCodes(patientId, time.Hour)
+	if err != nil {
+		t.Fatalf("counting codes: %s", err)
+	}
+	if count != before+1 {
+		t.Fatalf("expected %d codes in the last hour, got %d", before+1, count)
+	}
+}
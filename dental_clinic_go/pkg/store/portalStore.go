@@ -0,0 +1,12 @@
+package store
+
+import "time"
+
+type PortalStore interface {
+	CreateCode(patientId int, codeHash string, expiresAt time.Time) error
+	CountCodes(patientId int, window time.Duration) (int, error)
+	UseCode(patientId int, codeHash string, now time.Time, maxAttempts int) error
+	CreateSession(patientId int, tokenHash string, expiresAt time.Time) error
+	GetSession(tokenHash string, now time.Time) (int, error)
+	DeleteSession(tokenHash string) error
+}
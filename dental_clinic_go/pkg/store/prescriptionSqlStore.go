@@ -0,0 +1,129 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"time"
+)
+
+type prescriptionSqlStore struct {
+	DB *sql.DB
+}
+
+// NewPrescriptionSqlStore crea un nuevo store de recetas
+func NewPrescriptionSqlStore(db *sql.DB) PrescriptionStore {
+	return &prescriptionSqlStore{db}
+}
+
+// GetByID devuelve una receta con sus medicamentos
+func (s *prescriptionSqlStore) GetByID(id int) (domain.Prescription, error) {
+	prescriptions, err := s.getPrescriptions("prescription.id = ?", id)
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	if len(prescriptions) == 0 {
+		return domain.Prescription{}, sql.ErrNoRows
+	}
+	return prescriptions[0], nil
+}
+
+// GetByPatient devuelve las recetas de un paciente, primero las mas recientes
+func (s *prescriptionSqlStore) GetByPatient(patientId int) ([]domain.Prescription, error) {
+	return s.getPrescriptions("prescription.patient_id = ?", patientId)
+}
+
+// Create agrega una receta y sus medicamentos en una transaccion
+func (s *prescriptionSqlStore) Create(p domain.Prescription) (domain.Prescription, error) {
+	date, err := time.Parse("2006-01-02", p.Date)
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	var appointmentId sql.NullInt64
+	if p.AppointmentId != 0 {
+		appointmentId = sql.NullInt64{Int64: int64(p.AppointmentId), Valid: true}
+	}
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT INTO prescription (patient_id, appointment_id, dentist_id, date, notes, allergy_override) VALUES (?, ?, ?, ?, ?, ?);",
+		p.PatientId, appointmentId, p.Dentist.Id, date, p.Notes, p.AllergyOverride)
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	p.Id = int(insertedId)
+	for i, item := range p.Items {
+		result, err := tx.Exec("INSERT INTO prescription_item (prescription_id, position, drug, dose, frequency, duration, notes) VALUES (?, ?, ?, ?, ?, ?, ?);",
+			p.Id, i+1, item.Drug, item.Dose, item.Frequency, item.Duration, item.Notes)
+		if err != nil {
+			return domain.Prescription{}, err
+		}
+		itemId, _ := result.LastInsertId()
+		p.Items[i].Id = int(itemId)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.Prescription{}, err
+	}
+	return p, nil
+}
+
+// getPrescriptions busca las recetas que cumplen la condicion y completa sus medicamentos
+func (s *prescriptionSqlStore) getPrescriptions(condition string, args ...interface{}) ([]domain.Prescription, error) {
+	prescriptions := []domain.Prescription{}
+
+	query := "SELECT prescription.id, prescription.patient_id, prescription.appointment_id, prescription.date, prescription.notes, prescription.allergy_override, prescription.created_at, dentist.* FROM prescription INNER JOIN dentist ON prescription.dentist_id = dentist.id WHERE " + condition + " ORDER BY prescription.date DESC, prescription.id DESC"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.Prescription{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p domain.Prescription
+		var appointmentId sql.NullInt64
+		err := rows.Scan(&p.Id, &p.PatientId, &appointmentId, &p.Date, &p.Notes, &p.AllergyOverride, &p.CreatedAt, &p.Dentist.Id, &p.Dentist.Name, &p.Dentist.LastName, &p.Dentist.License)
+		if err != nil {
+			return []domain.Prescription{}, err
+		}
+		p.AppointmentId = int(appointmentId.Int64)
+		prescriptions = append(prescriptions, p)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Prescription{}, err
+	}
+	for i := range prescriptions {
+		prescriptions[i].Items, err = s.getItems(prescriptions[i].Id)
+		if err != nil {
+			return []domain.Prescription{}, err
+		}
+	}
+	return prescriptions, nil
+}
+
+// getItems devuelve los medicamentos de una receta en orden
+func (s *prescriptionSqlStore) getItems(prescriptionId int) ([]domain.PrescriptionItem, error) {
+	items := []domain.PrescriptionItem{}
+
+	query := "SELECT id, drug, dose, frequency, duration, notes FROM prescription_item WHERE prescription_id = ? ORDER BY position"
+	rows, err := s.DB.Query(query, prescriptionId)
+	if err != nil {
+		return []domain.PrescriptionItem{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.PrescriptionItem
+		err := rows.Scan(&item.Id, &item.Drug, &item.Dose, &item.Frequency, &item.Duration, &item.Notes)
+		if err != nil {
+			return []domain.PrescriptionItem{}, err
+		}
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.PrescriptionItem{}, err
+	}
+	return items, nil
+}
@@ -0,0 +1,9 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type PrescriptionStore interface {
+	GetByID(id int) (domain.Prescription, error)
+	GetByPatient(patientId int) ([]domain.Prescription, error)
+	Create(p domain.Prescription) (domain.Prescription, error)
+}
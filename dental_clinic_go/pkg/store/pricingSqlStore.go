@@ -0,0 +1,219 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+)
+
+type pricingSqlStore struct {
+	DB *sql.DB
+}
+
+// NewPricingSqlStore crea un nuevo store de listas de precios y descuentos
+func NewPricingSqlStore(db *sql.DB) PricingStore {
+	return &pricingSqlStore{db}
+}
+
+// GetLists devuelve las listas de un financiador sin sus precios, las mas nuevas primero. Un insurerId
+// negativo devuelve las listas de todos.
+func (s *pricingSqlStore) GetLists(insurerId int) ([]domain.PriceList, error) {
+	lists := []domain.PriceList{}
+
+	query := "SELECT id, name, COALESCE(insurer_id, 0), effective_from, created_at FROM price_list WHERE ? < 0 OR COALESCE(insurer_id, 0) = ? ORDER BY COALESCE(insurer_id, 0), effective_from DESC, id DESC"
+	rows, err := s.DB.Query(query, insurerId, insurerId)
+	if err != nil {
+		return []domain.PriceList{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var l domain.PriceList
+		err := rows.Scan(&l.Id, &l.Name, &l.InsurerId, &l.EffectiveFrom, &l.CreatedAt)
+		if err != nil {
+			return []domain.PriceList{}, err
+		}
+		lists = append(lists, l)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.PriceList{}, err
+	}
+	return lists, nil
+}
+
+// GetList devuelve una lista con sus precios
+func (s *pricingSqlStore) GetList(id int) (domain.PriceList, error) {
+	var l domain.PriceList
+	query := "SELECT id, name, COALESCE(insurer_id, 0), effective_from, created_at FROM price_list WHERE id = ?"
+	err := s.DB.QueryRow(query, id).Scan(&l.Id, &l.Name, &l.InsurerId, &l.EffectiveFrom, &l.CreatedAt)
+	if err != nil {
+		return domain.PriceList{}, err
+	}
+	l.Prices, err = s.getPrices(id)
+	if err != nil {
+		return domain.PriceList{}, err
+	}
+	return l, nil
+}
+
+// GetPrice devuelve el precio de un procedimiento en la lista mas reciente del financiador vigente en la
+// fecha que lo incluye y el id de esa lista, si ninguna lo incluye el id es 0
+func (s *pricingSqlStore) GetPrice(procedureCode string, date string, insurerId int) (domain.Price, int, error) {
+	var price domain.Price
+	var listId int
+	query := `SELECT price_list.id, price.procedure_code, price.amount FROM price INNER JOIN price_list ON price.list_id = price_list.id
+		WHERE price.procedure_code = ? AND price_list.effective_from <= ? AND COALESCE(price_list.insurer_id, 0) = ?
+		ORDER BY price_list.effective_from DESC, price_list.id DESC LIMIT 1`
+	err := s.DB.QueryRow(query, procedureCode, date, insurerId).Scan(&listId, &price.ProcedureCode, &price.Amount)
+	if err == sql.ErrNoRows {
+		return domain.Price{}, 0, nil
+	}
+	if err != nil {
+		return domain.Price{}, 0, err
+	}
+	return price, listId, nil
+}
+
+// CreateList agrega una lista y sus precios en una transaccion
+func (s *pricingSqlStore) CreateList(list domain.PriceList) (domain.PriceList, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.PriceList{}, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT INTO price_list (name, insurer_id, effective_from) VALUES (?, ?, ?);", list.Name, nullInt(list.InsurerId), list.EffectiveFrom)
+	if err != nil {
+		return domain.PriceList{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	list.Id = int(insertedId)
+	err = insertPrices(tx, list)
+	if err != nil {
+		return domain.PriceList{}, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.PriceList{}, err
+	}
+	return list, nil
+}
+
+// UpdateList reemplaza el nombre, la fecha de vigencia y los precios de una lista
+func (s *pricingSqlStore) UpdateList(list domain.PriceList) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	_, err = tx.Exec("UPDATE price_list SET name = ?, effective_from = ? WHERE id = ?", list.Name, list.EffectiveFrom, list.Id)
+	if err != nil {
+		return err
+	}
+	_, err = tx.Exec("DELETE FROM price WHERE list_id = ?", list.Id)
+	if err != nil {
+		return err
+	}
+	err = insertPrices(tx, list)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// GetDiscounts devuelve todos los descuentos, activos o no
+func (s *pricingSqlStore) GetDiscounts() ([]domain.DiscountRule, error) {
+	return s.getDiscounts("1 = 1")
+}
+
+// GetDiscount devuelve un descuento por su id
+func (s *pricingSqlStore) GetDiscount(id int) (domain.DiscountRule, error) {
+	discounts, err := s.getDiscounts("id = ?", id)
+	if err != nil {
+		return domain.DiscountRule{}, err
+	}
+	if len(discounts) == 0 {
+		return domain.DiscountRule{}, sql.ErrNoRows
+	}
+	return discounts[0], nil
+}
+
+// CreateDiscount agrega un descuento
+func (s *pricingSqlStore) CreateDiscount(rule domain.DiscountRule) (domain.DiscountRule, error) {
+	result, err := s.DB.Exec("INSERT INTO discount_rule (name, discount_type, value, procedure_code, min_family_members, valid_from, valid_to, active) VALUES (?, ?, ?, ?, ?, ?, ?, ?);",
+		rule.Name, rule.Type, rule.Value, nullString(rule.ProcedureCode), rule.MinFamilyMembers, nullString(rule.ValidFrom), nullString(rule.ValidTo), rule.Active)
+	if err != nil {
+		return domain.DiscountRule{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	rule.Id = int(insertedId)
+	return rule, nil
+}
+
+// UpdateDiscount actualiza un descuento
+func (s *pricingSqlStore) UpdateDiscount(rule domain.DiscountRule) error {
+	_, err := s.DB.Exec("UPDATE discount_rule SET name = ?, discount_type = ?, value = ?, procedure_code = ?, min_family_members = ?, valid_from = ?, valid_to = ?, active = ? WHERE id = ?",
+		rule.Name, rule.Type, rule.Value, nullString(rule.ProcedureCode), rule.MinFamilyMembers, nullString(rule.ValidFrom), nullString(rule.ValidTo), rule.Active, rule.Id)
+	return err
+}
+
+// getPrices devuelve los precios de una lista
+func (s *pricingSqlStore) getPrices(listId int) ([]domain.Price, error) {
+	prices := []domain.Price{}
+
+	query := "SELECT procedure_code, amount FROM price WHERE list_id = ? ORDER BY procedure_code"
+	rows, err := s.DB.Query(query, listId)
+	if err != nil {
+		return []domain.Price{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var p domain.Price
+		err := rows.Scan(&p.ProcedureCode, &p.Amount)
+		if err != nil {
+			return []domain.Price{}, err
+		}
+		prices = append(prices, p)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Price{}, err
+	}
+	return prices, nil
+}
+
+// getDiscounts busca los descuentos que cumplen la condicion
+func (s *pricingSqlStore) getDiscounts(condition string, args ...interface{}) ([]domain.DiscountRule, error) {
+	discounts := []domain.DiscountRule{}
+
+	query := "SELECT id, name, discount_type, value, COALESCE(procedure_code, ''), min_family_members, COALESCE(valid_from, ''), COALESCE(valid_to, ''), active FROM discount_rule WHERE " + condition + " ORDER BY id"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.DiscountRule{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var d domain.DiscountRule
+		err := rows.Scan(&d.Id, &d.Name, &d.Type, &d.Value, &d.ProcedureCode, &d.MinFamilyMembers, &d.ValidFrom, &d.ValidTo, &d.Active)
+		if err != nil {
+			return []domain.DiscountRule{}, err
+		}
+		discounts = append(discounts, d)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.DiscountRule{}, err
+	}
+	return discounts, nil
+}
+
+/* ---------------------------------- Utils --------------------------------- */
+
+// insertPrices agrega los precios de una lista dentro de una transaccion
+func insertPrices(tx *sql.Tx, list domain.PriceList) error {
+	for _, p := range list.Prices {
+		_, err := tx.Exec("INSERT INTO price (list_id, procedure_code, amount) VALUES (?, ?, ?);", list.Id, p.ProcedureCode, p.Amount)
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
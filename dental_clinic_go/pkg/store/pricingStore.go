@@ -0,0 +1,15 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type PricingStore interface {
+	GetLists(insurerId int) ([]domain.PriceList, error)
+	GetList(id int) (domain.PriceList, error)
+	GetPrice(procedureCode string, date string, insurerId int) (domain.Price, int, error)
+	CreateList(list domain.PriceList) (domain.PriceList, error)
+	UpdateList(list domain.PriceList) error
+	GetDiscounts() ([]domain.DiscountRule, error)
+	GetDiscount(id int) (domain.DiscountRule, error)
+	CreateDiscount(rule domain.DiscountRule) (domain.DiscountRule, error)
+	UpdateDiscount(rule domain.DiscountRule) error
+}
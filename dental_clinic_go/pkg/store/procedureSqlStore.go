@@ -0,0 +1,145 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+)
+
+type procedureSqlStore struct {
+	DB *sql.DB
+}
+
+// NewProcedureSqlStore crea un nuevo store del catalogo de procedimientos
+func NewProcedureSqlStore(db *sql.DB) ProcedureStore {
+	return &procedureSqlStore{db}
+}
+
+// GetAll devuelve el catalogo, si category no esta vacia solo los procedimientos de esa categoria
+func (s *procedureSqlStore) GetAll(category string) ([]domain.Procedure, error) {
+	procedures := []domain.Procedure{}
+
+	query := "SELECT code, name, category, default_fee, default_duration, teeth, surfaces, specialty FROM dental_procedure WHERE ? = '' OR category = ? ORDER BY code"
+	rows, err := s.DB.Query(query, category, category)
+	if err != nil {
+		return []domain.Procedure{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var procedure domain.Procedure
+		var specialty sql.NullString
+		err := rows.Scan(&procedure.Code, &procedure.Name, &procedure.Category, &procedure.Fee, &procedure.Duration, &procedure.Teeth, &procedure.Surfaces, &specialty)
+		if err != nil {
+			return []domain.Procedure{}, err
+		}
+		procedure.Specialty = specialty.String
+		procedures = append(procedures, procedure)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Procedure{}, err
+	}
+	return procedures, nil
+}
+
+// GetByCode devuelve un procedimiento por su codigo
+func (s *procedureSqlStore) GetByCode(code string) (domain.Procedure, error) {
+	var procedure domain.Procedure
+	var specialty sql.NullString
+	query := "SELECT code, name, category, default_fee, default_duration, teeth, surfaces, specialty FROM dental_procedure WHERE code = ?;"
+	row := s.DB.QueryRow(query, code)
+	err := row.Scan(&procedure.Code, &procedure.Name, &procedure.Category, &procedure.Fee, &procedure.Duration, &procedure.Teeth, &procedure.Surfaces, &specialty)
+	if err != nil {
+		return domain.Procedure{}, err
+	}
+	procedure.Specialty = specialty.String
+	return procedure, nil
+}
+
+// Create agrega un nuevo procedimiento
+func (s *procedureSqlStore) Create(procedure domain.Procedure) (domain.Procedure, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO dental_procedure (code, name, category, default_fee, default_duration, teeth, surfaces, specialty) VALUES (?, ?, ?, ?, ?, ?, ?, ?);")
+	if err != nil {
+		return domain.Procedure{}, err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(procedure.Code, procedure.Name, procedure.Category, procedure.Fee, procedure.Duration, procedure.Teeth, procedure.Surfaces, nullString(procedure.Specialty))
+	if err != nil {
+		return domain.Procedure{}, err
+	}
+	return procedure, nil
+}
+
+// Update actualiza un procedimiento
+func (s *procedureSqlStore) Update(procedure domain.Procedure) (domain.Procedure, error) {
+	stmt, err := s.DB.Prepare("UPDATE dental_procedure SET name = ?, category = ?, default_fee = ?, default_duration = ?, teeth = ?, surfaces = ?, specialty = ? WHERE code = ?;")
+	if err != nil {
+		return domain.Procedure{}, err
+	}
+	defer stmt.Close()
+	_, err = stmt.Exec(procedure.Name, procedure.Category, procedure.Fee, procedure.Duration, procedure.Teeth, procedure.Surfaces, nullString(procedure.Specialty), procedure.Code)
+	if err != nil {
+		return domain.Procedure{}, err
+	}
+	return procedure, nil
+}
+
+// Delete elimina un procedimiento
+func (s *procedureSqlStore) Delete(code string) error {
+	stmt := "DELETE FROM dental_procedure WHERE code = ?"
+	_, err := s.DB.Exec(stmt, code)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// Import crea los codigos nuevos y actualiza los existentes en una sola transaccion, si alguno falla no se
+// guarda ninguno. keepSpecialty conserva la especialidad de los codigos existentes.
+func (s *procedureSqlStore) Import(procedures []domain.Procedure, keepSpecialty bool) (domain.ProcedureImport, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.ProcedureImport{}, err
+	}
+	defer tx.Rollback()
+	result := domain.ProcedureImport{}
+	for _, p := range procedures {
+		var specialty sql.NullString
+		err := tx.QueryRow("SELECT specialty FROM dental_procedure WHERE code = ? FOR UPDATE", p.Code).Scan(&specialty)
+		switch {
+		case err == sql.ErrNoRows:
+			_, err = tx.Exec("INSERT INTO dental_procedure (code, name, category, default_fee, default_duration, teeth, surfaces, specialty) VALUES (?, ?, ?, ?, ?, ?, ?, ?);",
+				p.Code, p.Name, p.Category, p.Fee, p.Duration, p.Teeth, p.Surfaces, nullString(p.Specialty))
+			if err != nil {
+				return domain.ProcedureImport{}, err
+			}
+			result.Created++
+		case err != nil:
+			return domain.ProcedureImport{}, err
+		default:
+			if keepSpecialty {
+				p.Specialty = specialty.String
+			}
+			_, err = tx.Exec("UPDATE dental_procedure SET name = ?, category = ?, default_fee = ?, default_duration = ?, teeth = ?, surfaces = ?, specialty = ? WHERE code = ?;",
+				p.Name, p.Category, p.Fee, p.Duration, p.Teeth, p.Surfaces, nullString(p.Specialty), p.Code)
+			if err != nil {
+				return domain.ProcedureImport{}, err
+			}
+			result.Updated++
+		}
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.ProcedureImport{}, err
+	}
+	return result, nil
+}
+
+// nullString guarda los textos vacios como NULL, para las claves foraneas opcionales
+func nullString(value string) sql.NullString {
+	return sql.NullString{String: value, Valid: value != ""}
+}
+
+// nullInt convierte un id opcional en NULL cuando es 0
+func nullInt(value int) sql.NullInt64 {
+	return sql.NullInt64{Int64: int64(value), Valid: value != 0}
+}
@@ -0,0 +1,12 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type ProcedureStore interface {
+	GetAll(category string) ([]domain.Procedure, error)
+	GetByCode(code string) (domain.Procedure, error)
+	Create(procedure domain.Procedure) (domain.Procedure, error)
+	Update(procedure domain.Procedure) (domain.Procedure, error)
+	Delete(code string) error
+	Import(procedures []domain.Procedure, keepSpecialty bool) (domain.ProcedureImport, error)
+}
@@ -0,0 +1,104 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+)
+
+type referralSqlStore struct {
+	DB *sql.DB
+}
+
+// NewReferralSqlStore crea un nuevo store de derivaciones
+func NewReferralSqlStore(db *sql.DB) ReferralStore {
+	return &referralSqlStore{db}
+}
+
+// GetByID devuelve una derivacion
+func (s *referralSqlStore) GetByID(id int) (domain.Referral, error) {
+	referrals, err := s.getReferrals("referral.id = ?", id)
+	if err != nil {
+		return domain.Referral{}, err
+	}
+	if len(referrals) == 0 {
+		return domain.Referral{}, sql.ErrNoRows
+	}
+	return referrals[0], nil
+}
+
+// GetByPatient devuelve las derivaciones de un paciente
+func (s *referralSqlStore) GetByPatient(patientId int) ([]domain.Referral, error) {
+	return s.getReferrals("referral.patient_id = ?", patientId)
+}
+
+// GetInbox devuelve las derivaciones en un estado dirigidas a un dentista o, si no tienen
+// dentista asignado, a alguna de sus especialidades
+func (s *referralSqlStore) GetInbox(dentistId int, status string) ([]domain.Referral, error) {
+	return s.getReferrals("referral.status = ? AND (referral.to_dentist_id = ? OR (referral.to_dentist_id IS NULL AND referral.specialty IN (SELECT specialty FROM dentist_specialty WHERE dentist_id = ?)))",
+		status, dentistId, dentistId)
+}
+
+// GetByAppointment devuelve las derivaciones agendadas en un turno
+func (s *referralSqlStore) GetByAppointment(appointmentId int) ([]domain.Referral, error) {
+	return s.getReferrals("referral.appointment_id = ?", appointmentId)
+}
+
+// Create agrega una derivacion
+func (s *referralSqlStore) Create(referral domain.Referral) (domain.Referral, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO referral (patient_id, from_dentist_id, to_dentist_id, specialty, reason, urgency, status, response) VALUES (?, ?, ?, ?, ?, ?, ?, ?);")
+	if err != nil {
+		return domain.Referral{}, err
+	}
+	defer stmt.Close()
+	result, err := stmt.Exec(referral.PatientId, referral.FromDentist.Id, nullInt(referral.ToDentist.Id), nullString(referral.Specialty), referral.Reason, referral.Urgency, referral.Status, referral.Response)
+	if err != nil {
+		return domain.Referral{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	referral.Id = int(insertedId)
+	return referral, nil
+}
+
+// Update actualiza el dentista asignado, el estado, la respuesta y el turno de una derivacion
+func (s *referralSqlStore) Update(referral domain.Referral) error {
+	stmt := "UPDATE referral SET to_dentist_id = ?, status = ?, response = ?, appointment_id = ? WHERE id = ?"
+	result, err := s.DB.Exec(stmt, nullInt(referral.ToDentist.Id), referral.Status, referral.Response, nullInt(referral.AppointmentId), referral.Id)
+	if err != nil {
+		return err
+	}
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if rowsAffected == 0 {
+		return sql.ErrNoRows
+	}
+	return nil
+}
+
+// getReferrals busca las derivaciones que cumplen la condicion, primero las mas urgentes
+func (s *referralSqlStore) getReferrals(condition string, args ...interface{}) ([]domain.Referral, error) {
+	referrals := []domain.Referral{}
+
+	query := "SELECT referral.id, referral.patient_id, COALESCE(referral.specialty, ''), referral.reason, referral.urgency, referral.status, referral.response, COALESCE(referral.appointment_id, 0), referral.created_at, from_dentist.*, COALESCE(to_dentist.id, 0), COALESCE(to_dentist.name, ''), COALESCE(to_dentist.last_name, ''), COALESCE(to_dentist.license, '') FROM referral INNER JOIN dentist from_dentist ON referral.from_dentist_id = from_dentist.id LEFT JOIN dentist to_dentist ON referral.to_dentist_id = to_dentist.id WHERE " + condition + " ORDER BY FIELD(referral.urgency, 'urgent', 'priority', 'routine'), referral.created_at, referral.id"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.Referral{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var r domain.Referral
+		err := rows.Scan(&r.Id, &r.PatientId, &r.Specialty, &r.Reason, &r.Urgency, &r.Status, &r.Response, &r.AppointmentId, &r.CreatedAt,
+			&r.FromDentist.Id, &r.FromDentist.Name, &r.FromDentist.LastName, &r.FromDentist.License,
+			&r.ToDentist.Id, &r.ToDentist.Name, &r.ToDentist.LastName, &r.ToDentist.License)
+		if err != nil {
+			return []domain.Referral{}, err
+		}
+		referrals = append(referrals, r)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.Referral{}, err
+	}
+	return referrals, nil
+}
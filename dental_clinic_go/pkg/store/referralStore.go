@@ -0,0 +1,12 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type ReferralStore interface {
+	GetByID(id int) (domain.Referral, error)
+	GetByPatient(patientId int) ([]domain.Referral, error)
+	GetInbox(dentistId int, status string) ([]domain.Referral, error)
+	GetByAppointment(appointmentId int) ([]domain.Referral, error)
+	Create(referral domain.Referral) (domain.Referral, error)
+	Update(referral domain.Referral) error
+}
@@ -0,0 +1,174 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+)
+
+type treatmentSqlStore struct {
+	DB *sql.DB
+}
+
+// NewTreatmentSqlStore crea un nuevo store de planes de tratamiento
+func NewTreatmentSqlStore(db *sql.DB) TreatmentStore {
+	return &treatmentSqlStore{db}
+}
+
+// GetByID devuelve un plan de tratamiento con sus procedimientos
+func (s *treatmentSqlStore) GetByID(id int) (domain.TreatmentPlan, error) {
+	var plan domain.TreatmentPlan
+	query := "SELECT treatment_plan.id, treatment_plan.patient_id, treatment_plan.status, treatment_plan.notes, treatment_plan.created_at, dentist.* FROM treatment_plan INNER JOIN dentist ON treatment_plan.dentist_id = dentist.id WHERE treatment_plan.id = ?;"
+	row := s.DB.QueryRow(query, id)
+	err := row.Scan(&plan.Id, &plan.PatientId, &plan.Status, &plan.Notes, &plan.CreatedAt, &plan.Dentist.Id, &plan.Dentist.Name, &plan.Dentist.LastName, &plan.Dentist.License)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	plan.Items, err = s.getItems("plan_id = ?", id)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	return plan, nil
+}
+
+// GetByPatient devuelve los planes de tratamiento de un paciente
+func (s *treatmentSqlStore) GetByPatient(patientId int) ([]domain.TreatmentPlan, error) {
+	var ids []int
+	rows, err := s.DB.Query("SELECT id FROM treatment_plan WHERE patient_id = ? ORDER BY created_at DESC", patientId)
+	if err != nil {
+		return []domain.TreatmentPlan{}, err
+	}
+	defer rows.Close()
+	for rows.Next() {
+		var id int
+		if err := rows.Scan(&id); err != nil {
+			return []domain.TreatmentPlan{}, err
+		}
+		ids = append(ids, id)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.TreatmentPlan{}, err
+	}
+	plans := []domain.TreatmentPlan{}
+	for _, id := range ids {
+		plan, err := s.GetByID(id)
+		if err != nil {
+			return []domain.TreatmentPlan{}, err
+		}
+		plans = append(plans, plan)
+	}
+	return plans, nil
+}
+
+// Create agrega un plan de tratamiento y sus procedimientos en una transaccion
+func (s *treatmentSqlStore) Create(plan domain.TreatmentPlan) (domain.TreatmentPlan, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT INTO treatment_plan (patient_id, dentist_id, status, notes) VALUES (?, ?, ?, ?);",
+		plan.PatientId, plan.Dentist.Id, plan.Status, plan.Notes)
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	plan.Id = int(insertedId)
+	for i := range plan.Items {
+		plan.Items[i].PlanId = plan.Id
+		result, err := tx.Exec("INSERT INTO treatment_item (plan_id, position, tooth, surfaces, procedure_code, description, estimated_cost, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?);",
+			plan.Id, plan.Items[i].Position, plan.Items[i].Tooth, plan.Items[i].Surfaces, nullString(plan.Items[i].ProcedureCode), plan.Items[i].Description, plan.Items[i].EstimatedCost, plan.Items[i].Status)
+		if err != nil {
+			return domain.TreatmentPlan{}, err
+		}
+		itemId, _ := result.LastInsertId()
+		plan.Items[i].Id = int(itemId)
+	}
+	err = tx.Commit()
+	if err != nil {
+		return domain.TreatmentPlan{}, err
+	}
+	return plan, nil
+}
+
+// UpdateStatus actualiza el estado de un plan
+func (s *treatmentSqlStore) UpdateStatus(id int, status string) error {
+	stmt := "UPDATE treatment_plan SET status = ? WHERE id = ?"
+	_, err := s.DB.Exec(stmt, status, id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// GetItemByID devuelve un procedimiento por su id
+func (s *treatmentSqlStore) GetItemByID(id int) (domain.TreatmentItem, error) {
+	items, err := s.getItems("id = ?", id)
+	if err != nil {
+		return domain.TreatmentItem{}, err
+	}
+	if len(items) == 0 {
+		return domain.TreatmentItem{}, sql.ErrNoRows
+	}
+	return items[0], nil
+}
+
+// GetItemsByAppointment devuelve los procedimientos agendados en un turno
+func (s *treatmentSqlStore) GetItemsByAppointment(appointmentId int) ([]domain.TreatmentItem, error) {
+	return s.getItems("appointment_id = ?", appointmentId)
+}
+
+// CreateItem agrega un procedimiento a un plan
+func (s *treatmentSqlStore) CreateItem(item domain.TreatmentItem) (domain.TreatmentItem, error) {
+	stmt, err := s.DB.Prepare("INSERT INTO treatment_item (plan_id, position, tooth, surfaces, procedure_code, description, estimated_cost, status) VALUES (?, ?, ?, ?, ?, ?, ?, ?);")
+	if err != nil {
+		return domain.TreatmentItem{}, err
+	}
+	defer stmt.Close()
+	result, err := stmt.Exec(item.PlanId, item.Position, item.Tooth, item.Surfaces, nullString(item.ProcedureCode), item.Description, item.EstimatedCost, item.Status)
+	if err != nil {
+		return domain.TreatmentItem{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	item.Id = int(insertedId)
+	return item, nil
+}
+
+// UpdateItem actualiza el estado y el turno de un procedimiento
+func (s *treatmentSqlStore) UpdateItem(item domain.TreatmentItem) error {
+	var appointmentId sql.NullInt64
+	if item.AppointmentId != 0 {
+		appointmentId = sql.NullInt64{Int64: int64(item.AppointmentId), Valid: true}
+	}
+	stmt := "UPDATE treatment_item SET status = ?, appointment_id = ? WHERE id = ?"
+	_, err := s.DB.Exec(stmt, item.Status, appointmentId, item.Id)
+	if err != nil {
+		return err
+	}
+	return nil
+}
+
+// getItems devuelve los procedimientos que cumplen una condicion ordenados por posicion
+func (s *treatmentSqlStore) getItems(condition string, args ...interface{}) ([]domain.TreatmentItem, error) {
+	items := []domain.TreatmentItem{}
+	query := "SELECT id, plan_id, position, tooth, surfaces, COALESCE(procedure_code, ''), description, estimated_cost, status, appointment_id FROM treatment_item WHERE " + condition + " ORDER BY position, id"
+	rows, err := s.DB.Query(query, args...)
+	if err != nil {
+		return []domain.TreatmentItem{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var item domain.TreatmentItem
+		var appointmentId sql.NullInt64
+		err := rows.Scan(&item.Id, &item.PlanId, &item.Position, &item.Tooth, &item.Surfaces, &item.ProcedureCode, &item.Description, &item.EstimatedCost, &item.Status, &appointmentId)
+		if err != nil {
+			return []domain.TreatmentItem{}, err
+		}
+		item.AppointmentId = int(appointmentId.Int64)
+		items = append(items, item)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.TreatmentItem{}, err
+	}
+	return items, nil
+}
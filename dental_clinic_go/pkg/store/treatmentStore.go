@@ -0,0 +1,14 @@
+package store
+
+import "dental_clinic_go/internal/domain"
+
+type TreatmentStore interface {
+	GetByID(id int) (domain.TreatmentPlan, error)
+	GetByPatient(patientId int) ([]domain.TreatmentPlan, error)
+	Create(plan domain.TreatmentPlan) (domain.TreatmentPlan, error)
+	UpdateStatus(id int, status string) error
+	GetItemByID(id int) (domain.TreatmentItem, error)
+	GetItemsByAppointment(appointmentId int) ([]domain.TreatmentItem, error)
+	CreateItem(item domain.TreatmentItem) (domain.TreatmentItem, error)
+	UpdateItem(item domain.TreatmentItem) error
+}
@@ -0,0 +1,207 @@
+package store
+
+import (
+	"database/sql"
+	"dental_clinic_go/internal/domain"
+	"errors"
+	"time"
+)
+
+type userSqlStore struct {
+	DB *sql.DB
+}
+
+// NewUserSqlStore crea un nuevo store de usuarios y sus sesiones
+func NewUserSqlStore(db *sql.DB) UserStore {
+	return &userSqlStore{db}
+}
+
+// GetAll devuelve todos los usuarios, activos o no
+func (s *userSqlStore) GetAll() ([]domain.User, error) {
+	users := []domain.User{}
+
+	query := "SELECT id, username, name, last_name, email, role, COALESCE(dentist_id, 0), active, created_at FROM app_user ORDER BY username"
+	rows, err := s.DB.Query(query)
+	if err != nil {
+		return []domain.User{}, err
+	}
+	defer rows.Close()
+
+	for rows.Next() {
+		var u domain.User
+		err := rows.Scan(&u.Id, &u.Username, &u.Name, &u.LastName, &u.Email, &u.Role, &u.DentistId, &u.Active, &u.CreatedAt)
+		if err != nil {
+			return []domain.User{}, err
+		}
+		users = append(users, u)
+	}
+	if err = rows.Err(); err != nil {
+		return []domain.User{}, err
+	}
+	return users, nil
+}
+
+// GetByID devuelve un usuario por su id
+func (s *userSqlStore) GetByID(id int) (domain.User, error) {
+	var u domain.User
+	query := "SELECT id, username, name, last_name, email, role, COALESCE(dentist_id, 0), active, created_at FROM app_user WHERE id = ?"
+	err := s.DB.QueryRow(query, id).Scan(&u.Id, &u.Username, &u.Name, &u.LastName, &u.Email, &u.Role, &u.DentistId, &u.Active, &u.CreatedAt)
+	if err != nil {
+		return domain.User{}, err
+	}
+	return u, nil
+}
+
+// GetByUsername devuelve un usuario y el hash de su contraseña para validar el login
+func (s *userSqlStore) GetByUsername(username string) (domain.User, string, error) {
+	var u domain.User
+	var passwordHash string
+	query := "SELECT id, username, name, last_name, email, role, COALESCE(dentist_id, 0), active, created_at, password_hash FROM app_user WHERE username = ?"
+	err := s.DB.QueryRow(query, username).Scan(&u.Id, &u.Username, &u.Name, &u.LastName, &u.Email, &u.Role, &u.DentistId, &u.Active, &u.CreatedAt, &passwordHash)
+	if err != nil {
+		return domain.User{}, "", err
+	}
+	return u, passwordHash, nil
+}
+
+// Count devuelve la cantidad de usuarios
+func (s *userSqlStore) Count() (int, error) {
+	var count int
+	err := s.DB.QueryRow("SELECT COUNT(*) FROM app_user").Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// CountActive devuelve la cantidad de usuarios activos de un rol
+func (s *userSqlStore) CountActive(role string) (int, error) {
+	var count int
+	err := s.DB.QueryRow("SELECT COUNT(*) FROM app_user WHERE role = ? AND active = TRUE", role).Scan(&count)
+	if err != nil {
+		return 0, err
+	}
+	return count, nil
+}
+
+// GetByDentist devuelve el usuario vinculado a un dentista
+func (s *userSqlStore) GetByDentist(dentistId int) (domain.User, error) {
+	var u domain.User
+	query := "SELECT id, username, name, last_name, email, role, COALESCE(dentist_id, 0), active, created_at FROM app_user WHERE dentist_id = ?"
+	err := s.DB.QueryRow(query, dentistId).Scan(&u.Id, &u.Username, &u.Name, &u.LastName, &u.Email, &u.Role, &u.DentistId, &u.Active, &u.CreatedAt)
+	if err != nil {
+		return domain.User{}, err
+	}
+	return u, nil
+}
+
+// Create agrega un usuario con el hash de su contraseña
+func (s *userSqlStore) Create(user domain.User, passwordHash string) (domain.User, error) {
+	result, err := s.DB.Exec("INSERT INTO app_user (username, name, last_name, email, role, dentist_id, password_hash, active) VALUES (?, ?, ?, ?, ?, ?, ?, ?);",
+		user.Username, user.Name, user.LastName, user.Email, user.Role, nullInt(user.DentistId), passwordHash, user.Active)
+	if err != nil {
+		return domain.User{}, err
+	}
+	insertedId, _ := result.LastInsertId()
+	user.Id = int(insertedId)
+	return user, nil
+}
+
+// Update actualiza los datos de un usuario, la contraseña se cambia aparte
+func (s *userSqlStore) Update(user domain.User) error {
+	_, err := s.DB.Exec("UPDATE app_user SET name = ?, last_name = ?, email = ?, role = ?, dentist_id = ?, active = ? WHERE id = ?",
+		user.Name, user.LastName, user.Email, user.Role, nullInt(user.DentistId), user.Active, user.Id)
+	return err
+}
+
+// UpdatePassword reemplaza el hash de la contraseña de un usuario
+func (s *userSqlStore) UpdatePassword(id int, passwordHash string) error {
+	_, err := s.DB.Exec("UPDATE app_user SET password_hash = ? WHERE id = ?", passwordHash, id)
+	return err
+}
+
+// CreateSession abre una sesion y registra su primer refresh token
+func (s *userSqlStore) CreateSession(userId int, refreshHash string, expiresAt time.Time) (int, error) {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return 0, err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("INSERT INTO user_session (user_id, refresh_hash, expires_at) VALUES (?, ?, ?);", userId, refreshHash, expiresAt)
+	if err != nil {
+		return 0, err
+	}
+	insertedId, _ := result.LastInsertId()
+	_, err = tx.Exec("INSERT INTO user_session_token (session_id, refresh_hash) VALUES (?, ?);", insertedId, refreshHash)
+	if err != nil {
+		return 0, err
+	}
+	err = tx.Commit()
+	if err != nil {
+		return 0, err
+	}
+	return int(insertedId), nil
+}
+
+// GetSession busca la sesion vigente de un refresh token, sea el actual o cualquiera de los que ya se rotaron
+func (s *userSqlStore) GetSession(refreshHash string, now time.Time) (domain.UserSession, error) {
+	var session domain.UserSession
+	query := `SELECT s.id, s.user_id, s.refresh_hash <> t.refresh_hash, s.revoked_at IS NOT NULL FROM user_session_token t
+		JOIN user_session s ON s.id = t.session_id WHERE t.refresh_hash = ? AND s.expires_at > ?`
+	err := s.DB.QueryRow(query, refreshHash, now).Scan(&session.Id, &session.UserId, &session.Rotated, &session.Revoked)
+	if err != nil {
+		return domain.UserSession{}, err
+	}
+	return session, nil
+}
+
+// GetActiveSession busca una sesion por su id si no vencio ni fue revocada y su usuario sigue activo,
+// junto con el rol y el dentista actuales del usuario
+func (s *userSqlStore) GetActiveSession(id int, now time.Time) (domain.UserSession, error) {
+	var session domain.UserSession
+	query := `SELECT s.id, s.user_id, u.role, COALESCE(u.dentist_id, 0) FROM user_session s JOIN app_user u ON u.id = s.user_id
+		WHERE s.id = ? AND s.revoked_at IS NULL AND s.expires_at > ? AND u.active = TRUE`
+	err := s.DB.QueryRow(query, id, now).Scan(&session.Id, &session.UserId, &session.Role, &session.DentistId)
+	if err != nil {
+		return domain.UserSession{}, err
+	}
+	return session, nil
+}
+
+// RotateSession reemplaza el refresh token de una sesion y lo registra, falla si otro pedido ya lo roto
+func (s *userSqlStore) RotateSession(id int, previousHash string, refreshHash string, expiresAt time.Time) error {
+	tx, err := s.DB.Begin()
+	if err != nil {
+		return err
+	}
+	defer tx.Rollback()
+	result, err := tx.Exec("UPDATE user_session SET refresh_hash = ?, expires_at = ? WHERE id = ? AND refresh_hash = ? AND revoked_at IS NULL",
+		refreshHash, expiresAt, id, previousHash)
+	if err != nil {
+		return err
+	}
+	affected, err := result.RowsAffected()
+	if err != nil {
+		return err
+	}
+	if affected == 0 {
+		return errors.New("session already rotated")
+	}
+	_, err = tx.Exec("INSERT INTO user_session_token (session_id, refresh_hash) VALUES (?, ?);", id, refreshHash)
+	if err != nil {
+		return err
+	}
+	return tx.Commit()
+}
+
+// RevokeSession cierra una sesion
+func (s *userSqlStore) RevokeSession(id int) error {
+	_, err := s.DB.Exec("UPDATE user_session SET revoked_at = NOW() WHERE id = ? AND revoked_at IS NULL", id)
+	return err
+}
+
+// RevokeSessions cierra las sesiones de un usuario salvo exceptId, 0 las cierra todas
+func (s *userSqlStore) RevokeSessions(userId int, exceptId int) error {
+	_, err := s.DB.Exec("UPDATE user_session SET revoked_at = NOW() WHERE user_id = ? AND id <> ? AND revoked_at IS NULL", userId, exceptId)
+	return err
+}
@@ -0,0 +1,24 @@
+package store
+
+import (
+	"dental_clinic_go/internal/domain"
+	"time"
+)
+
+type UserStore interface {
+	GetAll() ([]domain.User, error)
+	GetByID(id int) (domain.User, error)
+	GetByUsername(username string) (domain.User, string, error)
+	GetByDentist(dentistId int) (domain.User, error)
+	Count() (int, error)
+	CountActive(role string) (int, error)
+	Create(user domain.User, passwordHash string) (domain.User, error)
+	Update(user domain.User) error
+	UpdatePassword(id int, passwordHash string) error
+	CreateSession(userId int, refreshHash string, expiresAt time.Time) (int, error)
+	GetSession(refreshHash string, now time.Time) (domain.UserSession, error)
+	GetActiveSession(id int, now time.Time) (domain.UserSession, error)
+	RotateSession(id int, previousHash string, refreshHash string, expiresAt time.Time) error
+	RevokeSession(id int) error
+	RevokeSessions(userId int, exceptId int) error
+}
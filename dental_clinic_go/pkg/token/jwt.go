@@ -0,0 +1,86 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// jwtHeader es el encabezado fijo de los JWT que emite la clinica, solo se aceptan tokens HS256
+var jwtHeader = base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"HS256","typ":"JWT"}`))
+
+// Claims son los datos de un JWT de acceso
+type Claims struct {
+	// Subject es el id del usuario y SessionId la sesion que abrio al hacer login
+	Subject   string `json:"sub"`
+	SessionId int    `json:"sid"`
+	Username  string `json:"username"`
+	// Role es el rol del usuario al emitir el token, los permisos se validan con el rol vigente
+	Role string `json:"role"`
+	// DentistId es el dentista vinculado al usuario, se completa con el vigente al validar el token
+	DentistId int   `json:"did,omitempty"`
+	IssuedAt  int64 `json:"iat"`
+	ExpiresAt int64 `json:"exp"`
+}
+
+// UserId devuelve el id del usuario del token, 0 si el subject no es un id
+func (c Claims) UserId() int {
+	id, _ := strconv.Atoi(c.Subject)
+	return id
+}
+
+type JWT struct {
+	secret []byte
+}
+
+// NewJWT crea un emisor de JWT firmados con HMAC-SHA256
+func NewJWT(secret string) *JWT {
+	return &JWT{[]byte(secret)}
+}
+
+// Issue firma un JWT con los claims, que deben traer su fecha de vencimiento
+func (j *JWT) Issue(claims Claims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	body := jwtHeader + "." + base64.RawURLEncoding.EncodeToString(payload)
+	return body + "." + base64.RawURLEncoding.EncodeToString(j.mac(body)), nil
+}
+
+// Parse valida la firma y el vencimiento de un JWT y devuelve sus claims
+func (j *JWT) Parse(token string, now time.Time) (Claims, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 || parts[0] != jwtHeader {
+		return Claims{}, errors.New("invalid token")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil || !hmac.Equal(signature, j.mac(parts[0]+"."+parts[1])) {
+		return Claims{}, errors.New("invalid token")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil {
+		return Claims{}, errors.New("invalid token")
+	}
+	var claims Claims
+	err = json.Unmarshal(payload, &claims)
+	if err != nil {
+		return Claims{}, errors.New("invalid token")
+	}
+	if now.Unix() >= claims.ExpiresAt {
+		return Claims{}, errors.New("expired token")
+	}
+	return claims, nil
+}
+
+// mac calcula el HMAC-SHA256 de un valor
+func (j *JWT) mac(value string) []byte {
+	m := hmac.New(sha256.New, j.secret)
+	m.Write([]byte(value))
+	return m.Sum(nil)
+}
@@ -0,0 +1,76 @@
+package token
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestJWTParse(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	jwt := NewJWT("secret")
+	claims := Claims{Subject: "7", SessionId: 3, Username: "ana", Role: "admin", IssuedAt: now.Unix(), ExpiresAt: now.Add(15 * time.Minute).Unix()}
+	valid, err := jwt.Issue(claims)
+	if err != nil {
+		t.Fatalf("issuing token: %s", err)
+	}
+	parts := strings.Split(valid, ".")
+	tampered := parts[0] + "." + base64.RawURLEncoding.EncodeToString([]byte(`{"sub":"1","sid":3,"role":"admin","exp":9999999999}`)) + "." + parts[2]
+	otherKey, _ := NewJWT("other").Issue(claims)
+	noneAlg := base64.RawURLEncoding.EncodeToString([]byte(`{"alg":"none","typ":"JWT"}`)) + "." + parts[1] + "."
+
+	tests := []struct {
+		name  string
+		token string
+		now   time.Time
+		err   string
+	}{
+		{"valid", valid, now, ""},
+		{"valid until the last second", valid, now.Add(15*time.Minute - time.Second), ""},
+		{"expired at exp", valid, now.Add(15 * time.Minute), "expired token"},
+		{"expired after exp", valid, now.Add(time.Hour), "expired token"},
+		{"signed with another secret", otherKey, now, "invalid token"},
+		{"tampered payload", tampered, now, "invalid token"},
+		{"bad signature encoding", parts[0] + "." + parts[1] + ".%%%", now, "invalid token"},
+		{"alg none", noneAlg, now, "invalid token"},
+		{"missing parts", parts[0] + "." + parts[1], now, "invalid token"},
+		{"empty", "", now, "invalid token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := jwt.Parse(tt.token, tt.now)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if got != claims {
+				t.Fatalf("expected claims %+v, got %+v", claims, got)
+			}
+			if got.UserId() != 7 {
+				t.Fatalf("expected user id 7, got %d", got.UserId())
+			}
+		})
+	}
+}
+
+func TestClaimsUserId(t *testing.T) {
+	tests := []struct {
+		subject string
+		want    int
+	}{
+		{"42", 42},
+		{"", 0},
+		{"ana", 0},
+	}
+	for _, tt := range tests {
+		if got := (Claims{Subject: tt.subject}).UserId(); got != tt.want {
+			t.Errorf("subject %q: expected %d, got %d", tt.subject, tt.want, got)
+		}
+	}
+}
@@ -0,0 +1,61 @@
+package token
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"errors"
+	"strconv"
+	"strings"
+	"time"
+)
+
+type Signer struct {
+	secret []byte
+}
+
+// NewSigner crea un firmador de tokens HMAC-SHA256
+func NewSigner(secret string) *Signer {
+	return &Signer{[]byte(secret)}
+}
+
+// Sign firma un payload con su fecha de vencimiento
+func (s *Signer) Sign(payload string, expiresAt time.Time) string {
+	body := base64.RawURLEncoding.EncodeToString([]byte(payload + "|" + strconv.FormatInt(expiresAt.Unix(), 10)))
+	return body + "." + base64.RawURLEncoding.EncodeToString(s.mac(body))
+}
+
+// Verify valida la firma y el vencimiento de un token y devuelve su payload
+func (s *Signer) Verify(token string, now time.Time) (string, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 2 {
+		return "", errors.New("invalid token")
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[1])
+	if err != nil || !hmac.Equal(signature, s.mac(parts[0])) {
+		return "", errors.New("invalid token")
+	}
+	body, err := base64.RawURLEncoding.DecodeString(parts[0])
+	if err != nil {
+		return "", errors.New("invalid token")
+	}
+	separator := strings.LastIndex(string(body), "|")
+	if separator < 0 {
+		return "", errors.New("invalid token")
+	}
+	expiresAt, err := strconv.ParseInt(string(body[separator+1:]), 10, 64)
+	if err != nil {
+		return "", errors.New("invalid token")
+	}
+	if now.Unix() >= expiresAt {
+		return "", errors.New("expired token")
+	}
+	return string(body[:separator]), nil
+}
+
+// mac calcula el HMAC-SHA256 de un valor
+func (s *Signer) mac(value string) []byte {
+	m := hmac.New(sha256.New, s.secret)
+	m.Write([]byte(value))
+	return m.Sum(nil)
+}
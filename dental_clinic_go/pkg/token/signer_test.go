@@ -0,0 +1,59 @@
+package token
+
+import (
+	"encoding/base64"
+	"strings"
+	"testing"
+	"time"
+)
+
+func TestSignerVerify(t *testing.T) {
+	now := time.Date(2026, 10, 19, 12, 0, 0, 0, time.UTC)
+	signer := NewSigner("secret")
+	valid := signer.Sign("7:confirm", now.Add(time.Hour))
+	parts := strings.Split(valid, ".")
+	tampered := base64.RawURLEncoding.EncodeToString([]byte("8:confirm|"+strings.Split(string(mustDecode(t, parts[0])), "|")[1])) + "." + parts[1]
+	extended := base64.RawURLEncoding.EncodeToString([]byte("7:confirm|9999999999")) + "." + parts[1]
+
+	tests := []struct {
+		name    string
+		token   string
+		now     time.Time
+		payload string
+		err     string
+	}{
+		{"valid", valid, now, "7:confirm", ""},
+		{"expired", valid, now.Add(time.Hour), "", "expired token"},
+		{"signed with another secret", NewSigner("other").Sign("7:confirm", now.Add(time.Hour)), now, "", "invalid token"},
+		{"other appointment", tampered, now, "", "invalid token"},
+		{"extended expiration", extended, now, "", "invalid token"},
+		{"missing signature", parts[0], now, "", "invalid token"},
+		{"bad signature encoding", parts[0] + ".%%%", now, "", "invalid token"},
+		{"empty", "", now, "", "invalid token"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			payload, err := signer.Verify(tt.token, tt.now)
+			if tt.err != "" {
+				if err == nil || err.Error() != tt.err {
+					t.Fatalf("expected error %q, got %v", tt.err, err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %s", err)
+			}
+			if payload != tt.payload {
+				t.Fatalf("expected payload %q, got %q", tt.payload, payload)
+			}
+		})
+	}
+}
+
+func mustDecode(t *testing.T, value string) []byte {
+	decoded, err := base64.RawURLEncoding.DecodeString(value)
+	if err != nil {
+		t.Fatalf("decoding %s: %s", value, err)
+	}
+	return decoded
+}
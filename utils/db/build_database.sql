@@ -23,44 +23,766 @@ INSERT INTO dentist (name, last_name, license) VALUES
   ("Luis", "Díaz", "86421"),
   ("Marta", "Sánchez", "75310");
 
+CREATE TABLE IF NOT EXISTS specialty (
+  code VARCHAR(20) NOT NULL,
+  name VARCHAR(50) NOT NULL,
+  PRIMARY KEY (code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO specialty (code, name) VALUES
+  ("orthodontics", "Ortodoncia"),
+  ("endodontics", "Endodoncia"),
+  ("periodontics", "Periodoncia"),
+  ("oral_surgery", "Cirugía bucal"),
+  ("pediatric", "Odontopediatría");
+
+CREATE TABLE IF NOT EXISTS dentist_specialty (
+  dentist_id INT(11) NOT NULL,
+  specialty VARCHAR(20) NOT NULL,
+  PRIMARY KEY (dentist_id, specialty),
+  KEY (specialty),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id) ON DELETE CASCADE,
+  FOREIGN KEY (specialty) REFERENCES specialty(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO dentist_specialty (dentist_id, specialty) VALUES
+  (2, "periodontics"),
+  (3, "endodontics"),
+  (5, "orthodontics"),
+  (6, "pediatric"),
+  (7, "oral_surgery"),
+  (9, "oral_surgery");
+
 CREATE TABLE IF NOT EXISTS patient (
   id INT(11) NOT NULL AUTO_INCREMENT,
   name VARCHAR(50) NOT NULL,
   last_name VARCHAR(50) NOT NULL,
-  domicilio VARCHAR(50) NOT NULL,
+  address_street VARCHAR(100) NOT NULL DEFAULT '',
+  address_number VARCHAR(20) NOT NULL DEFAULT '',
+  address_city VARCHAR(50) NOT NULL DEFAULT '',
+  address_province VARCHAR(50) NOT NULL DEFAULT '',
+  address_postal_code VARCHAR(10) NOT NULL DEFAULT '',
   dni INT(11) NOT NULL,
+  birth_date DATE NULL,
   email VARCHAR(50) NOT NULL,
+  preferred_language VARCHAR(10) NOT NULL DEFAULT 'es',
+  contact_channel VARCHAR(20) NOT NULL DEFAULT 'email',
+  reminders BOOLEAN NOT NULL DEFAULT TRUE,
   admission_date DATE NOT NULL,
   PRIMARY KEY (id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
-INSERT INTO patient (name, last_name, domicilio, dni, email, admission_date) VALUES
-  ("Ana", "García", "Calle Falsa 123", 12345678, "ana.garcia@gmail.com", "2022-01-15"),
-  ("Pedro", "Martínez", "Avenida Siempreviva 456", 23456789, "pedro.martinez@yahoo.com", "2022-02-01"),
-  ("Sofía", "López", "Calle Falsa 456", 34567890, "sofia.lopez@hotmail.com", "2022-03-10"),
-  ("Carlos", "González", "Calle Real 789", 45678901, "carlos.gonzalez@gmail.com", "2022-03-15"),
-  ("Laura", "Fernández", "Calle Mayor 1011", 56789012, "laura.fernandez@yahoo.com", "2022-04-05"),
-  ("Pablo", "Sánchez", "Avenida del Sol 1213", 67890123, "pablo.sanchez@hotmail.com", "2022-04-10"),
-  ("Lucía", "Romero", "Calle del Prado 1415", 78901234, "lucia.romero@gmail.com", "2022-05-01"),
-  ("Miguel", "Gómez", "Calle Nueva 1617", 89012345, "miguel.gomez@yahoo.com", "2022-05-15"),
-  ("Elena", "Hernández", "Avenida de la Libertad 1819", 90123456, "elena.hernandez@hotmail.com", "2022-06-01"),
-  ("María", "Jiménez", "Calle Mayor 2021", 12345679, "maria.jimenez@gmail.com", "2022-06-15");
+INSERT INTO patient (name, last_name, address_street, address_number, dni, email, admission_date) VALUES
+  ("Ana", "García", "Calle Falsa", "123", 12345678, "ana.garcia@gmail.com", "2022-01-15"),
+  ("Pedro", "Martínez", "Avenida Siempreviva", "456", 23456789, "pedro.martinez@yahoo.com", "2022-02-01"),
+  ("Sofía", "López", "Calle Falsa", "456", 34567890, "sofia.lopez@hotmail.com", "2022-03-10"),
+  ("Carlos", "González", "Calle Real", "789", 45678901, "carlos.gonzalez@gmail.com", "2022-03-15"),
+  ("Laura", "Fernández", "Calle Mayor", "1011", 56789012, "laura.fernandez@yahoo.com", "2022-04-05"),
+  ("Pablo", "Sánchez", "Avenida del Sol", "1213", 67890123, "pablo.sanchez@hotmail.com", "2022-04-10"),
+  ("Lucía", "Romero", "Calle del Prado", "1415", 78901234, "lucia.romero@gmail.com", "2022-05-01"),
+  ("Miguel", "Gómez", "Calle Nueva", "1617", 89012345, "miguel.gomez@yahoo.com", "2022-05-15"),
+  ("Elena", "Hernández", "Avenida de la Libertad", "1819", 90123456, "elena.hernandez@hotmail.com", "2022-06-01"),
+  ("María", "Jiménez", "Calle Mayor", "2021", 12345679, "maria.jimenez@gmail.com", "2022-06-15");
+
+CREATE TABLE IF NOT EXISTS patient_phone (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  phone_type VARCHAR(20) NOT NULL,
+  number VARCHAR(30) NOT NULL,
+  is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS dental_procedure (
+  code VARCHAR(20) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  category VARCHAR(50) NOT NULL,
+  default_fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+  default_duration INT(11) NOT NULL DEFAULT 30,
+  teeth VARCHAR(100) NOT NULL DEFAULT '',
+  surfaces VARCHAR(5) NOT NULL DEFAULT '',
+  specialty VARCHAR(20) NULL,
+  PRIMARY KEY (code),
+  FOREIGN KEY (specialty) REFERENCES specialty(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO dental_procedure (code, name, category, default_fee, default_duration, teeth, surfaces, specialty) VALUES
+  ("01.01", "Consulta y diagnóstico", "diagnostico", 5000.00, 30, "", "", NULL),
+  ("01.04", "Radiografía periapical", "diagnostico", 3000.00, 15, "permanent", "", NULL),
+  ("02.01", "Obturación con amalgama", "operatoria", 9000.00, 45, "", "MODBL", NULL),
+  ("02.08", "Obturación con resina compuesta", "operatoria", 12000.00, 45, "", "MODBL", NULL),
+  ("03.01", "Tratamiento de conducto unirradicular", "endodoncia", 30000.00, 60, "", "", "endodontics"),
+  ("03.02", "Tratamiento de conducto multirradicular", "endodoncia", 45000.00, 90, "", "", "endodontics"),
+  ("04.01", "Corona de porcelana", "protesis", 80000.00, 60, "", "", NULL),
+  ("05.01", "Limpieza dental de rutina", "prevencion", 8000.00, 30, "", "", NULL),
+  ("05.04", "Aplicación de flúor", "prevencion", 4000.00, 15, "", "", NULL),
+  ("06.01", "Raspaje y alisado radicular", "periodoncia", 15000.00, 45, "", "", "periodontics"),
+  ("07.01", "Extracción simple", "cirugia", 12000.00, 30, "", "", NULL),
+  ("07.05", "Extracción de muela del juicio", "cirugia", 35000.00, 60, "18,28,38,48", "", "oral_surgery"),
+  ("08.01", "Ortodoncia", "ortodoncia", 60000.00, 45, "", "", "orthodontics"),
+  ("09.01", "Implante dental", "implantes", 250000.00, 90, "permanent", "", "oral_surgery");
 
 CREATE TABLE IF NOT EXISTS appointment (
   id INT(11) NOT NULL AUTO_INCREMENT,
   date DATE NOT NULL,
   hour TIME NOT NULL,
   description TEXT NOT NULL,
+  procedure_code VARCHAR(20) NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'scheduled',
   patient_id INT(11) NOT NULL,
   dentist_id INT(11) NOT NULL,
+  slot_taken TINYINT(1) AS (IF(status = 'cancelled', NULL, 1)) STORED,
   PRIMARY KEY (id),
+  UNIQUE KEY (dentist_id, date, hour, slot_taken),
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code),
   FOREIGN KEY (patient_id) REFERENCES patient(id),
   FOREIGN KEY (dentist_id) REFERENCES dentist(id)
 ) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
 
-INSERT INTO appointment (date, hour, description, patient_id, dentist_id) VALUES
-  ('2023-04-12', '10:00:00', 'Limpieza dental de rutina', 1, 1),
-  ('2023-04-13', '15:30:00', 'Revisión y tratamiento de caries', 2, 3),
-  ('2023-04-15', '11:00:00', 'Ortodoncia', 4, 5),
-  ('2023-04-16', '16:45:00', 'Extracción de muela del juicio', 6, 7),
-  ('2023-04-17', '14:15:00', 'Implante dental', 8, 9);
+INSERT INTO appointment (date, hour, description, procedure_code, patient_id, dentist_id) VALUES
+  ('2023-04-12', '10:00:00', 'Limpieza dental de rutina', '05.01', 1, 1),
+  ('2023-04-13', '15:30:00', 'Revisión y tratamiento de caries', '02.08', 2, 3),
+  ('2023-04-15', '11:00:00', 'Ortodoncia', '08.01', 4, 5),
+  ('2023-04-16', '16:45:00', 'Extracción de muela del juicio', '07.05', 6, 7),
+  ('2023-04-17', '14:15:00', 'Implante dental', '09.01', 8, 9);
+
+CREATE TABLE IF NOT EXISTS portal_code (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  code_hash CHAR(64) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  used TINYINT(1) NOT NULL DEFAULT 0,
+  attempts INT(11) NOT NULL DEFAULT 0,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS portal_session (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY (token_hash),
+  FOREIGN KEY (patient_id) REFERENCES patient(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS policy_event (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  appointment_id INT(11) NOT NULL,
+  type VARCHAR(20) NOT NULL,
+  fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY (appointment_id, type),
+  FOREIGN KEY (patient_id) REFERENCES patient(id) ON DELETE CASCADE,
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS chart_entry (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  tooth TINYINT NOT NULL,
+  surfaces VARCHAR(5) NOT NULL DEFAULT '',
+  tooth_condition VARCHAR(20) NOT NULL,
+  procedure_code VARCHAR(20) NULL,
+  date DATE NOT NULL,
+  notes TEXT NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (patient_id, tooth),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id),
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS treatment_plan (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'proposed',
+  notes TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS treatment_item (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  plan_id INT(11) NOT NULL,
+  position INT(11) NOT NULL,
+  tooth TINYINT NOT NULL DEFAULT 0,
+  surfaces VARCHAR(5) NOT NULL DEFAULT '',
+  procedure_code VARCHAR(20) NULL,
+  description TEXT NOT NULL,
+  estimated_cost DECIMAL(10,2) NOT NULL DEFAULT 0,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  appointment_id INT(11) NULL,
+  PRIMARY KEY (id),
+  FOREIGN KEY (plan_id) REFERENCES treatment_plan(id) ON DELETE CASCADE,
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS clinical_note (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  appointment_id INT(11) NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  subjective TEXT NOT NULL,
+  objective TEXT NOT NULL,
+  assessment TEXT NOT NULL,
+  plan TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'draft',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  signed_at DATETIME NULL,
+  PRIMARY KEY (id),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id) ON DELETE RESTRICT,
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS clinical_addendum (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  note_id INT(11) NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  text TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (note_id) REFERENCES clinical_note(id) ON DELETE RESTRICT,
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS allergy (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  substance VARCHAR(100) NOT NULL,
+  severity VARCHAR(20) NOT NULL,
+  reaction VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS medication (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  dose VARCHAR(50) NOT NULL DEFAULT '',
+  frequency VARCHAR(50) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS medical_condition (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  notes TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS medical_review (
+  patient_id INT(11) NOT NULL,
+  reviewed_at DATE NOT NULL,
+  PRIMARY KEY (patient_id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS prescription (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  appointment_id INT(11) NULL,
+  dentist_id INT(11) NOT NULL,
+  date DATE NOT NULL,
+  notes TEXT NOT NULL,
+  allergy_override BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS prescription_item (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  prescription_id INT(11) NOT NULL,
+  position INT(11) NOT NULL,
+  drug VARCHAR(100) NOT NULL,
+  dose VARCHAR(50) NOT NULL,
+  frequency VARCHAR(50) NOT NULL,
+  duration VARCHAR(50) NOT NULL DEFAULT '',
+  notes TEXT NOT NULL,
+  PRIMARY KEY (id),
+  FOREIGN KEY (prescription_id) REFERENCES prescription(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS attachment (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  appointment_id INT(11) NULL,
+  file_name VARCHAR(255) NOT NULL,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  content_type VARCHAR(100) NOT NULL,
+  size BIGINT NOT NULL,
+  checksum CHAR(64) NOT NULL,
+  storage_key VARCHAR(255) NOT NULL,
+  has_thumbnail BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (checksum),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS consent_template (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  name VARCHAR(150) NOT NULL,
+  body TEXT NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS consent_template_procedure (
+  template_id INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NOT NULL,
+  PRIMARY KEY (template_id, procedure_code),
+  FOREIGN KEY (template_id) REFERENCES consent_template(id),
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS consent (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  template_id INT(11) NOT NULL,
+  patient_id INT(11) NOT NULL,
+  appointment_id INT(11) NULL,
+  treatment_plan_id INT(11) NULL,
+  body TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  signer_name VARCHAR(150) NOT NULL DEFAULT '',
+  signature_key VARCHAR(255) NOT NULL DEFAULT '',
+  signed_at DATETIME NULL,
+  signed_ip VARCHAR(45) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (patient_id, status),
+  FOREIGN KEY (template_id) REFERENCES consent_template(id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id),
+  FOREIGN KEY (treatment_plan_id) REFERENCES treatment_plan(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO consent_template (id, name, body) VALUES
+(1, "Consentimiento informado para cirugía oral", "Declaro haber sido informado/a sobre el procedimiento quirúrgico a realizar, sus riesgos (dolor, inflamación, sangrado, infección, parestesia), alternativas y cuidados posteriores, y autorizo al profesional a realizarlo.");
+
+INSERT INTO consent_template_procedure (template_id, procedure_code) VALUES
+(1, "07.01"), (1, "07.05"), (1, "09.01");
+
+CREATE TABLE IF NOT EXISTS perio_exam (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  date DATE NOT NULL,
+  notes TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (patient_id, date),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS perio_tooth (
+  exam_id INT(11) NOT NULL,
+  tooth INT(11) NOT NULL,
+  mobility TINYINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (exam_id, tooth),
+  FOREIGN KEY (exam_id) REFERENCES perio_exam(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS perio_site (
+  exam_id INT(11) NOT NULL,
+  tooth INT(11) NOT NULL,
+  site VARCHAR(2) NOT NULL,
+  depth TINYINT NOT NULL,
+  recession TINYINT NOT NULL DEFAULT 0,
+  bleeding BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (exam_id, tooth, site),
+  FOREIGN KEY (exam_id, tooth) REFERENCES perio_tooth(exam_id, tooth)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS lab_order (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  treatment_item_id INT(11) NOT NULL,
+  lab_name VARCHAR(100) NOT NULL,
+  description VARCHAR(255) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'sent',
+  sent_date DATE NOT NULL,
+  expected_date DATE NOT NULL,
+  received_date DATE NULL,
+  fitted_date DATE NULL,
+  notes TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (status, expected_date),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id),
+  FOREIGN KEY (treatment_item_id) REFERENCES treatment_item(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS patient_relation (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  related_id INT(11) NOT NULL,
+  relationship VARCHAR(20) NOT NULL,
+  responsible BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY (patient_id, related_id),
+  KEY (related_id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id) ON DELETE CASCADE,
+  FOREIGN KEY (related_id) REFERENCES patient(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS referral (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  from_dentist_id INT(11) NOT NULL,
+  to_dentist_id INT(11) NULL,
+  specialty VARCHAR(20) NULL,
+  reason TEXT NOT NULL,
+  urgency VARCHAR(20) NOT NULL DEFAULT 'routine',
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  response TEXT NOT NULL,
+  appointment_id INT(11) NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (to_dentist_id, status),
+  KEY (specialty, status),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (from_dentist_id) REFERENCES dentist(id),
+  FOREIGN KEY (to_dentist_id) REFERENCES dentist(id),
+  FOREIGN KEY (specialty) REFERENCES specialty(code),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS invoice_sequence (
+  series VARCHAR(10) NOT NULL,
+  last_number INT(11) NOT NULL DEFAULT 0,
+  PRIMARY KEY (series)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS invoice (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  series VARCHAR(10) NULL,
+  number INT(11) NULL,
+  patient_id INT(11) NOT NULL,
+  appointment_id INT(11) NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'draft',
+  issue_date DATE NULL,
+  bill_to_id INT(11) NOT NULL,
+  bill_to_name VARCHAR(50) NOT NULL,
+  bill_to_last_name VARCHAR(50) NOT NULL,
+  bill_to_email VARCHAR(50) NOT NULL,
+  subtotal DECIMAL(10,2) NOT NULL DEFAULT 0,
+  discount DECIMAL(10,2) NOT NULL DEFAULT 0,
+  tax DECIMAL(10,2) NOT NULL DEFAULT 0,
+  total DECIMAL(10,2) NOT NULL DEFAULT 0,
+  notes TEXT NOT NULL,
+  void_reason VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY (series, number),
+  KEY (status),
+  KEY (appointment_id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS invoice_item (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  invoice_id INT(11) NOT NULL,
+  position INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NULL,
+  description VARCHAR(255) NOT NULL,
+  tooth INT(11) NOT NULL DEFAULT 0,
+  quantity INT(11) NOT NULL DEFAULT 1,
+  unit_price DECIMAL(10,2) NOT NULL,
+  discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+  discount_fixed DECIMAL(10,2) NOT NULL DEFAULT 0,
+  tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0,
+  subtotal DECIMAL(10,2) NOT NULL,
+  discount DECIMAL(10,2) NOT NULL DEFAULT 0,
+  tax DECIMAL(10,2) NOT NULL DEFAULT 0,
+  total DECIMAL(10,2) NOT NULL,
+  PRIMARY KEY (id),
+  KEY (invoice_id, position),
+  FOREIGN KEY (invoice_id) REFERENCES invoice(id) ON DELETE CASCADE,
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS ledger_entry (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  entry_type VARCHAR(20) NOT NULL,
+  entry_date DATE NOT NULL,
+  debit DECIMAL(10,2) NOT NULL DEFAULT 0,
+  credit DECIMAL(10,2) NOT NULL DEFAULT 0,
+  method VARCHAR(20) NULL,
+  invoice_id INT(11) NULL,
+  reference VARCHAR(100) NOT NULL DEFAULT '',
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (patient_id, entry_date),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (invoice_id) REFERENCES invoice(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS payment_allocation (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  entry_id INT(11) NOT NULL,
+  invoice_id INT(11) NOT NULL,
+  amount DECIMAL(10,2) NOT NULL,
+  PRIMARY KEY (id),
+  KEY (invoice_id),
+  FOREIGN KEY (entry_id) REFERENCES ledger_entry(id) ON DELETE CASCADE,
+  FOREIGN KEY (invoice_id) REFERENCES invoice(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS insurer (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  code VARCHAR(20) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  insurer_type VARCHAR(20) NOT NULL,
+  claim_format VARCHAR(20) NOT NULL DEFAULT 'csv',
+  PRIMARY KEY (id),
+  UNIQUE KEY (code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS insurance_plan (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  insurer_id INT(11) NOT NULL,
+  code VARCHAR(20) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY (insurer_id, code),
+  FOREIGN KEY (insurer_id) REFERENCES insurer(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS coverage_rule (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  plan_id INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NOT NULL,
+  percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+  copay DECIMAL(10,2) NOT NULL DEFAULT 0,
+  annual_limit INT(11) NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  UNIQUE KEY (plan_id, procedure_code),
+  FOREIGN KEY (plan_id) REFERENCES insurance_plan(id) ON DELETE CASCADE,
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS patient_coverage (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  plan_id INT(11) NOT NULL,
+  member_number VARCHAR(30) NOT NULL,
+  valid_from DATE NOT NULL,
+  valid_to DATE NULL,
+  PRIMARY KEY (id),
+  KEY (patient_id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (plan_id) REFERENCES insurance_plan(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS claim_batch (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  insurer_id INT(11) NOT NULL,
+  date_from DATE NOT NULL,
+  date_to DATE NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'open',
+  total DECIMAL(10,2) NOT NULL DEFAULT 0,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (insurer_id, status),
+  FOREIGN KEY (insurer_id) REFERENCES insurer(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS claim (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  batch_id INT(11) NOT NULL,
+  appointment_id INT(11) NOT NULL,
+  coverage_id INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NOT NULL,
+  service_date DATE NOT NULL,
+  fee DECIMAL(10,2) NOT NULL,
+  copay DECIMAL(10,2) NOT NULL DEFAULT 0,
+  amount DECIMAL(10,2) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY (appointment_id),
+  KEY (batch_id),
+  FOREIGN KEY (batch_id) REFERENCES claim_batch(id) ON DELETE CASCADE,
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id),
+  FOREIGN KEY (coverage_id) REFERENCES patient_coverage(id),
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO insurer (id, code, name, insurer_type, claim_format) VALUES
+  (1, "OSDE", "OSDE", "prepaga", "csv"),
+  (2, "IOMA", "Instituto de Obra Médico Asistencial", "obra_social", "fixed_width");
+
+INSERT INTO insurance_plan (id, insurer_id, code, name) VALUES
+  (1, 1, "210", "OSDE 210"),
+  (2, 1, "310", "OSDE 310"),
+  (3, 2, "GRAL", "IOMA General");
+
+INSERT INTO coverage_rule (plan_id, procedure_code, percent, copay, annual_limit) VALUES
+  (1, "01.01", 100.00, 0.00, 2),
+  (1, "05.01", 100.00, 0.00, 2),
+  (1, "02.08", 70.00, 0.00, 0),
+  (1, "03.01", 50.00, 0.00, 0),
+  (2, "01.01", 100.00, 0.00, 0),
+  (2, "05.01", 100.00, 0.00, 2),
+  (2, "02.08", 100.00, 0.00, 0),
+  (2, "03.01", 80.00, 0.00, 0),
+  (2, "03.02", 80.00, 0.00, 0),
+  (3, "01.01", 100.00, 1500.00, 1),
+  (3, "05.01", 100.00, 1500.00, 1),
+  (3, "07.01", 100.00, 2000.00, 0);
+
+INSERT INTO patient_coverage (patient_id, plan_id, member_number, valid_from, valid_to) VALUES
+  (1, 1, "61234567801", "2022-01-01", NULL),
+  (2, 3, "IO-23456789/00", "2022-01-01", NULL),
+  (3, 2, "61345678902", "2022-03-01", "2023-12-31");
+
+CREATE TABLE IF NOT EXISTS price_list (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  name VARCHAR(100) NOT NULL,
+  insurer_id INT(11) NULL,
+  effective_from DATE NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (insurer_id, effective_from),
+  FOREIGN KEY (insurer_id) REFERENCES insurer(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS price (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  list_id INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NOT NULL,
+  amount DECIMAL(10,2) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY (list_id, procedure_code),
+  FOREIGN KEY (list_id) REFERENCES price_list(id) ON DELETE CASCADE,
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS discount_rule (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  name VARCHAR(100) NOT NULL,
+  discount_type VARCHAR(20) NOT NULL,
+  value DECIMAL(10,2) NOT NULL,
+  procedure_code VARCHAR(20) NULL,
+  min_family_members INT(11) NOT NULL DEFAULT 0,
+  valid_from DATE NULL,
+  valid_to DATE NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  PRIMARY KEY (id),
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO price_list (id, name, insurer_id, effective_from) VALUES
+  (1, "Aranceles 2022", NULL, "2022-01-01"),
+  (2, "Aranceles OSDE 2022", 1, "2022-01-01");
+
+INSERT INTO price (list_id, procedure_code, amount) VALUES
+  (1, "01.01", 5000.00),
+  (1, "01.04", 3000.00),
+  (1, "02.01", 9000.00),
+  (1, "02.08", 12000.00),
+  (1, "03.01", 30000.00),
+  (1, "03.02", 45000.00),
+  (1, "04.01", 80000.00),
+  (1, "05.01", 8000.00),
+  (1, "05.04", 4000.00),
+  (1, "06.01", 15000.00),
+  (1, "07.01", 12000.00),
+  (1, "07.05", 35000.00),
+  (1, "08.01", 60000.00),
+  (1, "09.01", 250000.00),
+  (2, "01.01", 4500.00),
+  (2, "02.08", 10800.00),
+  (2, "03.01", 27000.00),
+  (2, "03.02", 40500.00),
+  (2, "05.01", 7200.00);
+
+INSERT INTO discount_rule (id, name, discount_type, value, procedure_code, min_family_members, valid_from, valid_to, active) VALUES
+  (1, "Grupo familiar", "family", 10.00, NULL, 2, NULL, NULL, TRUE);
+
+CREATE TABLE IF NOT EXISTS commission_rule (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  dentist_id INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NULL,
+  percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  KEY (dentist_id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id) ON DELETE CASCADE,
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT INTO commission_rule (id, dentist_id, procedure_code, percent) VALUES
+  (1, 1, NULL, 40.00),
+  (2, 1, "09.01", 30.00),
+  (3, 2, NULL, 40.00),
+  (4, 3, NULL, 45.00),
+  (5, 3, "03.01", 50.00),
+  (6, 3, "03.02", 50.00);
+
+CREATE TABLE IF NOT EXISTS app_user (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  username VARCHAR(50) NOT NULL,
+  name VARCHAR(50) NOT NULL,
+  last_name VARCHAR(50) NOT NULL DEFAULT '',
+  email VARCHAR(100) NOT NULL DEFAULT '',
+  role VARCHAR(20) NOT NULL,
+  dentist_id INT(11) NULL,
+  password_hash VARCHAR(100) NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY (username),
+  UNIQUE KEY (dentist_id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS user_session (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  user_id INT(11) NOT NULL,
+  refresh_hash CHAR(64) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  revoked_at DATETIME NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY (refresh_hash),
+  KEY (user_id),
+  FOREIGN KEY (user_id) REFERENCES app_user(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS user_session_token (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  session_id INT(11) NOT NULL,
+  refresh_hash CHAR(64) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY (refresh_hash),
+  FOREIGN KEY (session_id) REFERENCES user_session(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,25 @@
+-- Estado de los turnos y tablas del portal de pacientes para bases creadas antes de este cambio
+
+USE dental_clinic_db;
+
+ALTER TABLE appointment ADD COLUMN status VARCHAR(20) NOT NULL DEFAULT 'scheduled' AFTER description;
+
+CREATE TABLE IF NOT EXISTS portal_code (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  code_hash CHAR(64) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  used TINYINT(1) NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS portal_session (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  token_hash CHAR(64) NOT NULL,
+  expires_at DATETIME NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY (token_hash),
+  FOREIGN KEY (patient_id) REFERENCES patient(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,16 @@
+-- Eventos de la politica de ausencias y cancelaciones tardias
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS policy_event (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  appointment_id INT(11) NOT NULL,
+  type VARCHAR(20) NOT NULL,
+  fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY (appointment_id, type),
+  FOREIGN KEY (patient_id) REFERENCES patient(id) ON DELETE CASCADE,
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,19 @@
+-- Odontograma por paciente con numeracion FDI
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS chart_entry (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  tooth TINYINT NOT NULL,
+  surfaces VARCHAR(5) NOT NULL DEFAULT '',
+  tooth_condition VARCHAR(20) NOT NULL,
+  date DATE NOT NULL,
+  notes TEXT NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (patient_id, tooth),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,31 @@
+-- Planes de tratamiento con procedimientos ordenados
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS treatment_plan (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'proposed',
+  notes TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS treatment_item (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  plan_id INT(11) NOT NULL,
+  position INT(11) NOT NULL,
+  tooth TINYINT NOT NULL DEFAULT 0,
+  surfaces VARCHAR(5) NOT NULL DEFAULT '',
+  procedure_code VARCHAR(20) NOT NULL DEFAULT '',
+  description TEXT NOT NULL,
+  estimated_cost DECIMAL(10,2) NOT NULL DEFAULT 0,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  appointment_id INT(11) NULL,
+  PRIMARY KEY (id),
+  FOREIGN KEY (plan_id) REFERENCES treatment_plan(id) ON DELETE CASCADE,
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
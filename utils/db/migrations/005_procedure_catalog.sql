@@ -0,0 +1,50 @@
+-- Catalogo de procedimientos y codigos en turnos, planes de tratamiento y odontograma
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS dental_procedure (
+  code VARCHAR(20) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  category VARCHAR(50) NOT NULL,
+  default_fee DECIMAL(10,2) NOT NULL DEFAULT 0,
+  default_duration INT(11) NOT NULL DEFAULT 30,
+  teeth VARCHAR(100) NOT NULL DEFAULT '',
+  surfaces VARCHAR(5) NOT NULL DEFAULT '',
+  PRIMARY KEY (code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT IGNORE INTO dental_procedure (code, name, category, default_fee, default_duration, teeth, surfaces) VALUES
+  ("01.01", "Consulta y diagnóstico", "diagnostico", 5000.00, 30, "", ""),
+  ("01.04", "Radiografía periapical", "diagnostico", 3000.00, 15, "permanent", ""),
+  ("02.01", "Obturación con amalgama", "operatoria", 9000.00, 45, "", "MODBL"),
+  ("02.08", "Obturación con resina compuesta", "operatoria", 12000.00, 45, "", "MODBL"),
+  ("03.01", "Tratamiento de conducto unirradicular", "endodoncia", 30000.00, 60, "", ""),
+  ("03.02", "Tratamiento de conducto multirradicular", "endodoncia", 45000.00, 90, "", ""),
+  ("04.01", "Corona de porcelana", "protesis", 80000.00, 60, "", ""),
+  ("05.01", "Limpieza dental de rutina", "prevencion", 8000.00, 30, "", ""),
+  ("05.04", "Aplicación de flúor", "prevencion", 4000.00, 15, "", ""),
+  ("06.01", "Raspaje y alisado radicular", "periodoncia", 15000.00, 45, "", ""),
+  ("07.01", "Extracción simple", "cirugia", 12000.00, 30, "", ""),
+  ("07.05", "Extracción de muela del juicio", "cirugia", 35000.00, 60, "18,28,38,48", ""),
+  ("08.01", "Ortodoncia", "ortodoncia", 60000.00, 45, "", ""),
+  ("09.01", "Implante dental", "implantes", 250000.00, 90, "permanent", "");
+
+ALTER TABLE appointment
+  ADD COLUMN procedure_code VARCHAR(20) NULL AFTER description,
+  ADD FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code);
+
+-- Los turnos existentes toman el codigo del procedimiento con el mismo nombre
+UPDATE appointment
+  INNER JOIN dental_procedure ON appointment.description = dental_procedure.name
+  SET appointment.procedure_code = dental_procedure.code;
+UPDATE appointment SET procedure_code = '02.08' WHERE description = 'Revisión y tratamiento de caries';
+
+ALTER TABLE treatment_item MODIFY procedure_code VARCHAR(20) NULL;
+UPDATE treatment_item SET procedure_code = NULL WHERE procedure_code = '';
+UPDATE treatment_item SET procedure_code = NULL
+  WHERE procedure_code NOT IN (SELECT code FROM dental_procedure);
+ALTER TABLE treatment_item ADD FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code);
+
+ALTER TABLE chart_entry
+  ADD COLUMN procedure_code VARCHAR(20) NULL AFTER tooth_condition,
+  ADD FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code);
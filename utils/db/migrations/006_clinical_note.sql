@@ -0,0 +1,30 @@
+-- Notas clinicas de los turnos y sus agregados, los turnos con notas no se pueden eliminar
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS clinical_note (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  appointment_id INT(11) NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  subjective TEXT NOT NULL,
+  objective TEXT NOT NULL,
+  assessment TEXT NOT NULL,
+  plan TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'draft',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  signed_at DATETIME NULL,
+  PRIMARY KEY (id),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id) ON DELETE RESTRICT,
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS clinical_addendum (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  note_id INT(11) NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  text TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (note_id) REFERENCES clinical_note(id) ON DELETE RESTRICT,
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,42 @@
+-- Historia medica de los pacientes: alergias, medicacion, condiciones y fecha de revision
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS allergy (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  substance VARCHAR(100) NOT NULL,
+  severity VARCHAR(20) NOT NULL,
+  reaction VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS medication (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  dose VARCHAR(50) NOT NULL DEFAULT '',
+  frequency VARCHAR(50) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS medical_condition (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  notes TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS medical_review (
+  patient_id INT(11) NOT NULL,
+  reviewed_at DATE NOT NULL,
+  PRIMARY KEY (patient_id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,31 @@
+-- Recetas emitidas por los dentistas
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS prescription (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  appointment_id INT(11) NULL,
+  dentist_id INT(11) NOT NULL,
+  date DATE NOT NULL,
+  notes TEXT NOT NULL,
+  allergy_override BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS prescription_item (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  prescription_id INT(11) NOT NULL,
+  position INT(11) NOT NULL,
+  drug VARCHAR(100) NOT NULL,
+  dose VARCHAR(50) NOT NULL,
+  frequency VARCHAR(50) NOT NULL,
+  duration VARCHAR(50) NOT NULL DEFAULT '',
+  notes TEXT NOT NULL,
+  PRIMARY KEY (id),
+  FOREIGN KEY (prescription_id) REFERENCES prescription(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,21 @@
+-- Metadata de los archivos adjuntos de pacientes y turnos, el contenido se guarda en FILES_DIR
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS attachment (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  appointment_id INT(11) NULL,
+  file_name VARCHAR(255) NOT NULL,
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  content_type VARCHAR(100) NOT NULL,
+  size BIGINT NOT NULL,
+  checksum CHAR(64) NOT NULL,
+  storage_key VARCHAR(255) NOT NULL,
+  has_thumbnail BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (checksum),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,47 @@
+-- Plantillas de consentimiento informado y consentimientos firmados por los pacientes
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS consent_template (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  name VARCHAR(150) NOT NULL,
+  body TEXT NOT NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS consent_template_procedure (
+  template_id INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NOT NULL,
+  PRIMARY KEY (template_id, procedure_code),
+  FOREIGN KEY (template_id) REFERENCES consent_template(id),
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS consent (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  template_id INT(11) NOT NULL,
+  patient_id INT(11) NOT NULL,
+  appointment_id INT(11) NULL,
+  treatment_plan_id INT(11) NULL,
+  body TEXT NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  signer_name VARCHAR(150) NOT NULL DEFAULT '',
+  signature_key VARCHAR(255) NOT NULL DEFAULT '',
+  signed_at DATETIME NULL,
+  signed_ip VARCHAR(45) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (patient_id, status),
+  FOREIGN KEY (template_id) REFERENCES consent_template(id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id),
+  FOREIGN KEY (treatment_plan_id) REFERENCES treatment_plan(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT IGNORE INTO consent_template (id, name, body) VALUES
+(1, "Consentimiento informado para cirugía oral", "Declaro haber sido informado/a sobre el procedimiento quirúrgico a realizar, sus riesgos (dolor, inflamación, sangrado, infección, parestesia), alternativas y cuidados posteriores, y autorizo al profesional a realizarlo.");
+
+INSERT IGNORE INTO consent_template_procedure (template_id, procedure_code) VALUES
+(1, "07.01"), (1, "07.05"), (1, "09.01");
@@ -0,0 +1,35 @@
+-- Examenes periodontales con profundidad, recesion y sangrado de seis sitios y movilidad por pieza
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS perio_exam (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  date DATE NOT NULL,
+  notes TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (patient_id, date),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS perio_tooth (
+  exam_id INT(11) NOT NULL,
+  tooth INT(11) NOT NULL,
+  mobility TINYINT NOT NULL DEFAULT 0,
+  PRIMARY KEY (exam_id, tooth),
+  FOREIGN KEY (exam_id) REFERENCES perio_exam(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS perio_site (
+  exam_id INT(11) NOT NULL,
+  tooth INT(11) NOT NULL,
+  site VARCHAR(2) NOT NULL,
+  depth TINYINT NOT NULL,
+  recession TINYINT NOT NULL DEFAULT 0,
+  bleeding BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (exam_id, tooth, site),
+  FOREIGN KEY (exam_id, tooth) REFERENCES perio_tooth(exam_id, tooth)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
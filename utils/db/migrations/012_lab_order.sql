@@ -0,0 +1,24 @@
+-- Ordenes de laboratorio de coronas y protesis con su fecha de entrega
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS lab_order (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  dentist_id INT(11) NOT NULL,
+  treatment_item_id INT(11) NOT NULL,
+  lab_name VARCHAR(100) NOT NULL,
+  description VARCHAR(255) NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'sent',
+  sent_date DATE NOT NULL,
+  expected_date DATE NOT NULL,
+  received_date DATE NULL,
+  fitted_date DATE NULL,
+  notes TEXT NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (status, expected_date),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id),
+  FOREIGN KEY (treatment_item_id) REFERENCES treatment_item(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,17 @@
+-- Relaciones entre pacientes (guardian, padre, conyuge) y el responsable de contacto y facturacion
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS patient_relation (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  related_id INT(11) NOT NULL,
+  relationship VARCHAR(20) NOT NULL,
+  responsible BOOLEAN NOT NULL DEFAULT FALSE,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY (patient_id, related_id),
+  KEY (related_id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id) ON DELETE CASCADE,
+  FOREIGN KEY (related_id) REFERENCES patient(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,37 @@
+-- Domicilio estructurado, fecha de nacimiento, telefonos, idioma y preferencias de contacto de los pacientes
+
+USE dental_clinic_db;
+
+ALTER TABLE patient
+  ADD COLUMN address_street VARCHAR(100) NOT NULL DEFAULT '' AFTER last_name,
+  ADD COLUMN address_number VARCHAR(20) NOT NULL DEFAULT '' AFTER address_street,
+  ADD COLUMN address_city VARCHAR(50) NOT NULL DEFAULT '' AFTER address_number,
+  ADD COLUMN address_province VARCHAR(50) NOT NULL DEFAULT '' AFTER address_city,
+  ADD COLUMN address_postal_code VARCHAR(10) NOT NULL DEFAULT '' AFTER address_province,
+  ADD COLUMN birth_date DATE NULL AFTER dni,
+  ADD COLUMN preferred_language VARCHAR(10) NOT NULL DEFAULT 'es' AFTER email,
+  ADD COLUMN contact_channel VARCHAR(20) NOT NULL DEFAULT 'email' AFTER preferred_language,
+  ADD COLUMN reminders BOOLEAN NOT NULL DEFAULT TRUE AFTER contact_channel;
+
+-- Los domicilios que terminan en un numero se separan en calle y numero, el resto queda
+-- completo en la calle para no perder datos
+UPDATE patient
+  SET address_street = TRIM(LEFT(TRIM(domicilio), CHAR_LENGTH(TRIM(domicilio)) - CHAR_LENGTH(SUBSTRING_INDEX(TRIM(domicilio), ' ', -1)))),
+      address_number = SUBSTRING_INDEX(TRIM(domicilio), ' ', -1)
+  WHERE TRIM(domicilio) LIKE '% %'
+    AND SUBSTRING_INDEX(TRIM(domicilio), ' ', -1) REGEXP '^[0-9]+[A-Za-z]?$';
+UPDATE patient
+  SET address_street = TRIM(domicilio)
+  WHERE address_number = '';
+
+ALTER TABLE patient DROP COLUMN domicilio;
+
+CREATE TABLE IF NOT EXISTS patient_phone (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  phone_type VARCHAR(20) NOT NULL,
+  number VARCHAR(30) NOT NULL,
+  is_primary BOOLEAN NOT NULL DEFAULT FALSE,
+  PRIMARY KEY (id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,37 @@
+-- Especialidades de los dentistas y la especialidad que requiere cada procedimiento del catalogo
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS specialty (
+  code VARCHAR(20) NOT NULL,
+  name VARCHAR(50) NOT NULL,
+  PRIMARY KEY (code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT IGNORE INTO specialty (code, name) VALUES
+  ("orthodontics", "Ortodoncia"),
+  ("endodontics", "Endodoncia"),
+  ("periodontics", "Periodoncia"),
+  ("oral_surgery", "Cirugía bucal"),
+  ("pediatric", "Odontopediatría");
+
+CREATE TABLE IF NOT EXISTS dentist_specialty (
+  dentist_id INT(11) NOT NULL,
+  specialty VARCHAR(20) NOT NULL,
+  PRIMARY KEY (dentist_id, specialty),
+  KEY (specialty),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id) ON DELETE CASCADE,
+  FOREIGN KEY (specialty) REFERENCES specialty(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+ALTER TABLE dental_procedure
+  ADD COLUMN specialty VARCHAR(20) NULL AFTER surfaces,
+  ADD FOREIGN KEY (specialty) REFERENCES specialty(code);
+
+UPDATE dental_procedure SET specialty = 'endodontics' WHERE code IN ('03.01', '03.02');
+UPDATE dental_procedure SET specialty = 'periodontics' WHERE code = '06.01';
+UPDATE dental_procedure SET specialty = 'oral_surgery' WHERE code IN ('07.05', '09.01');
+UPDATE dental_procedure SET specialty = 'orthodontics' WHERE code = '08.01';
+
+-- Las especialidades de los dentistas existentes se cargan con PUT /dentists/:id, hasta
+-- entonces solo pueden agendar los procedimientos que no requieren especialidad
@@ -0,0 +1,25 @@
+-- Derivaciones de pacientes entre dentistas o a una especialidad
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS referral (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  from_dentist_id INT(11) NOT NULL,
+  to_dentist_id INT(11) NULL,
+  specialty VARCHAR(20) NULL,
+  reason TEXT NOT NULL,
+  urgency VARCHAR(20) NOT NULL DEFAULT 'routine',
+  status VARCHAR(20) NOT NULL DEFAULT 'pending',
+  response TEXT NOT NULL,
+  appointment_id INT(11) NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (to_dentist_id, status),
+  KEY (specialty, status),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (from_dentist_id) REFERENCES dentist(id),
+  FOREIGN KEY (to_dentist_id) REFERENCES dentist(id),
+  FOREIGN KEY (specialty) REFERENCES specialty(code),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id) ON DELETE SET NULL
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,57 @@
+-- Facturas con numeracion correlativa por serie, los borradores no consumen numeros
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS invoice_sequence (
+  series VARCHAR(10) NOT NULL,
+  last_number INT(11) NOT NULL DEFAULT 0,
+  PRIMARY KEY (series)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS invoice (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  series VARCHAR(10) NULL,
+  number INT(11) NULL,
+  patient_id INT(11) NOT NULL,
+  appointment_id INT(11) NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'draft',
+  issue_date DATE NULL,
+  bill_to_id INT(11) NOT NULL,
+  bill_to_name VARCHAR(50) NOT NULL,
+  bill_to_last_name VARCHAR(50) NOT NULL,
+  bill_to_email VARCHAR(50) NOT NULL,
+  subtotal DECIMAL(10,2) NOT NULL DEFAULT 0,
+  discount DECIMAL(10,2) NOT NULL DEFAULT 0,
+  tax DECIMAL(10,2) NOT NULL DEFAULT 0,
+  total DECIMAL(10,2) NOT NULL DEFAULT 0,
+  notes TEXT NOT NULL,
+  void_reason VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY (series, number),
+  KEY (status),
+  KEY (appointment_id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS invoice_item (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  invoice_id INT(11) NOT NULL,
+  position INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NULL,
+  description VARCHAR(255) NOT NULL,
+  tooth INT(11) NOT NULL DEFAULT 0,
+  quantity INT(11) NOT NULL DEFAULT 1,
+  unit_price DECIMAL(10,2) NOT NULL,
+  discount_percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+  tax_rate DECIMAL(5,2) NOT NULL DEFAULT 0,
+  subtotal DECIMAL(10,2) NOT NULL,
+  discount DECIMAL(10,2) NOT NULL DEFAULT 0,
+  tax DECIMAL(10,2) NOT NULL DEFAULT 0,
+  total DECIMAL(10,2) NOT NULL,
+  PRIMARY KEY (id),
+  KEY (invoice_id, position),
+  FOREIGN KEY (invoice_id) REFERENCES invoice(id) ON DELETE CASCADE,
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,32 @@
+-- Cuenta corriente de pacientes: cargos, pagos, devoluciones, ajustes e imputacion de pagos a facturas
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS ledger_entry (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  entry_type VARCHAR(20) NOT NULL,
+  entry_date DATE NOT NULL,
+  debit DECIMAL(10,2) NOT NULL DEFAULT 0,
+  credit DECIMAL(10,2) NOT NULL DEFAULT 0,
+  method VARCHAR(20) NULL,
+  invoice_id INT(11) NULL,
+  reference VARCHAR(100) NOT NULL DEFAULT '',
+  description VARCHAR(255) NOT NULL DEFAULT '',
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (patient_id, entry_date),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (invoice_id) REFERENCES invoice(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS payment_allocation (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  entry_id INT(11) NOT NULL,
+  invoice_id INT(11) NOT NULL,
+  amount DECIMAL(10,2) NOT NULL,
+  PRIMARY KEY (id),
+  KEY (invoice_id),
+  FOREIGN KEY (entry_id) REFERENCES ledger_entry(id) ON DELETE CASCADE,
+  FOREIGN KEY (invoice_id) REFERENCES invoice(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
@@ -0,0 +1,104 @@
+-- Obras sociales y prepagas: planes, reglas de cobertura por procedimiento, coberturas de pacientes y lotes de prestaciones
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS insurer (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  code VARCHAR(20) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  insurer_type VARCHAR(20) NOT NULL,
+  claim_format VARCHAR(20) NOT NULL DEFAULT 'csv',
+  PRIMARY KEY (id),
+  UNIQUE KEY (code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS insurance_plan (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  insurer_id INT(11) NOT NULL,
+  code VARCHAR(20) NOT NULL,
+  name VARCHAR(100) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY (insurer_id, code),
+  FOREIGN KEY (insurer_id) REFERENCES insurer(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS coverage_rule (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  plan_id INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NOT NULL,
+  percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+  copay DECIMAL(10,2) NOT NULL DEFAULT 0,
+  annual_limit INT(11) NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  UNIQUE KEY (plan_id, procedure_code),
+  FOREIGN KEY (plan_id) REFERENCES insurance_plan(id) ON DELETE CASCADE,
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS patient_coverage (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  patient_id INT(11) NOT NULL,
+  plan_id INT(11) NOT NULL,
+  member_number VARCHAR(30) NOT NULL,
+  valid_from DATE NOT NULL,
+  valid_to DATE NULL,
+  PRIMARY KEY (id),
+  KEY (patient_id),
+  FOREIGN KEY (patient_id) REFERENCES patient(id),
+  FOREIGN KEY (plan_id) REFERENCES insurance_plan(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS claim_batch (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  insurer_id INT(11) NOT NULL,
+  date_from DATE NOT NULL,
+  date_to DATE NOT NULL,
+  status VARCHAR(20) NOT NULL DEFAULT 'open',
+  total DECIMAL(10,2) NOT NULL DEFAULT 0,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (insurer_id, status),
+  FOREIGN KEY (insurer_id) REFERENCES insurer(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS claim (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  batch_id INT(11) NOT NULL,
+  appointment_id INT(11) NOT NULL,
+  coverage_id INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NOT NULL,
+  service_date DATE NOT NULL,
+  fee DECIMAL(10,2) NOT NULL,
+  copay DECIMAL(10,2) NOT NULL DEFAULT 0,
+  amount DECIMAL(10,2) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY (appointment_id),
+  KEY (batch_id),
+  FOREIGN KEY (batch_id) REFERENCES claim_batch(id) ON DELETE CASCADE,
+  FOREIGN KEY (appointment_id) REFERENCES appointment(id),
+  FOREIGN KEY (coverage_id) REFERENCES patient_coverage(id),
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT IGNORE INTO insurer (id, code, name, insurer_type, claim_format) VALUES
+  (1, "OSDE", "OSDE", "prepaga", "csv"),
+  (2, "IOMA", "Instituto de Obra Médico Asistencial", "obra_social", "fixed_width");
+
+INSERT IGNORE INTO insurance_plan (id, insurer_id, code, name) VALUES
+  (1, 1, "210", "OSDE 210"),
+  (2, 1, "310", "OSDE 310"),
+  (3, 2, "GRAL", "IOMA General");
+
+INSERT IGNORE INTO coverage_rule (plan_id, procedure_code, percent, copay, annual_limit) VALUES
+  (1, "01.01", 100.00, 0.00, 2),
+  (1, "05.01", 100.00, 0.00, 2),
+  (1, "02.08", 70.00, 0.00, 0),
+  (1, "03.01", 50.00, 0.00, 0),
+  (2, "01.01", 100.00, 0.00, 0),
+  (2, "05.01", 100.00, 0.00, 2),
+  (2, "02.08", 100.00, 0.00, 0),
+  (2, "03.01", 80.00, 0.00, 0),
+  (2, "03.02", 80.00, 0.00, 0),
+  (3, "01.01", 100.00, 1500.00, 1),
+  (3, "05.01", 100.00, 1500.00, 1),
+  (3, "07.01", 100.00, 2000.00, 0);
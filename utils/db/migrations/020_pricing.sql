@@ -0,0 +1,69 @@
+-- Listas de precios versionadas por fecha de vigencia y financiador, reglas de descuento y descuento fijo en items de factura
+
+USE dental_clinic_db;
+
+ALTER TABLE invoice_item ADD COLUMN discount_fixed DECIMAL(10,2) NOT NULL DEFAULT 0 AFTER discount_percent;
+
+CREATE TABLE IF NOT EXISTS price_list (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  name VARCHAR(100) NOT NULL,
+  insurer_id INT(11) NULL,
+  effective_from DATE NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  KEY (insurer_id, effective_from),
+  FOREIGN KEY (insurer_id) REFERENCES insurer(id)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS price (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  list_id INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NOT NULL,
+  amount DECIMAL(10,2) NOT NULL,
+  PRIMARY KEY (id),
+  UNIQUE KEY (list_id, procedure_code),
+  FOREIGN KEY (list_id) REFERENCES price_list(id) ON DELETE CASCADE,
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+CREATE TABLE IF NOT EXISTS discount_rule (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  name VARCHAR(100) NOT NULL,
+  discount_type VARCHAR(20) NOT NULL,
+  value DECIMAL(10,2) NOT NULL,
+  procedure_code VARCHAR(20) NULL,
+  min_family_members INT(11) NOT NULL DEFAULT 0,
+  valid_from DATE NULL,
+  valid_to DATE NULL,
+  active BOOLEAN NOT NULL DEFAULT TRUE,
+  PRIMARY KEY (id),
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT IGNORE INTO price_list (id, name, insurer_id, effective_from) VALUES
+  (1, "Aranceles 2022", NULL, "2022-01-01"),
+  (2, "Aranceles OSDE 2022", 1, "2022-01-01");
+
+INSERT IGNORE INTO price (list_id, procedure_code, amount) VALUES
+  (1, "01.01", 5000.00),
+  (1, "01.04", 3000.00),
+  (1, "02.01", 9000.00),
+  (1, "02.08", 12000.00),
+  (1, "03.01", 30000.00),
+  (1, "03.02", 45000.00),
+  (1, "04.01", 80000.00),
+  (1, "05.01", 8000.00),
+  (1, "05.04", 4000.00),
+  (1, "06.01", 15000.00),
+  (1, "07.01", 12000.00),
+  (1, "07.05", 35000.00),
+  (1, "08.01", 60000.00),
+  (1, "09.01", 250000.00),
+  (2, "01.01", 4500.00),
+  (2, "02.08", 10800.00),
+  (2, "03.01", 27000.00),
+  (2, "03.02", 40500.00),
+  (2, "05.01", 7200.00);
+
+INSERT IGNORE INTO discount_rule (id, name, discount_type, value, procedure_code, min_family_members, valid_from, valid_to, active) VALUES
+  (1, "Grupo familiar", "family", 10.00, NULL, 2, NULL, NULL, TRUE);
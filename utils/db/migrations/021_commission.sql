@@ -0,0 +1,22 @@
+-- Reglas de comision de los dentistas sobre lo que producen, por defecto y por procedimiento
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS commission_rule (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  dentist_id INT(11) NOT NULL,
+  procedure_code VARCHAR(20) NULL,
+  percent DECIMAL(5,2) NOT NULL DEFAULT 0,
+  PRIMARY KEY (id),
+  KEY (dentist_id),
+  FOREIGN KEY (dentist_id) REFERENCES dentist(id) ON DELETE CASCADE,
+  FOREIGN KEY (procedure_code) REFERENCES dental_procedure(code)
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT IGNORE INTO commission_rule (id, dentist_id, procedure_code, percent) VALUES
+  (1, 1, NULL, 40.00),
+  (2, 1, "09.01", 30.00),
+  (3, 2, NULL, 40.00),
+  (4, 3, NULL, 45.00),
+  (5, 3, "03.01", 50.00),
+  (6, 3, "03.02", 50.00);
@@ -0,0 +1,6 @@
+-- Rol de cada usuario del personal, los usuarios existentes quedan como admin porque ya tenian acceso a todo
+
+USE dental_clinic_db;
+
+ALTER TABLE app_user ADD COLUMN role VARCHAR(20) NOT NULL DEFAULT 'admin' AFTER email;
+ALTER TABLE app_user ALTER COLUMN role DROP DEFAULT;
@@ -0,0 +1,26 @@
+-- Limite de intentos y de pedidos de codigos del portal, e indice unico para no reservar dos veces el mismo horario.
+-- Desde esta migracion tampoco el personal puede dar dos turnos activos a un dentista en la misma fecha y hora,
+-- hay que cancelar o mover uno antes. Si la base ya tiene turnos superpuestos la migracion se detiene sin cambiar
+-- nada, se listan con:
+--   SELECT dentist_id, date, hour, GROUP_CONCAT(id) FROM appointment WHERE status <> 'cancelled'
+--   GROUP BY dentist_id, date, hour HAVING COUNT(*) > 1;
+
+USE dental_clinic_db;
+
+DROP PROCEDURE IF EXISTS check_double_bookings;
+DELIMITER //
+CREATE PROCEDURE check_double_bookings()
+BEGIN
+  IF EXISTS (SELECT 1 FROM appointment WHERE status <> 'cancelled' GROUP BY dentist_id, date, hour HAVING COUNT(*) > 1) THEN
+    SIGNAL SQLSTATE '45000' SET MESSAGE_TEXT = 'there are double booked appointments, cancel or move them before running this migration';
+  END IF;
+END //
+DELIMITER ;
+CALL check_double_bookings();
+DROP PROCEDURE check_double_bookings;
+
+ALTER TABLE portal_code ADD COLUMN attempts INT(11) NOT NULL DEFAULT 0 AFTER used;
+ALTER TABLE portal_code ADD COLUMN created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP AFTER attempts;
+
+ALTER TABLE appointment ADD COLUMN slot_taken TINYINT(1) AS (IF(status = 'cancelled', NULL, 1)) STORED;
+ALTER TABLE appointment ADD UNIQUE KEY (dentist_id, date, hour, slot_taken);
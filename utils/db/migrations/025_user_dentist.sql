@@ -0,0 +1,7 @@
+-- Dentista vinculado a cada usuario, las notas clinicas se firman con el dentista del usuario que inicio la sesion
+
+USE dental_clinic_db;
+
+ALTER TABLE app_user ADD COLUMN dentist_id INT(11) NULL AFTER role;
+ALTER TABLE app_user ADD UNIQUE KEY (dentist_id);
+ALTER TABLE app_user ADD FOREIGN KEY (dentist_id) REFERENCES dentist(id);
@@ -0,0 +1,20 @@
+-- Todos los refresh tokens que emitio cada sesion, para revocarla si se vuelve a usar cualquiera ya rotado
+
+USE dental_clinic_db;
+
+CREATE TABLE IF NOT EXISTS user_session_token (
+  id INT(11) NOT NULL AUTO_INCREMENT,
+  session_id INT(11) NOT NULL,
+  refresh_hash CHAR(64) NOT NULL,
+  created_at DATETIME NOT NULL DEFAULT CURRENT_TIMESTAMP,
+  PRIMARY KEY (id),
+  UNIQUE KEY (refresh_hash),
+  FOREIGN KEY (session_id) REFERENCES user_session(id) ON DELETE CASCADE
+) ENGINE=InnoDB DEFAULT CHARSET=utf8mb4;
+
+INSERT IGNORE INTO user_session_token (session_id, refresh_hash)
+  SELECT id, previous_hash FROM user_session WHERE previous_hash IS NOT NULL;
+INSERT IGNORE INTO user_session_token (session_id, refresh_hash)
+  SELECT id, refresh_hash FROM user_session;
+
+ALTER TABLE user_session DROP COLUMN previous_hash;